@@ -10,7 +10,7 @@ endif
 " Keywords and Control Flow
 syntax keyword vintKeyword let const return break continue
 syntax keyword vintKeyword import package include
-syntax keyword vintKeyword defer repeat throw
+syntax keyword vintKeyword defer repeat throw try catch finally
 
 " Conditionals and Loops
 syntax keyword vintConditional if else switch case default
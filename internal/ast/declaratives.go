@@ -193,16 +193,19 @@ type StructMethod struct {
 	ParamTypes []Type         // parallel to Parameters, nil for untyped
 	ReturnType Type           // nil for void/untyped
 	Defaults   map[string]Expression
+	Variadic   bool // the last parameter collects the remaining arguments
+	Generator  bool // the body yields, so calling the method returns a generator
 	Body       *BlockStatement
 }
 
 // StructStatement represents a struct declaration
 // Example: struct User { name: "default", age: 0, func greet() { return "hi" } }
 type StructStatement struct {
-	Token   token.Token    // The 'struct' token
-	Name    *Identifier    // Struct name (e.g., "User")
-	Fields  []StructField  // Struct fields with optional defaults
-	Methods []StructMethod // Struct methods
+	Token      token.Token      // The 'struct' token
+	Name       *Identifier      // Struct name (e.g., "User")
+	TypeParams []*TypeParameter // <T> for a generic struct: struct Box<T> { value: T }
+	Fields     []StructField    // Struct fields with optional defaults
+	Methods    []StructMethod   // Struct methods
 }
 
 func (ss *StructStatement) statementNode()       {}
@@ -212,6 +215,7 @@ func (ss *StructStatement) String() string {
 
 	out.WriteString("struct ")
 	out.WriteString(ss.Name.String())
+	out.WriteString(TypeParametersString(ss.TypeParams))
 	out.WriteString(" {\n")
 
 	for _, f := range ss.Fields {
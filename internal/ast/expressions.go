@@ -96,6 +96,7 @@ type CallExpression struct {
 	Token     token.Token
 	Function  Expression // can be Identifier or FunctionLiteral
 	Arguments []Expression
+	Optional  bool // f?.(x), which gives null if f is null
 }
 
 func (ce *CallExpression) expressionNode()      {}
@@ -109,6 +110,9 @@ func (ce *CallExpression) String() string {
 	}
 
 	out.WriteString(ce.Function.String())
+	if ce.Optional {
+		out.WriteString("?.")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(args, ", "))
 	out.WriteString(")")
@@ -118,9 +122,10 @@ func (ce *CallExpression) String() string {
 
 // IndexExpression represents array/dict indexing like arr[0], dict["key"]
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Optional bool // a?.[i], which gives null if a is null
 }
 
 func (ie *IndexExpression) expressionNode()      {}
@@ -130,6 +135,9 @@ func (ie *IndexExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?.")
+	}
 	out.WriteString("[")
 	out.WriteString(ie.Index.String())
 	out.WriteString("])")
@@ -139,10 +147,11 @@ func (ie *IndexExpression) String() string {
 
 // SliceExpression represents array slicing like arr[1:3]
 type SliceExpression struct {
-	Token token.Token
-	Left  Expression
-	Start Expression
-	End   Expression
+	Token    token.Token
+	Left     Expression
+	Start    Expression
+	End      Expression
+	Optional bool // a?.[i:j], which gives null if a is null
 }
 
 func (se *SliceExpression) expressionNode()      {}
@@ -152,6 +161,9 @@ func (se *SliceExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(se.Left.String())
+	if se.Optional {
+		out.WriteString("?.")
+	}
 	out.WriteString("[")
 	if se.Start != nil {
 		out.WriteString(se.Start.String())
@@ -255,6 +267,7 @@ type ForIn struct {
 	Token    token.Token
 	Key      string
 	Value    string
+	Pattern  Expression // destructures each value instead of Value: for [k, v] in pairs
 	Iterable Expression
 	Block    *BlockStatement
 }
@@ -268,7 +281,11 @@ func (fi *ForIn) String() string {
 	if fi.Key != "" {
 		out.WriteString(fi.Key + ", ")
 	}
-	out.WriteString(fi.Value + " ")
+	if fi.Pattern != nil {
+		out.WriteString(fi.Pattern.String() + " ")
+	} else {
+		out.WriteString(fi.Value + " ")
+	}
 	out.WriteString("in ")
 	out.WriteString(fi.Iterable.String() + " {\n")
 	out.WriteString("\t" + fi.Block.String())
@@ -370,6 +387,7 @@ type MethodExpression struct {
 	Method    Expression
 	Arguments []Expression
 	Defaults  map[string]Expression
+	Optional  bool // obj?.method(), which gives null if obj is null
 }
 
 func (me *MethodExpression) expressionNode()      {}
@@ -377,7 +395,11 @@ func (me *MethodExpression) TokenLiteral() string { return me.Token.Literal }
 func (me *MethodExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(me.Object.String())
-	out.WriteString(".")
+	if me.Optional {
+		out.WriteString("?.")
+	} else {
+		out.WriteString(".")
+	}
 	out.WriteString(me.Method.String())
 
 	return out.String()
@@ -395,9 +417,10 @@ func (pa *PropertyAssignment) String() string       { return "tach" }
 
 type PropertyExpression struct {
 	Expression
-	Token    token.Token // The . token
+	Token    token.Token // The . or ?. token
 	Object   Expression
 	Property Expression
+	Optional bool // obj?.field, which gives null if obj is null
 }
 
 func (pe *PropertyExpression) expressionNode()      {}
@@ -543,6 +566,20 @@ func (me *MatchExpression) String() string {
 	return out.String()
 }
 
+// TypePattern matches a value of a type in a match case and binds it to a
+// name: `c: Circle => ...`, `s: Shape => ...` or `_: string => ...`.
+type TypePattern struct {
+	Token token.Token // the ':' token
+	Name  *Identifier
+	Type  Type
+}
+
+func (tp *TypePattern) expressionNode()      {}
+func (tp *TypePattern) TokenLiteral() string { return tp.Token.Literal }
+func (tp *TypePattern) String() string {
+	return tp.Name.String() + ": " + tp.Type.String()
+}
+
 // Array pattern for destructuring: [first, second, ...rest]
 type ArrayPattern struct {
 	Token    token.Token  // the '[' token
@@ -570,23 +607,91 @@ func (ap *ArrayPattern) String() string {
 	return out.String()
 }
 
-// Spread pattern for array destructuring: ...rest
-type SpreadPattern struct {
+// SpreadExpression expands an array into the arguments of a call or the
+// elements of an array literal, or a dict into a dict literal:
+// f(...args), [...a, ...b], {...defaults, ...overrides}.
+type SpreadExpression struct {
 	Token token.Token // the '...' token
-	Name  *Identifier // variable name to bind remaining elements
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string {
+	return "..." + se.Value.String()
 }
 
-func (sp *SpreadPattern) expressionNode()      {}
-func (sp *SpreadPattern) TokenLiteral() string { return sp.Token.Literal }
-func (sp *SpreadPattern) String() string {
+// DictPattern destructures a dictionary: {name, age: years, ...others}.
+// The value under Keys[i] is bound to Values[i], which is an identifier,
+// a nested pattern, or an Assign that gives an identifier a default.
+type DictPattern struct {
+	Token  token.Token // the '{' token
+	Keys   []string
+	Values []Expression
+	Rest   *Identifier // binds the pairs no key names (...name)
+}
+
+func (dp *DictPattern) expressionNode()      {}
+func (dp *DictPattern) TokenLiteral() string { return dp.Token.Literal }
+func (dp *DictPattern) String() string {
 	var out bytes.Buffer
-	out.WriteString("...")
-	if sp.Name != nil {
-		out.WriteString(sp.Name.String())
+	out.WriteString("{")
+
+	entries := []string{}
+	for i, key := range dp.Keys {
+		switch v := dp.Values[i].(type) {
+		case *Identifier:
+			if v.Value == key {
+				entries = append(entries, key)
+				continue
+			}
+		case *Assign:
+			if v.Name.Value == key {
+				entries = append(entries, key+" = "+v.Value.String())
+				continue
+			}
+		}
+		entries = append(entries, key+": "+dp.Values[i].String())
+	}
+
+	if dp.Rest != nil {
+		entries = append(entries, "..."+dp.Rest.String())
 	}
+
+	out.WriteString(strings.Join(entries, ", "))
+	out.WriteString("}")
 	return out.String()
 }
 
+// PatternNames returns the identifiers a destructuring pattern binds, in
+// the order they appear.
+func PatternNames(pattern Expression) []*Identifier {
+	var names []*Identifier
+	switch p := pattern.(type) {
+	case *Identifier:
+		if p.Value != "_" {
+			names = append(names, p)
+		}
+	case *Assign:
+		names = append(names, PatternNames(p.Name)...)
+	case *ArrayPattern:
+		for _, e := range p.Elements {
+			names = append(names, PatternNames(e)...)
+		}
+		if p.Rest != nil {
+			names = append(names, PatternNames(p.Rest)...)
+		}
+	case *DictPattern:
+		for _, v := range p.Values {
+			names = append(names, PatternNames(v)...)
+		}
+		if p.Rest != nil {
+			names = append(names, PatternNames(p.Rest)...)
+		}
+	}
+	return names
+}
+
 // StructLiteral represents struct instantiation with brace syntax:
 // StructName{field1: value1, field2: value2}
 type StructLiteral struct {
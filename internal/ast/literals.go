@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/vintlang/vintlang/internal/token"
@@ -27,6 +29,17 @@ func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+// BigIntLiteral represents integers too large for an IntegerLiteral, and
+// integers with an n suffix like 123n
+type BigIntLiteral struct {
+	Token token.Token
+	Value *big.Int
+}
+
+func (bl *BigIntLiteral) expressionNode()      {}
+func (bl *BigIntLiteral) TokenLiteral() string { return bl.Token.Literal }
+func (bl *BigIntLiteral) String() string       { return bl.Token.Literal }
+
 // FloatLiteral represents floating point values like 3.14, 2.718
 type FloatLiteral struct {
 	Token token.Token
@@ -47,6 +60,42 @@ func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// InterpolatedString is a string with embedded expressions, such as
+// "Hello ${name}" or `total: ${price:.2f}`. Its parts are StringLiterals
+// for the text and Interpolations for the expressions.
+type InterpolatedString struct {
+	Token token.Token // the TEMPLATE token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+	out.WriteString("\"")
+	for _, part := range is.Parts {
+		out.WriteString(part.String())
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
+// Interpolation is one ${value} or ${value:format} in an InterpolatedString.
+type Interpolation struct {
+	Token  token.Token // the TEMPLATE token, positioned at the '$'
+	Value  Expression
+	Format string
+}
+
+func (i *Interpolation) expressionNode()      {}
+func (i *Interpolation) TokenLiteral() string { return i.Token.Literal }
+func (i *Interpolation) String() string {
+	if i.Format != "" {
+		return "${" + i.Value.String() + ":" + i.Format + "}"
+	}
+	return "${" + i.Value.String() + "}"
+}
+
 // Boolean represents boolean values like true, false
 type Boolean struct {
 	Token token.Token
@@ -81,18 +130,40 @@ func (al *ArrayLiteral) String() string {
 }
 
 // DictLiteral represents dictionary/hash literals like {key: value}
+// A key that is a *SpreadExpression merges another dict into the literal;
+// its value in Pairs is the spread's Value.
 type DictLiteral struct {
 	Token token.Token
 	Pairs map[Expression]Expression
+	Keys  []Expression // the keys of Pairs in source order
 }
 
 func (dl *DictLiteral) expressionNode()      {}
 func (dl *DictLiteral) TokenLiteral() string { return dl.Token.Literal }
+
+// OrderedKeys returns the keys in source order. Literals built without
+// Keys fall back to the order of their String() form.
+func (dl *DictLiteral) OrderedKeys() []Expression {
+	if len(dl.Keys) == len(dl.Pairs) {
+		return dl.Keys
+	}
+	keys := make([]Expression, 0, len(dl.Pairs))
+	for key := range dl.Pairs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
 func (dl *DictLiteral) String() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for key, value := range dl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range dl.OrderedKeys() {
+		if _, ok := key.(*SpreadExpression); ok {
+			pairs = append(pairs, key.String())
+			continue
+		}
+		pairs = append(pairs, key.String()+":"+dl.Pairs[key].String())
 	}
 
 	out.WriteString("(")
@@ -108,6 +179,8 @@ type FunctionLiteral struct {
 	Name       string
 	Parameters []*Identifier
 	Defaults   map[string]Expression
+	Variadic   bool // the last parameter collects the remaining arguments: func(a, ...rest)
+	Generator  bool // the body yields, so calling the function returns a generator
 	Body       *BlockStatement
 }
 
@@ -121,6 +194,9 @@ func (fl *FunctionLiteral) String() string {
 	for _, p := range fl.Parameters {
 		params = append(params, p.String())
 	}
+	if fl.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
 
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
@@ -137,6 +213,7 @@ type AsyncFunctionLiteral struct {
 	Name       string
 	Parameters []*Identifier
 	Defaults   map[string]Expression
+	Variadic   bool // the last parameter collects the remaining arguments
 	Body       *BlockStatement
 }
 
@@ -149,6 +226,9 @@ func (afl *AsyncFunctionLiteral) String() string {
 	for _, p := range afl.Parameters {
 		params = append(params, p.String())
 	}
+	if afl.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
 
 	out.WriteString("async func")
 	out.WriteString("(")
@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"strconv"
 	"strings"
 
 	"github.com/vintlang/vintlang/internal/token"
@@ -31,6 +32,31 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+// DestructureStatement binds the parts of a value to the names in a
+// pattern: let [first, ...rest] = items, or const {name, age} = user.
+type DestructureStatement struct {
+	Token   token.Token // the let or const token
+	Pattern Expression  // *ArrayPattern or *DictPattern
+	Value   Expression
+}
+
+func (ds *DestructureStatement) statementNode()       {}
+func (ds *DestructureStatement) TokenLiteral() string { return ds.Token.Literal }
+func (ds *DestructureStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ds.TokenLiteral() + " ")
+	out.WriteString(ds.Pattern.String())
+	out.WriteString(" = ")
+
+	if ds.Value != nil {
+		out.WriteString(ds.Value.String())
+	}
+
+	out.WriteString(";")
+	return out.String()
+}
+
 // ConstStatement represents constant declarations like: const PI = 3.14;
 type ConstStatement struct {
 	Token token.Token
@@ -75,6 +101,20 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+// YieldStatement hands a value to whoever is iterating over a generator,
+// and waits until the next value is asked for. A function whose body
+// contains one is a generator function.
+type YieldStatement struct {
+	Token token.Token // the 'yield' token
+	Value Expression
+}
+
+func (ys *YieldStatement) statementNode()       {}
+func (ys *YieldStatement) TokenLiteral() string { return ys.Token.Literal }
+func (ys *YieldStatement) String() string {
+	return ys.TokenLiteral() + " " + ys.Value.String() + ";"
+}
+
 // ExpressionStatement represents expressions used as statements
 type ExpressionStatement struct {
 	Token      token.Token
@@ -139,6 +179,77 @@ func (gs *GoStatement) String() string {
 	return out.String()
 }
 
+// SelectCase is a single arm of a select statement. Op is "receive",
+// "send", "timeout" or "default":
+//
+//	case v, ok = receive(ch) { ... }
+//	case send(ch, x) { ... }
+//	case timeout(500ms) { ... }
+//	default { ... }
+type SelectCase struct {
+	Token   token.Token // the 'case' or 'default' token
+	Op      string
+	Value   *Identifier // receive: optional name the received value is bound to
+	Ok      *Identifier // receive: optional name bound to false once the channel is closed
+	Channel Expression  // receive and send
+	Send    Expression  // send: the value to send
+	Timeout Expression  // timeout: how long to wait
+	Unit    string      // timeout: unit written after a number, e.g. "ms"
+	Block   *BlockStatement
+}
+
+func (sc *SelectCase) TokenLiteral() string { return sc.Token.Literal }
+func (sc *SelectCase) String() string {
+	var out bytes.Buffer
+
+	switch sc.Op {
+	case "default":
+		out.WriteString("default")
+	case "receive":
+		out.WriteString("case ")
+		if sc.Value != nil {
+			out.WriteString(sc.Value.String())
+			if sc.Ok != nil {
+				out.WriteString(", " + sc.Ok.String())
+			}
+			out.WriteString(" = ")
+		}
+		out.WriteString("receive(" + sc.Channel.String() + ")")
+	case "send":
+		out.WriteString("case send(" + sc.Channel.String() + ", " + sc.Send.String() + ")")
+	case "timeout":
+		out.WriteString("case timeout(" + sc.Timeout.String() + sc.Unit + ")")
+	}
+	out.WriteString(" { ")
+	out.WriteString(sc.Block.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// SelectStatement waits on several channel operations at once and runs the
+// block of the first one that can proceed.
+type SelectStatement struct {
+	Token token.Token // the 'select' token
+	Cases []*SelectCase
+}
+
+func (ss *SelectStatement) statementNode()       {}
+func (ss *SelectStatement) expressionNode()      {}
+func (ss *SelectStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *SelectStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("select { ")
+	for _, sc := range ss.Cases {
+		out.WriteString(sc.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
 // PackageBlock represents package block statements
 type PackageBlock struct {
 	Token      token.Token
@@ -203,3 +314,86 @@ func (ts *ThrowStatement) String() string {
 
 	return out.String()
 }
+
+// CatchClause represents a single catch arm of a try statement:
+// catch (e: NotFound) { ... } or catch (e) { ... }
+type CatchClause struct {
+	Token     token.Token // the 'catch' token
+	Param     *Identifier // optional name the caught value is bound to
+	ErrorType *Identifier // optional error type filter
+	Block     *BlockStatement
+}
+
+func (cc *CatchClause) TokenLiteral() string { return cc.Token.Literal }
+func (cc *CatchClause) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("catch")
+	if cc.Param != nil {
+		out.WriteString(" (")
+		out.WriteString(cc.Param.String())
+		if cc.ErrorType != nil {
+			out.WriteString(": ")
+			out.WriteString(cc.ErrorType.String())
+		}
+		out.WriteString(")")
+	}
+	out.WriteString(" { ")
+	out.WriteString(cc.Block.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// TryStatement represents try/catch/finally blocks like:
+// try { ... } catch (e: NotFound) { ... } catch (e) { ... } finally { ... }
+type TryStatement struct {
+	Token        token.Token // the 'try' token
+	Block        *BlockStatement
+	CatchClauses []*CatchClause
+	Finally      *BlockStatement
+}
+
+func (ts *TryStatement) statementNode()       {}
+func (ts *TryStatement) expressionNode()      {}
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try { ")
+	out.WriteString(ts.Block.String())
+	out.WriteString(" }")
+	for _, cc := range ts.CatchClauses {
+		out.WriteString(" ")
+		out.WriteString(cc.String())
+	}
+	if ts.Finally != nil {
+		out.WriteString(" finally { ")
+		out.WriteString(ts.Finally.String())
+		out.WriteString(" }")
+	}
+
+	return out.String()
+}
+
+// TestStatement represents a named test block like: test "adds numbers" { ... }
+// Test blocks only run under `vint test`; a normal run skips them.
+type TestStatement struct {
+	Token token.Token // the 'test' token
+	Name  string
+	Body  *BlockStatement
+}
+
+func (ts *TestStatement) statementNode()       {}
+func (ts *TestStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TestStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("test ")
+	out.WriteString(strconv.Quote(ts.Name))
+	out.WriteString(" { ")
+	out.WriteString(ts.Body.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
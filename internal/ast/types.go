@@ -142,6 +142,135 @@ func (mrt *MultiReturnType) String() string {
 	return "(" + strings.Join(parts, ", ") + ")"
 }
 
+// Interface type: a set of methods that a struct satisfies by having them,
+// declared with `interface Shape { area(): float; name(): string }`. The
+// parser resolves the interface's name to this node wherever it is used
+// as a type, the same way it resolves aliases.
+type InterfaceType struct {
+	Token   token.Token // the interface name in its declaration
+	Name    string
+	Methods []*InterfaceMethod // including those of embedded interfaces
+}
+
+func (it *InterfaceType) expressionNode()      {}
+func (it *InterfaceType) typeNode()            {}
+func (it *InterfaceType) TokenLiteral() string { return it.Token.Literal }
+func (it *InterfaceType) String() string       { return it.Name }
+
+// Method returns the method called name, or nil.
+func (it *InterfaceType) Method(name string) *InterfaceMethod {
+	for _, m := range it.Methods {
+		if m.Name.Value == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// InterfaceMethod is a method signature in an interface: area(): float
+type InterfaceMethod struct {
+	Name       *Identifier
+	Parameters []*TypedParameter
+	ReturnType Type // nil if any value may be returned
+}
+
+func (im *InterfaceMethod) String() string {
+	params := make([]string, len(im.Parameters))
+	for i, p := range im.Parameters {
+		params[i] = p.String()
+	}
+	result := im.Name.String() + "(" + strings.Join(params, ", ") + ")"
+	if im.ReturnType != nil {
+		result += ": " + im.ReturnType.String()
+	}
+	return result
+}
+
+// Interface statement: interface Shape { Named; area(): float }
+type InterfaceStatement struct {
+	Token   token.Token // the 'interface' token
+	Name    *Identifier
+	Embeds  []*Identifier // interfaces whose methods are included
+	Methods []*InterfaceMethod
+	Type    *InterfaceType // what the name stands for as a type
+}
+
+func (is *InterfaceStatement) statementNode()       {}
+func (is *InterfaceStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *InterfaceStatement) String() string {
+	members := []string{}
+	for _, e := range is.Embeds {
+		members = append(members, e.String())
+	}
+	for _, m := range is.Methods {
+		members = append(members, m.String())
+	}
+	return "interface " + is.Name.String() + " { " + strings.Join(members, "; ") + " }"
+}
+
+// TypeParameter is a type parameter of a generic function or struct: T in
+// func first<T>(xs: []T): T, or T: number with a constraint. Inside the
+// declaration the parser resolves T in annotations to this node.
+type TypeParameter struct {
+	Token      token.Token // the parameter's name
+	Name       string
+	Constraint Type // nil if any type is allowed
+}
+
+func (tp *TypeParameter) expressionNode()      {}
+func (tp *TypeParameter) typeNode()            {}
+func (tp *TypeParameter) TokenLiteral() string { return tp.Token.Literal }
+func (tp *TypeParameter) String() string       { return tp.Name }
+
+// TypeParametersString formats type parameters as in a declaration: <K, V: number>
+func TypeParametersString(params []*TypeParameter) string {
+	if len(params) == 0 {
+		return ""
+	}
+	parts := make([]string, len(params))
+	for i, tp := range params {
+		parts[i] = tp.Name
+		if tp.Constraint != nil {
+			parts[i] += ": " + tp.Constraint.String()
+		}
+	}
+	return "<" + strings.Join(parts, ", ") + ">"
+}
+
+// Generic type: a generic struct with type arguments, Box<int>
+type GenericType struct {
+	Token token.Token // the struct name
+	Name  string
+	Args  []Type
+}
+
+func (gt *GenericType) expressionNode()      {}
+func (gt *GenericType) typeNode()            {}
+func (gt *GenericType) TokenLiteral() string { return gt.Token.Literal }
+func (gt *GenericType) String() string       { return gt.Name + typeArgumentsString(gt.Args) }
+
+func typeArgumentsString(args []Type) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.String()
+	}
+	return "<" + strings.Join(parts, ", ") + ">"
+}
+
+// InstantiateExpression gives a generic function or struct its type
+// arguments explicitly, before it is called: first<int>(xs), Box<string>()
+type InstantiateExpression struct {
+	Token    token.Token // the '<' token
+	Function Expression
+	TypeArgs []Type
+}
+
+func (ie *InstantiateExpression) expressionNode()      {}
+func (ie *InstantiateExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *InstantiateExpression) String() string {
+	return ie.Function.String() + typeArgumentsString(ie.TypeArgs)
+}
+
 // Type alias statement: type UserID = int
 type TypeAliasStatement struct {
 	Token  token.Token // the 'type' token
@@ -181,12 +310,19 @@ type TypedParameter struct {
 	Identifier *Identifier
 	Type       Type
 	Default    Expression // optional default value
+	Variadic   bool       // ...name: collects the remaining arguments into an array of Type
 }
 
 func (tp *TypedParameter) expressionNode() {}
 func (tp *TypedParameter) TokenLiteral() string { return tp.Token.Literal }
 func (tp *TypedParameter) String() string {
-	result := tp.Identifier.String() + ": " + tp.Type.String()
+	result := tp.Identifier.String()
+	if tp.Variadic {
+		result = "..." + result
+	}
+	if tp.Type != nil {
+		result += ": " + tp.Type.String()
+	}
 	if tp.Default != nil {
 		result += " = " + tp.Default.String()
 	}
@@ -229,10 +365,12 @@ func (tls *TypedLetStatement) String() string {
 
 // Enhanced function literal with typed parameters and return type
 type TypedFunctionLiteral struct {
-	Token      token.Token // the 'func' token
-	Name       string      // optional function name
+	Token      token.Token      // the 'func' token
+	Name       string           // optional function name
+	TypeParams []*TypeParameter // <T, U: number> for a generic function
 	Parameters []*TypedParameter
 	ReturnType Type // optional return type annotation
+	Generator  bool // the body yields, so calling the function returns a generator
 	Body       *BlockStatement
 }
 
@@ -246,7 +384,7 @@ func (tfl *TypedFunctionLiteral) String() string {
 		}
 		params += p.String()
 	}
-	result := tfl.TokenLiteral() + "(" + params + ")"
+	result := tfl.TokenLiteral() + TypeParametersString(tfl.TypeParams) + "(" + params + ")"
 	if tfl.ReturnType != nil {
 		result += ": " + tfl.ReturnType.String()
 	}
@@ -296,6 +296,20 @@ func (da *DependencyAnalyzer) findModuleFile(name string) string {
 			}
 		}
 	}
+
+	// Packages fetched with `vint get` are directories with a file of the
+	// same name, whose other files it can import
+	for _, path := range da.searchPaths {
+		file := filepath.Join(path, basename, basename+".vint")
+		if da.fileExists(file) {
+			da.addSearchPath(filepath.Dir(file))
+			absFile, err := filepath.Abs(file)
+			if err == nil {
+				return absFile
+			}
+			return file
+		}
+	}
 	return ""
 }
 
@@ -0,0 +1,609 @@
+// Package checker implements `vint check`, which finds type errors in
+// annotated code before it runs. It infers the types of expressions and
+// checks them wherever the program declares a type:
+//
+//   - typed let statements and later assignments to them
+//   - arguments to functions and struct methods with typed parameters
+//   - return statements of functions with a return type
+//   - struct fields, in constructor calls and field assignments
+//   - builtins and module functions that declare parameter and return types
+//
+// Types are gradual: anything the checker cannot know is `any`, which is
+// compatible with every type, so code without annotations is left alone.
+package checker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/evaluator/builtins"
+	"github.com/vintlang/vintlang/internal/module"
+	"github.com/vintlang/vintlang/internal/token"
+	"github.com/vintlang/vintlang/internal/utils"
+)
+
+// Diagnostic is a type error at a position in a file.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// Check returns the type errors in program, in source order.
+func Check(program *ast.Program) []Diagnostic {
+	c := &checker{
+		scope:   newScope(nil),
+		structs: map[string]*structInfo{},
+		ifaces:  map[string]*typ{},
+		enums:   map[string]map[string]*typ{},
+	}
+	c.statements(program.Statements)
+	sort.SliceStable(c.diagnostics, func(i, j int) bool {
+		a, b := c.diagnostics[i], c.diagnostics[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return c.diagnostics
+}
+
+type checker struct {
+	scope       *scope
+	structs     map[string]*structInfo
+	ifaces      map[string]*typ
+	enums       map[string]map[string]*typ // member types by enum name
+	result      *typ                       // return type of the function being checked, nil if none
+	diagnostics []Diagnostic
+}
+
+type structInfo struct {
+	fields  []field
+	methods map[string]*typ
+	params  []*typ // type parameters, if generic
+}
+
+type field struct {
+	name       string
+	typ        *typ
+	hasDefault bool
+}
+
+func (s *structInfo) field(name string) (field, bool) {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+type variable struct {
+	typ      *typ
+	declared bool   // the type was annotated and is enforced on assignment
+	funcs    []*typ // overloads, for functions
+}
+
+type scope struct {
+	outer *scope
+	vars  map[string]*variable
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{outer: outer, vars: map[string]*variable{}}
+}
+
+func (s *scope) lookup(name string) *variable {
+	for ; s != nil; s = s.outer {
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *checker) errorf(tok token.Token, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		File:    tok.File,
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// define declares name in the current scope. Like the interpreter,
+// declaring a function again under the same name adds an overload.
+func (c *checker) define(name string, t *typ, declared bool) {
+	if existing, ok := c.scope.vars[name]; ok && t.kind == kindFunc && existing.typ.kind == kindFunc {
+		existing.funcs = append(existing.funcs, t)
+		return
+	}
+	v := &variable{typ: t, declared: declared}
+	if t.kind == kindFunc {
+		v.funcs = []*typ{t}
+	}
+	c.scope.vars[name] = v
+}
+
+func (c *checker) push() { c.scope = newScope(c.scope) }
+func (c *checker) pop()  { c.scope = c.scope.outer }
+
+// lookupType resolves a type name declared in the program.
+func (c *checker) lookupType(name string) *typ {
+	if _, ok := c.structs[name]; ok {
+		return c.structType(name)
+	}
+	if iface, ok := c.ifaces[name]; ok {
+		return iface
+	}
+	if _, ok := c.enums[name]; ok {
+		return &typ{kind: kindEnum, name: name}
+	}
+	return nil
+}
+
+// structType returns the type of instances of the struct called name.
+func (c *checker) structType(name string) *typ {
+	t := &typ{kind: kindStruct, name: name}
+	if info := c.structs[name]; info != nil {
+		t.methods = info.methods
+	}
+	return t
+}
+
+func (c *checker) typeOf(t ast.Type) *typ {
+	return fromAST(t, c.lookupType)
+}
+
+// expect checks that expr can be used where a want is declared, reporting
+// mismatch(got) otherwise. Array and dict literals are checked element by
+// element so that the error points at the element.
+func (c *checker) expect(expr ast.Expression, want *typ, mismatch func(got *typ) string) {
+	switch lit := expr.(type) {
+	case *ast.ArrayLiteral:
+		if want.kind == kindArray {
+			for _, el := range lit.Elements {
+				c.expect(el, want.elem, func(got *typ) string {
+					return fmt.Sprintf("cannot use %s as an element of %s", got, want)
+				})
+			}
+			return
+		}
+	case *ast.DictLiteral:
+		if want.kind == kindDict {
+			for _, key := range lit.Keys {
+				c.expect(key, want.key, func(got *typ) string {
+					return fmt.Sprintf("cannot use %s as a key of %s", got, want)
+				})
+				c.expect(lit.Pairs[key], want.elem, func(got *typ) string {
+					return fmt.Sprintf("cannot use %s as a value of %s", got, want)
+				})
+			}
+			return
+		}
+	}
+	if got := c.expr(expr); !assignable(got, want) {
+		c.errorf(start(expr), "%s", mismatch(got))
+	}
+}
+
+func (c *checker) statements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		c.statement(stmt)
+	}
+}
+
+func (c *checker) block(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	c.push()
+	c.statements(block.Statements)
+	c.pop()
+}
+
+func (c *checker) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		c.define(s.Name.Value, c.expr(s.Value), false)
+	case *ast.TypedLetStatement:
+		want := c.typeOf(s.TypeAnnotation.Type)
+		if s.Value != nil {
+			c.expect(s.Value, want, func(got *typ) string {
+				return fmt.Sprintf("cannot assign %s to variable '%s' of type %s", got, s.Name.Value, want)
+			})
+		}
+		c.define(s.Name.Value, want, true)
+	case *ast.ConstStatement:
+		c.define(s.Name.Value, c.expr(s.Value), false)
+	case *ast.DestructureStatement:
+		c.pattern(s.Pattern, c.expr(s.Value))
+	case *ast.ReturnStatement:
+		if s.ReturnValue == nil {
+			return
+		}
+		if want := c.result; want != nil {
+			c.expect(s.ReturnValue, want, func(got *typ) string {
+				return fmt.Sprintf("function returns %s, but body returned %s", want, got)
+			})
+		} else {
+			c.expr(s.ReturnValue)
+		}
+	case *ast.YieldStatement:
+		c.expr(s.Value)
+	case *ast.ExpressionStatement:
+		if s.Expression == nil {
+			return
+		}
+		t := c.expr(s.Expression)
+		// func name() {} at statement level declares name.
+		switch fn := s.Expression.(type) {
+		case *ast.FunctionLiteral:
+			if fn.Name != "" {
+				c.define(fn.Name, t, false)
+			}
+		case *ast.TypedFunctionLiteral:
+			if fn.Name != "" {
+				c.define(fn.Name, t, false)
+			}
+		}
+	case *ast.BlockStatement:
+		c.block(s)
+	case *ast.StructStatement:
+		c.structStatement(s)
+	case *ast.InterfaceStatement:
+		c.interfaceStatement(s)
+	case *ast.EnumStatement:
+		members := map[string]*typ{}
+		for name, value := range s.Values {
+			members[name] = c.expr(value)
+		}
+		c.enums[s.Name.Value] = members
+		c.define(s.Name.Value, &typ{kind: kindEnum, name: s.Name.Value}, false)
+	case *ast.ErrorDeclaration:
+		ctor := &typ{kind: kindFunc, result: errorType, label: s.Name.Value}
+		for _, p := range s.Parameters {
+			ctor.params = append(ctor.params, anyType)
+			ctor.paramNames = append(ctor.paramNames, p.Value)
+		}
+		ctor.required = len(ctor.params)
+		c.define(s.Name.Value, ctor, false)
+	case *ast.PackageBlock:
+		c.push()
+		c.statements(s.Statements)
+		c.pop()
+	case *ast.TestStatement:
+		c.block(s.Body)
+	case *ast.GoStatement:
+		c.expr(s.Expression)
+	case *ast.DeferStatement:
+		c.expr(s.Call)
+	case *ast.ThrowStatement:
+		c.expr(s.ErrorExpr)
+	case *ast.TryStatement:
+		c.block(s.Block)
+		for _, clause := range s.CatchClauses {
+			c.push()
+			if clause.Param != nil {
+				c.define(clause.Param.Value, anyType, false)
+			}
+			c.block(clause.Block)
+			c.pop()
+		}
+		c.block(s.Finally)
+	case *ast.SelectStatement:
+		for _, sc := range s.Cases {
+			c.expr(sc.Channel)
+			c.expr(sc.Send)
+			c.expr(sc.Timeout)
+			c.push()
+			if sc.Value != nil {
+				c.define(sc.Value.Value, anyType, false)
+			}
+			if sc.Ok != nil {
+				c.define(sc.Ok.Value, boolType, false)
+			}
+			c.block(sc.Block)
+			c.pop()
+		}
+	case *ast.TodoStatement:
+		c.expr(s.Value)
+	case *ast.WarnStatement:
+		c.expr(s.Value)
+	case *ast.ErrorStatement:
+		c.expr(s.Value)
+	case *ast.InfoStatement:
+		c.expr(s.Value)
+	case *ast.DebugStatement:
+		c.expr(s.Value)
+	case *ast.NoteStatement:
+		c.expr(s.Value)
+	case *ast.SuccessStatement:
+		c.expr(s.Value)
+	case *ast.TraceStatement:
+		c.expr(s.Value)
+	case *ast.FatalStatement:
+		c.expr(s.Value)
+	case *ast.CriticalStatement:
+		c.expr(s.Value)
+	case *ast.LogStatement:
+		c.expr(s.Value)
+	}
+}
+
+func (c *checker) structStatement(s *ast.StructStatement) {
+	name := s.Name.Value
+	info := &structInfo{methods: map[string]*typ{}}
+	// Register the struct first so that fields and methods can refer to it.
+	c.structs[name] = info
+	c.define(name, &typ{kind: kindStructDef, name: name}, false)
+	for _, tp := range s.TypeParams {
+		info.params = append(info.params, c.typeOf(tp))
+	}
+
+	// Names the struct declares itself, which override promoted ones
+	own := map[string]bool{}
+	for _, f := range s.Fields {
+		if c.embedded(f, name) == nil {
+			own[f.Name.Value] = true
+		}
+	}
+	for _, m := range s.Methods {
+		own[m.Name.Value] = true
+	}
+
+	promotedFrom := map[string]string{}
+	for _, f := range s.Fields {
+		if embedded := c.embedded(f, name); embedded != nil {
+			// Promote the fields and methods of the embedded struct
+			from := f.Name.Value
+			for _, ef := range embedded.fields {
+				if own[ef.name] {
+					continue
+				}
+				if other, ok := promotedFrom[ef.name]; ok {
+					c.errorf(f.Name.Token, "struct '%s' gets field '%s' from both '%s' and '%s'", name, ef.name, other, from)
+					continue
+				}
+				promotedFrom[ef.name] = from
+				info.fields = append(info.fields, ef)
+			}
+			for _, mname := range utils.SortedKeys(embedded.methods) {
+				fn := embedded.methods[mname]
+				if own[mname] {
+					continue
+				}
+				if other, ok := promotedFrom[mname+"()"]; ok {
+					c.errorf(f.Name.Token, "struct '%s' gets method '%s' from both '%s' and '%s'", name, mname, other, from)
+					continue
+				}
+				promotedFrom[mname+"()"] = from
+				info.methods[mname] = fn
+			}
+			continue
+		}
+		fl := field{name: f.Name.Value, typ: anyType, hasDefault: f.Default != nil}
+		if f.Type != nil {
+			fl.typ = c.typeOf(f.Type)
+		}
+		if f.Default != nil {
+			want := fl.typ
+			c.expect(f.Default, want, func(got *typ) string {
+				return fmt.Sprintf("default value of field '%s' in struct '%s' expects %s, got %s", fl.name, name, want, got)
+			})
+		}
+		info.fields = append(info.fields, fl)
+	}
+
+	for _, m := range s.Methods {
+		fn := &typ{kind: kindFunc, result: anyType, label: m.Name.Value}
+		for i, p := range m.Parameters {
+			pt := anyType
+			if i < len(m.ParamTypes) && m.ParamTypes[i] != nil {
+				pt = c.typeOf(m.ParamTypes[i])
+			}
+			fn.params = append(fn.params, pt)
+			fn.paramNames = append(fn.paramNames, p.Value)
+			if _, ok := m.Defaults[p.Value]; !ok && !(m.Variadic && i == len(m.Parameters)-1) {
+				fn.required = i + 1
+			}
+		}
+		fn.variadic = m.Variadic
+		if fn.params == nil {
+			fn.params = []*typ{}
+		}
+		if m.ReturnType != nil {
+			fn.result = c.typeOf(m.ReturnType)
+		}
+		info.methods[m.Name.Value] = fn
+	}
+
+	// Check the bodies once every method is known, so they can call each
+	// other through this.
+	for _, m := range s.Methods {
+		fn := info.methods[m.Name.Value]
+		this := c.structType(name)
+		this.args = info.params
+		c.push()
+		c.define("this", this, false)
+		c.functionBody(fn, m.ReturnType != nil, m.Body)
+		c.pop()
+	}
+}
+
+// embedded returns the struct a field of the struct called name embeds,
+// or nil if it is an ordinary field. A field embeds a struct when it is
+// just the name of a struct declared before, with no type or default.
+func (c *checker) embedded(f ast.StructField, name string) *structInfo {
+	if f.Type != nil || f.Default != nil || f.Name.Value == name {
+		return nil
+	}
+	return c.structs[f.Name.Value]
+}
+
+func (c *checker) interfaceStatement(s *ast.InterfaceStatement) {
+	// Register the interface first so that its methods can refer to it.
+	iface := &typ{kind: kindInterface, name: s.Name.Value, methods: map[string]*typ{}}
+	c.ifaces[iface.name] = iface
+	c.define(iface.name, anyType, false)
+
+	for _, m := range s.Type.Methods {
+		fn := &typ{kind: kindFunc, params: []*typ{}, result: anyType, label: m.Name.Value}
+		for _, p := range m.Parameters {
+			pt := anyType
+			if p.Type != nil {
+				pt = c.typeOf(p.Type)
+			}
+			fn.params = append(fn.params, pt)
+			fn.paramNames = append(fn.paramNames, p.Identifier.Value)
+			fn.variadic = p.Variadic
+		}
+		fn.required = len(fn.params)
+		if fn.variadic {
+			fn.required--
+		}
+		if m.ReturnType != nil {
+			fn.result = c.typeOf(m.ReturnType)
+		}
+		iface.methods[m.Name.Value] = fn
+	}
+}
+
+// functionBody checks the body of a function of type fn, with its
+// parameters in scope.
+func (c *checker) functionBody(fn *typ, hasResult bool, body *ast.BlockStatement) {
+	outer := c.result
+	c.result = nil
+	if hasResult {
+		c.result = fn.result
+	}
+	c.push()
+	for i, name := range fn.paramNames {
+		if fn.variadic && i == len(fn.paramNames)-1 {
+			// The variadic parameter is an array of the remaining arguments
+			c.define(name, arrayOf(fn.params[i]), false)
+			continue
+		}
+		c.define(name, fn.params[i], false)
+	}
+	if body != nil {
+		c.statements(body.Statements)
+	}
+	c.pop()
+	c.result = outer
+}
+
+// start returns the token an expression starts at, for positions.
+func start(expr ast.Expression) token.Token {
+	switch e := expr.(type) {
+	case *ast.InfixExpression:
+		return start(e.Left)
+	case *ast.CallExpression:
+		return start(e.Function)
+	case *ast.IndexExpression:
+		return start(e.Left)
+	case *ast.SliceExpression:
+		return start(e.Left)
+	case *ast.MethodExpression:
+		return start(e.Object)
+	case *ast.PropertyExpression:
+		return start(e.Object)
+	case *ast.TypeCastExpression:
+		return start(e.Expression)
+	case *ast.TypeCheckExpression:
+		return start(e.Expression)
+	case *ast.RangeExpression:
+		return start(e.Start)
+	case *ast.PostfixExpression:
+		return e.Token
+	case *ast.Assign:
+		return e.Name.Token
+	case *ast.Identifier:
+		return e.Token
+	}
+	return tokenOf(expr)
+}
+
+// tokenOf returns the Token field that every AST node has.
+func tokenOf(node ast.Node) token.Token {
+	switch n := node.(type) {
+	case *ast.IntegerLiteral:
+		return n.Token
+	case *ast.BigIntLiteral:
+		return n.Token
+	case *ast.FloatLiteral:
+		return n.Token
+	case *ast.StringLiteral:
+		return n.Token
+	case *ast.InterpolatedString:
+		return n.Token
+	case *ast.Interpolation:
+		return n.Token
+	case *ast.Boolean:
+		return n.Token
+	case *ast.Null:
+		return n.Token
+	case *ast.ArrayLiteral:
+		return n.Token
+	case *ast.DictLiteral:
+		return n.Token
+	case *ast.FunctionLiteral:
+		return n.Token
+	case *ast.TypedFunctionLiteral:
+		return n.Token
+	case *ast.PrefixExpression:
+		return n.Token
+	case *ast.BuiltinExpression:
+		return n.Token
+	case *ast.IfExpression:
+		return n.Token
+	case *ast.AwaitExpression:
+		return n.Token
+	case *ast.ChannelExpression:
+		return n.Token
+	case *ast.At:
+		return n.Token
+	}
+	return token.Token{}
+}
+
+// builtinType returns the type of the builtin called name, or nil if
+// there is none.
+func builtinType(name string) *typ {
+	b, ok := builtins.GetBuiltin(name)
+	if !ok {
+		return nil
+	}
+	fn := &typ{kind: kindFunc, result: anyType, label: name}
+	if b.ParamTypes != nil {
+		for _, p := range b.ParamTypes {
+			fn.params = append(fn.params, fromAST(p, noTypes))
+		}
+	}
+	if b.ReturnType != nil {
+		fn.result = fromAST(b.ReturnType, noTypes)
+	}
+	return fn
+}
+
+// moduleFunction returns the type of a function in a builtin module, or
+// nil if the module has no such function.
+func moduleFunction(modName, name string) *typ {
+	mod := module.Mapper[modName]
+	if _, ok := mod.Functions[name]; !ok {
+		return nil
+	}
+	fn := &typ{kind: kindFunc, result: anyType, label: modName + "." + name + "()"}
+	for _, p := range mod.FuncTypes[name] {
+		fn.params = append(fn.params, fromAST(p, noTypes))
+	}
+	if ret := mod.FuncReturns[name]; ret != nil {
+		fn.result = fromAST(ret, noTypes)
+	}
+	return fn
+}
+
+func noTypes(string) *typ { return nil }
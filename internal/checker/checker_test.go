@@ -0,0 +1,267 @@
+package checker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+)
+
+func check(t *testing.T, src string) []string {
+	t.Helper()
+	p := parser.New(lexer.NewWithFilename(src, "test.vint"))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("parse errors: %v", errs)
+	}
+	var got []string
+	for _, d := range Check(program) {
+		got = append(got, d.String())
+	}
+	return got
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			"typed let",
+			"let a: int = 1\nlet b: float64 = 100\nlet c: string = \"x\" + \"y\"\nlet d: []int = [1, \"two\"]\nlet e: {string: int} = {\"a\": true}\n",
+			[]string{
+				"test.vint:2:18: cannot assign int to variable 'b' of type float64",
+				"test.vint:4:20: cannot use string as an element of []int",
+				"test.vint:5:30: cannot use bool as a value of {string: int}",
+			},
+		},
+		{
+			"assignments",
+			"let n: int = 1\nn = \"one\"\nn += \"x\"\nlet free = 1\nfree = \"ok\"\n",
+			[]string{
+				"test.vint:2:5: cannot assign string to variable 'n' of type int",
+				"test.vint:3:3: cannot use '+=' operator between int and string",
+			},
+		},
+		{
+			"functions",
+			"let add = func(a: int, b: int): int { return a + b }\nadd(1, \"2\")\nadd(b = true, a = 1)\nlet s: string = add(1, 2)\nlet bad = func(): []int { return 5 }\n",
+			[]string{
+				"test.vint:2:8: parameter 'b' expects int, got string",
+				"test.vint:3:9: parameter 'b' expects int, got bool",
+				"test.vint:4:17: cannot assign int to variable 's' of type string",
+				"test.vint:5:34: function returns []int, but body returned int",
+			},
+		},
+		{
+			"function values",
+			"let apply = func(x: int, f: func(int) int): int { return f(x) }\napply(1, func(n: int): int { return n })\napply(1, func(s: string): int { return 0 })\n",
+			[]string{
+				"test.vint:3:10: parameter 'f' expects func(int) int, got func(string) int",
+			},
+		},
+		{
+			"overloads",
+			"func area(r: float64): float64 { return r * r }\nfunc area(w: int, h: int): int { return w * h }\nlet a: int = area(2, 3)\nlet b: float64 = area(2.0)\narea(1, \"x\")\n",
+			[]string{
+				"test.vint:5:9: parameter 'h' expects int, got string",
+			},
+		},
+		{
+			"structs",
+			"struct Point {\n    x: float64,\n    y: float64 = 0.0,\n    func scale(k: float64): Point { return Point(x = this.x * k, y = this.y * k) }\n}\nlet p = Point(x = 1)\nlet q = Point(z = 1.0)\nlet r = Point(2.0)\nr.x = \"a\"\nr.scale(\"b\")\nr.move()\nlet n: int = r.y\n",
+			[]string{
+				"test.vint:6:19: field 'x' in struct 'Point' expects float64, got int",
+				"test.vint:7:9: missing value for field 'x' in struct 'Point'",
+				"test.vint:7:15: struct 'Point' has no field 'z'",
+				"test.vint:9:7: field 'x' in struct 'Point' expects float64, got string",
+				"test.vint:10:9: parameter 'k' in method 'scale' expects float64, got string",
+				"test.vint:11:3: struct 'Point' has no method 'move'",
+				"test.vint:12:14: cannot assign float64 to variable 'n' of type int",
+			},
+		},
+		{
+			"interfaces and embedding",
+			"interface Shape { area(): float; name(): string }\nstruct Circle { r: float; func area(): float { return this.r }; func name(): string { return \"c\" } }\nstruct Sq { s: float; func area(): int { return 1 } }\nlet a: Shape = Circle(1.0)\nlet b: Shape = Sq(1.0)\na.perimeter()\nlet n: int = a.area()\nstruct User { name: string; func hello(): string { return this.name } }\nstruct Admin { User; level: int }\nlet h: int = Admin(name = \"x\", level = 1).hello()\nAdmin(name = 1, level = 1)\nlet m = match a {\n    c: Circle => c.nope()\n    _ => 0\n}\n",
+			[]string{
+				"test.vint:5:16: cannot assign Sq to variable 'b' of type Shape",
+				"test.vint:6:3: interface 'Shape' has no method 'perimeter'",
+				"test.vint:7:14: cannot assign float64 to variable 'n' of type int",
+				"test.vint:10:14: cannot assign string to variable 'h' of type int",
+				"test.vint:11:14: field 'name' in struct 'Admin' expects string, got int",
+				"test.vint:13:20: struct 'Circle' has no method 'nope'",
+			},
+		},
+		{
+			"pointers",
+			"let n: int = 1\nlet p = &n\n*p = \"one\"\nlet free = 1\nlet q = &free\n*q = \"ok\"\nlet r: *float = null\n*r = 2\nlet s: *string = &n\n*n = 1\n",
+			[]string{
+				"test.vint:3:6: cannot assign string through pointer to int",
+				"test.vint:8:6: cannot assign int through pointer to float64",
+				"test.vint:9:18: cannot assign *int to variable 's' of type *string",
+				"test.vint:10:1: cannot dereference int, which is not a pointer",
+			},
+		},
+		{
+			"generics",
+			"let first = func<T>(xs: [T]): T { return xs[0] }\nlet pair = func<T>(a: T, b: T): [T] { return [a, b] }\nlet sum = func<T: number>(a: T, b: T): T { return a }\nlet n: string = first([1, 2])\npair(1, \"x\")\nsum(\"a\", \"b\")\nlet bad = func<T>(x: T): T { return 1 }\nsum<string>\nstruct Box<T> { value: T; func set(v: T) { this.value = v } }\nlet b = Box(value = 1)\nb.set(\"x\")\nlet c: Box<string> = b\nlet s: string = b.value\nBox<string>(value = 2)\n",
+			[]string{
+				"test.vint:4:17: cannot assign int to variable 'n' of type string",
+				"test.vint:5:9: parameter 'b' expects int, got string",
+				"test.vint:6:5: parameter 'a' expects number, got string",
+				"test.vint:6:10: parameter 'b' expects number, got string",
+				"test.vint:7:37: function returns T, but body returned int",
+				"test.vint:8:1: type argument string for 'T' does not satisfy number",
+				"test.vint:11:7: parameter 'v' in method 'set' expects int, got string",
+				"test.vint:12:22: cannot assign Box<int> to variable 'c' of type Box<string>",
+				"test.vint:13:17: cannot assign int to variable 's' of type string",
+				"test.vint:14:21: field 'value' in struct 'Box' expects string, got int",
+			},
+		},
+		{
+			"optional chaining",
+			"struct User { name: string }\nlet u = User(name = \"Ann\")\nu?.nmae\nlet n = null\nn?.[0]\nn?.[1:]\nlet m: int = u?.name\nlet k: int = u.name\nlet d: {string: int} = {\"a\": 1}\nlet s: string = d.a\n",
+			[]string{
+				"test.vint:3:4: struct 'User' has no field 'nmae'",
+				"test.vint:8:14: cannot assign string to variable 'k' of type int",
+				"test.vint:10:17: cannot assign int to variable 's' of type string",
+			},
+		},
+		{
+			"generators",
+			"let g = func(n: int) { yield n + \"x\" }\nlet c: chan string = chan(1)\nfor i, v in c { let n: int = v }\n",
+			[]string{
+				"test.vint:1:32: cannot use '+' operator between int and string",
+				"test.vint:3:30: cannot assign string to variable 'n' of type int",
+			},
+		},
+		{
+			"big numbers",
+			"let a: bigint = 5n * 2\nlet b: int = 5n\nlet d: decimal = decimal(\"1.5\") + 2\nlet e: float = d\nlet f = 5n & 1.5\n",
+			[]string{
+				"test.vint:4:16: cannot assign decimal to variable 'e' of type float64",
+				"test.vint:5:12: cannot use '&' operator between bigint and float64",
+			},
+		},
+		{
+			"enums",
+			"enum Status { Active = 1, Banned = 2 }\nlet s: Status = Status.Active\nlet i: int = Status.Banned\nStatus.Deleted\n",
+			[]string{
+				"test.vint:2:17: cannot assign int to variable 's' of type Status",
+				"test.vint:4:8: enum 'Status' has no member 'Deleted'",
+			},
+		},
+		{
+			"modules",
+			"import string\nlet t: int = string.trim(\"  a \")\nstring.toUpper(5)\nstring.nope(\"x\")\nlet r: string = string.replace(\"a\", \"b\", \"c\")\n",
+			[]string{
+				"test.vint:2:14: cannot assign string to variable 't' of type int",
+				"test.vint:3:16: argument 1 to string.toUpper() expects string, got int",
+				"test.vint:4:8: module 'string' has no function 'nope'",
+			},
+		},
+		{
+			"operators",
+			"let a = 1 + \"x\"\nlet b = \"x\" - \"y\"\nlet c = -\"x\"\nlet d = \"ab\" * 2\nlet e = [1] + [2]\nlet f = 1.5 % 2.0\nlet g = \"abc\"[0]\nlet h = 1 + unknown\n",
+			[]string{
+				"test.vint:1:11: cannot use '+' operator between int and string",
+				"test.vint:2:13: cannot use '-' operator between string and string",
+				"test.vint:3:9: cannot use '-' operator on string",
+				"test.vint:6:13: cannot use '%' operator between float64 and float64",
+				"test.vint:7:14: cannot index string",
+			},
+		},
+		{
+			"bitwise operators",
+			"let a: int = 0xFF & ~0b1010 | 1 << 4\nlet s: string = 1 | 2\nlet b = 1.5 & 1\nlet c = ~\"x\"\nlet n = 0\nn |= 1.5\n",
+			[]string{
+				"test.vint:2:17: cannot assign int to variable 's' of type string",
+				"test.vint:3:13: cannot use '&' operator between float64 and int",
+				"test.vint:4:9: cannot use '~' operator on string",
+				"test.vint:6:3: cannot use '|=' operator between int and float64",
+			},
+		},
+		{
+			"interpolated strings",
+			"let n: int = \"${1}\"\nlet s: string = `a ${1 + \"x\"}`\nlet t: string = \"${n:.2f}\"\n",
+			[]string{
+				"test.vint:1:14: cannot assign string to variable 'n' of type int",
+				"test.vint:2:24: cannot use '+' operator between int and string",
+			},
+		},
+		{
+			"inference through loops and scopes",
+			"let xs: []string = [\"a\", \"b\"]\nfor i, x in xs {\n    let n: int = i\n    let m: int = x\n}\nif (true) {\n    let y: int = 1\n}\nlet y: string = \"shadow is fine\"\n",
+			[]string{
+				"test.vint:4:18: cannot assign string to variable 'm' of type int",
+			},
+		},
+		{
+			"destructuring",
+			"let xs: []int = [1, 2]\nlet [a, ...rest] = xs\nlet s: string = a\nlet r: []string = rest\nlet [c] = 5\nlet {k} = \"text\"\n",
+			[]string{
+				"test.vint:3:17: cannot assign int to variable 's' of type string",
+				"test.vint:4:19: cannot assign []int to variable 'r' of type []string",
+				"test.vint:5:5: cannot destructure int as an array",
+				"test.vint:6:5: cannot destructure string as a dict",
+			},
+		},
+		{
+			"variadics and spreads",
+			"let sum = func(...xs: int): int { let s: string = xs\n return 0 }\nsum(1, 2, \"3\")\nsum(...[1], \"x\")\nlet f = func(a) { return 1 }\nlet f = func(a, ...rest: string) { return 2 }\nf(1, 2)\nlet b = [...5]\nlet d = {...\"x\"}\n",
+			[]string{
+				"test.vint:1:51: cannot assign []int to variable 's' of type string",
+				"test.vint:3:11: parameter 'xs' expects int, got string",
+				"test.vint:7:6: parameter 'rest' expects string, got int",
+				"test.vint:8:10: cannot spread int",
+				"test.vint:9:10: cannot spread string into a dict",
+			},
+		},
+		{
+			"untyped code is left alone",
+			"let x = 1\nx = \"now a string\"\nlet f = func(a, b) { return a + b }\nf(1, 2)\nf(\"a\", \"b\")\nlet d = {}\nd[\"k\"] = [1, \"mixed\"]\nprint(x + 1)\n",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, tt.input)
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.vint")
+	bad := filepath.Join(dir, "bad.vint")
+	os.WriteFile(good, []byte("let a: int = 1\n"), 0o644)
+	os.WriteFile(bad, []byte("let a: int = 1\nlet b: string = a\n"), 0o644)
+
+	var out bytes.Buffer
+	if code := Main([]string{good}, &out); code != 0 {
+		t.Errorf("exit code %d for a correct file, want 0:\n%s", code, out.String())
+	}
+
+	out.Reset()
+	if code := Main([]string{dir}, &out); code != 1 {
+		t.Errorf("exit code %d for a directory with a type error, want 1", code)
+	}
+	for _, want := range []string{bad + ":2:17: cannot assign int to variable 'b' of type string", "let b: string = a", "^"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	if code := Main([]string{"--strict"}, &bytes.Buffer{}); code != 2 {
+		t.Errorf("exit code %d for an unknown flag, want 2", code)
+	}
+}
@@ -0,0 +1,876 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/module"
+)
+
+// expr checks expr and returns its type.
+func (c *checker) expr(expr ast.Expression) *typ {
+	switch e := expr.(type) {
+	case nil:
+		return anyType
+	case *ast.IntegerLiteral:
+		return intType
+	case *ast.BigIntLiteral:
+		return bigIntType
+	case *ast.FloatLiteral:
+		return floatType
+	case *ast.StringLiteral:
+		return stringType
+	case *ast.InterpolatedString:
+		// Any value can be interpolated; only the expressions need checking
+		for _, part := range e.Parts {
+			if in, ok := part.(*ast.Interpolation); ok {
+				c.expr(in.Value)
+			}
+		}
+		return stringType
+	case *ast.Boolean:
+		return boolType
+	case *ast.Null:
+		return nilType
+
+	case *ast.Identifier:
+		if v := c.scope.lookup(e.Value); v != nil {
+			return v.typ
+		}
+		if fn := builtinType(e.Value); fn != nil {
+			return fn
+		}
+		return anyType
+	case *ast.BuiltinExpression:
+		if fn := builtinType(e.Name); fn != nil {
+			return fn
+		}
+		return anyType
+	case *ast.InstantiateExpression:
+		return c.instantiate(e)
+
+	case *ast.ArrayLiteral:
+		// The elements of an array can change type later, so only a
+		// declared element type is trusted.
+		for _, el := range e.Elements {
+			c.expr(el)
+		}
+		return arrayOf(anyType)
+	case *ast.DictLiteral:
+		for _, key := range e.Keys {
+			if spread, ok := key.(*ast.SpreadExpression); ok {
+				if t := c.expr(spread.Value); t.isConcrete() && t.kind != kindDict {
+					c.errorf(spread.Token, "cannot spread %s into a dict", t)
+				}
+				continue
+			}
+			c.expr(key)
+			c.expr(e.Pairs[key])
+		}
+		return dictOf(anyType, anyType)
+	case *ast.SpreadExpression:
+		// ...value in a call or an array literal
+		if t := c.expr(e.Value); t.isConcrete() && t.kind != kindArray {
+			c.errorf(e.Token, "cannot spread %s", t)
+		}
+		return anyType
+
+	case *ast.FunctionLiteral:
+		fn := &typ{kind: kindFunc, params: []*typ{}, variadic: e.Variadic, result: anyType}
+		for i, p := range e.Parameters {
+			fn.params = append(fn.params, anyType)
+			fn.paramNames = append(fn.paramNames, p.Value)
+			if _, ok := e.Defaults[p.Value]; !ok && !(e.Variadic && i == len(e.Parameters)-1) {
+				fn.required = i + 1
+			}
+		}
+		c.functionBody(fn, false, e.Body)
+		return fn
+	case *ast.TypedFunctionLiteral:
+		return c.typedFunction(e)
+	case *ast.AsyncFunctionLiteral:
+		fn := &typ{kind: kindFunc, variadic: e.Variadic, result: anyType}
+		for _, p := range e.Parameters {
+			fn.paramNames = append(fn.paramNames, p.Value)
+			fn.params = append(fn.params, anyType)
+		}
+		c.functionBody(fn, false, e.Body)
+		fn.params = nil // calling it returns a promise, whatever the arguments
+		return fn
+
+	case *ast.PrefixExpression:
+		return c.prefix(e)
+	case *ast.InfixExpression:
+		return c.infix(e)
+	case *ast.PostfixExpression:
+		if v := c.scope.lookup(e.Token.Literal); v != nil && v.typ.isNumeric() {
+			return v.typ
+		}
+		return anyType
+
+	case *ast.CallExpression:
+		return optional(e.Optional, c.call(e))
+	case *ast.MethodExpression:
+		return optional(e.Optional, c.method(e))
+	case *ast.PropertyExpression:
+		return optional(e.Optional, c.property(e))
+
+	case *ast.IndexExpression:
+		left := c.expr(e.Left)
+		index := c.expr(e.Index)
+		switch {
+		case e.Optional && left.kind == kindNil:
+		case left.kind == kindArray:
+			if index.isConcrete() && index.kind != kindInt && index.kind != kindNumber {
+				c.errorf(e.Token, "cannot index %s with %s", left, index)
+			}
+			return optional(e.Optional, left.elem)
+		case left.kind == kindDict:
+			return optional(e.Optional, left.elem)
+		case left.isConcrete():
+			c.errorf(e.Token, "cannot index %s", left)
+		}
+		return anyType
+	case *ast.SliceExpression:
+		left := c.expr(e.Left)
+		c.expr(e.Start)
+		c.expr(e.End)
+		if left.kind == kindArray {
+			return optional(e.Optional, left)
+		}
+		if left.isConcrete() && !(e.Optional && left.kind == kindNil) {
+			c.errorf(e.Token, "cannot slice %s", left)
+		}
+		return anyType
+
+	case *ast.Assign:
+		return c.assign(e)
+	case *ast.AssignEqual:
+		return c.assignEqual(e)
+	case *ast.AssignmentExpression:
+		return c.assignIndex(e)
+	case *ast.PropertyAssignment:
+		return c.assignProperty(e)
+
+	case *ast.IfExpression:
+		c.expr(e.Condition)
+		c.block(e.Consequence)
+		c.block(e.Alternative)
+		return anyType
+	case *ast.WhileExpression:
+		c.expr(e.Condition)
+		c.block(e.Consequence)
+		return anyType
+	case *ast.ForIn:
+		c.forIn(e)
+		return anyType
+	case *ast.RepeatStatement:
+		c.expr(e.Count)
+		c.push()
+		c.define(e.VarName, intType, false)
+		c.block(e.Block)
+		c.pop()
+		return anyType
+	case *ast.SwitchExpression:
+		c.expr(e.Value)
+		for _, choice := range e.Choices {
+			for _, ex := range choice.Expr {
+				c.expr(ex)
+			}
+			c.push()
+			if choice.Variable != nil {
+				c.define(choice.Variable.Value, anyType, false)
+			}
+			c.expr(choice.Guard)
+			c.block(choice.Block)
+			c.pop()
+		}
+		return anyType
+	case *ast.MatchExpression:
+		c.expr(e.Value)
+		for _, mc := range e.Cases {
+			// Patterns bind names rather than being evaluated.
+			c.push()
+			for _, v := range mc.Variables {
+				c.define(v.Value, anyType, false)
+			}
+			if tp, ok := mc.Pattern.(*ast.TypePattern); ok && tp.Name.Value != "_" {
+				c.define(tp.Name.Value, c.typeOf(tp.Type), false)
+			}
+			c.expr(mc.Guard)
+			c.block(mc.Block)
+			c.pop()
+		}
+		return anyType
+
+	case *ast.TypeCastExpression:
+		c.expr(e.Expression)
+		return c.typeOf(e.TargetType)
+	case *ast.TypeCheckExpression:
+		c.expr(e.Expression)
+		return boolType
+	case *ast.RangeExpression:
+		c.expr(e.Start)
+		c.expr(e.End)
+		return anyType
+	case *ast.AwaitExpression:
+		c.expr(e.Value)
+		return anyType
+	case *ast.ChannelExpression:
+		c.expr(e.Buffer)
+		return &typ{kind: kindChan, elem: anyType}
+
+	case *ast.Import:
+		for name := range e.Identifiers {
+			if _, ok := module.Mapper[name]; ok {
+				c.define(name, &typ{kind: kindModule, name: name}, false)
+			} else {
+				c.define(name, anyType, false)
+			}
+		}
+		return anyType
+	case *ast.Package:
+		c.block(e.Block)
+		return anyType
+	}
+	return anyType
+}
+
+func (c *checker) typedFunction(e *ast.TypedFunctionLiteral) *typ {
+	fn := &typ{kind: kindFunc, params: []*typ{}, result: anyType}
+	for _, tp := range e.TypeParams {
+		fn.typeParams = append(fn.typeParams, c.typeOf(tp))
+	}
+	for i, p := range e.Parameters {
+		pt := anyType
+		if p.Type != nil {
+			pt = c.typeOf(p.Type)
+		}
+		fn.params = append(fn.params, pt)
+		fn.paramNames = append(fn.paramNames, p.Identifier.Value)
+		if p.Default != nil {
+			name := p.Identifier.Value
+			c.expect(p.Default, pt, func(got *typ) string {
+				return fmt.Sprintf("default value of parameter '%s' expects %s, got %s", name, pt, got)
+			})
+		} else if p.Variadic {
+			fn.variadic = true
+		} else {
+			fn.required = i + 1
+		}
+	}
+	if e.ReturnType != nil {
+		fn.result = c.typeOf(e.ReturnType)
+	}
+	// A named function can call itself.
+	c.push()
+	if e.Name != "" {
+		c.define(e.Name, fn, false)
+	}
+	c.functionBody(fn, e.ReturnType != nil, e.Body)
+	c.pop()
+	return fn
+}
+
+func (c *checker) prefix(e *ast.PrefixExpression) *typ {
+	right := c.expr(e.Right)
+	switch e.Operator {
+	case "!":
+		return boolType
+	case "-", "+":
+		if right.isNumeric() {
+			return right
+		}
+		if right.isConcrete() {
+			c.errorf(e.Token, "cannot use '%s' operator on %s", e.Operator, right)
+		}
+	case "~":
+		if right.kind == kindInt || right.kind == kindBigInt {
+			return right
+		}
+		if right.isConcrete() && right.kind != kindNumber {
+			c.errorf(e.Token, "cannot use '~' operator on %s", right)
+		}
+	case "&":
+		// Writing through a pointer to a variable without a declared type
+		// can change its type, as assigning to it can.
+		if ident, ok := e.Right.(*ast.Identifier); ok {
+			if v := c.scope.lookup(ident.Value); v != nil && !v.declared {
+				return &typ{kind: kindPointer, elem: anyType}
+			}
+		}
+		return &typ{kind: kindPointer, elem: right}
+	case "*":
+		if right.kind == kindPointer {
+			return right.elem
+		}
+		if right.isConcrete() {
+			c.errorf(e.Token, "cannot dereference %s, which is not a pointer", right)
+		}
+	}
+	return anyType
+}
+
+var arithmetic = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "**": true,
+	"&": true, "|": true, "^": true, "<<": true, ">>": true,
+}
+
+func (c *checker) infix(e *ast.InfixExpression) *typ {
+	left, right := c.expr(e.Left), c.expr(e.Right)
+	op := e.Operator
+	switch op {
+	case "in", "==", "!=":
+		return boolType
+	case "??":
+		if left.kind == kindNil {
+			return right
+		}
+		if same(left, right) {
+			return left
+		}
+		return anyType
+	}
+	if t := binary(op, left, right); t != nil {
+		return t
+	}
+	if left.isConcrete() && right.isConcrete() {
+		c.errorf(e.Token, "cannot use '%s' operator between %s and %s", op, left, right)
+	}
+	if arithmetic[op] {
+		return anyType
+	}
+	return boolType
+}
+
+// binary returns the type of left op right, following the interpreter's
+// rules, or nil if the interpreter does not support the operation on
+// those types. Numbers are tricky: int / int, int + float and so on can
+// give either an int or a float, depending on the values.
+func binary(op string, left, right *typ) *typ {
+	if left.kind == kindAny || right.kind == kindAny || !left.isConcrete() || !right.isConcrete() {
+		return anyType
+	}
+	l, r := left.kind, right.kind
+	switch op {
+	case "&&", "||":
+		if l == kindBool && r == kindBool {
+			return boolType
+		}
+	case "<", "<=", ">", ">=":
+		if left.isNumeric() && right.isNumeric() {
+			return boolType
+		}
+	case "&", "|", "^", "<<", ">>":
+		// Bitwise operators need integers; a number may turn out to be one
+		switch {
+		case l == kindInt && r == kindInt:
+			return intType
+		case isInteger(l) && isInteger(r) && (l == kindBigInt || r == kindBigInt):
+			return bigIntType
+		case (isInteger(l) || l == kindNumber) && (isInteger(r) || r == kindNumber):
+			return anyType
+		}
+	case "+", "-", "*", "/", "%", "**":
+		switch {
+		case l == kindInt && r == kindInt:
+			switch op {
+			case "/":
+				return numberType
+			case "**":
+				return floatType
+			}
+			return intType
+		case l == kindFloat && r == kindFloat:
+			if op != "%" {
+				return floatType
+			}
+		case (l == kindDecimal || r == kindDecimal) && left.isNumeric() && right.isNumeric():
+			// Any number with a decimal gives a decimal
+			return decimalType
+		case isInteger(l) && isInteger(r):
+			// One of them is a bigint
+			if op == "/" || op == "**" {
+				return numberType
+			}
+			return bigIntType
+		case (l == kindBigInt && r == kindFloat || l == kindFloat && r == kindBigInt) && op != "%":
+			return floatType
+		case left.isNumeric() && right.isNumeric():
+			return numberType
+		case op == "+" && l == kindString && r == kindString:
+			return stringType
+		case op == "+" && l == kindArray && r == kindArray:
+			return arrayOf(anyType)
+		case op == "+" && l == kindDict && r == kindDict:
+			return dictOf(anyType, anyType)
+		case op == "*" && (l == kindString && right.isNumeric() || left.isNumeric() && r == kindString):
+			return stringType
+		case op == "*" && (l == kindArray && right.isNumeric() || left.isNumeric() && r == kindArray):
+			return arrayOf(anyType)
+		}
+	default:
+		return anyType
+	}
+	return nil
+}
+
+// isInteger reports whether k is an integer kind of either size.
+func isInteger(k kind) bool {
+	return k == kindInt || k == kindBigInt
+}
+
+// resolve picks the overload of a function that a call with argc
+// arguments runs, as the interpreter does: one that takes exactly that
+// many, or else the first variadic one that takes at least that many. An
+// argc of -1 means the count is not known because of a ...spread.
+func resolve(v *variable, argc int) *typ {
+	if len(v.funcs) <= 1 {
+		return v.typ
+	}
+	if argc < 0 {
+		return anyType
+	}
+	var variadic *typ
+	for _, fn := range v.funcs {
+		switch {
+		case fn.variadic:
+			if argc >= fn.required && variadic == nil {
+				variadic = fn
+			}
+		case argc <= len(fn.params) && argc >= fn.required:
+			return fn
+		}
+	}
+	if variadic != nil {
+		return variadic
+	}
+	return anyType
+}
+
+// argumentCount returns the number of arguments in a call, or -1 if a
+// ...spread makes it unknown.
+func argumentCount(args []ast.Expression) int {
+	for _, arg := range args {
+		if _, ok := arg.(*ast.SpreadExpression); ok {
+			return -1
+		}
+	}
+	return len(args)
+}
+
+// optional returns the type of a link in a chain, which is any for a ?.
+// link, since that gives null when its object is null.
+func optional(isOptional bool, t *typ) *typ {
+	if isOptional {
+		return anyType
+	}
+	return t
+}
+
+func (c *checker) call(e *ast.CallExpression) *typ {
+	var callee *typ
+	if ident, ok := e.Function.(*ast.Identifier); ok {
+		if v := c.scope.lookup(ident.Value); v != nil {
+			callee = resolve(v, argumentCount(e.Arguments))
+		}
+	}
+	if callee == nil {
+		callee = c.expr(e.Function)
+	}
+
+	switch callee.kind {
+	case kindFunc:
+		b := c.arguments(callee, e.Arguments, "")
+		return b.substitute(callee.result)
+	case kindStructDef:
+		return c.structCall(callee, e)
+	}
+	for _, arg := range e.Arguments {
+		c.expr(arg)
+	}
+	return anyType
+}
+
+// arguments checks the arguments of a call to fn. Keyword arguments,
+// written name = value, are matched to parameters by name. The arguments
+// after a ...spread are not matched to parameters, since the spread may
+// stand for any number of them. For a generic function, the type
+// parameters are inferred from the arguments and returned.
+func (c *checker) arguments(fn *typ, args []ast.Expression, method string) bindings {
+	var b bindings
+	if fn.typeParams != nil {
+		b = bindings{}
+	}
+	positional := 0
+	for _, arg := range args {
+		i := positional
+		value := arg
+		if kw, ok := arg.(*ast.Assign); ok && fn.paramNames != nil {
+			value = kw.Value
+			i = indexOf(fn.paramNames, kw.Name.Value)
+		} else if _, ok := arg.(*ast.SpreadExpression); ok {
+			positional = -1
+			i = -1
+		} else if positional >= 0 {
+			positional++
+		}
+		if fn.variadic && i >= len(fn.params) {
+			i = len(fn.params) - 1
+		}
+		if fn.params == nil || i < 0 || i >= len(fn.params) {
+			c.expr(value)
+			continue
+		}
+		want := fn.params[i]
+		mismatch := func(got *typ) string {
+			switch {
+			case method != "":
+				return fmt.Sprintf("parameter '%s' in method '%s' expects %s, got %s", fn.paramNames[i], method, want, got)
+			case fn.paramNames != nil:
+				return fmt.Sprintf("parameter '%s' expects %s, got %s", fn.paramNames[i], want, got)
+			}
+			return fmt.Sprintf("argument %d to %s expects %s, got %s", i+1, fn.label, want, got)
+		}
+		if b != nil && hasParams(want) {
+			got := c.argumentType(value)
+			if want = b.infer(want, got); !assignable(got, want) {
+				c.errorf(start(value), "%s", mismatch(got))
+			}
+			continue
+		}
+		c.expect(value, want, mismatch)
+	}
+	return b
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// structCall checks a call that makes an instance of a struct, with field
+// values given by position or as field = value, and returns the type of
+// the instance. The type arguments of a generic struct are inferred from
+// the fields unless they were given.
+func (c *checker) structCall(def *typ, e *ast.CallExpression) *typ {
+	name := def.name
+	info := c.structs[name]
+	b := c.structBindings(def)
+	given := map[string]bool{}
+	for i, arg := range e.Arguments {
+		var f field
+		var ok bool
+		value := arg
+		if kw, isKeyword := arg.(*ast.Assign); isKeyword {
+			value = kw.Value
+			if f, ok = info.field(kw.Name.Value); !ok {
+				c.errorf(kw.Name.Token, "struct '%s' has no field '%s'", name, kw.Name.Value)
+				c.expr(value)
+				continue
+			}
+		} else if i < len(info.fields) {
+			f, ok = info.fields[i], true
+		}
+		if !ok {
+			c.expr(value)
+			continue
+		}
+		given[f.name] = true
+		want := f.typ
+		mismatch := func(got *typ) string {
+			return fmt.Sprintf("field '%s' in struct '%s' expects %s, got %s", f.name, name, want, got)
+		}
+		if hasParams(want) {
+			got := c.argumentType(value)
+			if want = b.infer(want, got); !assignable(got, want) {
+				c.errorf(start(value), "%s", mismatch(got))
+			}
+			continue
+		}
+		c.expect(value, want, mismatch)
+	}
+
+	var missing []string
+	for _, f := range info.fields {
+		if !given[f.name] && !f.hasDefault {
+			missing = append(missing, "'"+f.name+"'")
+		}
+	}
+	if len(missing) > 0 {
+		plural := ""
+		if len(missing) > 1 {
+			plural = "s"
+		}
+		c.errorf(start(e), "missing value%s for field%s %s in struct '%s'", plural, plural, strings.Join(missing, ", "), name)
+	}
+
+	t := c.structType(name)
+	for _, p := range info.params {
+		arg, ok := b[p.name]
+		if !ok {
+			arg = anyType
+		}
+		t.args = append(t.args, arg)
+	}
+	return t
+}
+
+func (c *checker) method(e *ast.MethodExpression) *typ {
+	obj := c.expr(e.Object)
+	name, _ := e.Method.(*ast.Identifier)
+	if name == nil {
+		c.expr(e.Method)
+	}
+	if obj.kind == kindPointer {
+		obj = obj.elem
+	}
+
+	var fn *typ
+	var method string
+	switch {
+	case name == nil:
+	case obj.kind == kindModule:
+		fn = moduleFunction(obj.name, name.Value)
+		mod := module.Mapper[obj.name]
+		if fn == nil && mod.Variables[name.Value] == nil && mod.Submodules[name.Value] == nil {
+			c.errorf(name.Token, "module '%s' has no function '%s'", obj.name, name.Value)
+		}
+	case obj.kind == kindInterface && obj.methods != nil:
+		fn, method = obj.methods[name.Value], name.Value
+		if fn == nil {
+			c.errorf(name.Token, "interface '%s' has no method '%s'", obj.name, name.Value)
+		}
+	case obj.kind == kindStruct && c.structs[obj.name] != nil:
+		info := c.structs[obj.name]
+		fn, method = info.methods[name.Value], name.Value
+		if _, isField := info.field(name.Value); fn == nil && !isField {
+			c.errorf(name.Token, "struct '%s' has no method '%s'", obj.name, name.Value)
+		}
+		if fn != nil {
+			fn = c.structBindings(obj).substitute(fn)
+		}
+	}
+
+	if fn == nil {
+		for _, arg := range e.Arguments {
+			c.expr(arg)
+		}
+		for _, value := range e.Defaults {
+			c.expr(value)
+		}
+		return anyType
+	}
+
+	c.arguments(fn, e.Arguments, method)
+	for key, value := range e.Defaults {
+		i := indexOf(fn.paramNames, key)
+		if i < 0 || fn.params == nil {
+			c.expr(value)
+			continue
+		}
+		want := fn.params[i]
+		c.expect(value, want, func(got *typ) string {
+			return fmt.Sprintf("parameter '%s' in method '%s' expects %s, got %s", key, method, want, got)
+		})
+	}
+	return fn.result
+}
+
+func (c *checker) property(e *ast.PropertyExpression) *typ {
+	obj := c.expr(e.Object)
+	name, ok := e.Property.(*ast.Identifier)
+	if !ok {
+		return anyType
+	}
+	if obj.kind == kindPointer {
+		obj = obj.elem
+	}
+	switch obj.kind {
+	case kindStruct:
+		info := c.structs[obj.name]
+		if info == nil {
+			break
+		}
+		if f, ok := info.field(name.Value); ok {
+			return c.structBindings(obj).substitute(f.typ)
+		}
+		if fn, ok := info.methods[name.Value]; ok {
+			return c.structBindings(obj).substitute(fn)
+		}
+		c.errorf(name.Token, "struct '%s' has no field '%s'", obj.name, name.Value)
+	case kindEnum:
+		members := c.enums[obj.name]
+		if members == nil {
+			break
+		}
+		if t, ok := members[name.Value]; ok {
+			return t
+		}
+		c.errorf(name.Token, "enum '%s' has no member '%s'", obj.name, name.Value)
+	case kindDict:
+		return obj.elem
+	}
+	return anyType
+}
+
+// assign checks name = value. Variables declared with a type keep it;
+// others take the type of what is assigned.
+func (c *checker) assign(e *ast.Assign) *typ {
+	v := c.scope.lookup(e.Name.Value)
+	if v != nil && v.declared {
+		c.expect(e.Value, v.typ, func(got *typ) string {
+			return fmt.Sprintf("cannot assign %s to variable '%s' of type %s", got, e.Name.Value, v.typ)
+		})
+		return v.typ
+	}
+	t := c.expr(e.Value)
+	if v != nil && !same(v.typ, t) {
+		v.typ, v.funcs = anyType, nil
+	}
+	return t
+}
+
+// assignEqual checks compound assignments such as x += 1.
+func (c *checker) assignEqual(e *ast.AssignEqual) *typ {
+	left := c.expr(e.Left)
+	right := c.expr(e.Value)
+	op := strings.TrimSuffix(e.Token.Literal, "=")
+	result := binary(op, left, right)
+	if result == nil {
+		c.errorf(e.Token, "cannot use '%s' operator between %s and %s", e.Token.Literal, left, right)
+		return anyType
+	}
+	if v := c.scope.lookup(e.Left.Value); v != nil {
+		if v.declared && !assignable(result, v.typ) {
+			c.errorf(start(e.Value), "cannot assign %s to variable '%s' of type %s", result, e.Left.Value, v.typ)
+		} else if !v.declared && !same(v.typ, result) {
+			v.typ, v.funcs = anyType, nil
+		}
+	}
+	return result
+}
+
+// assignIndex checks container[index] = value, and *pointer = value.
+func (c *checker) assignIndex(e *ast.AssignmentExpression) *typ {
+	if deref, ok := e.Left.(*ast.PrefixExpression); ok && deref.Operator == "*" {
+		target := c.expr(deref)
+		if e.Token.Literal != "=" {
+			c.expr(e.Value)
+			return target
+		}
+		c.expect(e.Value, target, func(got *typ) string {
+			return fmt.Sprintf("cannot assign %s through pointer to %s", got, target)
+		})
+		return target
+	}
+	index, ok := e.Left.(*ast.IndexExpression)
+	if !ok {
+		c.expr(e.Left)
+		return c.expr(e.Value)
+	}
+	container := c.expr(index.Left)
+	c.expr(index.Index)
+	if container.kind != kindArray && container.kind != kindDict {
+		return c.expr(e.Value)
+	}
+	c.expect(e.Value, container.elem, func(got *typ) string {
+		return fmt.Sprintf("cannot use %s as an element of %s", got, container)
+	})
+	return container.elem
+}
+
+// assignProperty checks object.field = value.
+func (c *checker) assignProperty(e *ast.PropertyAssignment) *typ {
+	obj := c.expr(e.Name.Object)
+	name, ok := e.Name.Property.(*ast.Identifier)
+	if obj.kind == kindPointer {
+		obj = obj.elem
+	}
+	if !ok || obj.kind != kindStruct || c.structs[obj.name] == nil {
+		return c.expr(e.Value)
+	}
+	f, ok := c.structs[obj.name].field(name.Value)
+	if !ok {
+		c.errorf(name.Token, "struct '%s' has no field '%s'", obj.name, name.Value)
+		return c.expr(e.Value)
+	}
+	want := c.structBindings(obj).substitute(f.typ)
+	c.expect(e.Value, want, func(got *typ) string {
+		return fmt.Sprintf("field '%s' in struct '%s' expects %s, got %s", f.name, obj.name, want, got)
+	})
+	return want
+}
+
+func (c *checker) forIn(e *ast.ForIn) {
+	iterable := c.expr(e.Iterable)
+	key, value := anyType, anyType
+	switch iterable.kind {
+	case kindArray, kindChan:
+		key, value = intType, iterable.elem
+	case kindString:
+		key, value = intType, stringType
+	case kindDict:
+		if e.Key != "" {
+			key, value = iterable.key, iterable.elem
+		}
+	}
+	c.push()
+	if e.Key != "" {
+		c.define(e.Key, key, false)
+	}
+	if e.Pattern != nil {
+		c.pattern(e.Pattern, value)
+	} else {
+		c.define(e.Value, value, false)
+	}
+	c.block(e.Block)
+	c.pop()
+}
+
+// pattern defines the names in a destructuring pattern, given the type of
+// the value it takes apart.
+func (c *checker) pattern(pattern ast.Expression, t *typ) {
+	switch p := pattern.(type) {
+	case *ast.Identifier:
+		if p.Value != "_" {
+			c.define(p.Value, t, false)
+		}
+	case *ast.Assign:
+		if def := c.expr(p.Value); !same(def, t) {
+			t = anyType
+		}
+		c.pattern(p.Name, t)
+	case *ast.ArrayPattern:
+		elem := anyType
+		if t.kind == kindArray {
+			elem = t.elem
+		} else if t.isConcrete() {
+			c.errorf(p.Token, "cannot destructure %s as an array", t)
+		}
+		for _, e := range p.Elements {
+			c.pattern(e, elem)
+		}
+		if p.Rest != nil {
+			c.pattern(p.Rest, arrayOf(elem))
+		}
+	case *ast.DictPattern:
+		value, rest := anyType, dictOf(anyType, anyType)
+		if t.kind == kindDict {
+			value, rest = t.elem, t
+		} else if t.isConcrete() {
+			c.errorf(p.Token, "cannot destructure %s as a dict", t)
+		}
+		for _, v := range p.Values {
+			c.pattern(v, value)
+		}
+		if p.Rest != nil {
+			c.pattern(p.Rest, rest)
+		}
+	}
+}
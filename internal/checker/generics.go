@@ -0,0 +1,201 @@
+package checker
+
+import "github.com/vintlang/vintlang/internal/ast"
+
+// bindings maps the type parameters of a generic function or struct, by
+// name, to the types they stand for in one call or instance.
+type bindings map[string]*typ
+
+// infer binds the type parameters in want from got, the type of the value
+// given for it, and returns want with the bound parameters substituted. A
+// parameter that is already bound keeps its type, and one whose
+// constraint got does not satisfy stays unbound and becomes the
+// constraint, so that the value is reported against it.
+func (b bindings) infer(want, got *typ) *typ {
+	switch want.kind {
+	case kindParam:
+		if bound, ok := b[want.name]; ok {
+			return bound
+		}
+		if got.kind == kindAny || want.elem != nil && !assignable(got, want.elem) {
+			return b.substitute(want)
+		}
+		b[want.name] = got
+		return got
+	case kindArray, kindPointer, kindChan:
+		if got.kind == want.kind {
+			return &typ{kind: want.kind, elem: b.infer(want.elem, got.elem)}
+		}
+	case kindDict:
+		if got.kind == kindDict {
+			return dictOf(b.infer(want.key, got.key), b.infer(want.elem, got.elem))
+		}
+	case kindStruct:
+		if got.kind == kindStruct && got.name == want.name && len(got.args) == len(want.args) {
+			instance := *want
+			instance.args = make([]*typ, len(want.args))
+			for i := range want.args {
+				instance.args[i] = b.infer(want.args[i], got.args[i])
+			}
+			return &instance
+		}
+	}
+	return b.substitute(want)
+}
+
+// substitute returns t with its type parameters replaced by what they are
+// bound to. Unbound parameters become their constraint, or any.
+func (b bindings) substitute(t *typ) *typ {
+	if !hasParams(t) {
+		return t
+	}
+	switch t.kind {
+	case kindParam:
+		if bound, ok := b[t.name]; ok {
+			return bound
+		}
+		if t.elem != nil {
+			return t.elem
+		}
+		return anyType
+	case kindArray, kindPointer, kindChan:
+		return &typ{kind: t.kind, elem: b.substitute(t.elem)}
+	case kindDict:
+		return dictOf(b.substitute(t.key), b.substitute(t.elem))
+	case kindStruct:
+		instance := *t
+		instance.args = make([]*typ, len(t.args))
+		for i, arg := range t.args {
+			instance.args[i] = b.substitute(arg)
+		}
+		return &instance
+	case kindFunc:
+		fn := *t
+		fn.params = make([]*typ, len(t.params))
+		for i, p := range t.params {
+			fn.params[i] = b.substitute(p)
+		}
+		fn.result = b.substitute(t.result)
+		return &fn
+	}
+	return t
+}
+
+// hasParams reports whether t mentions a type parameter.
+func hasParams(t *typ) bool {
+	if t == nil {
+		return false
+	}
+	switch t.kind {
+	case kindParam:
+		return true
+	case kindArray, kindPointer, kindChan:
+		return hasParams(t.elem)
+	case kindDict:
+		return hasParams(t.key) || hasParams(t.elem)
+	case kindStruct:
+		for _, arg := range t.args {
+			if hasParams(arg) {
+				return true
+			}
+		}
+	case kindFunc:
+		for _, p := range t.params {
+			if hasParams(p) {
+				return true
+			}
+		}
+		return hasParams(t.result)
+	}
+	return false
+}
+
+// structBindings returns what the type parameters of a generic struct
+// stand for in t, an instance of it or the struct given type arguments.
+// Parameters whose argument is unknown are left unbound.
+func (c *checker) structBindings(t *typ) bindings {
+	b := bindings{}
+	info := c.structs[t.name]
+	if info == nil {
+		return b
+	}
+	for i, p := range info.params {
+		if i < len(t.args) && t.args[i].kind != kindAny {
+			b[p.name] = t.args[i]
+		}
+	}
+	return b
+}
+
+// argumentType returns the type of a value given for a parameter or field
+// whose type mentions a type parameter. An array literal there has the
+// type of its elements when they all have the same one, so that
+// first([1, 2]) binds T in first<T>(xs: [T]) to int.
+func (c *checker) argumentType(value ast.Expression) *typ {
+	lit, ok := value.(*ast.ArrayLiteral)
+	if !ok {
+		return c.expr(value)
+	}
+	var elem *typ
+	for _, el := range lit.Elements {
+		t := c.expr(el)
+		switch {
+		case elem == nil:
+			elem = t
+		case !same(elem, t):
+			elem = anyType
+		}
+	}
+	if elem == nil {
+		elem = anyType
+	}
+	return arrayOf(elem)
+}
+
+// instantiate checks f<type arguments>, which gives the type parameters
+// of a generic function or struct explicitly.
+func (c *checker) instantiate(e *ast.InstantiateExpression) *typ {
+	t := c.expr(e.Function)
+	var params []*typ
+	switch t.kind {
+	case kindFunc:
+		params = t.typeParams
+	case kindStructDef:
+		if info := c.structs[t.name]; info != nil {
+			params = info.params
+		}
+	default:
+		return anyType
+	}
+	name := e.Function.String()
+	if params == nil {
+		c.errorf(start(e.Function), "'%s' is not generic, so it cannot take type arguments", name)
+		return t
+	}
+	if len(e.TypeArgs) != len(params) {
+		plural := ""
+		if len(params) != 1 {
+			plural = "s"
+		}
+		c.errorf(start(e.Function), "'%s' takes %d type argument%s, got %d", name, len(params), plural, len(e.TypeArgs))
+	}
+
+	b := bindings{}
+	args := make([]*typ, len(params))
+	for i, p := range params {
+		args[i] = anyType
+		if i < len(e.TypeArgs) {
+			args[i] = c.typeOf(e.TypeArgs[i])
+		}
+		if p.elem != nil && !assignable(args[i], p.elem) {
+			c.errorf(start(e.Function), "type argument %s for '%s' does not satisfy %s", args[i], p.name, p.elem)
+		}
+		b[p.name] = args[i]
+	}
+	if t.kind == kindStructDef {
+		return &typ{kind: kindStructDef, name: t.name, args: args}
+	}
+	fn := *b.substitute(t)
+	fn.typeParams = nil
+	return &fn
+}
@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/formatter"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/styles"
+)
+
+// Main runs `vint check` and returns the exit code: 0 if the files have no
+// type errors, 1 if they do or do not parse, and 2 for bad usage.
+// Directories are searched for .vint files.
+func Main(args []string, out io.Writer) int {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown flag %s", arg)))
+			return 2
+		}
+	}
+
+	files, err := formatter.Discover(args)
+	if err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+
+	failed, count := false, 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			failed = true
+			continue
+		}
+		p := parser.New(lexer.NewWithFilename(string(src), file))
+		program := p.ParseProgram()
+		if errs := p.Errors(); len(errs) > 0 {
+			for _, msg := range errs {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(msg))
+			}
+			failed = true
+			continue
+		}
+
+		lines := strings.Split(string(src), "\n")
+		for _, d := range Check(program) {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(d.context(lines)))
+			count++
+		}
+	}
+
+	switch {
+	case count == 1:
+		fmt.Fprintln(out, styles.ErrorStyle.Render("1 type error"))
+	case count > 1:
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("%d type errors", count)))
+	case !failed:
+		fmt.Fprintln(out, styles.SuccessStyle.Render(fmt.Sprintf("No type errors in %d file(s)", len(files))))
+	}
+	if failed || count > 0 {
+		return 1
+	}
+	return 0
+}
+
+// context formats d like a parse error, with the line of source it is on
+// and a caret under its column.
+func (d Diagnostic) context(lines []string) string {
+	if d.Line < 1 || d.Line > len(lines) || d.Column < 1 {
+		return d.String()
+	}
+	src := strings.TrimRight(lines[d.Line-1], "\r")
+	return fmt.Sprintf("%s\n    %s\n    %s^", d, src, strings.Repeat(" ", d.Column-1))
+}
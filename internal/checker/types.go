@@ -0,0 +1,379 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+)
+
+type kind int
+
+const (
+	kindAny    kind = iota // unknown; compatible with everything
+	kindNumber             // int or float, e.g. the result of 3 / 2
+	kindInt
+	kindFloat
+	kindBigInt
+	kindDecimal
+	kindString
+	kindBool
+	kindNil
+	kindError
+	kindArray
+	kindDict
+	kindFunc
+	kindPointer
+	kindChan
+	kindStruct    // an instance of the struct called name
+	kindStructDef // the struct called name itself, which is called to make instances
+	kindInterface // the interface called name, which structs satisfy by having its methods
+	kindEnum      // the enum called name
+	kindNamed     // a type name that is not declared in this file
+	kindModule    // the builtin module called name
+	kindParam     // the type parameter called name; elem is its constraint, nil if none
+)
+
+// typ is the static type of an expression.
+type typ struct {
+	kind kind
+	name string // struct, enum, named and module types
+	elem *typ   // array, pointer and chan element, dict value, type parameter constraint
+	key  *typ   // dict key
+	args []*typ // type arguments of an instance of a generic struct, nil if unknown
+
+	// Methods by name of interfaces, and of structs declared in this file.
+	// nil if they are unknown.
+	methods map[string]*typ
+
+	// Functions. params is nil if they are unknown, and result is never
+	// nil. paramNames is set for vint functions and label for builtins, to
+	// name the parameter or function in messages. Parameters from required
+	// on have defaults. If variadic, the last parameter collects the
+	// remaining arguments and params holds the type of each of them.
+	params     []*typ
+	paramNames []string
+	required   int
+	variadic   bool
+	result     *typ
+	label      string
+	typeParams []*typ // of generic functions
+}
+
+var (
+	anyType     = &typ{kind: kindAny}
+	numberType  = &typ{kind: kindNumber}
+	intType     = &typ{kind: kindInt}
+	floatType   = &typ{kind: kindFloat}
+	bigIntType  = &typ{kind: kindBigInt}
+	decimalType = &typ{kind: kindDecimal}
+	stringType  = &typ{kind: kindString}
+	boolType    = &typ{kind: kindBool}
+	nilType     = &typ{kind: kindNil}
+	errorType   = &typ{kind: kindError}
+)
+
+func arrayOf(elem *typ) *typ { return &typ{kind: kindArray, elem: elem} }
+
+func dictOf(key, value *typ) *typ { return &typ{kind: kindDict, key: key, elem: value} }
+
+func (t *typ) String() string {
+	switch t.kind {
+	case kindNumber:
+		return "number"
+	case kindInt:
+		return "int"
+	case kindFloat:
+		return "float64"
+	case kindBigInt:
+		return "bigint"
+	case kindDecimal:
+		return "decimal"
+	case kindString:
+		return "string"
+	case kindBool:
+		return "bool"
+	case kindNil:
+		return "nil"
+	case kindError:
+		return "error"
+	case kindArray:
+		return "[]" + t.elem.String()
+	case kindDict:
+		return "{" + t.key.String() + ": " + t.elem.String() + "}"
+	case kindFunc:
+		if t.params == nil {
+			return "func"
+		}
+		params := make([]string, len(t.params))
+		for i, p := range t.params {
+			params[i] = p.String()
+		}
+		if t.variadic {
+			params[len(params)-1] = "..." + params[len(params)-1]
+		}
+		s := "func(" + strings.Join(params, ", ") + ")"
+		if t.result.kind != kindAny {
+			s += " " + t.result.String()
+		}
+		return s
+	case kindPointer:
+		return "*" + t.elem.String()
+	case kindChan:
+		return "chan " + t.elem.String()
+	case kindStruct:
+		if t.args != nil {
+			args := make([]string, len(t.args))
+			for i, a := range t.args {
+				args[i] = a.String()
+			}
+			return t.name + "<" + strings.Join(args, ", ") + ">"
+		}
+		return t.name
+	case kindInterface, kindEnum, kindNamed, kindParam:
+		return t.name
+	case kindStructDef:
+		return "struct " + t.name
+	case kindModule:
+		return "module " + t.name
+	}
+	return "any"
+}
+
+// isNumeric reports whether t can hold a number.
+func (t *typ) isNumeric() bool {
+	switch t.kind {
+	case kindInt, kindFloat, kindBigInt, kindDecimal, kindNumber:
+		return true
+	}
+	return false
+}
+
+// isConcrete reports whether t is fully known and one of the types that
+// the interpreter's operators handle, so that an operator the interpreter
+// rejects can be reported.
+func (t *typ) isConcrete() bool {
+	switch t.kind {
+	case kindNumber, kindInt, kindFloat, kindBigInt, kindDecimal, kindString, kindBool, kindNil, kindArray, kindDict:
+		return true
+	}
+	return false
+}
+
+// intTypes are the type names the interpreter stores as integers.
+var intTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "byte": true,
+}
+
+// fromAST converts a type annotation. Type names other than the builtin
+// ones are looked up with lookup, which returns nil for unknown names.
+func fromAST(t ast.Type, lookup func(name string) *typ) *typ {
+	switch t := t.(type) {
+	case *ast.BasicType:
+		switch {
+		case intTypes[t.Name]:
+			return intType
+		case t.Name == "float" || t.Name == "float32" || t.Name == "float64":
+			return floatType
+		case t.Name == "bigint":
+			return bigIntType
+		case t.Name == "decimal":
+			return decimalType
+		case t.Name == "string":
+			return stringType
+		case t.Name == "bool":
+			return boolType
+		case t.Name == "nil":
+			return nilType
+		case t.Name == "error":
+			return errorType
+		case t.Name == "any":
+			return anyType
+		case t.Name == "number":
+			return numberType
+		}
+		if named := lookup(t.Name); named != nil {
+			return named
+		}
+		return &typ{kind: kindNamed, name: t.Name}
+	case *ast.ArrayType:
+		return arrayOf(fromAST(t.ElementType, lookup))
+	case *ast.FixedArrayType:
+		return arrayOf(fromAST(t.ElementType, lookup))
+	case *ast.DictType:
+		return dictOf(fromAST(t.KeyType, lookup), fromAST(t.ValueType, lookup))
+	case *ast.PointerType:
+		return &typ{kind: kindPointer, elem: fromAST(t.BaseType, lookup)}
+	case *ast.ChannelType:
+		return &typ{kind: kindChan, elem: fromAST(t.ElementType, lookup)}
+	case *ast.FunctionType:
+		fn := &typ{kind: kindFunc, params: []*typ{}, result: anyType}
+		for _, p := range t.Parameters {
+			fn.params = append(fn.params, fromAST(p, lookup))
+		}
+		fn.required = len(fn.params)
+		if t.ReturnType != nil {
+			fn.result = fromAST(t.ReturnType, lookup)
+		}
+		return fn
+	case *ast.InterfaceType:
+		if named := lookup(t.Name); named != nil {
+			return named
+		}
+		// Without the declaration its methods are unknown, and any struct
+		// is taken to satisfy it.
+		return &typ{kind: kindInterface, name: t.Name}
+	case *ast.TypeParameter:
+		param := &typ{kind: kindParam, name: t.Name}
+		if t.Constraint != nil {
+			param.elem = fromAST(t.Constraint, lookup)
+		}
+		return param
+	case *ast.GenericType:
+		named := lookup(t.Name)
+		if named == nil || named.kind != kindStruct {
+			return &typ{kind: kindNamed, name: t.Name}
+		}
+		instance := *named
+		for _, arg := range t.Args {
+			instance.args = append(instance.args, fromAST(arg, lookup))
+		}
+		return &instance
+	}
+	// Multiple return values are not checked by the interpreter either.
+	return anyType
+}
+
+// assignable reports whether a value of type src can be used where dst is
+// declared. It follows the interpreter's checks, and also checks the
+// element types of containers and the signatures of functions.
+func assignable(src, dst *typ) bool {
+	if src.kind == kindAny || dst.kind == kindAny {
+		return true
+	}
+	// Inside a generic function a type parameter only matches itself, and
+	// its values can be used where its constraint can.
+	if dst.kind == kindParam {
+		return src.kind == kindParam && src.name == dst.name
+	}
+	if src.kind == kindParam {
+		return src.elem != nil && assignable(src.elem, dst)
+	}
+	switch dst.kind {
+	case kindInt:
+		// Integers that outgrow int64 become bigints, and are still ints
+		return src.kind == kindInt || src.kind == kindBigInt || src.kind == kindNumber
+	case kindBigInt:
+		// An int expression gives a bigint when it overflows
+		return src.kind == kindBigInt || src.kind == kindInt || src.kind == kindNumber
+	case kindFloat, kindDecimal:
+		return src.kind == dst.kind || src.kind == kindNumber
+	case kindNumber:
+		return src.isNumeric()
+	case kindString, kindBool, kindNil, kindError, kindModule:
+		return src.kind == dst.kind && src.name == dst.name
+	case kindStruct, kindEnum, kindNamed:
+		// A declared type name matches instances of the struct or the
+		// enum with that name, and with the same type arguments if both
+		// are known.
+		switch src.kind {
+		case kindStruct, kindEnum, kindNamed:
+			return src.name == dst.name && sameArgs(src.args, dst.args)
+		case kindInterface:
+			// The struct in an interface value is only known at runtime.
+			return dst.kind != kindEnum
+		}
+		return false
+	case kindInterface:
+		// Structs satisfy an interface by having its methods.
+		switch src.kind {
+		case kindStruct, kindInterface:
+			return src.name == dst.name || satisfies(src.methods, dst.methods)
+		case kindNamed:
+			return true
+		}
+		return false
+	case kindStructDef:
+		return src.kind == kindStructDef && src.name == dst.name
+	case kindPointer:
+		// nil is the nil pointer of every pointer type
+		return src.kind == kindNil || src.kind == kindPointer && assignable(src.elem, dst.elem)
+	case kindArray, kindChan:
+		return src.kind == dst.kind && assignable(src.elem, dst.elem)
+	case kindDict:
+		return src.kind == kindDict && assignable(src.key, dst.key) && assignable(src.elem, dst.elem)
+	case kindFunc:
+		if src.kind != kindFunc {
+			return false
+		}
+		if src.params != nil && dst.params != nil && src.variadic == dst.variadic {
+			if len(src.params) != len(dst.params) {
+				return false
+			}
+			for i := range src.params {
+				if !assignable(dst.params[i], src.params[i]) {
+					return false
+				}
+			}
+		}
+		return assignable(src.result, dst.result)
+	}
+	return true
+}
+
+// satisfies reports whether a struct or interface with the methods have
+// has every method in want, with the same parameters and result. Unknown
+// methods satisfy anything, and an unannotated parameter or result matches
+// any type, as it does in the interpreter.
+func satisfies(have, want map[string]*typ) bool {
+	if have == nil || want == nil {
+		return true
+	}
+	for name, w := range want {
+		h, ok := have[name]
+		if !ok {
+			return false
+		}
+		if len(h.params) != len(w.params) || h.variadic != w.variadic {
+			return false
+		}
+		for i := range w.params {
+			if !sameOrAny(h.params[i], w.params[i]) {
+				return false
+			}
+		}
+		if !sameOrAny(h.result, w.result) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameOrAny is same, except that any matches every type. So does a type
+// parameter of a generic struct's method, since what it stands for is not
+// known from the struct's methods alone.
+func sameOrAny(a, b *typ) bool {
+	return a.kind == kindAny || b.kind == kindAny || a.kind == kindParam || b.kind == kindParam || same(a, b)
+}
+
+// sameArgs reports whether two lists of type arguments match, when both
+// are known.
+func sameArgs(a, b []*typ) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameOrAny(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// same reports whether a and b are the same type.
+func same(a, b *typ) bool {
+	return a.kind == b.kind && a.String() == b.String()
+}
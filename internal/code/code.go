@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"sort"
 )
 
 type Instructions []byte
@@ -22,6 +23,52 @@ const (
 	OpEqual
 	OpNotEqual
 	OpGreaterThan
+	OpGreaterEqual
+	OpMod
+	OpPow
+	OpAnd
+	OpOr
+	OpIn
+	OpCoalesce
+	OpBitAnd
+	OpBitOr
+	OpBitXor
+	OpShiftLeft
+	OpShiftRight
+	OpMinus
+	OpBang
+	OpBitNot
+	OpNull
+	OpDup
+	OpJump
+	OpJumpNotTruthy
+	OpGetGlobal
+	OpSetGlobal
+	OpAssignGlobal
+	OpGetLocal
+	OpSetLocal
+	OpMakeCell
+	OpGetLocalCell
+	OpSetLocalCell
+	OpGetFree
+	OpSetFree
+	OpGetFreeCell
+	OpArray
+	OpDict
+	OpIndex
+	OpSetIndex
+	OpRange
+	OpInterpolate
+	OpConcat
+	OpCall
+	OpCallKeywords
+	OpMethod
+	OpReturnValue
+	OpReturn
+	OpClosure
+	OpSkipIfBound
+	OpIterInit
+	OpIterNext
 )
 
 type Definition struct {
@@ -41,6 +88,62 @@ var definitions = map[Opcode]*Definition{
 	OpEqual:       {"OpEqual", []int{}},
 	OpNotEqual:    {"OpNotEqual", []int{}},
 	OpGreaterThan: {"OpGreaterThan", []int{}},
+
+	OpGreaterEqual: {"OpGreaterEqual", []int{}},
+	OpMod:          {"OpMod", []int{}},
+	OpPow:          {"OpPow", []int{}},
+	OpAnd:          {"OpAnd", []int{}},
+	OpOr:           {"OpOr", []int{}},
+	OpIn:           {"OpIn", []int{}},
+	OpCoalesce:     {"OpCoalesce", []int{}},
+	OpBitAnd:       {"OpBitAnd", []int{}},
+	OpBitOr:        {"OpBitOr", []int{}},
+	OpBitXor:       {"OpBitXor", []int{}},
+	OpShiftLeft:    {"OpShiftLeft", []int{}},
+	OpShiftRight:   {"OpShiftRight", []int{}},
+	OpMinus:        {"OpMinus", []int{}},
+	OpBang:         {"OpBang", []int{}},
+	OpBitNot:       {"OpBitNot", []int{}},
+	OpNull:         {"OpNull", []int{}},
+	OpDup:          {"OpDup", []int{}},
+
+	OpJump:          {"OpJump", []int{2}},          // Operand is the absolute target offset
+	OpJumpNotTruthy: {"OpJumpNotTruthy", []int{2}}, // Pops the condition, jumps if it is falsy
+
+	OpGetGlobal:    {"OpGetGlobal", []int{2}},
+	OpSetGlobal:    {"OpSetGlobal", []int{2}},    // Defines the global (let/const)
+	OpAssignGlobal: {"OpAssignGlobal", []int{2}}, // Fails if the global was never defined
+	OpGetLocal:     {"OpGetLocal", []int{1}},
+	OpSetLocal:     {"OpSetLocal", []int{1}},
+
+	// Locals captured by a closure live in cells so that the closure and
+	// the enclosing function share them.
+	OpMakeCell:     {"OpMakeCell", []int{}},
+	OpGetLocalCell: {"OpGetLocalCell", []int{1}},
+	OpSetLocalCell: {"OpSetLocalCell", []int{1}},
+	OpGetFree:      {"OpGetFree", []int{1}},
+	OpSetFree:      {"OpSetFree", []int{1}},
+	OpGetFreeCell:  {"OpGetFreeCell", []int{1}}, // Pushes the cell itself, to capture it again
+
+	OpArray:    {"OpArray", []int{2}}, // Operand is the number of elements
+	OpDict:     {"OpDict", []int{2}},  // Operand is the number of keys plus values
+	OpIndex:    {"OpIndex", []int{}},
+	OpSetIndex: {"OpSetIndex", []int{}},
+	OpRange:    {"OpRange", []int{}},
+
+	OpInterpolate: {"OpInterpolate", []int{2}}, // Turns a ${value} into a string; operand is the format spec constant
+	OpConcat:      {"OpConcat", []int{2}},      // Joins that many strings into one
+
+	OpCall:         {"OpCall", []int{1}},            // Operand is the number of arguments
+	OpCallKeywords: {"OpCallKeywords", []int{1, 1}}, // Positional and keyword argument counts
+	OpMethod:       {"OpMethod", []int{2, 1}},       // Method name constant and argument count
+	OpReturnValue:  {"OpReturnValue", []int{}},
+	OpReturn:       {"OpReturn", []int{}},
+	OpClosure:      {"OpClosure", []int{2, 1}}, // Function constant and number of free variables
+
+	OpSkipIfBound: {"OpSkipIfBound", []int{1, 2}}, // Skips a default value if the parameter was passed
+	OpIterInit:    {"OpIterInit", []int{}},
+	OpIterNext:    {"OpIterNext", []int{2}}, // Pushes key and value, or jumps when exhausted
 }
 
 func Lookup(op byte) (*Definition, error) {
@@ -71,6 +174,8 @@ func Make(op Opcode, operands ...int) []byte {
 		switch width {
 		case 2:
 			binary.BigEndian.PutUint16(instruction[offset:], uint16(o))
+		case 1:
+			instruction[offset] = byte(o)
 		}
 		offset += width
 	}
@@ -108,6 +213,8 @@ func (ins Instructions) fmtInstruction(def *Definition, operands []int) string {
 		return def.Name
 	case 1:
 		return fmt.Sprintf("%s %d", def.Name, operands[0])
+	case 2:
+		return fmt.Sprintf("%s %d %d", def.Name, operands[0], operands[1])
 	}
 
 	return fmt.Sprintf("ERROR: unhandled operandCount for %s\n", def.Name)
@@ -120,10 +227,41 @@ func ReadOperands(def *Definition, ins Instructions) ([]int, int) {
 	for i, width := range def.OperandWidths {
 		switch width {
 		case 2:
-			operands[i] = int(binary.BigEndian.Uint16(ins[offset:]))
+			operands[i] = int(ReadUint16(ins[offset:]))
+		case 1:
+			operands[i] = int(ReadUint8(ins[offset:]))
 		}
 		offset += width
 	}
 
 	return operands, offset
 }
+
+func ReadUint16(ins Instructions) uint16 {
+	return binary.BigEndian.Uint16(ins)
+}
+
+func ReadUint8(ins Instructions) uint8 {
+	return uint8(ins[0])
+}
+
+// Position ties an instruction offset to the source token it was compiled from.
+type Position struct {
+	Offset int
+	File   string
+	Line   int
+	Column int
+}
+
+// SourceMap lists positions in increasing offset order.
+type SourceMap []Position
+
+// Lookup returns the position of the instruction at offset: the last entry
+// recorded at or before it.
+func (m SourceMap) Lookup(offset int) (Position, bool) {
+	i := sort.Search(len(m), func(i int) bool { return m[i].Offset > offset })
+	if i == 0 {
+		return Position{}, false
+	}
+	return m[i-1], true
+}
@@ -9,6 +9,8 @@ func TestMake(t *testing.T) {
 		expected []byte
 	}{
 		{OpConstant, []int{65534}, []byte{byte(OpConstant), 255, 254}},
+		{OpGetLocal, []int{255}, []byte{byte(OpGetLocal), 255}},
+		{OpClosure, []int{65534, 255}, []byte{byte(OpClosure), 255, 254, 255}},
 	}
 
 	for _, tt := range tests {
@@ -51,6 +53,29 @@ func TestInstructionsString(t *testing.T) {
 	}
 }
 
+func TestInstructionsStringOperandWidths(t *testing.T) {
+	instructions := []Instructions{
+		Make(OpAdd),
+		Make(OpGetLocal, 1),
+		Make(OpClosure, 65535, 255),
+	}
+
+	expected := `0000 OpAdd
+0001 OpGetLocal 1
+0003 OpClosure 65535 255
+`
+
+	concatted := Instructions{}
+	for _, ins := range instructions {
+		concatted = append(concatted, ins...)
+	}
+
+	if concatted.String() != expected {
+		t.Errorf("instructions wrongly formatted.\nwant=%q\ngot=%q",
+			expected, concatted.String())
+	}
+}
+
 func TestReadOperands(t *testing.T) {
 	tests := []struct {
 		op        Opcode
@@ -58,6 +83,8 @@ func TestReadOperands(t *testing.T) {
 		bytesRead int
 	}{
 		{OpConstant, []int{65535}, 2},
+		{OpGetLocal, []int{255}, 1},
+		{OpClosure, []int{65535, 255}, 3},
 	}
 
 	for _, tt := range tests {
@@ -80,3 +107,31 @@ func TestReadOperands(t *testing.T) {
 		}
 	}
 }
+
+func TestSourceMapLookup(t *testing.T) {
+	positions := SourceMap{
+		{Offset: 0, Line: 1},
+		{Offset: 4, Line: 2},
+		{Offset: 9, Line: 5},
+	}
+
+	tests := []struct {
+		offset int
+		line   int
+		found  bool
+	}{
+		{0, 1, true},
+		{3, 1, true},
+		{4, 2, true},
+		{20, 5, true},
+		{-1, 0, false},
+	}
+
+	for _, tt := range tests {
+		pos, ok := positions.Lookup(tt.offset)
+		if ok != tt.found || pos.Line != tt.line {
+			t.Errorf("Lookup(%d) = (line %d, %t), want (line %d, %t)",
+				tt.offset, pos.Line, ok, tt.line, tt.found)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package compiler
+
+import (
+	"reflect"
+
+	"github.com/vintlang/vintlang/internal/ast"
+)
+
+var nodeType = reflect.TypeOf((*ast.Node)(nil)).Elem()
+
+// capturedNames returns every name referenced inside function literals nested
+// in node. Locals with one of these names are stored in cells so closures
+// share them with the enclosing function instead of copying their value.
+// The analysis is by name only, which may put a few extra locals in cells
+// but never misses a captured one.
+func capturedNames(node ast.Node) map[string]bool {
+	names := map[string]bool{}
+	walk(reflect.ValueOf(node), false, names)
+	return names
+}
+
+func walk(v reflect.Value, nested bool, names map[string]bool) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if v.Type().Implements(nodeType) {
+			switch n := v.Interface().(type) {
+			case *ast.FunctionLiteral:
+				nested = true
+			case *ast.Identifier:
+				if nested {
+					names[n.Value] = true
+				}
+				return
+			case *ast.PostfixExpression:
+				if nested {
+					names[n.Token.Literal] = true
+				}
+				return
+			}
+		}
+		walk(v.Elem(), nested, names)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walk(v.Field(i), nested, names)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), nested, names)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walk(iter.Key(), nested, names)
+			walk(iter.Value(), nested, names)
+		}
+	}
+}
@@ -2,32 +2,67 @@ package compiler
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/vintlang/vintlang/internal/ast"
 	"github.com/vintlang/vintlang/internal/code"
 	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/token"
 )
 
 type Bytecode struct {
 	Instructions code.Instructions
 	Constants    []object.VintObject
+	NumLocals    int      // local slots used by blocks at the top level
+	Globals      []string // global slot names, by index
+	Positions    code.SourceMap
+}
+
+type EmittedInstruction struct {
+	Opcode   code.Opcode
+	Position int
+}
+
+// CompilationScope holds the instructions of the function being compiled.
+type CompilationScope struct {
+	instructions        code.Instructions
+	lastInstruction     EmittedInstruction
+	previousInstruction EmittedInstruction
+	positions           code.SourceMap
+	loops               []*loop
+}
+
+// loop tracks the jumps of the innermost loop being compiled
+type loop struct {
+	continueTarget int
+	breaks         []int
 }
 
 type Compiler struct {
-	instructions code.Instructions
-	constants    []object.VintObject
+	constants []object.VintObject
+
+	symbolTable *SymbolTable
+
+	scopes     []CompilationScope
+	scopeIndex int
 }
 
 func New() *Compiler {
 	return &Compiler{
-		instructions: code.Instructions{},
-		constants:    []object.VintObject{},
+		constants:   []object.VintObject{},
+		symbolTable: NewSymbolTable(),
+		scopes:      []CompilationScope{{instructions: code.Instructions{}}},
+		scopeIndex:  0,
 	}
 }
 
 func (c *Compiler) Compile(node ast.Node) error {
 	switch node := node.(type) {
 	case *ast.Program:
+		for name := range capturedNames(node) {
+			c.symbolTable.captured[name] = true
+		}
 		for _, s := range node.Statements {
 			err := c.Compile(s)
 			if err != nil {
@@ -36,75 +71,704 @@ func (c *Compiler) Compile(node ast.Node) error {
 		}
 
 	case *ast.ExpressionStatement:
+		if node.Expression == nil {
+			return nil
+		}
 		err := c.Compile(node.Expression)
 		if err != nil {
 			return err
 		}
 		c.emit(code.OpPop)
 
-	case *ast.InfixExpression:
-		if node.Operator == "<" {
-			// For less than, we need to swap operands and use greater than
-			err := c.Compile(node.Right)
+	case *ast.BlockStatement:
+		for _, s := range node.Statements {
+			err := c.Compile(s)
 			if err != nil {
 				return err
 			}
-			err = c.Compile(node.Left)
+		}
+
+	case *ast.TestStatement:
+		// Test blocks only run under `vint test`
+
+	case *ast.LetStatement:
+		return c.compileDefinition(node.Name, node.Value, false)
+
+	case *ast.ConstStatement:
+		return c.compileDefinition(node.Name, node.Value, true)
+
+	case *ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			c.emit(code.OpReturn)
+			return nil
+		}
+		err := c.Compile(node.ReturnValue)
+		if err != nil {
+			return err
+		}
+		c.emit(code.OpReturnValue)
+
+	case *ast.Identifier:
+		c.mark(node.Token)
+		symbol, ok := c.symbolTable.Resolve(node.Value)
+		if !ok {
+			symbol = c.symbolTable.DeclareGlobal(node.Value)
+		}
+		c.loadSymbol(symbol)
+
+	case *ast.InfixExpression:
+		return c.compileInfix(node)
+
+	case *ast.PrefixExpression:
+		err := c.Compile(node.Right)
+		if err != nil {
+			return err
+		}
+		c.mark(node.Token)
+		switch node.Operator {
+		case "!":
+			c.emit(code.OpBang)
+		case "-":
+			c.emit(code.OpMinus)
+		case "~":
+			c.emit(code.OpBitNot)
+		default:
+			return c.unsupported(node, node.Token)
+		}
+
+	case *ast.PostfixExpression:
+		return c.compilePostfix(node)
+
+	case *ast.Boolean:
+		if node.Value {
+			c.emit(code.OpTrue)
+		} else {
+			c.emit(code.OpFalse)
+		}
+
+	case *ast.Null:
+		c.emit(code.OpNull)
+
+	case *ast.IntegerLiteral:
+		integer := &object.Integer{Value: node.Value}
+		c.emit(code.OpConstant, c.addConstant(integer))
+
+	case *ast.BigIntLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.BigInt{Value: node.Value}))
+
+	case *ast.FloatLiteral:
+		float := &object.Float{Value: node.Value}
+		c.emit(code.OpConstant, c.addConstant(float))
+
+	case *ast.StringLiteral:
+		str := &object.String{Value: node.Value}
+		c.emit(code.OpConstant, c.addConstant(str))
+
+	case *ast.InterpolatedString:
+		for _, part := range node.Parts {
+			err := c.Compile(part)
 			if err != nil {
 				return err
 			}
-			c.emit(code.OpGreaterThan)
-		} else {
-			err := c.Compile(node.Left)
+		}
+		c.emit(code.OpConcat, len(node.Parts))
+
+	case *ast.Interpolation:
+		err := c.Compile(node.Value)
+		if err != nil {
+			return err
+		}
+		c.mark(node.Token)
+		c.emit(code.OpInterpolate, c.addConstant(&object.String{Value: node.Format}))
+
+	case *ast.ArrayLiteral:
+		for _, el := range node.Elements {
+			err := c.Compile(el)
 			if err != nil {
 				return err
 			}
+		}
+		c.emit(code.OpArray, len(node.Elements))
 
-			err = c.Compile(node.Right)
+	case *ast.DictLiteral:
+		// Keys are compiled in source order, which is also the order of the dict
+		for _, k := range node.OrderedKeys() {
+			err := c.Compile(k)
+			if err != nil {
+				return err
+			}
+			err = c.Compile(node.Pairs[k])
 			if err != nil {
 				return err
 			}
+		}
+		c.mark(node.Token)
+		c.emit(code.OpDict, len(node.Pairs)*2)
+
+	case *ast.IndexExpression:
+		if node.Optional {
+			return c.unsupportedOptional(node.Token)
+		}
+		err := c.Compile(node.Left)
+		if err != nil {
+			return err
+		}
+		err = c.Compile(node.Index)
+		if err != nil {
+			return err
+		}
+		c.mark(node.Token)
+		c.emit(code.OpIndex)
+
+	case *ast.RangeExpression:
+		err := c.Compile(node.Start)
+		if err != nil {
+			return err
+		}
+		err = c.Compile(node.End)
+		if err != nil {
+			return err
+		}
+		c.mark(node.Token)
+		c.emit(code.OpRange)
+
+	case *ast.IfExpression:
+		return c.compileIf(node)
+
+	case *ast.WhileExpression:
+		return c.compileWhile(node)
+
+	case *ast.ForIn:
+		return c.compileForIn(node)
 
-			switch node.Operator {
-			case "+":
-				c.emit(code.OpAdd)
-			case "-":
-				c.emit(code.OpSub)
-			case "*":
-				c.emit(code.OpMul)
-			case "/":
-				c.emit(code.OpDiv)
-			case ">":
-				c.emit(code.OpGreaterThan)
-			case "==":
-				c.emit(code.OpEqual)
-			case "!=":
-				c.emit(code.OpNotEqual)
-			default:
-				return fmt.Errorf("unknown operator: %s", node.Operator)
+	case *ast.Break:
+		l := c.currentLoop()
+		if l == nil {
+			return c.errorf(node.Token, "'break' outside of a loop")
+		}
+		l.breaks = append(l.breaks, c.emit(code.OpJump, 9999))
+
+	case *ast.Continue:
+		l := c.currentLoop()
+		if l == nil {
+			return c.errorf(node.Token, "'continue' outside of a loop")
+		}
+		c.emit(code.OpJump, l.continueTarget)
+
+	case *ast.Assign:
+		err := c.Compile(node.Value)
+		if err != nil {
+			return err
+		}
+		c.mark(node.Token)
+		c.emit(code.OpDup)
+		return c.storeSymbol(node.Name)
+
+	case *ast.AssignEqual:
+		return c.compileAssignEqual(node)
+
+	case *ast.AssignmentExpression:
+		return c.compileIndexAssignment(node)
+
+	case *ast.FunctionLiteral:
+		return c.compileFunction(node, node.Name)
+
+	case *ast.CallExpression:
+		if node.Optional {
+			return c.unsupportedOptional(node.Token)
+		}
+		return c.compileCall(node)
+
+	case *ast.MethodExpression:
+		if node.Optional {
+			return c.unsupportedOptional(node.Token)
+		}
+		return c.compileMethod(node)
+
+	default:
+		return c.unsupported(node, token.Token{})
+	}
+
+	return nil
+}
+
+// compileDefinition compiles let and const. A function value is bound before
+// its body is compiled so that it can call itself recursively.
+func (c *Compiler) compileDefinition(name *ast.Identifier, value ast.Expression, constant bool) error {
+	define := c.symbolTable.Define
+	if constant {
+		define = c.symbolTable.DefineConst
+	}
+
+	if fn, ok := value.(*ast.FunctionLiteral); ok {
+		symbol := define(name.Value)
+		if symbol.Cell {
+			c.emit(code.OpNull)
+			c.emit(code.OpMakeCell)
+			c.emit(code.OpSetLocal, symbol.Index)
+			if err := c.compileFunction(fn, name.Value); err != nil {
+				return err
 			}
+			c.emit(code.OpSetLocalCell, symbol.Index)
+			return nil
+		}
+		if err := c.compileFunction(fn, name.Value); err != nil {
+			return err
 		}
+		c.defineSymbol(symbol)
+		return nil
+	}
 
-	case *ast.Boolean:
-		if node.Value {
-			c.emit(code.OpTrue)
+	if err := c.Compile(value); err != nil {
+		return err
+	}
+	c.defineSymbol(define(name.Value))
+	return nil
+}
+
+// defineSymbol pops the value on top of the stack into a new binding.
+func (c *Compiler) defineSymbol(s Symbol) {
+	switch {
+	case s.Scope == GlobalScope:
+		c.emit(code.OpSetGlobal, s.Index)
+	case s.Cell:
+		c.emit(code.OpMakeCell)
+		c.emit(code.OpSetLocal, s.Index)
+	default:
+		c.emit(code.OpSetLocal, s.Index)
+	}
+}
+
+// storeSymbol pops the value on top of the stack into an existing binding.
+func (c *Compiler) storeSymbol(name *ast.Identifier) error {
+	s, ok := c.symbolTable.Resolve(name.Value)
+	if !ok {
+		s = c.symbolTable.DeclareGlobal(name.Value)
+	}
+	if s.Constant {
+		return c.errorf(name.Token, "Cannot assign to constant '%s'", name.Value)
+	}
+
+	switch {
+	case s.Scope == GlobalScope:
+		c.emit(code.OpAssignGlobal, s.Index)
+	case s.Scope == FreeScope:
+		c.emit(code.OpSetFree, s.Index)
+	case s.Cell:
+		c.emit(code.OpSetLocalCell, s.Index)
+	default:
+		c.emit(code.OpSetLocal, s.Index)
+	}
+	return nil
+}
+
+func (c *Compiler) loadSymbol(s Symbol) {
+	switch {
+	case s.Scope == GlobalScope:
+		c.emit(code.OpGetGlobal, s.Index)
+	case s.Scope == FreeScope:
+		c.emit(code.OpGetFree, s.Index)
+	case s.Cell:
+		c.emit(code.OpGetLocalCell, s.Index)
+	default:
+		c.emit(code.OpGetLocal, s.Index)
+	}
+}
+
+var infixOpcodes = map[string]code.Opcode{
+	"+":  code.OpAdd,
+	"-":  code.OpSub,
+	"*":  code.OpMul,
+	"/":  code.OpDiv,
+	"%":  code.OpMod,
+	"**": code.OpPow,
+	">":  code.OpGreaterThan,
+	">=": code.OpGreaterEqual,
+	"==": code.OpEqual,
+	"!=": code.OpNotEqual,
+	"&&": code.OpAnd,
+	"||": code.OpOr,
+	"in": code.OpIn,
+	"??": code.OpCoalesce,
+	"&":  code.OpBitAnd,
+	"|":  code.OpBitOr,
+	"^":  code.OpBitXor,
+	"<<": code.OpShiftLeft,
+	">>": code.OpShiftRight,
+}
+
+func (c *Compiler) compileInfix(node *ast.InfixExpression) error {
+	if node.Operator == "<" || node.Operator == "<=" {
+		// For less than, we need to swap operands and use greater than
+		err := c.Compile(node.Right)
+		if err != nil {
+			return err
+		}
+		err = c.Compile(node.Left)
+		if err != nil {
+			return err
+		}
+		c.mark(node.Token)
+		if node.Operator == "<" {
+			c.emit(code.OpGreaterThan)
 		} else {
-			c.emit(code.OpFalse)
+			c.emit(code.OpGreaterEqual)
 		}
+		return nil
+	}
 
-	case *ast.IntegerLiteral:
-		integer := &object.Integer{Value: node.Value}
-		c.emit(code.OpConstant, c.addConstant(integer))
+	op, ok := infixOpcodes[node.Operator]
+	if !ok {
+		return c.errorf(node.Token, "unknown operator: %s", node.Operator)
+	}
+
+	err := c.Compile(node.Left)
+	if err != nil {
+		return err
+	}
+	err = c.Compile(node.Right)
+	if err != nil {
+		return err
+	}
+	c.mark(node.Token)
+	c.emit(op)
+	return nil
+}
+
+func (c *Compiler) compilePostfix(node *ast.PostfixExpression) error {
+	name := &ast.Identifier{Token: node.Token, Value: node.Token.Literal}
+	if err := c.Compile(name); err != nil {
+		return err
+	}
+	c.emit(code.OpConstant, c.addConstant(&object.Integer{Value: 1}))
+	c.mark(node.Token)
+	switch node.Operator {
+	case "++":
+		c.emit(code.OpAdd)
+	case "--":
+		c.emit(code.OpSub)
+	default:
+		return c.errorf(node.Token, "Unknown operator: %s", node.Operator)
+	}
+	c.emit(code.OpDup)
+	return c.storeSymbol(name)
+}
+
+func (c *Compiler) compileAssignEqual(node *ast.AssignEqual) error {
+	if err := c.Compile(node.Left); err != nil {
+		return err
+	}
+	if err := c.Compile(node.Value); err != nil {
+		return err
+	}
+	op, ok := infixOpcodes[strings.TrimSuffix(node.Token.Literal, "=")]
+	if !ok {
+		return c.errorf(node.Token, "unknown operator: %s", node.Token.Literal)
+	}
+	c.mark(node.Token)
+	c.emit(op)
+	c.emit(code.OpDup)
+	return c.storeSymbol(node.Left)
+}
+
+// compileIndexAssignment compiles `a[i] = v` and `a[i] += v`. Like the
+// evaluator, the target and index are evaluated again for compound operators.
+func (c *Compiler) compileIndexAssignment(node *ast.AssignmentExpression) error {
+	target, ok := node.Left.(*ast.IndexExpression)
+	if !ok {
+		if ident, ok := node.Left.(*ast.Identifier); ok {
+			return c.Compile(&ast.Assign{Token: node.Token, Name: ident, Value: node.Value})
+		}
+		return c.errorf(node.Token, "Use an identifier instead of %T", node.Left)
+	}
 
+	if err := c.Compile(target.Left); err != nil {
+		return err
 	}
+	if err := c.Compile(target.Index); err != nil {
+		return err
+	}
+
+	operator := strings.TrimSuffix(node.Token.Literal, "=")
+	if operator != "" {
+		if err := c.Compile(target); err != nil {
+			return err
+		}
+	}
+	if err := c.Compile(node.Value); err != nil {
+		return err
+	}
+	c.mark(node.Token)
+	if operator != "" {
+		op, ok := infixOpcodes[operator]
+		if !ok {
+			return c.errorf(node.Token, "unknown operator: %s", node.Token.Literal)
+		}
+		c.emit(op)
+	}
+	c.emit(code.OpSetIndex)
+	return nil
+}
+
+func (c *Compiler) compileIf(node *ast.IfExpression) error {
+	err := c.Compile(node.Condition)
+	if err != nil {
+		return err
+	}
+
+	// Emit an `OpJumpNotTruthy` with a bogus value
+	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
+
+	err = c.compileBlockValue(node.Consequence)
+	if err != nil {
+		return err
+	}
+
+	// Emit an `OpJump` with a bogus value
+	jumpPos := c.emit(code.OpJump, 9999)
+	c.changeOperand(jumpNotTruthyPos, len(c.currentInstructions()))
+
+	if node.Alternative == nil {
+		c.emit(code.OpNull)
+	} else {
+		err := c.compileBlockValue(node.Alternative)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.changeOperand(jumpPos, len(c.currentInstructions()))
+	return nil
+}
+
+// compileBlockValue compiles a block that leaves the value of its last
+// expression on the stack, as if/else blocks do.
+func (c *Compiler) compileBlockValue(block *ast.BlockStatement) error {
+	err := c.Compile(block)
+	if err != nil {
+		return err
+	}
+	if c.lastInstructionIs(code.OpPop) {
+		c.removeLastInstruction()
+	} else {
+		c.emit(code.OpNull)
+	}
+	return nil
+}
+
+func (c *Compiler) compileWhile(node *ast.WhileExpression) error {
+	start := len(c.currentInstructions())
+	err := c.Compile(node.Condition)
+	if err != nil {
+		return err
+	}
+	exitJump := c.emit(code.OpJumpNotTruthy, 9999)
+
+	l := c.enterLoop(start)
+	err = c.Compile(node.Consequence)
+	if err != nil {
+		return err
+	}
+	c.emit(code.OpJump, start)
+	c.leaveLoop()
+
+	end := len(c.currentInstructions())
+	c.changeOperand(exitJump, end)
+	for _, pos := range l.breaks {
+		c.changeOperand(pos, end)
+	}
+	c.emit(code.OpNull)
+	return nil
+}
+
+func (c *Compiler) compileForIn(node *ast.ForIn) error {
+	if node.Pattern != nil {
+		return c.unsupported(node.Pattern, token.Token{})
+	}
+	err := c.Compile(node.Iterable)
+	if err != nil {
+		return err
+	}
+	c.mark(node.Token)
+	c.emit(code.OpIterInit)
 
+	// Loop variables and lets in the body are scoped to the loop
+	c.symbolTable = NewBlockSymbolTable(c.symbolTable)
+
+	next := c.emit(code.OpIterNext, 9999)
+	c.defineSymbol(c.symbolTable.Define(node.Value))
+	if node.Key != "" {
+		c.defineSymbol(c.symbolTable.Define(node.Key))
+	} else {
+		c.emit(code.OpPop)
+	}
+
+	l := c.enterLoop(next)
+	err = c.Compile(node.Block)
+	if err != nil {
+		return err
+	}
+	c.emit(code.OpJump, next)
+	c.leaveLoop()
+
+	c.symbolTable = c.symbolTable.parent
+
+	end := len(c.currentInstructions())
+	c.changeOperand(next, end)
+	for _, pos := range l.breaks {
+		c.changeOperand(pos, end)
+	}
+	c.emit(code.OpPop) // the iterator
+	c.emit(code.OpNull)
+	return nil
+}
+
+func (c *Compiler) compileFunction(node *ast.FunctionLiteral, name string) error {
+	if node.Variadic {
+		return c.errorf(node.Parameters[len(node.Parameters)-1].Token, "variadic parameters are not supported by the bytecode compiler yet")
+	}
+	c.enterScope()
+	for n := range capturedNames(node.Body) {
+		c.symbolTable.captured[n] = true
+	}
+
+	params := make([]string, len(node.Parameters))
+	required := len(node.Parameters)
+	for i, p := range node.Parameters {
+		params[i] = p.Value
+		c.symbolTable.Define(p.Value)
+		if _, ok := node.Defaults[p.Value]; ok && i < required {
+			required = i
+		}
+	}
+
+	// Missing arguments are left unset; fill in their default values
+	for i, p := range node.Parameters {
+		def, ok := node.Defaults[p.Value]
+		if !ok {
+			continue
+		}
+		skip := c.emit(code.OpSkipIfBound, i, 9999)
+		if err := c.Compile(def); err != nil {
+			return err
+		}
+		c.emit(code.OpSetLocal, i)
+		c.changeSecondOperand(skip, len(c.currentInstructions()))
+	}
+
+	// Parameters captured by closures are moved into cells
+	for i, p := range node.Parameters {
+		if sym, _ := c.symbolTable.Resolve(p.Value); sym.Cell {
+			c.emit(code.OpGetLocal, i)
+			c.emit(code.OpMakeCell)
+			c.emit(code.OpSetLocal, i)
+		}
+	}
+
+	if err := c.Compile(node.Body); err != nil {
+		return err
+	}
+
+	// The value of the last expression is the function's result
+	if c.lastInstructionIs(code.OpPop) {
+		c.replaceLastPopWithReturn()
+	}
+	if !c.lastInstructionIs(code.OpReturnValue) {
+		c.emit(code.OpReturn)
+	}
+
+	freeSymbols := c.symbolTable.FreeSymbols
+	numLocals := c.symbolTable.NumLocals()
+	scope := c.leaveScope()
+
+	for _, s := range freeSymbols {
+		if !s.Cell {
+			return c.errorf(node.Token, "internal error: '%s' is captured but not stored in a cell", s.Name)
+		}
+		if s.Scope == FreeScope {
+			c.emit(code.OpGetFreeCell, s.Index)
+		} else {
+			c.emit(code.OpGetLocal, s.Index)
+		}
+	}
+
+	compiledFn := &object.CompiledFunction{
+		Instructions:  scope.instructions,
+		NumLocals:     numLocals,
+		NumParameters: len(node.Parameters),
+		NumRequired:   required,
+		Parameters:    params,
+		Name:          name,
+		Positions:     scope.positions,
+	}
+	c.emit(code.OpClosure, c.addConstant(compiledFn), len(freeSymbols))
+	return nil
+}
+
+func (c *Compiler) compileCall(node *ast.CallExpression) error {
+	if err := c.Compile(node.Function); err != nil {
+		return err
+	}
+
+	var positional, keywords []ast.Expression
+	for _, arg := range node.Arguments {
+		if kw, ok := arg.(*ast.Assign); ok {
+			keywords = append(keywords, kw)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	for _, arg := range positional {
+		if err := c.Compile(arg); err != nil {
+			return err
+		}
+	}
+	for _, arg := range keywords {
+		kw := arg.(*ast.Assign)
+		c.emit(code.OpConstant, c.addConstant(&object.String{Value: kw.Name.Value}))
+		if err := c.Compile(kw.Value); err != nil {
+			return err
+		}
+	}
+
+	c.markCall(node.Function, node.Token)
+	if len(keywords) > 0 {
+		c.emit(code.OpCallKeywords, len(positional), len(keywords))
+	} else {
+		c.emit(code.OpCall, len(positional))
+	}
+	return nil
+}
+
+func (c *Compiler) compileMethod(node *ast.MethodExpression) error {
+	method, ok := node.Method.(*ast.Identifier)
+	if !ok || len(node.Defaults) > 0 {
+		return c.unsupported(node, node.Token)
+	}
+
+	if err := c.Compile(node.Object); err != nil {
+		return err
+	}
+	for _, arg := range node.Arguments {
+		if err := c.Compile(arg); err != nil {
+			return err
+		}
+	}
+
+	c.mark(method.Token)
+	name := c.addConstant(&object.String{Value: method.Value})
+	c.emit(code.OpMethod, name, len(node.Arguments))
 	return nil
 }
 
 func (c *Compiler) Bytecode() *Bytecode {
 	return &Bytecode{
-		Instructions: c.instructions,
+		Instructions: c.currentInstructions(),
 		Constants:    c.constants,
+		NumLocals:    c.symbolTable.NumLocals(),
+		Globals:      c.symbolTable.GlobalNames(),
+		Positions:    c.scopes[c.scopeIndex].positions,
 	}
 }
 
@@ -115,7 +779,162 @@ func (c *Compiler) addConstant(obj object.VintObject) int {
 
 func (c *Compiler) emit(op code.Opcode, operands ...int) int {
 	ins := code.Make(op, operands...)
-	pos := len(c.instructions)
-	c.instructions = append(c.instructions, ins...)
+	pos := c.addInstruction(ins)
+
+	c.setLastInstruction(op, pos)
+
 	return pos
 }
+
+func (c *Compiler) addInstruction(ins []byte) int {
+	posNewInstruction := len(c.currentInstructions())
+	c.scopes[c.scopeIndex].instructions = append(c.currentInstructions(), ins...)
+	return posNewInstruction
+}
+
+func (c *Compiler) currentInstructions() code.Instructions {
+	return c.scopes[c.scopeIndex].instructions
+}
+
+func (c *Compiler) setLastInstruction(op code.Opcode, pos int) {
+	previous := c.scopes[c.scopeIndex].lastInstruction
+	last := EmittedInstruction{Opcode: op, Position: pos}
+
+	c.scopes[c.scopeIndex].previousInstruction = previous
+	c.scopes[c.scopeIndex].lastInstruction = last
+}
+
+func (c *Compiler) lastInstructionIs(op code.Opcode) bool {
+	if len(c.currentInstructions()) == 0 {
+		return false
+	}
+	return c.scopes[c.scopeIndex].lastInstruction.Opcode == op
+}
+
+func (c *Compiler) removeLastInstruction() {
+	scope := &c.scopes[c.scopeIndex]
+	pos := scope.lastInstruction.Position
+
+	scope.instructions = scope.instructions[:pos]
+	scope.lastInstruction = scope.previousInstruction
+
+	for len(scope.positions) > 0 && scope.positions[len(scope.positions)-1].Offset >= pos {
+		scope.positions = scope.positions[:len(scope.positions)-1]
+	}
+}
+
+func (c *Compiler) replaceLastPopWithReturn() {
+	lastPos := c.scopes[c.scopeIndex].lastInstruction.Position
+	c.replaceInstruction(lastPos, code.Make(code.OpReturnValue))
+	c.scopes[c.scopeIndex].lastInstruction.Opcode = code.OpReturnValue
+}
+
+func (c *Compiler) replaceInstruction(pos int, newInstruction []byte) {
+	ins := c.currentInstructions()
+	copy(ins[pos:], newInstruction)
+}
+
+func (c *Compiler) changeOperand(opPos int, operand int) {
+	op := code.Opcode(c.currentInstructions()[opPos])
+	newInstruction := code.Make(op, operand)
+
+	c.replaceInstruction(opPos, newInstruction)
+}
+
+// changeSecondOperand patches the jump target of OpSkipIfBound
+func (c *Compiler) changeSecondOperand(opPos int, operand int) {
+	ins := c.currentInstructions()
+	op := code.Opcode(ins[opPos])
+	def, _ := code.Lookup(byte(op))
+	operands, _ := code.ReadOperands(def, ins[opPos+1:])
+	c.replaceInstruction(opPos, code.Make(op, operands[0], operand))
+}
+
+func (c *Compiler) enterScope() {
+	c.scopes = append(c.scopes, CompilationScope{instructions: code.Instructions{}})
+	c.scopeIndex++
+	c.symbolTable = NewEnclosedSymbolTable(c.symbolTable)
+}
+
+func (c *Compiler) leaveScope() CompilationScope {
+	scope := c.scopes[c.scopeIndex]
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.scopeIndex--
+	c.symbolTable = c.symbolTable.root().Outer
+	return scope
+}
+
+func (c *Compiler) enterLoop(continueTarget int) *loop {
+	l := &loop{continueTarget: continueTarget}
+	scope := &c.scopes[c.scopeIndex]
+	scope.loops = append(scope.loops, l)
+	return l
+}
+
+func (c *Compiler) leaveLoop() {
+	scope := &c.scopes[c.scopeIndex]
+	scope.loops = scope.loops[:len(scope.loops)-1]
+}
+
+func (c *Compiler) currentLoop() *loop {
+	loops := c.scopes[c.scopeIndex].loops
+	if len(loops) == 0 {
+		return nil
+	}
+	return loops[len(loops)-1]
+}
+
+// mark records that the next instruction was compiled from tok, so runtime
+// errors raised by it can point back at the source.
+func (c *Compiler) mark(tok token.Token) {
+	if tok.Line == 0 {
+		return
+	}
+	scope := &c.scopes[c.scopeIndex]
+	pos := code.Position{
+		Offset: len(scope.instructions),
+		File:   tok.File,
+		Line:   tok.Line,
+		Column: tok.Column,
+	}
+	if n := len(scope.positions); n > 0 && scope.positions[n-1].Offset == pos.Offset {
+		scope.positions[n-1] = pos
+		return
+	}
+	scope.positions = append(scope.positions, pos)
+}
+
+// markCall points calls at the callee name rather than the parenthesis
+func (c *Compiler) markCall(callee ast.Expression, fallback token.Token) {
+	if ident, ok := callee.(*ast.Identifier); ok {
+		c.mark(ident.Token)
+		return
+	}
+	c.mark(fallback)
+}
+
+func (c *Compiler) errorf(tok token.Token, format string, a ...any) error {
+	msg := fmt.Sprintf(format, a...)
+	if tok.Line > 0 {
+		return fmt.Errorf("line %d: %s", tok.Line, msg)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+func (c *Compiler) unsupported(node ast.Node, tok token.Token) error {
+	if tok.Line == 0 {
+		// Every node keeps the token it starts with in a Token field
+		v := reflect.Indirect(reflect.ValueOf(node))
+		if v.Kind() == reflect.Struct {
+			if f := v.FieldByName("Token"); f.IsValid() {
+				tok, _ = f.Interface().(token.Token)
+			}
+		}
+	}
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+	return c.errorf(tok, "%s is not supported by the bytecode compiler yet", name)
+}
+
+func (c *Compiler) unsupportedOptional(tok token.Token) error {
+	return c.errorf(tok, "optional chaining (?.) is not supported by the bytecode compiler yet")
+}
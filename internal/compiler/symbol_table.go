@@ -0,0 +1,180 @@
+package compiler
+
+type SymbolScope string
+
+const (
+	GlobalScope SymbolScope = "GLOBAL"
+	LocalScope  SymbolScope = "LOCAL"
+	FreeScope   SymbolScope = "FREE"
+)
+
+type Symbol struct {
+	Name     string
+	Scope    SymbolScope
+	Index    int
+	Cell     bool // the slot holds an *object.Cell shared with closures
+	Constant bool
+}
+
+// SymbolTable resolves names for one block. Every function gets a root table;
+// for..in bodies get a nested block table that shares the function's local
+// slots, so that loop variables do not leak out of the loop.
+type SymbolTable struct {
+	Outer *SymbolTable // innermost table of the enclosing function
+
+	parent *SymbolTable // enclosing block of the same function
+	store  map[string]Symbol
+
+	// Only used on root tables
+	numDefinitions int
+	numGlobals     *int
+	globalNames    *[]string
+	FreeSymbols    []Symbol
+	captured       map[string]bool // names used inside nested functions
+}
+
+// NewSymbolTable returns the table for the top level of a program.
+func NewSymbolTable() *SymbolTable {
+	numGlobals := 0
+	globalNames := []string{}
+	return &SymbolTable{
+		store:       make(map[string]Symbol),
+		numGlobals:  &numGlobals,
+		globalNames: &globalNames,
+		captured:    map[string]bool{},
+	}
+}
+
+// NewEnclosedSymbolTable returns the root table of a function defined inside outer.
+func NewEnclosedSymbolTable(outer *SymbolTable) *SymbolTable {
+	return &SymbolTable{
+		Outer:    outer,
+		store:    make(map[string]Symbol),
+		captured: map[string]bool{},
+	}
+}
+
+// NewBlockSymbolTable returns a table for a nested block of the same function.
+func NewBlockSymbolTable(parent *SymbolTable) *SymbolTable {
+	return &SymbolTable{
+		Outer:  parent.Outer,
+		parent: parent,
+		store:  make(map[string]Symbol),
+	}
+}
+
+func (s *SymbolTable) root() *SymbolTable {
+	for s.parent != nil {
+		s = s.parent
+	}
+	return s
+}
+
+func (s *SymbolTable) isGlobal() bool {
+	return s.Outer == nil && s.parent == nil
+}
+
+// NumLocals is the number of local slots the function needs.
+func (s *SymbolTable) NumLocals() int {
+	return s.root().numDefinitions
+}
+
+// GlobalNames lists the global slots by index.
+func (s *SymbolTable) GlobalNames() []string {
+	t := s
+	for t.Outer != nil || t.parent != nil {
+		if t.parent != nil {
+			t = t.parent
+		} else {
+			t = t.Outer
+		}
+	}
+	return *t.globalNames
+}
+
+func (s *SymbolTable) Define(name string) Symbol {
+	return s.define(name, false)
+}
+
+func (s *SymbolTable) DefineConst(name string) Symbol {
+	return s.define(name, true)
+}
+
+func (s *SymbolTable) define(name string, constant bool) Symbol {
+	if s.isGlobal() {
+		if sym, ok := s.store[name]; ok {
+			// Redeclaring a global reuses its slot
+			sym.Constant = constant
+			s.store[name] = sym
+			return sym
+		}
+		sym := Symbol{Name: name, Scope: GlobalScope, Index: *s.numGlobals, Constant: constant}
+		*s.numGlobals++
+		*s.globalNames = append(*s.globalNames, name)
+		s.store[name] = sym
+		return sym
+	}
+
+	root := s.root()
+	sym := Symbol{
+		Name:     name,
+		Scope:    LocalScope,
+		Index:    root.numDefinitions,
+		Cell:     root.captured[name],
+		Constant: constant,
+	}
+	root.numDefinitions++
+	s.store[name] = sym
+	return sym
+}
+
+// DeclareGlobal reserves a global slot for a name that is not defined yet.
+// Functions may refer to globals that are defined further down the file,
+// and the VM fills slots named after builtins with the builtin itself.
+func (s *SymbolTable) DeclareGlobal(name string) Symbol {
+	t := s
+	for !t.isGlobal() {
+		if t.parent != nil {
+			t = t.parent
+		} else {
+			t = t.Outer
+		}
+	}
+	if sym, ok := t.store[name]; ok {
+		return sym
+	}
+	return t.Define(name)
+}
+
+func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
+	for t := s; t != nil; t = t.parent {
+		if sym, ok := t.store[name]; ok {
+			return sym, true
+		}
+	}
+
+	root := s.root()
+	if root.Outer == nil {
+		return Symbol{}, false
+	}
+
+	sym, ok := root.Outer.Resolve(name)
+	if !ok || sym.Scope == GlobalScope {
+		return sym, ok
+	}
+	return root.defineFree(sym), true
+}
+
+func (s *SymbolTable) defineFree(original Symbol) Symbol {
+	s.FreeSymbols = append(s.FreeSymbols, original)
+
+	sym := Symbol{
+		Name:     original.Name,
+		Scope:    FreeScope,
+		Index:    len(s.FreeSymbols) - 1,
+		Cell:     true,
+		Constant: original.Constant,
+	}
+	s.store[original.Name] = sym
+	return sym
+}
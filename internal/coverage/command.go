@@ -0,0 +1,83 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/repl"
+	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/toolkit"
+)
+
+// Main runs `vint --cover` and returns the exit code:
+//
+//	vint --cover out.cov file.vint [args]
+//
+// The program runs as with `vint file.vint`. When it has finished a
+// summary is written to out, and the reports to out.cov and out.html.
+func Main(args []string, out io.Writer) int {
+	if len(args) < 2 || strings.HasSuffix(args[0], ".vint") || !strings.HasSuffix(args[1], ".vint") {
+		fmt.Fprintln(out, styles.ErrorStyle.Render("Usage: vint --cover out.cov file.vint [args]"))
+		return 2
+	}
+	report, file := args[0], args[1]
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	toolkit.CLI_ARGS = append(toolkit.CLI_ARGS, args[2:]...)
+	if dir, err := filepath.Abs(filepath.Dir(file)); err == nil {
+		evaluator.AddSearchPath(dir)
+	}
+
+	profile := New()
+	evaluator.SetCoverage(profile)
+	repl.ReadWithFilename(string(contents), file)
+	evaluator.SetCoverage(nil)
+
+	fmt.Fprintln(out)
+	if err := Write(out, profile.Files(), report); err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	return 0
+}
+
+// Write writes the summary of files to out, the LCOV tracefile to report
+// and the HTML page next to it, named like report with an .html
+// extension.
+func Write(out io.Writer, files []*File, report string) error {
+	if err := WriteSummary(out, files); err != nil {
+		return err
+	}
+	page := strings.TrimSuffix(report, filepath.Ext(report)) + ".html"
+	if page == report {
+		page += ".html"
+	}
+	if err := writeFile(report, files, WriteLCOV); err != nil {
+		return err
+	}
+	if err := writeFile(page, files, WriteHTML); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, styles.HelpStyle.Render(fmt.Sprintf("Wrote the LCOV report to %s and the HTML report to %s", report, page)))
+	return nil
+}
+
+func writeFile(name string, files []*File, write func(io.Writer, []*File) error) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f, files); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
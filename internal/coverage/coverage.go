@@ -0,0 +1,326 @@
+// Package coverage measures which lines of a Vint program ran and which
+// branches of its if, switch and match expressions were taken, and
+// reports it as a summary, an LCOV file and an HTML page.
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// position is where a statement or branching expression starts.
+type position struct {
+	Line   int
+	Column int
+}
+
+// branchKey is one branch of the expression at a position.
+type branchKey struct {
+	position
+	Branch int
+}
+
+// Profile records the statements and branches a program runs. It is an
+// evaluator.Coverage.
+type Profile struct {
+	mu         sync.Mutex
+	statements map[string]map[position]int  // runs by file and position
+	branches   map[string]map[branchKey]int // times taken by file
+}
+
+// New returns an empty profile.
+func New() *Profile {
+	return &Profile{
+		statements: map[string]map[position]int{},
+		branches:   map[string]map[branchKey]int{},
+	}
+}
+
+// Statement records that stmt ran.
+func (p *Profile) Statement(stmt ast.Statement) {
+	tok, ok := tokenOf(stmt)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	runs, ok := p.statements[tok.File]
+	if !ok {
+		runs = map[position]int{}
+		p.statements[tok.File] = runs
+	}
+	runs[position{tok.Line, tok.Column}]++
+}
+
+// Branch records that a branch of node was taken.
+func (p *Profile) Branch(node ast.Expression, branch int) {
+	tok, ok := tokenOf(node)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	taken, ok := p.branches[tok.File]
+	if !ok {
+		taken = map[branchKey]int{}
+		p.branches[tok.File] = taken
+	}
+	taken[branchKey{position{tok.Line, tok.Column}, branch}]++
+}
+
+// File is the coverage of one source file.
+type File struct {
+	Name     string // as the program named it
+	Source   []string
+	Lines    []Line   // the lines with statements, in order
+	Branches []Branch // in order of position
+}
+
+// Line is a line with statements on it. Hits is how many times the
+// statement that ran least often ran, so a line only counts as covered
+// when all its statements ran; Partial is set when only some did.
+type Line struct {
+	Line    int
+	Hits    int
+	Partial bool
+}
+
+// Branch is one way an if, switch or match can go. Point numbers the
+// expressions with branches in a file, and Branch the ways each one can
+// go, as described for evaluator.Coverage.
+type Branch struct {
+	Line   int
+	Column int
+	Point  int
+	Branch int
+	Taken  int
+}
+
+// Covered counts the covered lines and taken branches of a file, and how
+// many there are.
+func (f *File) Covered() (lines, totalLines, branches, totalBranches int) {
+	for _, l := range f.Lines {
+		if l.Hits > 0 {
+			lines++
+		}
+	}
+	for _, b := range f.Branches {
+		if b.Taken > 0 {
+			branches++
+		}
+	}
+	return lines, len(f.Lines), branches, len(f.Branches)
+}
+
+// Files returns the coverage of every file the program ran a statement
+// of, in order of name. Each file is parsed again to find what could have
+// run.
+func (p *Profile) Files() []*File {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var files []*File
+	for name := range p.statements {
+		files = append(files, p.file(name))
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files
+}
+
+func (p *Profile) file(name string) *File {
+	f := &File{Name: name}
+	statements := map[position]int{}
+	branches := map[branchKey]int{}
+	var points []position
+
+	if source, err := os.ReadFile(name); err == nil {
+		f.Source = strings.Split(strings.TrimSuffix(string(source), "\n"), "\n")
+		program := parser.New(lexer.NewWithFilename(string(source), name)).ParseProgram()
+		walk(reflect.ValueOf(program), map[uintptr]bool{}, func(node ast.Node) {
+			switch node := node.(type) {
+			case *ast.Program:
+				addStatements(statements, node.Statements)
+			case *ast.BlockStatement:
+				addStatements(statements, node.Statements)
+			case *ast.IfExpression:
+				points = addBranches(branches, points, node, 2)
+			case *ast.SwitchExpression:
+				count := len(node.Choices) + 1
+				for _, c := range node.Choices {
+					if c.Default {
+						count--
+						break
+					}
+				}
+				points = addBranches(branches, points, node, count)
+			case *ast.MatchExpression:
+				count := len(node.Cases) + 1
+				for _, c := range node.Cases {
+					if ident, ok := c.Pattern.(*ast.Identifier); ok && ident.Value == "_" {
+						count--
+						break
+					}
+				}
+				points = addBranches(branches, points, node, count)
+			}
+		})
+	}
+
+	// What ran is covered even if the file has changed since
+	for pos, runs := range p.statements[name] {
+		statements[pos] = runs
+	}
+	for key, taken := range p.branches[name] {
+		if _, ok := branches[key]; !ok {
+			points = append(points, key.position)
+		}
+		branches[key] = taken
+	}
+
+	byLine := map[int]*Line{}
+	for pos, runs := range statements {
+		l, ok := byLine[pos.Line]
+		if !ok {
+			byLine[pos.Line] = &Line{Line: pos.Line, Hits: runs}
+			continue
+		}
+		if (runs == 0) != (l.Hits == 0) {
+			l.Partial = true
+		}
+		if runs < l.Hits {
+			l.Hits = runs
+		}
+	}
+	for _, l := range byLine {
+		f.Lines = append(f.Lines, *l)
+	}
+	sort.Slice(f.Lines, func(i, j int) bool { return f.Lines[i].Line < f.Lines[j].Line })
+
+	sort.Slice(points, func(i, j int) bool { return before(points[i], points[j]) })
+	number := map[position]int{}
+	for _, pos := range points {
+		if _, ok := number[pos]; !ok {
+			number[pos] = len(number)
+		}
+	}
+	for key, taken := range branches {
+		f.Branches = append(f.Branches, Branch{Line: key.Line, Column: key.Column, Point: number[key.position], Branch: key.Branch, Taken: taken})
+	}
+	sort.Slice(f.Branches, func(i, j int) bool {
+		a, b := f.Branches[i], f.Branches[j]
+		if a.Point != b.Point {
+			return a.Point < b.Point
+		}
+		return a.Branch < b.Branch
+	})
+	return f
+}
+
+func before(a, b position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+func addStatements(statements map[position]int, stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if tok, ok := tokenOf(stmt); ok {
+			statements[position{tok.Line, tok.Column}] = 0
+		}
+	}
+}
+
+func addBranches(branches map[branchKey]int, points []position, node ast.Node, count int) []position {
+	tok, ok := tokenOf(node)
+	if !ok {
+		return points
+	}
+	pos := position{tok.Line, tok.Column}
+	for i := 0; i < count; i++ {
+		branches[branchKey{pos, i}] = 0
+	}
+	return append(points, pos)
+}
+
+// tokenOf returns the Token a node starts with. Nodes without one, or
+// without a position, are not counted.
+func tokenOf(node ast.Node) (token.Token, bool) {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return token.Token{}, false
+	}
+	field := v.Elem().FieldByName("Token")
+	if !field.IsValid() {
+		return token.Token{}, false
+	}
+	tok, ok := field.Interface().(token.Token)
+	if !ok || tok.Line == 0 {
+		return token.Token{}, false
+	}
+	return tok, true
+}
+
+var nodeType = reflect.TypeOf((*ast.Node)(nil)).Elem()
+
+// walk calls visit for every node reachable from v. The syntax tree has
+// many node types, so it is walked through reflection rather than a type
+// switch that would need to know them all.
+func walk(v reflect.Value, seen map[uintptr]bool, visit func(ast.Node)) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			walk(v.Elem(), seen, visit)
+		}
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		if v.Type().Implements(nodeType) {
+			visit(v.Interface().(ast.Node))
+		}
+		walk(v.Elem(), seen, visit)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walk(v.Field(i), seen, visit)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), seen, visit)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walk(iter.Key(), seen, visit)
+			walk(iter.Value(), seen, visit)
+		}
+	}
+}
+
+// displayName shows a file relative to the current directory when it is
+// below it.
+func displayName(file string) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return file
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return file
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return abs
+}
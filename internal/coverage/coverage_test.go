@@ -0,0 +1,103 @@
+package coverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const library = `package shapes {
+    let area = func(w, h) {
+        if (w < 0) {
+            return 0
+        }
+        return w * h
+    }
+    let unused = func() { return 1 }
+}
+`
+
+const program = `import shapes
+let size = func(n) {
+    switch (n) {
+        case 1 {
+            return "small"
+        }
+        default {
+            return "big"
+        }
+    }
+}
+let a = shapes.area(2, 3)
+let s = size(5)
+if (a > 1) { s = size(1) }
+`
+
+func writeFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, source := range map[string]string{"shapes.vint": library, "main.vint": program} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCoverCommand(t *testing.T) {
+	dir := writeFiles(t)
+	report := filepath.Join(dir, "out.cov")
+	var out bytes.Buffer
+	if code := Main([]string{report, filepath.Join(dir, "main.vint")}, &out); code != 0 {
+		t.Fatalf("exit code %d\n%s", code, out.String())
+	}
+	for _, want := range []string{"main.vint", "8/8 100.0%", "3/4  75.0%", "shapes.vint", "4/6  66.7%", "1/2  50.0%", "Total"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summary is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	lcov, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := strings.Split(string(lcov), "end_of_record\n")
+	if len(records) != 3 || !strings.HasPrefix(records[0], "TN:\nSF:") {
+		t.Fatalf("lcov:\n%s", lcov)
+	}
+	shapes := records[1]
+	if !strings.Contains(records[0], "main.vint") {
+		shapes = records[0]
+	}
+	want := "BRDA:3,0,0,0\nBRDA:3,0,1,1\nBRF:2\nBRH:1\n" +
+		"DA:1,1\nDA:2,1\nDA:3,1\nDA:4,0\nDA:6,1\nDA:8,0\nLF:6\nLH:4\n"
+	if !strings.HasSuffix(shapes, want) {
+		t.Errorf("shapes.vint record:\n%s\nwant it to end with:\n%s", shapes, want)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "out.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`class="line missed"`,
+		`class="line partial" title="branch 0.0 taken 0 times`,
+		`<span class="num">6</span><span class="hits">1</span>        return w * h</span>`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("page is missing %s", want)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var out bytes.Buffer
+	if code := Main([]string{"main.vint"}, &out); code != 2 {
+		t.Errorf("exit code %d", code)
+	}
+	if code := Main([]string{"main.vint", "out.cov"}, &out); code != 2 {
+		t.Errorf("exit code %d", code)
+	}
+}
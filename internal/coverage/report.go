@@ -0,0 +1,140 @@
+package coverage
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// WriteSummary writes a table of the line and branch coverage of each
+// file, and of all of them together.
+func WriteSummary(w io.Writer, files []*File) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-40s %17s %17s\n", "File", "Lines", "Branches")
+	var lines, totalLines, branches, totalBranches int
+	for _, f := range files {
+		l, tl, br, tb := f.Covered()
+		lines, totalLines, branches, totalBranches = lines+l, totalLines+tl, branches+br, totalBranches+tb
+		fmt.Fprintf(&b, "%-40s %17s %17s\n", displayName(f.Name), ratio(l, tl), ratio(br, tb))
+	}
+	fmt.Fprintf(&b, "%-40s %17s %17s\n", "Total", ratio(lines, totalLines), ratio(branches, totalBranches))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func ratio(covered, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d %5.1f%%", covered, total, percent(covered, total))
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(covered) / float64(total)
+}
+
+// WriteLCOV writes the coverage as an LCOV tracefile, the format read by
+// genhtml and most CI coverage services.
+func WriteLCOV(w io.Writer, files []*File) error {
+	var b strings.Builder
+	b.WriteString("TN:\n")
+	for _, f := range files {
+		fmt.Fprintf(&b, "SF:%s\n", displayName(f.Name))
+		taken := map[int]int{} // by point, to tell points that never ran
+		for _, br := range f.Branches {
+			taken[br.Point] += br.Taken
+		}
+		for _, br := range f.Branches {
+			count := "-"
+			if taken[br.Point] > 0 {
+				count = fmt.Sprint(br.Taken)
+			}
+			fmt.Fprintf(&b, "BRDA:%d,%d,%d,%s\n", br.Line, br.Point, br.Branch, count)
+		}
+		lines, totalLines, branches, totalBranches := f.Covered()
+		fmt.Fprintf(&b, "BRF:%d\nBRH:%d\n", totalBranches, branches)
+		for _, l := range f.Lines {
+			fmt.Fprintf(&b, "DA:%d,%d\n", l.Line, l.Hits)
+		}
+		fmt.Fprintf(&b, "LF:%d\nLH:%d\n", totalLines, lines)
+		b.WriteString("end_of_record\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+const htmlStyle = `body { font-family: sans-serif; margin: 2em; }
+table.summary td, table.summary th { padding: 0.2em 1em; text-align: left; }
+pre { font-size: 13px; line-height: 1.4; }
+.line { display: block; white-space: pre; }
+.num, .hits { display: inline-block; text-align: right; color: #888; }
+.num { width: 4em; padding-right: 1em; }
+.hits { width: 5em; padding-right: 1em; }
+.covered { background: #dfd; }
+.partial { background: #ffc; }
+.missed { background: #fdd; }`
+
+// WriteHTML writes the coverage as a page with a summary and the source
+// of each file, its lines shaded by whether they ran. The branches of a
+// line are shown when the pointer rests on it.
+func WriteHTML(w io.Writer, files []*File) error {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Vint coverage</title>\n")
+	fmt.Fprintf(&b, "<style>\n%s\n</style>\n</head>\n<body>\n<h1>Vint coverage</h1>\n", htmlStyle)
+
+	b.WriteString("<table class=\"summary\">\n<tr><th>File</th><th>Lines</th><th>Branches</th></tr>\n")
+	for i, f := range files {
+		l, tl, br, tb := f.Covered()
+		fmt.Fprintf(&b, "<tr><td><a href=\"#file%d\">%s</a></td><td>%s</td><td>%s</td></tr>\n",
+			i, html.EscapeString(displayName(f.Name)), ratio(l, tl), ratio(br, tb))
+	}
+	b.WriteString("</table>\n")
+
+	for i, f := range files {
+		fmt.Fprintf(&b, "<h2 id=\"file%d\">%s</h2>\n<pre>", i, html.EscapeString(displayName(f.Name)))
+		lines := map[int]Line{}
+		for _, l := range f.Lines {
+			lines[l.Line] = l
+		}
+		branches := map[int][]string{}
+		for _, br := range f.Branches {
+			branches[br.Line] = append(branches[br.Line], fmt.Sprintf("branch %d.%d taken %d times", br.Point, br.Branch, br.Taken))
+		}
+		for n, text := range f.Source {
+			n++
+			class, hits := "", ""
+			if l, ok := lines[n]; ok {
+				switch {
+				case l.Partial:
+					class = "partial"
+				case l.Hits > 0:
+					class = "covered"
+				default:
+					class = "missed"
+				}
+				hits = fmt.Sprint(l.Hits)
+			}
+			title := ""
+			if notes, ok := branches[n]; ok {
+				title = fmt.Sprintf(" title=\"%s\"", html.EscapeString(strings.Join(notes, "\n")))
+				if class == "covered" {
+					for _, br := range f.Branches {
+						if br.Line == n && br.Taken == 0 {
+							class = "partial"
+						}
+					}
+				}
+			}
+			fmt.Fprintf(&b, "<span class=\"line %s\"%s><span class=\"num\">%d</span><span class=\"hits\">%s</span>%s</span>",
+				class, title, n, hits, html.EscapeString(text))
+		}
+		b.WriteString("</pre>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,501 @@
+package debugger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/toolkit"
+)
+
+// The Debug Adapter Protocol frames JSON messages with a Content-Length
+// header, like the Language Server Protocol, but has messages of its own:
+// requests from the client, and responses and events from the server.
+
+type request struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type response struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type event struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+// readRequest reads one message framed by a Content-Length header.
+func readRequest(r *bufio.Reader) (*request, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// The subset of the protocol types the server uses.
+
+type source struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type stackFrame struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Source source `json:"source"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type dapScope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+type dapBreakpoint struct {
+	ID       int    `json:"id"`
+	Verified bool   `json:"verified"`
+	Line     int    `json:"line"`
+	Source   source `json:"source"`
+}
+
+type launchArguments struct {
+	Program     string   `json:"program"`
+	Args        []string `json:"args"`
+	StopOnEntry bool     `json:"stopOnEntry"`
+}
+
+type setBreakpointsArguments struct {
+	Source      source `json:"source"`
+	Breakpoints []struct {
+		Line      int    `json:"line"`
+		Condition string `json:"condition"`
+	} `json:"breakpoints"`
+}
+
+// Server is a debug adapter for one client connection. It debugs the
+// program named by the client's launch request.
+type Server struct {
+	in      *bufio.Reader
+	out     io.Writer
+	writeMu sync.Mutex // responses and events are written from several goroutines
+	seq     int
+
+	session    *Session
+	launch     *launchArguments
+	configured bool
+	done       <-chan struct{}
+
+	// While the program is stopped: where, and what the variables
+	// references given to the client stand for, an environment or a
+	// value with children. They are forgotten when it runs again.
+	mu      sync.Mutex
+	stop    *Stop
+	handles []any
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	s := &Server{in: bufio.NewReader(in), out: out}
+	s.session = NewSession(s.stopped)
+	return s
+}
+
+// Serve handles requests until the client disconnects, and returns the
+// exit code.
+func (s *Server) Serve() int {
+	for {
+		req, err := readRequest(s.in)
+		if err != nil {
+			s.session.Terminate()
+			if errors.Is(err, io.EOF) {
+				return 0
+			}
+			return 1
+		}
+		if req.Type != "request" {
+			continue
+		}
+		if !s.handle(req) {
+			return 0
+		}
+	}
+}
+
+// handle answers a request, and returns false once the client has
+// disconnected.
+func (s *Server) handle(req *request) bool {
+	var body any
+	var err error
+	switch req.Command {
+	case "initialize":
+		body = map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsConditionalBreakpoints":   true,
+			"supportsEvaluateForHovers":        true,
+			"supportsTerminateRequest":         true,
+		}
+		s.respond(req, body, nil)
+		s.event("initialized", nil)
+		return true
+	case "launch":
+		var args launchArguments
+		if err = json.Unmarshal(req.Arguments, &args); err == nil && args.Program == "" {
+			err = errors.New("launch needs the program to debug")
+		}
+		if err == nil {
+			if _, statErr := os.Stat(args.Program); statErr != nil {
+				err = statErr
+			}
+		}
+		if err == nil {
+			s.launch = &args
+		}
+	case "setBreakpoints":
+		body, err = s.setBreakpoints(req.Arguments)
+	case "setExceptionBreakpoints":
+		body = map[string]any{"breakpoints": []any{}}
+	case "configurationDone":
+		s.configured = true
+	case "threads":
+		body = threads()
+	case "stackTrace":
+		body, err = s.stackTrace(req.Arguments)
+	case "scopes":
+		body, err = s.scopes(req.Arguments)
+	case "variables":
+		body, err = s.variables(req.Arguments)
+	case "evaluate":
+		body, err = s.evaluate(req.Arguments)
+	case "continue", "next", "stepIn", "stepOut":
+		// Answer before the program runs, so the response comes before
+		// the event of the next stop
+		s.forget()
+		s.respond(req, map[string]any{"allThreadsContinued": true}, nil)
+		if err := s.resume(req.Command); err != nil {
+			s.output("stderr", err.Error()+"\n")
+		}
+		return true
+	case "pause":
+		s.session.Pause()
+	case "terminate":
+		s.session.Terminate()
+	case "disconnect":
+		s.session.Terminate()
+		s.respond(req, nil, nil)
+		return false
+	default:
+		err = fmt.Errorf("unsupported request %s", req.Command)
+	}
+	s.respond(req, body, err)
+
+	if s.launch != nil && s.configured && s.done == nil {
+		s.start()
+	}
+	return true
+}
+
+func (s *Server) resume(command string) error {
+	switch command {
+	case "next":
+		return s.session.Next()
+	case "stepIn":
+		return s.session.StepIn()
+	case "stepOut":
+		return s.session.StepOut()
+	}
+	return s.session.Continue()
+}
+
+// start runs the program once the client has launched and configured it.
+// What it prints is sent to the client as output events.
+func (s *Server) start() {
+	toolkit.CLI_ARGS = append(toolkit.CLI_ARGS, s.launch.Args...)
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		s.output("stderr", err.Error()+"\n")
+		s.event("terminated", nil)
+		return
+	}
+	os.Stdout = w
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		buf := make([]byte, 4096)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				s.output("stdout", string(buf[:n]))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	s.done = s.session.Start(s.launch.Program, s.launch.StopOnEntry)
+	go func() {
+		<-s.done
+		os.Stdout = stdout
+		w.Close()
+		<-forwarded
+		r.Close()
+		s.event("exited", map[string]any{"exitCode": 0})
+		s.event("terminated", nil)
+	}()
+}
+
+// stopped is called by the session when the program stops.
+func (s *Server) stopped(stop *Stop) {
+	s.mu.Lock()
+	s.stop = stop
+	s.handles = nil
+	s.mu.Unlock()
+
+	if stop.Err != nil {
+		s.output("stderr", stop.Err.Error()+"\n")
+	}
+	body := map[string]any{
+		"reason":            stop.Reason,
+		"threadId":          stop.Thread,
+		"allThreadsStopped": true,
+	}
+	if stop.Breakpoint != nil {
+		body["hitBreakpointIds"] = []int{stop.Breakpoint.ID}
+	}
+	s.event("stopped", body)
+}
+
+// forget drops what the client was told about the stopped program.
+func (s *Server) forget() {
+	s.mu.Lock()
+	s.stop = nil
+	s.handles = nil
+	s.mu.Unlock()
+}
+
+func (s *Server) setBreakpoints(raw json.RawMessage) (any, error) {
+	var args setBreakpointsArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	file := args.Source.Path
+	if file == "" {
+		return nil, errors.New("setBreakpoints needs a source path")
+	}
+	s.session.ClearBreakpoints(file)
+	breakpoints := []dapBreakpoint{}
+	for _, b := range args.Breakpoints {
+		bp := s.session.SetBreakpoint(file, b.Line, b.Condition)
+		breakpoints = append(breakpoints, dapBreakpoint{
+			ID: bp.ID, Verified: true, Line: bp.Line,
+			Source: source{Name: filepath.Base(bp.File), Path: bp.File},
+		})
+	}
+	return map[string]any{"breakpoints": breakpoints}, nil
+}
+
+// threads lists the threads of the program: the program itself, and the
+// goroutines started with `go` and by async functions.
+func threads() any {
+	list := []map[string]any{}
+	for _, thread := range evaluator.DebugThreads() {
+		name := "main"
+		if thread != evaluator.MainThread {
+			name = fmt.Sprintf("goroutine %d", thread)
+		}
+		list = append(list, map[string]any{"id": thread, "name": name})
+	}
+	return map[string]any{"threads": list}
+}
+
+// stackTrace gives the calls of the thread that stopped. The other
+// threads are waiting to run, and have no frames to show.
+func (s *Server) stackTrace(raw json.RawMessage) (any, error) {
+	var args struct {
+		ThreadID int `json:"threadId"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return nil, ErrRunning
+	}
+	frames := []stackFrame{}
+	if args.ThreadID != s.stop.Thread {
+		return map[string]any{"stackFrames": frames, "totalFrames": 0}, nil
+	}
+	for n := range s.stop.Stack {
+		frame, _ := s.stop.Frame(n)
+		path := absPath(frame.File)
+		frames = append(frames, stackFrame{
+			ID: n, Name: frame.Function,
+			Source: source{Name: filepath.Base(path), Path: path},
+			Line:   frame.Line, Column: frame.Column,
+		})
+	}
+	return map[string]any{"stackFrames": frames, "totalFrames": len(frames)}, nil
+}
+
+func (s *Server) scopes(raw json.RawMessage) (any, error) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return nil, ErrRunning
+	}
+	frame, ok := s.stop.Frame(args.FrameID)
+	if !ok {
+		return nil, fmt.Errorf("there is no frame %d", args.FrameID)
+	}
+	list := []dapScope{}
+	for _, sc := range scopes(frame.Env) {
+		list = append(list, dapScope{Name: sc.Name, VariablesReference: s.reference(sc.Env)})
+	}
+	return map[string]any{"scopes": list}, nil
+}
+
+func (s *Server) variables(raw json.RawMessage) (any, error) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if args.VariablesReference < 1 || args.VariablesReference > len(s.handles) {
+		return nil, fmt.Errorf("unknown variables reference %d", args.VariablesReference)
+	}
+	var vars []variable
+	switch target := s.handles[args.VariablesReference-1].(type) {
+	case *object.Environment:
+		vars = envVariables(target)
+	case object.VintObject:
+		vars = children(target)
+	}
+	list := []dapVariable{}
+	for _, v := range vars {
+		list = append(list, s.variable(v.Name, v.Value))
+	}
+	return map[string]any{"variables": list}, nil
+}
+
+func (s *Server) evaluate(raw json.RawMessage) (any, error) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	value, err := s.session.Evaluate(args.Expression, args.FrameID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.variable("", value)
+	return map[string]any{"result": v.Value, "type": v.Type, "variablesReference": v.VariablesReference}, nil
+}
+
+// variable describes a value, with a reference to its children if it has
+// any. s.mu must be held.
+func (s *Server) variable(name string, value object.VintObject) dapVariable {
+	v := dapVariable{Name: name, Value: show(value, 500)}
+	if value != nil {
+		v.Type = string(value.Type())
+		if hasChildren(value) {
+			v.VariablesReference = s.reference(value)
+		}
+	}
+	return v
+}
+
+// reference returns a variables reference for an environment or a value.
+// s.mu must be held.
+func (s *Server) reference(target any) int {
+	s.handles = append(s.handles, target)
+	return len(s.handles)
+}
+
+func (s *Server) output(category, text string) {
+	s.event("output", map[string]any{"category": category, "output": text})
+}
+
+func (s *Server) respond(req *request, body any, err error) {
+	resp := response{Type: "response", RequestSeq: req.Seq, Success: err == nil, Command: req.Command, Body: body}
+	if err != nil {
+		resp.Message = err.Error()
+		resp.Body = nil
+	}
+	s.write(func(seq int) any { resp.Seq = seq; return resp })
+}
+
+func (s *Server) event(name string, body any) {
+	s.write(func(seq int) any { return event{Seq: seq, Type: "event", Event: name, Body: body} })
+}
+
+// write numbers and sends a message. The message is made under the lock
+// so that sequence numbers go out in order.
+func (s *Server) write(message func(seq int) any) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.seq++
+	body, err := json.Marshal(message(s.seq))
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(body))
+	s.out.Write(body)
+}
@@ -0,0 +1,343 @@
+package debugger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const program = `let total = 0
+let add = func(a, b) {
+    let sum = a + b
+    return sum
+}
+for i in [1, 2, 3] {
+    total = add(total, i)
+}
+let done = total
+`
+
+func writeProgram(t *testing.T) string {
+	t.Helper()
+	return writeSource(t, program)
+}
+
+func writeSource(t *testing.T, source string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "prog.vint")
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// debugAt runs the terminal debugger on the program with the given
+// commands, one per line, and returns what it printed.
+func debugAt(t *testing.T, commands ...string) string {
+	t.Helper()
+	return debugSource(t, program, commands...)
+}
+
+func debugSource(t *testing.T, source string, commands ...string) string {
+	t.Helper()
+	file := writeSource(t, source)
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(commands, "\n") + "\n")
+	result := make(chan int, 1)
+	go func() { result <- Main([]string{file}, in, &out) }()
+	select {
+	case code := <-result:
+		if code != 0 {
+			t.Fatalf("exit code %d\n%s", code, out.String())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out")
+	}
+	return out.String()
+}
+
+func expectInOrder(t *testing.T, out string, want ...string) {
+	t.Helper()
+	rest := out
+	for _, w := range want {
+		i := strings.Index(rest, w)
+		if i < 0 {
+			t.Fatalf("expected %q after the earlier output in\n%s", w, out)
+		}
+		rest = rest[i+len(w):]
+	}
+}
+
+func TestTerminalBreakpointsAndStepping(t *testing.T) {
+	out := debugAt(t,
+		"break 7 if i == 2",
+		"continue",
+		"print total",
+		"step",
+		"step",
+		"vars",
+		"stack",
+		"out",
+		"print total",
+		"next",
+		"print total = 100",
+		"continue",
+	)
+	expectInOrder(t, out,
+		"<main> at", "prog.vint:1",
+		"Breakpoint 1 at", "prog.vint:7 if i == 2",
+		"Breakpoint 1, <main> at", "prog.vint:7",
+		"(vint) 1\n", // total before the second call
+		"add at", "prog.vint:3",
+		"add at", "prog.vint:4",
+		"Locals:", "a = 1", "b = 2", "sum = 3", "Globals:", "add = func(a, b) {…}", "total = 1",
+		"> #0  add at", "#1  <main> at",
+		"<main> at", "prog.vint:7", // the third time round the loop
+		"(vint) 3\n",
+		"<main> at", "prog.vint:9",
+		"100",
+		"The program has finished.",
+	)
+}
+
+func TestTerminalFrameAndErrors(t *testing.T) {
+	out := debugAt(t,
+		"break 4",
+		"c",
+		"frame 1",
+		"print i",
+		"print nosuchname",
+		"frame 9",
+		"bogus",
+		"delete 4",
+		"breakpoints",
+		"quit",
+	)
+	expectInOrder(t, out,
+		"Breakpoint 1, add at",
+		"<main> at", "prog.vint:7",
+		"(vint) 1\n",
+		"Error:",
+		"Error: there is no frame 9",
+		"Error: unknown command 'bogus'",
+		"Deleted the breakpoint",
+		"No breakpoints",
+	)
+	if strings.Contains(out, "The program has finished.") {
+		t.Errorf("quit let the program finish:\n%s", out)
+	}
+}
+
+func TestConditionErrorStops(t *testing.T) {
+	out := debugAt(t, "break 7 if nosuchname > 1", "c", "q")
+	expectInOrder(t, out, "Error: condition nosuchname > 1:", "Breakpoint 1, <main> at")
+}
+
+func TestGoroutineStack(t *testing.T) {
+	source := `let work = func(n) {
+    let doubled = n * 2
+    return doubled
+}
+let ch = chan
+let worker = func() {
+    let v = work(21)
+    send(ch, v)
+}
+go worker()
+let got = receive(ch)
+let done = got
+`
+	out := debugSource(t, source, "break 2", "continue", "stack", "out", "print v", "continue")
+	expectInOrder(t, out,
+		"Breakpoint 1, goroutine 2, work at", "prog.vint:2",
+		"> #0  work at", "#1  worker at", "prog.vint:7", "#2  <goroutine> at", "prog.vint:10",
+		"goroutine 2, worker at", "prog.vint:8",
+		"(vint) 42\n",
+		"The program has finished.",
+	)
+	if strings.Contains(out, "#3") {
+		t.Errorf("the goroutine has the main program's frames:\n%s", out)
+	}
+}
+
+// dapClient drives a Server over pipes the way an editor would.
+type dapClient struct {
+	t      *testing.T
+	in     io.WriteCloser
+	out    *bufio.Reader
+	seq    int
+	events []map[string]any
+}
+
+func startDAP(t *testing.T) *dapClient {
+	t.Helper()
+	clientToServer, serverIn := io.Pipe()
+	serverOut, serverToClient := io.Pipe()
+	go func() {
+		NewServer(clientToServer, serverToClient).Serve()
+		serverToClient.Close()
+	}()
+	t.Cleanup(func() { serverIn.Close() })
+	return &dapClient{t: t, in: serverIn, out: bufio.NewReader(serverOut)}
+}
+
+// next reads the next message from the server.
+func (c *dapClient) next() map[string]any {
+	c.t.Helper()
+	done := make(chan map[string]any, 1)
+	go func() {
+		var msg map[string]any
+		header, err := readHeader(c.out)
+		if err == nil {
+			body := make([]byte, header)
+			if _, err = io.ReadFull(c.out, body); err == nil {
+				err = json.Unmarshal(body, &msg)
+			}
+		}
+		done <- msg
+	}()
+	select {
+	case msg := <-done:
+		if msg == nil {
+			c.t.Fatal("server closed the connection")
+		}
+		return msg
+	case <-time.After(10 * time.Second):
+		c.t.Fatal("timed out waiting for the server")
+	}
+	return nil
+}
+
+func readHeader(r *bufio.Reader) (int, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return length, nil
+		}
+		fmt.Sscanf(line, "Content-Length: %d", &length)
+	}
+}
+
+// request sends a request and waits for its response, keeping the events
+// that arrive first.
+func (c *dapClient) request(command string, arguments any) map[string]any {
+	c.t.Helper()
+	c.seq++
+	body, _ := json.Marshal(map[string]any{"seq": c.seq, "type": "request", "command": command, "arguments": arguments})
+	fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	for {
+		msg := c.next()
+		if msg["type"] == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		if msg["request_seq"] != float64(c.seq) {
+			c.t.Fatalf("response to %v, want %d", msg["request_seq"], c.seq)
+		}
+		if msg["success"] != true {
+			c.t.Fatalf("%s failed: %v", command, msg["message"])
+		}
+		body, _ := msg["body"].(map[string]any)
+		return body
+	}
+}
+
+// event waits for the named event, returning its body.
+func (c *dapClient) event(name string) map[string]any {
+	c.t.Helper()
+	for {
+		var msg map[string]any
+		if len(c.events) > 0 {
+			msg, c.events = c.events[0], c.events[1:]
+		} else {
+			msg = c.next()
+		}
+		if msg["type"] == "event" && msg["event"] == name {
+			body, _ := msg["body"].(map[string]any)
+			return body
+		}
+	}
+}
+
+func TestDAPSession(t *testing.T) {
+	file := writeProgram(t)
+	c := startDAP(t)
+
+	caps := c.request("initialize", map[string]any{"adapterID": "vint"})
+	if caps["supportsConditionalBreakpoints"] != true {
+		t.Errorf("capabilities: %v", caps)
+	}
+	c.event("initialized")
+	c.request("launch", map[string]any{"program": file})
+	bps := c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": file},
+		"breakpoints": []map[string]any{{"line": 4, "condition": "a == 1"}},
+	})
+	if list := bps["breakpoints"].([]any); len(list) != 1 || list[0].(map[string]any)["verified"] != true {
+		t.Errorf("breakpoints: %v", bps)
+	}
+	c.request("configurationDone", nil)
+
+	stopped := c.event("stopped")
+	if stopped["reason"] != "breakpoint" {
+		t.Fatalf("stopped: %v", stopped)
+	}
+	if threads := c.request("threads", nil)["threads"].([]any); len(threads) != 1 || threads[0].(map[string]any)["name"] != "main" {
+		t.Errorf("threads: %v", threads)
+	}
+	trace := c.request("stackTrace", map[string]any{"threadId": 1})
+	frames := trace["stackFrames"].([]any)
+	top := frames[0].(map[string]any)
+	if len(frames) != 2 || top["name"] != "add" || top["line"] != float64(4) {
+		t.Fatalf("stack: %v", frames)
+	}
+
+	scopes := c.request("scopes", map[string]any{"frameId": 0})["scopes"].([]any)
+	locals := scopes[0].(map[string]any)
+	if locals["name"] != "Locals" {
+		t.Fatalf("scopes: %v", scopes)
+	}
+	vars := c.request("variables", map[string]any{"variablesReference": locals["variablesReference"]})["variables"].([]any)
+	var shown []string
+	for _, v := range vars {
+		v := v.(map[string]any)
+		shown = append(shown, fmt.Sprintf("%s=%s", v["name"], v["value"]))
+	}
+	if got := strings.Join(shown, " "); got != "a=1 b=2 sum=3" {
+		t.Errorf("locals: %s", got)
+	}
+
+	result := c.request("evaluate", map[string]any{"expression": "[a, b]", "frameId": 0})
+	if result["result"] != "[1, 2]" || result["variablesReference"] == float64(0) {
+		t.Errorf("evaluate: %v", result)
+	}
+	elements := c.request("variables", map[string]any{"variablesReference": result["variablesReference"]})["variables"].([]any)
+	if len(elements) != 2 || elements[1].(map[string]any)["value"] != "2" {
+		t.Errorf("elements: %v", elements)
+	}
+
+	c.request("next", map[string]any{"threadId": 1})
+	if stopped := c.event("stopped"); stopped["reason"] != "step" {
+		t.Fatalf("after next: %v", stopped)
+	}
+	top = c.request("stackTrace", map[string]any{"threadId": 1})["stackFrames"].([]any)[0].(map[string]any)
+	if top["name"] != "<main>" || top["line"] != float64(7) {
+		t.Errorf("after next, at %v", top)
+	}
+
+	c.request("continue", map[string]any{"threadId": 1})
+	c.event("terminated")
+	c.request("disconnect", nil)
+}
@@ -0,0 +1,424 @@
+// Package debugger runs a Vint program under the control of a debugger:
+// it stops the program at breakpoints and after steps, and lets the
+// variables and call stack be inspected while it is stopped. The Session
+// does the work; the terminal prompt of `vint debug` and the Debug Adapter
+// Protocol server of `vint debug --dap` drive it.
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/repl"
+)
+
+// Breakpoint stops the program before a statement on its line runs, if
+// its condition, an expression evaluated where the program stopped, is
+// true. A breakpoint without a condition always stops.
+type Breakpoint struct {
+	ID        int
+	File      string // absolute
+	Line      int
+	Condition string
+	Hits      int
+}
+
+// Stop describes where the program stopped and why: "entry" before the
+// first statement, "breakpoint", "step" after a step, or "pause". Thread
+// is the thread that stopped, and Stack its calls; the other threads wait
+// until the program runs again.
+type Stop struct {
+	Reason     string
+	Breakpoint *Breakpoint // for Reason "breakpoint"
+	Thread     int
+	Stack      []evaluator.Frame
+	Err        error // set if the breakpoint's condition could not be evaluated
+}
+
+// Frame returns frame n of the stack, the innermost call being 0.
+func (s *Stop) Frame(n int) (evaluator.Frame, bool) {
+	if n < 0 || n >= len(s.Stack) {
+		return evaluator.Frame{}, false
+	}
+	return s.Stack[len(s.Stack)-1-n], true
+}
+
+type stepMode int
+
+const (
+	modeRun  stepMode = iota // until a breakpoint
+	modeIn                   // to the next statement
+	modeOver                 // to the next statement not in a deeper call
+	modeOut                  // to the next statement after the current call returns
+)
+
+type commandKind int
+
+const (
+	cmdContinue commandKind = iota
+	cmdNext
+	cmdStepIn
+	cmdStepOut
+	cmdTerminate
+	cmdEvaluate
+)
+
+type command struct {
+	kind  commandKind
+	expr  string
+	frame int
+	reply chan evaluation
+}
+
+type evaluation struct {
+	value object.VintObject
+	err   error
+}
+
+// ErrRunning is returned for requests that need the program to be stopped.
+var ErrRunning = errors.New("the program is running")
+
+// Session debugs one run of a program.
+//
+// The program runs on a goroutine of its own. When it stops, the session
+// calls onStop on that goroutine, and the program waits there for
+// Continue, Next, StepIn, StepOut or Terminate. Meanwhile Evaluate runs
+// expressions on the program's goroutine, in the scope of any frame.
+type Session struct {
+	onStop func(*Stop)
+
+	bpMu        sync.Mutex
+	breakpoints map[string]map[int]*Breakpoint // by file and line
+	nextID      int
+
+	// The state of stepping, only used by the program's goroutines while
+	// they hold runMu. Goroutines started by `go` wait on runMu while the
+	// program is stopped. A step goes on in the thread it began in, and
+	// the other threads run until a breakpoint.
+	runMu    sync.Mutex
+	mode     stepMode
+	thread   int // that is stepping
+	depth    int // of its stack when the step began
+	absPaths map[string]string
+	last     map[int]position // where the previous statement of each thread was
+
+	commands   chan command
+	stopped    atomic.Bool
+	pause      atomic.Bool
+	evaluating atomic.Bool
+	terminated atomic.Bool
+}
+
+// NewSession returns a session that calls onStop each time the program
+// stops.
+func NewSession(onStop func(*Stop)) *Session {
+	return &Session{
+		onStop:      onStop,
+		breakpoints: map[string]map[int]*Breakpoint{},
+		absPaths:    map[string]string{},
+		last:        map[int]position{},
+		commands:    make(chan command),
+	}
+}
+
+type position struct {
+	file         string
+	line, column int
+}
+
+// Start runs the program in file on a new goroutine, stopping before its
+// first statement if stopOnEntry is set. The channel is closed when the
+// program has finished or been terminated.
+func (s *Session) Start(file string, stopOnEntry bool) <-chan struct{} {
+	done := make(chan struct{})
+	if stopOnEntry {
+		s.mode, s.thread = modeIn, evaluator.MainThread
+	}
+	go func() {
+		defer close(done)
+		defer evaluator.SetDebugHook(nil)
+
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Printf("Error: vint Failed to read the file: %s\n", file)
+			return
+		}
+		if dir, err := filepath.Abs(filepath.Dir(file)); err == nil {
+			evaluator.AddSearchPath(dir)
+		}
+		evaluator.SetDebugHook(s.hook)
+		repl.ReadWithFilename(string(contents), file)
+	}()
+	return done
+}
+
+// SetBreakpoint adds a breakpoint, replacing any other on the same line.
+func (s *Session) SetBreakpoint(file string, line int, condition string) *Breakpoint {
+	file = absPath(file)
+	s.bpMu.Lock()
+	defer s.bpMu.Unlock()
+	s.nextID++
+	bp := &Breakpoint{ID: s.nextID, File: file, Line: line, Condition: condition}
+	if s.breakpoints[file] == nil {
+		s.breakpoints[file] = map[int]*Breakpoint{}
+	}
+	s.breakpoints[file][line] = bp
+	return bp
+}
+
+// ClearBreakpoint removes the breakpoint on a line, reporting whether
+// there was one.
+func (s *Session) ClearBreakpoint(file string, line int) bool {
+	file = absPath(file)
+	s.bpMu.Lock()
+	defer s.bpMu.Unlock()
+	_, ok := s.breakpoints[file][line]
+	delete(s.breakpoints[file], line)
+	return ok
+}
+
+// ClearBreakpoints removes the breakpoints in file, or in every file if
+// file is "".
+func (s *Session) ClearBreakpoints(file string) {
+	s.bpMu.Lock()
+	defer s.bpMu.Unlock()
+	if file == "" {
+		s.breakpoints = map[string]map[int]*Breakpoint{}
+		return
+	}
+	delete(s.breakpoints, absPath(file))
+}
+
+// Breakpoints returns the breakpoints ordered by file and line.
+func (s *Session) Breakpoints() []*Breakpoint {
+	s.bpMu.Lock()
+	defer s.bpMu.Unlock()
+	var all []*Breakpoint
+	for _, lines := range s.breakpoints {
+		for _, bp := range lines {
+			all = append(all, bp)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].File != all[j].File {
+			return all[i].File < all[j].File
+		}
+		return all[i].Line < all[j].Line
+	})
+	return all
+}
+
+// Continue runs the stopped program until a breakpoint.
+func (s *Session) Continue() error { return s.resume(cmdContinue) }
+
+// Next runs the stopped program to the next statement, stepping over
+// calls.
+func (s *Session) Next() error { return s.resume(cmdNext) }
+
+// StepIn runs the stopped program to the next statement, which is the
+// first one of a function it calls.
+func (s *Session) StepIn() error { return s.resume(cmdStepIn) }
+
+// StepOut runs the stopped program until the current function returns.
+func (s *Session) StepOut() error { return s.resume(cmdStepOut) }
+
+// Pause stops the running program before its next statement.
+func (s *Session) Pause() {
+	s.pause.Store(true)
+}
+
+// Terminate ends the program: at once if it is stopped, or else before
+// its next statement.
+func (s *Session) Terminate() {
+	if s.terminated.Swap(true) {
+		return
+	}
+	if s.stopped.Load() {
+		s.commands <- command{kind: cmdTerminate}
+	}
+}
+
+func (s *Session) resume(kind commandKind) error {
+	if !s.stopped.Load() {
+		return ErrRunning
+	}
+	s.commands <- command{kind: kind}
+	return nil
+}
+
+// Evaluate evaluates an expression in the scope of frame n of the stopped
+// program, the innermost call being 0. Assignments change the program's
+// variables.
+func (s *Session) Evaluate(expr string, frame int) (object.VintObject, error) {
+	if !s.stopped.Load() {
+		return nil, ErrRunning
+	}
+	reply := make(chan evaluation)
+	s.commands <- command{kind: cmdEvaluate, expr: expr, frame: frame, reply: reply}
+	result := <-reply
+	return result.value, result.err
+}
+
+// hook is the evaluator's DebugHook.
+func (s *Session) hook(thread int, stmt ast.Statement, stack []evaluator.Frame) {
+	if s.evaluating.Load() {
+		return
+	}
+	if s.terminated.Load() {
+		runtime.Goexit()
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	top := stack[len(stack)-1]
+	depth := len(stack)
+	file := s.absPath(top.File)
+	// A breakpoint stops at the first statement on its line, not at the
+	// ones after it, but again when a loop comes back to the line
+	last := s.last[thread]
+	newLine := file != last.file || top.Line != last.line || top.Column <= last.column
+	s.last[thread] = position{file, top.Line, top.Column}
+
+	stop := &Stop{Thread: thread, Stack: stack}
+	stepping := thread == s.thread
+	switch {
+	case s.pause.Swap(false):
+		stop.Reason = "pause"
+	case stepping && s.mode == modeIn,
+		stepping && s.mode == modeOver && depth <= s.depth,
+		stepping && s.mode == modeOut && depth < s.depth:
+		stop.Reason = "step"
+		if s.depth == 0 {
+			stop.Reason = "entry"
+		}
+	}
+	if newLine {
+		if bp := s.breakpointAt(file, top.Line); bp != nil && stop.Reason == "" {
+			hit, err := s.conditionHolds(bp, top.Env)
+			if hit || err != nil {
+				s.bpMu.Lock()
+				bp.Hits++
+				s.bpMu.Unlock()
+				stop.Reason, stop.Breakpoint, stop.Err = "breakpoint", bp, err
+			}
+		}
+	}
+	if stop.Reason == "" {
+		return
+	}
+
+	s.stopped.Store(true)
+	s.onStop(stop)
+	for cmd := range s.commands {
+		if cmd.kind == cmdEvaluate {
+			frame, ok := stop.Frame(cmd.frame)
+			if !ok {
+				cmd.reply <- evaluation{err: fmt.Errorf("there is no frame %d", cmd.frame)}
+				continue
+			}
+			value, err := s.evaluate(cmd.expr, frame.Env)
+			cmd.reply <- evaluation{value, err}
+			continue
+		}
+
+		s.stopped.Store(false)
+		s.thread, s.depth = thread, depth
+		switch cmd.kind {
+		case cmdContinue:
+			s.mode = modeRun
+		case cmdNext:
+			s.mode = modeOver
+		case cmdStepIn:
+			s.mode = modeIn
+		case cmdStepOut:
+			s.mode = modeOut
+		case cmdTerminate:
+			runtime.Goexit()
+		}
+		return
+	}
+}
+
+func (s *Session) breakpointAt(file string, line int) *Breakpoint {
+	s.bpMu.Lock()
+	defer s.bpMu.Unlock()
+	return s.breakpoints[file][line]
+}
+
+func (s *Session) conditionHolds(bp *Breakpoint, env *object.Environment) (bool, error) {
+	if bp.Condition == "" {
+		return true, nil
+	}
+	value, err := s.evaluate(bp.Condition, env)
+	if err != nil {
+		return false, fmt.Errorf("condition %s: %v", bp.Condition, err)
+	}
+	switch value := value.(type) {
+	case *object.Boolean:
+		return value.Value, nil
+	case *object.Null:
+		return false, nil
+	}
+	return true, nil
+}
+
+// evaluate evaluates a single expression in env, with the hook turned off
+// so that the functions it calls run through.
+func (s *Session) evaluate(expr string, env *object.Environment) (object.VintObject, error) {
+	if env == nil {
+		return nil, errors.New("no scope to evaluate in")
+	}
+	p := parser.New(lexer.New(expr))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		return nil, errors.New(errs[0])
+	}
+	if len(program.Statements) != 1 {
+		return nil, fmt.Errorf("'%s' is not an expression", expr)
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not an expression", expr)
+	}
+
+	s.evaluating.Store(true)
+	defer s.evaluating.Store(false)
+	value := evaluator.Eval(stmt.Expression, env)
+	if errObj, ok := value.(*object.Error); ok {
+		return nil, errors.New(errObj.Message)
+	}
+	if value == nil {
+		value = &object.Null{}
+	}
+	return value, nil
+}
+
+// absPath is absPath with the results remembered, as the hook needs the
+// path of every statement's file.
+func (s *Session) absPath(file string) string {
+	abs, ok := s.absPaths[file]
+	if !ok {
+		abs = absPath(file)
+		s.absPaths[file] = abs
+	}
+	return abs
+}
+
+func absPath(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return file
+}
@@ -0,0 +1,351 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/toolkit"
+)
+
+// Main runs `vint debug` and returns the exit code:
+//
+//	vint debug file.vint [args]     debug at a prompt on the terminal
+//	vint debug --dap                serve the Debug Adapter Protocol on stdin and stdout
+//	vint debug --dap --listen addr  serve it to the first client to connect to addr
+func Main(args []string, in io.Reader, out io.Writer) int {
+	dap, listen := false, ""
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		switch {
+		case args[0] == "--dap":
+			dap = true
+		case args[0] == "--listen" && len(args) > 1:
+			listen = args[1]
+			args = args[1:]
+		default:
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown flag %s", args[0])))
+			return 2
+		}
+		args = args[1:]
+	}
+
+	if dap {
+		if len(args) > 0 {
+			fmt.Fprintln(out, styles.ErrorStyle.Render("Usage: vint debug --dap [--listen addr]"))
+			return 2
+		}
+		if listen == "" {
+			return NewServer(in, out).Serve()
+		}
+		ln, err := net.Listen("tcp", listen)
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return 1
+		}
+		fmt.Fprintln(out, styles.HelpStyle.Render("Debug adapter listening on "+ln.Addr().String()))
+		conn, err := ln.Accept()
+		ln.Close()
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return 1
+		}
+		defer conn.Close()
+		return NewServer(conn, conn).Serve()
+	}
+
+	if len(args) == 0 || !strings.HasSuffix(args[0], ".vint") || listen != "" {
+		fmt.Fprintln(out, styles.ErrorStyle.Render("Usage: vint debug file.vint [args]"))
+		return 2
+	}
+	toolkit.CLI_ARGS = append(toolkit.CLI_ARGS, args[1:]...)
+	return newTerminal(in, out).run(args[0])
+}
+
+const terminalHelp = `Commands:
+  break [file:]line [if cond]  stop at a line, when cond is true     (b)
+  delete [[file:]line]         remove a breakpoint, or all of them   (d)
+  breakpoints                  list the breakpoints                  (bl)
+  continue                     run to the next breakpoint            (c)
+  next                         run to the next line, over calls      (n)
+  step                         run to the next line, into calls      (s)
+  out                          run until the current function returns (o)
+  stack                        show the call stack                   (bt)
+  frame n                      inspect frame n of the stack          (f)
+  vars                         show the variables of each scope      (v)
+  print expr                   evaluate an expression                (p)
+  list                         show the code around the current line (l)
+  quit                         end the program                       (q)`
+
+// terminal is the prompt of `vint debug file.vint`.
+type terminal struct {
+	in      *bufio.Scanner
+	out     io.Writer
+	session *Session
+	stops   chan *Stop
+	stop    *Stop // where the program is stopped
+	frame   int   // the frame being inspected
+	main    string
+	sources map[string][]string
+}
+
+func newTerminal(in io.Reader, out io.Writer) *terminal {
+	t := &terminal{
+		in:      bufio.NewScanner(in),
+		out:     out,
+		stops:   make(chan *Stop),
+		sources: map[string][]string{},
+	}
+	t.session = NewSession(func(stop *Stop) { t.stops <- stop })
+	return t
+}
+
+func (t *terminal) run(file string) int {
+	if _, err := os.Stat(file); err != nil {
+		fmt.Fprintln(t.out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	t.main = file
+	fmt.Fprintln(t.out, styles.HelpStyle.Render("Debugging "+file+". Type help for the commands."))
+	done := t.session.Start(file, true)
+	for {
+		select {
+		case <-done:
+			fmt.Fprintln(t.out, styles.HelpStyle.Render("The program has finished."))
+			return 0
+		case stop := <-t.stops:
+			t.stop, t.frame = stop, 0
+			t.showStop()
+			if !t.prompt() {
+				t.session.Terminate()
+				<-done
+				return 0
+			}
+		}
+	}
+}
+
+// prompt reads commands until one resumes the program. It returns false
+// when the program should end.
+func (t *terminal) prompt() bool {
+	for {
+		fmt.Fprint(t.out, "(vint) ")
+		if !t.in.Scan() {
+			fmt.Fprintln(t.out)
+			return false
+		}
+		line := strings.TrimSpace(t.in.Text())
+		name, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+
+		var err error
+		switch name {
+		case "":
+			continue
+		case "continue", "c":
+			err = t.session.Continue()
+		case "next", "n":
+			err = t.session.Next()
+		case "step", "s":
+			err = t.session.StepIn()
+		case "out", "o":
+			err = t.session.StepOut()
+		case "quit", "q", "exit":
+			return false
+		default:
+			t.command(name, arg)
+			continue
+		}
+		if err != nil {
+			t.error(err)
+			continue
+		}
+		return true
+	}
+}
+
+// command runs a command that does not resume the program.
+func (t *terminal) command(name, arg string) {
+	switch name {
+	case "break", "b":
+		t.breakCommand(arg)
+	case "delete", "d":
+		if arg == "" {
+			t.session.ClearBreakpoints("")
+			fmt.Fprintln(t.out, "Deleted all breakpoints")
+			return
+		}
+		file, line, err := t.location(arg)
+		if err != nil {
+			t.error(err)
+		} else if !t.session.ClearBreakpoint(file, line) {
+			t.error(fmt.Errorf("there is no breakpoint at %s:%d", file, line))
+		} else {
+			fmt.Fprintf(t.out, "Deleted the breakpoint at %s:%d\n", file, line)
+		}
+	case "breakpoints", "bl":
+		breakpoints := t.session.Breakpoints()
+		if len(breakpoints) == 0 {
+			fmt.Fprintln(t.out, "No breakpoints")
+		}
+		for _, bp := range breakpoints {
+			fmt.Fprintf(t.out, "%d  %s%s  hit %d times\n", bp.ID, t.position(bp.File, bp.Line), conditionText(bp), bp.Hits)
+		}
+	case "stack", "bt", "where":
+		for n := range t.stop.Stack {
+			frame, _ := t.stop.Frame(n)
+			marker := "  "
+			if n == t.frame {
+				marker = "> "
+			}
+			fmt.Fprintf(t.out, "%s#%d  %s at %s\n", marker, n, frame.Function, t.position(frame.File, frame.Line))
+		}
+	case "frame", "f":
+		n, err := strconv.Atoi(arg)
+		if _, ok := t.stop.Frame(n); err != nil || !ok {
+			t.error(fmt.Errorf("there is no frame %s; see stack", arg))
+			return
+		}
+		t.frame = n
+		t.showFrame()
+	case "vars", "v":
+		frame, _ := t.stop.Frame(t.frame)
+		for _, sc := range scopes(frame.Env) {
+			vars := envVariables(sc.Env)
+			if len(vars) == 0 {
+				continue
+			}
+			fmt.Fprintln(t.out, styles.HelpStyle.Render(sc.Name+":"))
+			for _, v := range vars {
+				fmt.Fprintf(t.out, "  %s = %s\n", v.Name, show(v.Value, 200))
+			}
+		}
+	case "print", "p":
+		if arg == "" {
+			t.error(fmt.Errorf("print needs an expression"))
+			return
+		}
+		value, err := t.session.Evaluate(arg, t.frame)
+		if err != nil {
+			t.error(err)
+			return
+		}
+		fmt.Fprintln(t.out, show(value, 10000))
+	case "list", "l":
+		frame, _ := t.stop.Frame(t.frame)
+		t.listSource(frame.File, frame.Line, 5)
+	case "help", "h":
+		fmt.Fprintln(t.out, terminalHelp)
+	default:
+		t.error(fmt.Errorf("unknown command '%s'; type help for the commands", name))
+	}
+}
+
+// breakCommand handles `break [file:]line [if cond]`.
+func (t *terminal) breakCommand(arg string) {
+	where, condition, _ := strings.Cut(arg, " if ")
+	file, line, err := t.location(strings.TrimSpace(where))
+	if err != nil {
+		t.error(err)
+		return
+	}
+	bp := t.session.SetBreakpoint(file, line, strings.TrimSpace(condition))
+	fmt.Fprintf(t.out, "Breakpoint %d at %s%s\n", bp.ID, t.position(bp.File, bp.Line), conditionText(bp))
+}
+
+// location parses [file:]line. Without a file it is the file being
+// debugged.
+func (t *terminal) location(arg string) (string, int, error) {
+	file, lineText := t.main, arg
+	if i := strings.LastIndex(arg, ":"); i >= 0 {
+		file, lineText = arg[:i], arg[i+1:]
+		if _, err := os.Stat(file); err != nil && !filepath.IsAbs(file) {
+			// Files can also be named relative to the program
+			file = filepath.Join(filepath.Dir(t.main), file)
+		}
+	}
+	line, err := strconv.Atoi(lineText)
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("'%s' is not a [file:]line", arg)
+	}
+	return file, line, nil
+}
+
+func (t *terminal) showStop() {
+	stop := t.stop
+	switch stop.Reason {
+	case "breakpoint":
+		if stop.Err != nil {
+			t.error(stop.Err)
+		}
+		fmt.Fprintf(t.out, "Breakpoint %d, ", stop.Breakpoint.ID)
+	case "pause":
+		fmt.Fprint(t.out, "Paused, ")
+	}
+	if stop.Thread != evaluator.MainThread {
+		fmt.Fprintf(t.out, "goroutine %d, ", stop.Thread)
+	}
+	t.showFrame()
+}
+
+func (t *terminal) showFrame() {
+	frame, _ := t.stop.Frame(t.frame)
+	fmt.Fprintln(t.out, styles.HelpStyle.Render(fmt.Sprintf("%s at %s", frame.Function, t.position(frame.File, frame.Line))))
+	t.listSource(frame.File, frame.Line, 0)
+}
+
+// listSource prints the lines around line, marking line itself.
+func (t *terminal) listSource(file string, line, context int) {
+	lines := t.source(file)
+	for n := line - context; n <= line+context; n++ {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+		marker := "  "
+		if n == line {
+			marker = "->"
+		}
+		fmt.Fprintf(t.out, "%4d %s %s\n", n, marker, lines[n-1])
+	}
+}
+
+func (t *terminal) source(file string) []string {
+	lines, ok := t.sources[file]
+	if !ok {
+		if data, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(strings.ReplaceAll(string(data), "\t", "    "), "\n")
+		}
+		t.sources[file] = lines
+	}
+	return lines
+}
+
+// position shows a file and line, the file relative to the current
+// directory when it is below it.
+func (t *terminal) position(file string, line int) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+		}
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func (t *terminal) error(err error) {
+	fmt.Fprintln(t.out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+}
+
+func conditionText(bp *Breakpoint) string {
+	if bp.Condition == "" {
+		return ""
+	}
+	return " if " + bp.Condition
+}
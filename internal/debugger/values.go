@@ -0,0 +1,117 @@
+package debugger
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// scope is one environment of the chain a frame can see, named for
+// display.
+type scope struct {
+	Name string
+	Env  *object.Environment
+}
+
+// scopes lists env and the environments it is enclosed in, innermost
+// first: the block or function the frame is in, the closures around it,
+// and the globals.
+func scopes(env *object.Environment) []scope {
+	var chain []scope
+	for cur := env; cur != nil; cur = cur.Outer() {
+		name := "Closure"
+		switch {
+		case cur.Outer() == nil:
+			name = "Globals"
+		case cur == env:
+			name = "Locals"
+		}
+		chain = append(chain, scope{Name: name, Env: cur})
+	}
+	return chain
+}
+
+// variable is a named value shown by the debugger.
+type variable struct {
+	Name  string
+	Value object.VintObject
+}
+
+// envVariables returns the variables declared in env itself. The
+// evaluator's own bookkeeping, under names that are not identifiers, is
+// left out.
+func envVariables(env *object.Environment) []variable {
+	var vars []variable
+	for _, name := range env.Names() {
+		if name == "" || name == "@" {
+			continue
+		}
+		if value, ok := env.Get(name); ok {
+			vars = append(vars, variable{Name: name, Value: value})
+		}
+	}
+	return vars
+}
+
+// children returns the elements of an array, the pairs of a dict and the
+// fields of a struct, which a client can expand.
+func children(value object.VintObject) []variable {
+	switch value := value.(type) {
+	case *object.Array:
+		vars := make([]variable, len(value.Elements))
+		for i, element := range value.Elements {
+			vars[i] = variable{Name: strconv.Itoa(i), Value: element}
+		}
+		return vars
+	case *object.Dict:
+		var vars []variable
+		for _, pair := range value.OrderedPairs() {
+			vars = append(vars, variable{Name: pair.Key.Inspect(), Value: pair.Value})
+		}
+		return vars
+	case *object.StructInstance:
+		var vars []variable
+		for _, field := range value.Struct.Fields {
+			if v, ok := value.Fields.Get(field.Name); ok {
+				vars = append(vars, variable{Name: field.Name, Value: v})
+			}
+		}
+		return vars
+	}
+	return nil
+}
+
+// hasChildren reports whether children has anything to show for value.
+func hasChildren(value object.VintObject) bool {
+	switch value := value.(type) {
+	case *object.Array:
+		return len(value.Elements) > 0
+	case *object.Dict:
+		return len(value.Pairs) > 0
+	case *object.StructInstance:
+		return len(value.Struct.Fields) > 0
+	}
+	return false
+}
+
+// show returns how a value is displayed on one line, shortened to about
+// limit characters. Functions show only their parameters.
+func show(value object.VintObject, limit int) string {
+	if value == nil {
+		return "null"
+	}
+	s := value.Inspect()
+	switch value := value.(type) {
+	case *object.String:
+		s = strconv.Quote(value.Value)
+	case *object.Function:
+		s, _, _ = strings.Cut(s, "{")
+		s = strings.TrimSpace(s) + " {…}"
+	}
+	s = strings.ReplaceAll(s, "\n", " ")
+	if r := []rune(s); len(r) > limit {
+		s = string(r[:limit]) + "…"
+	}
+	return s
+}
@@ -26,12 +26,27 @@ func evalAssignEqual(node *ast.AssignEqual, env *object.Environment) object.Vint
 		return newVal
 	}
 
+	// Bigints and decimals work the way their operators do
+	switch node.Token.Literal {
+	case "+=", "-=", "*=", "/=":
+		if isBigNumber(left) && isNumber(value) || isNumber(left) && isBigNumber(value) {
+			val := evalBigNumberInfixExpression(strings.TrimSuffix(node.Token.Literal, "="), left, value, node.Token.Line)
+			if isError(val) {
+				return val
+			}
+			return assign(val)
+		}
+	}
+
 	switch node.Token.Literal {
 	case "+=":
 		switch arg := left.(type) {
 		case *object.Integer:
 			switch val := value.(type) {
 			case *object.Integer:
+				if promoted := integerOverflow("+", arg.Value, val.Value); promoted != nil {
+					return assign(promoted)
+				}
 				v := arg.Value + val.Value
 				return assign(&object.Integer{Value: v})
 			case *object.Float:
@@ -71,6 +86,9 @@ func evalAssignEqual(node *ast.AssignEqual, env *object.Environment) object.Vint
 		case *object.Integer:
 			switch val := value.(type) {
 			case *object.Integer:
+				if promoted := integerOverflow("-", arg.Value, val.Value); promoted != nil {
+					return assign(promoted)
+				}
 				v := arg.Value - val.Value
 				return assign(&object.Integer{Value: v})
 			case *object.Float:
@@ -101,6 +119,9 @@ func evalAssignEqual(node *ast.AssignEqual, env *object.Environment) object.Vint
 		case *object.Integer:
 			switch val := value.(type) {
 			case *object.Integer:
+				if promoted := integerOverflow("*", arg.Value, val.Value); promoted != nil {
+					return assign(promoted)
+				}
 				v := arg.Value * val.Value
 				return assign(&object.Integer{Value: v})
 			case *object.Float:
@@ -180,6 +201,13 @@ func evalAssignEqual(node *ast.AssignEqual, env *object.Environment) object.Vint
 			// Check for invalid operation on unsupported types
 			return newError("Line %d: Cannot use '/=' with %v", node.Token.Line, arg.Type())
 		}
+	case "&=", "|=", "^=", "<<=", ">>=":
+		// Bitwise assignments work on integers, like the operators they are made of
+		val := evalInfixExpression(strings.TrimSuffix(node.Token.Literal, "="), left, value, node.Token.Line)
+		if isError(val) {
+			return val
+		}
+		return assign(val)
 	default:
 		// Check for an unknown operation
 		return newError("Line %d: Unknown operation %s", node.Token.Line, node.Token.Literal)
@@ -0,0 +1,240 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// isBigNumber reports whether obj is a BigInt or a Decimal.
+func isBigNumber(obj object.VintObject) bool {
+	return obj.Type() == object.BIGINT_OBJ || obj.Type() == object.DECIMAL_OBJ
+}
+
+// isNumber reports whether obj is any kind of number.
+func isNumber(obj object.VintObject) bool {
+	switch obj.Type() {
+	case object.INTEGER_OBJ, object.FLOAT_OBJ, object.BIGINT_OBJ, object.DECIMAL_OBJ:
+		return true
+	}
+	return false
+}
+
+// evalBigNumberInfixExpression applies an operator to two numbers, at
+// least one of which is a BigInt or a Decimal. A Decimal with any other
+// number gives a Decimal, a BigInt with an Integer gives a BigInt, and a
+// BigInt with a Float gives a Float.
+func evalBigNumberInfixExpression(operator string, left, right object.VintObject, line int) object.VintObject {
+	if left.Type() == object.DECIMAL_OBJ || right.Type() == object.DECIMAL_OBJ {
+		return evalDecimalInfixExpression(operator, left, right, line)
+	}
+	if left.Type() == object.FLOAT_OBJ || right.Type() == object.FLOAT_OBJ {
+		return evalFloatInfixExpression(operator, toFloat(left), toFloat(right), line)
+	}
+	l, _ := object.ToBigInt(left)
+	r, _ := object.ToBigInt(right)
+	return evalBigIntInfixExpression(operator, l.Value, r.Value, line)
+}
+
+// toFloat converts an integer of either size to a Float.
+func toFloat(obj object.VintObject) *object.Float {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return &object.Float{Value: float64(obj.Value)}
+	case *object.BigInt:
+		f, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return &object.Float{Value: f}
+	}
+	return obj.(*object.Float)
+}
+
+func evalBigIntInfixExpression(operator string, left, right *big.Int, line int) object.VintObject {
+	result := new(big.Int)
+	switch operator {
+	case "+":
+		result.Add(left, right)
+	case "-":
+		result.Sub(left, right)
+	case "*":
+		result.Mul(left, right)
+	case "/":
+		if right.Sign() == 0 {
+			return newError("Line %d: Division by zero: cannot divide by zero", line)
+		}
+		// Like integer division, a division that does not come out even
+		// gives a float
+		quotient, remainder := result.QuoRem(left, right, new(big.Int))
+		if remainder.Sign() != 0 {
+			f, _ := new(big.Rat).SetFrac(left, right).Float64()
+			return &object.Float{Value: f}
+		}
+		return &object.BigInt{Value: quotient}
+	case "%":
+		if right.Sign() == 0 {
+			return newError("Line %d: Division by zero: cannot perform modulo operation with a zero divisor", line)
+		}
+		result.Rem(left, right)
+	case "**":
+		if right.Sign() < 0 {
+			l, _ := new(big.Float).SetInt(left).Float64()
+			r, _ := new(big.Float).SetInt(right).Float64()
+			return &object.Float{Value: math.Pow(l, r)}
+		}
+		if !right.IsInt64() {
+			return newError("Line %d: Exponent too large: %s", line, right)
+		}
+		result.Exp(left, right, nil)
+	case "&":
+		result.And(left, right)
+	case "|":
+		result.Or(left, right)
+	case "^":
+		result.Xor(left, right)
+	case "<<", ">>":
+		if right.Sign() < 0 {
+			return newError("Line %d: Negative shift count: cannot shift by %s", line, right)
+		}
+		if !right.IsUint64() || right.Uint64() > math.MaxUint32 {
+			return newError("Line %d: Shift count too large: %s", line, right)
+		}
+		if operator == "<<" {
+			result.Lsh(left, uint(right.Uint64()))
+		} else {
+			result.Rsh(left, uint(right.Uint64()))
+		}
+	case "<", "<=", ">", ">=", "==", "!=":
+		return compareResult(operator, left.Cmp(right))
+	default:
+		return newError("Line %d: Unsupported integer operation: '%s' operator cannot be used with integer values",
+			line, operator)
+	}
+	return &object.BigInt{Value: result}
+}
+
+func evalDecimalInfixExpression(operator string, left, right object.VintObject, line int) object.VintObject {
+	if operator == "**" {
+		exponent, ok := right.(*object.Integer)
+		if !ok {
+			return newError("Line %d: The power of a decimal must be an integer, got %s", line, right.Type())
+		}
+		base, err := object.ToDecimal(left)
+		if err != nil {
+			return newError("Line %d: %s", line, err)
+		}
+		result, err := base.Pow(exponent.Value)
+		if err != nil {
+			return newError("Line %d: %s", line, err)
+		}
+		return result
+	}
+
+	l, err := object.ToDecimal(left)
+	if err != nil {
+		return newError("Line %d: %s", line, err)
+	}
+	r, err := object.ToDecimal(right)
+	if err != nil {
+		return newError("Line %d: %s", line, err)
+	}
+	switch operator {
+	case "+":
+		return l.Add(r)
+	case "-":
+		return l.Sub(r)
+	case "*":
+		return l.Mul(r)
+	case "/":
+		places, mode := object.DecimalContext()
+		result, err := l.Quo(r, places, mode)
+		if err != nil {
+			return newError("Line %d: Division by zero: cannot divide by zero", line)
+		}
+		return result
+	case "%":
+		result, err := l.Rem(r)
+		if err != nil {
+			return newError("Line %d: Division by zero: cannot perform modulo operation with a zero divisor", line)
+		}
+		return result
+	case "<", "<=", ">", ">=", "==", "!=":
+		return compareResult(operator, l.Cmp(r))
+	default:
+		return newError("Line %d: Unsupported decimal operation: '%s' operator cannot be used with decimal values",
+			line, operator)
+	}
+}
+
+// compareResult gives the result of a comparison operator from cmp, which
+// is -1, 0 or +1 as the left operand is less than, equal to or greater
+// than the right.
+func compareResult(operator string, cmp int) object.VintObject {
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(cmp < 0)
+	case "<=":
+		return nativeBoolToBooleanObject(cmp <= 0)
+	case ">":
+		return nativeBoolToBooleanObject(cmp > 0)
+	case ">=":
+		return nativeBoolToBooleanObject(cmp >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(cmp == 0)
+	}
+	return nativeBoolToBooleanObject(cmp != 0)
+}
+
+// integerOverflow returns the result of an integer +, -, *, << or ** as a
+// BigInt if it does not fit in an Integer, or nil if it does.
+func integerOverflow(operator string, left, right int64) object.VintObject {
+	var overflows bool
+	switch operator {
+	case "**":
+		_, fits := integerPower(left, right)
+		overflows = right >= 0 && !fits
+	case "+":
+		sum := left + right
+		overflows = (left^sum)&(right^sum) < 0
+	case "-":
+		difference := left - right
+		overflows = (left^right)&(left^difference) < 0
+	case "*":
+		overflows = multiplyOverflows(left, right)
+	case "<<":
+		overflows = left != 0 && right >= 0 && (right >= 64 || (left<<right)>>right != left)
+	}
+	if !overflows {
+		return nil
+	}
+	return evalBigIntInfixExpression(operator, big.NewInt(left), big.NewInt(right), 0)
+}
+
+func multiplyOverflows(left, right int64) bool {
+	if left == 0 || right == 0 {
+		return false
+	}
+	product := left * right
+	return product/right != left || (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64)
+}
+
+// integerPower returns base ** exp for an exp that is not negative, by
+// repeated squaring, and whether the result fits in an Integer.
+func integerPower(base, exp int64) (int64, bool) {
+	result := int64(1)
+	for exp > 0 {
+		if exp&1 == 1 {
+			if multiplyOverflows(result, base) {
+				return 0, false
+			}
+			result *= base
+		}
+		exp >>= 1
+		if exp > 0 {
+			if multiplyOverflows(base, base) {
+				return 0, false
+			}
+			base *= base
+		}
+	}
+	return result, true
+}
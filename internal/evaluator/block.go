@@ -9,6 +9,9 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 	var result object.VintObject = NULL
 
 	for _, statement := range block.Statements {
+		if debugHook != nil || profiler != nil || coverage != nil {
+			statementHooks(statement, env)
+		}
 		result = Eval(statement, env)
 
 		if result != nil {
@@ -0,0 +1,82 @@
+package builtins
+
+// Arity is how many arguments a builtin accepts. Max is -1 when there is
+// no limit.
+type Arity struct {
+	Min int
+	Max int
+}
+
+// Accepts reports whether a call with n arguments is allowed.
+func (a Arity) Accepts(n int) bool {
+	return n >= a.Min && (a.Max < 0 || n <= a.Max)
+}
+
+// Arities holds the number of arguments of each builtin, as checked by
+// its implementation. Tools use it to find wrong calls before they run,
+// so a builtin that changes what it accepts should be updated here too.
+var Arities = map[string]Arity{
+	"args": {0, 0},
+
+	"exit":        {1, 1},
+	"sleep":       {1, 1},
+	"receive":     {1, 1},
+	"tryReceive":  {1, 1},
+	"close":       {1, 1},
+	"import":      {1, 1},
+	"open":        {1, 1},
+	"pop":         {1, 1},
+	"unique":      {1, 1},
+	"keys":        {1, 1},
+	"values":      {1, 1},
+	"not":         {1, 1},
+	"type":        {1, 1},
+	"chr":         {1, 1},
+	"ord":         {1, 1},
+	"len":         {1, 1},
+	"string":      {1, 1},
+	"int":         {1, 1},
+	"bigint":      {1, 1},
+	"decimal":     {1, 1},
+	"parseInt":    {1, 1},
+	"parseFloat":  {1, 1},
+	"copy":        {1, 1},
+	"clone":       {1, 1},
+	"is_null":     {1, 1},
+	"is_int":      {1, 1},
+	"is_float":    {1, 1},
+	"is_string":   {1, 1},
+	"is_bool":     {1, 1},
+	"is_array":    {1, 1},
+	"is_dict":     {1, 1},
+	"is_function": {1, 1},
+	"is_error":    {1, 1},
+	"is_number":   {1, 1},
+
+	"send":       {2, 2},
+	"trySend":    {2, 2},
+	"indexOf":    {2, 2},
+	"has_key":    {2, 2},
+	"and":        {2, 2},
+	"or":         {2, 2},
+	"xor":        {2, 2},
+	"nand":       {2, 2},
+	"nor":        {2, 2},
+	"eq":         {2, 2},
+	"convert":    {2, 2},
+	"pow":        {2, 2},
+	"startsWith": {2, 2},
+	"endsWith":   {2, 2},
+	"debounce":   {2, 2},
+
+	"input":  {0, 1},
+	"range":  {1, 3},
+	"write":  {2, 3},
+	"append": {2, -1},
+	"format": {1, -1},
+
+	"print":      {0, -1},
+	"println":    {0, -1},
+	"printErr":   {0, -1},
+	"printlnErr": {0, -1},
+}
@@ -46,6 +46,48 @@ func registerChannelBuiltins() {
 		},
 	})
 
+	RegisterBuiltin("trySend", &object.Builtin{
+		Fn: func(args ...object.VintObject) object.VintObject {
+			if len(args) != 2 {
+				return newError("trySend() takes exactly 2 arguments (channel, value), got %d", len(args))
+			}
+
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return newError("first argument to trySend() must be a channel, got %T", args[0])
+			}
+
+			sent, err := ch.TrySend(args[1])
+			if err != nil {
+				return newError("send error: %s", err.Error())
+			}
+			if sent {
+				return TRUE
+			}
+			return FALSE
+		},
+	})
+
+	RegisterBuiltin("tryReceive", &object.Builtin{
+		Fn: func(args ...object.VintObject) object.VintObject {
+			if len(args) != 1 {
+				return newError("tryReceive() takes exactly 1 argument (channel), got %d", len(args))
+			}
+
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return newError("argument to tryReceive() must be a channel, got %T", args[0])
+			}
+
+			// Returns [value, true] if a value was ready, [null, false] otherwise
+			value, ok := ch.TryReceive()
+			if !ok {
+				return &object.Array{Elements: []object.VintObject{NULL, FALSE}}
+			}
+			return &object.Array{Elements: []object.VintObject{value, TRUE}}
+		},
+	})
+
 	RegisterBuiltin("close", &object.Builtin{
 		Fn: func(args ...object.VintObject) object.VintObject {
 			if len(args) != 1 {
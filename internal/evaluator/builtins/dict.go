@@ -17,7 +17,7 @@ func registerDictBuiltins() {
 			}
 			dict := args[0].(*object.Dict)
 			keys := make([]object.VintObject, 0, len(dict.Pairs))
-			for _, pair := range dict.Pairs {
+			for _, pair := range dict.OrderedPairs() {
 				keys = append(keys, pair.Key)
 			}
 			return &object.Array{Elements: keys}
@@ -34,7 +34,7 @@ func registerDictBuiltins() {
 			}
 			dict := args[0].(*object.Dict)
 			values := make([]object.VintObject, 0, len(dict.Pairs))
-			for _, pair := range dict.Pairs {
+			for _, pair := range dict.OrderedPairs() {
 				values = append(values, pair.Value)
 			}
 			return &object.Array{Elements: values}
@@ -3,6 +3,7 @@ package builtins
 import (
 	"fmt"
 	"io"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -50,12 +51,28 @@ func getBooleanValue(obj object.VintObject) (bool, error) {
 	}
 }
 
+// isNumber reports whether obj is a number of any kind.
+func isNumber(obj object.VintObject) bool {
+	switch obj.Type() {
+	case object.INTEGER_OBJ, object.FLOAT_OBJ, object.BIGINT_OBJ, object.DECIMAL_OBJ:
+		return true
+	}
+	return false
+}
+
 func convertToInteger(value object.VintObject) object.VintObject {
 	switch value := value.(type) {
 	case *object.Integer:
 		return value
 	case *object.Float:
 		return &object.Integer{Value: int64(value.Value)}
+	case *object.BigInt:
+		if !value.Value.IsInt64() {
+			return newError("Cannot convert %s to integer: it is too large", value.Inspect())
+		}
+		return &object.Integer{Value: value.Value.Int64()}
+	case *object.Decimal:
+		return convertToInteger(&object.BigInt{Value: value.Truncate()})
 	case *object.String:
 		val, err := strconv.ParseInt(value.Value, 10, 64)
 		if err != nil {
@@ -78,6 +95,11 @@ func convertToFloat(value object.VintObject) object.VintObject {
 		return value
 	case *object.Integer:
 		return &object.Float{Value: float64(value.Value)}
+	case *object.BigInt:
+		val, _ := new(big.Float).SetInt(value.Value).Float64()
+		return &object.Float{Value: val}
+	case *object.Decimal:
+		return &object.Float{Value: value.Float64()}
 	case *object.String:
 		val, err := strconv.ParseFloat(value.Value, 64)
 		if err != nil {
@@ -94,6 +116,22 @@ func convertToFloat(value object.VintObject) object.VintObject {
 	}
 }
 
+func convertToBigInt(value object.VintObject) object.VintObject {
+	b, err := object.ToBigInt(value)
+	if err != nil {
+		return newError("Cannot convert to bigint: %s", err)
+	}
+	return b
+}
+
+func convertToDecimal(value object.VintObject) object.VintObject {
+	d, err := object.ToDecimal(value)
+	if err != nil {
+		return newError("Cannot convert to decimal: %s", err)
+	}
+	return d
+}
+
 func convertToString(value object.VintObject) object.VintObject {
 	return &object.String{Value: value.Inspect()}
 }
@@ -106,6 +144,10 @@ func convertToBoolean(value object.VintObject) object.VintObject {
 		return &object.Boolean{Value: value.Value != 0}
 	case *object.Float:
 		return &object.Boolean{Value: value.Value != 0.0}
+	case *object.BigInt:
+		return &object.Boolean{Value: value.Value.Sign() != 0}
+	case *object.Decimal:
+		return &object.Boolean{Value: value.Coef.Sign() != 0}
 	case *object.String:
 		val, err := strconv.ParseBool(value.Value)
 		if err != nil {
@@ -31,6 +31,10 @@ func registerTypeConversionBuiltins() {
 				return convertToInteger(value)
 			case "FLOAT":
 				return convertToFloat(value)
+			case "BIGINT":
+				return convertToBigInt(value)
+			case "DECIMAL":
+				return convertToDecimal(value)
 			case "STRING":
 				return convertToString(value)
 			case "BOOLEAN":
@@ -65,6 +69,26 @@ func registerTypeConversionBuiltins() {
 		},
 	})
 
+	RegisterBuiltin("bigint", &object.Builtin{
+		ReturnType: basicType("bigint"),
+		Fn: func(args ...object.VintObject) object.VintObject {
+			if len(args) != 1 {
+				return newError("bigint() requires exactly 1 argument, you provided %d", len(args))
+			}
+			return convertToBigInt(args[0])
+		},
+	})
+
+	RegisterBuiltin("decimal", &object.Builtin{
+		ReturnType: basicType("decimal"),
+		Fn: func(args ...object.VintObject) object.VintObject {
+			if len(args) != 1 {
+				return newError("decimal() requires exactly 1 argument, you provided %d", len(args))
+			}
+			return convertToDecimal(args[0])
+		},
+	})
+
 	RegisterBuiltin("parseInt", &object.Builtin{
 		Fn: func(args ...object.VintObject) object.VintObject {
 			if len(args) != 1 {
@@ -2,6 +2,7 @@ package builtins
 
 import (
 	"math"
+	"math/big"
 
 	"github.com/vintlang/vintlang/internal/object"
 )
@@ -24,11 +25,7 @@ func registerUtilityBuiltins() {
 				copy(newElements, obj.Elements)
 				return &object.Array{Elements: newElements}
 			case *object.Dict:
-				newPairs := make(map[object.HashKey]object.DictPair, len(obj.Pairs))
-				for k, v := range obj.Pairs {
-					newPairs[k] = object.DictPair{Key: v.Key, Value: v.Value}
-				}
-				return &object.Dict{Pairs: newPairs}
+				return obj.Method("copy", nil)
 			case *object.String:
 				return &object.String{Value: obj.Value}
 			default:
@@ -54,36 +51,34 @@ func registerUtilityBuiltins() {
 				return newError("pow() requires exactly 2 arguments, got %d", len(args))
 			}
 
-			var base, exp float64
-
-			switch b := args[0].(type) {
-			case *object.Integer:
-				base = float64(b.Value)
-			case *object.Float:
-				base = b.Value
-			default:
+			if !isNumber(args[0]) {
 				return newError("first argument to pow() must be a number, got %s", args[0].Type())
 			}
-
-			switch e := args[1].(type) {
-			case *object.Integer:
-				exp = float64(e.Value)
-			case *object.Float:
-				exp = e.Value
-			default:
+			if !isNumber(args[1]) {
 				return newError("second argument to pow() must be a number, got %s", args[1].Type())
 			}
 
-			result := math.Pow(base, exp)
-
-			// Return integer if both args were integers and result is whole
-			_, baseIsInt := args[0].(*object.Integer)
-			_, expIsInt := args[1].(*object.Integer)
-			if baseIsInt && expIsInt && exp >= 0 && result == math.Trunc(result) && !math.IsInf(result, 0) {
-				return &object.Integer{Value: int64(result)}
+			// Like **, an integer of either size to an integer power that is
+			// not negative is exact, and so is a decimal to an integer power
+			if exponent, ok := args[1].(*object.Integer); ok && exponent.Value >= 0 {
+				switch base := args[0].(type) {
+				case *object.Integer:
+					return object.IntegerFromBig(new(big.Int).Exp(big.NewInt(base.Value), big.NewInt(exponent.Value), nil))
+				case *object.BigInt:
+					return &object.BigInt{Value: new(big.Int).Exp(base.Value, big.NewInt(exponent.Value), nil)}
+				case *object.Decimal:
+					result, err := base.Pow(exponent.Value)
+					if err != nil {
+						return newError("pow(): %s", err)
+					}
+					return result
+				}
 			}
 
-			return &object.Float{Value: result}
+			// Other powers are worked out as floats
+			base := convertToFloat(args[0]).(*object.Float).Value
+			exp := convertToFloat(args[1]).(*object.Float).Value
+			return &object.Float{Value: math.Pow(base, exp)}
 		},
 	})
 
@@ -202,8 +197,7 @@ func registerUtilityBuiltins() {
 			if len(args) != 1 {
 				return newError("is_number() requires exactly 1 argument, got %d", len(args))
 			}
-			t := args[0].Type()
-			if t == object.INTEGER_OBJ || t == object.FLOAT_OBJ {
+			if isNumber(args[0]) {
 				return TRUE
 			}
 			return FALSE
@@ -220,11 +214,11 @@ func deepCopy(obj object.VintObject) object.VintObject {
 		}
 		return &object.Array{Elements: newElements}
 	case *object.Dict:
-		newPairs := make(map[object.HashKey]object.DictPair, len(o.Pairs))
-		for k, v := range o.Pairs {
-			newPairs[k] = object.DictPair{Key: deepCopy(v.Key), Value: deepCopy(v.Value)}
+		newDict := object.NewDict()
+		for _, pair := range o.OrderedPairs() {
+			newDict.SetPair(pair.Key.(object.Hashable).HashKey(), object.DictPair{Key: deepCopy(pair.Key), Value: deepCopy(pair.Value)})
 		}
-		return &object.Dict{Pairs: newPairs}
+		return newDict
 	case *object.String:
 		return &object.String{Value: o.Value}
 	case *object.Integer:
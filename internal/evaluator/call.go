@@ -5,78 +5,54 @@ import (
 	"github.com/vintlang/vintlang/internal/object"
 )
 
-// evalCall evaluates a function call expression by:
-// 1. Evaluating the function to be called.
-// 2. Evaluating the arguments for the function.
-// 3. Resolving overloads by arity (and later, by type).
-// 4. Applying the function with the evaluated arguments.
-func evalCall(node *ast.CallExpression, env *object.Environment) object.VintObject {
-	// Evaluate the function expression
-	function := Eval(node.Function, env)
-
-	// Check if an error occurred while evaluating the function
-	if isError(function) {
-		return function
+// evalCall evaluates a function call expression, given function, the
+// value of node.Function, by:
+// 1. Evaluating the arguments, expanding ...spread arguments.
+// 2. Resolving overloads by arity (and later, by type).
+// 3. Applying the function with the evaluated arguments.
+func evalCall(node *ast.CallExpression, function object.VintObject, env *object.Environment) object.VintObject {
+	if structDef, ok := function.(*object.Struct); ok {
+		// Struct instantiation: User(name = "Alice", age = 30) or User("Alice", 30)
+		return evalStructCall(node, structDef, env)
+	}
+
+	var overloads []*object.Function
+	if ident, ok := node.Function.(*ast.Identifier); ok {
+		overloads = env.GetAllFunctions(ident.Value)
 	}
 
 	var args []object.VintObject
 
-	// Overload resolution: if the function is an identifier, check for overloads
-	if ident, ok := node.Function.(*ast.Identifier); ok {
-		overloads := env.GetAllFunctions(ident.Value)
+	switch function.(type) {
+	case *object.Function, *object.Package:
+		positional, keywords, errObj := evalArguments(node.Arguments, env)
+		if errObj != nil {
+			return errObj
+		}
+
+		// Overload resolution: if the function is an identifier, check for overloads
 		if len(overloads) > 0 {
-			matched := false
-			for _, fn := range overloads {
-				paramCount := len(fn.Parameters)
-				argCount := len(node.Arguments)
-				// Allow match if all missing params have defaults
-				if argCount == paramCount {
-					function = fn
-					matched = true
-					break
-				} else if argCount < paramCount {
-					missing := paramCount - argCount
-					missingWithDefaults := 0
-					for i := argCount; i < paramCount; i++ {
-						paramName := fn.Parameters[i].Value
-						if _, ok := fn.Defaults[paramName]; ok {
-							missingWithDefaults++
-						}
-					}
-					if missingWithDefaults == missing {
-						function = fn
-						matched = true
-						break
-					}
-				}
-			}
-			if !matched {
+			argCount := len(positional) + len(keywords.Pairs)
+			matched := matchOverload(overloads, argCount)
+			if matched == nil {
 				// Improved error message with line number and code snippet
-				return newError("No matching overload for function '%s' with %d arguments at line %d. Source: %s", ident.Value, len(node.Arguments), node.Token.Line, node.Function.String())
+				return newError("No matching overload for function '%s' with %d arguments at line %d. Source: %s", node.Function.String(), argCount, node.Token.Line, node.Function.String())
 			}
+			function = matched
 		}
-	}
 
-	// Evaluate the arguments based on the function type
-	switch fn := function.(type) {
-	case *object.Function:
-		// If it's a regular function, evaluate its arguments
-		args = evalArgsExpressions(node, fn, env)
-	case *object.Package:
-		// If it's a package, look for the 'init' function inside the package
-		obj, ok := fn.Scope.Get("init")
-		if !ok {
-			return newError("Package does not have 'init'") // Return an error if 'init' is not found in the package
-		}
-		initFn, ok := obj.(*object.Function)
-		if !ok {
-			return newError("Package 'init' must be a function, got %s", obj.Type())
+		target, ok := function.(*object.Function)
+		if pkg, isPackage := function.(*object.Package); isPackage {
+			// If it's a package, look for the 'init' function inside the package
+			obj, found := pkg.Scope.Get("init")
+			if !found {
+				return newError("Package does not have 'init'") // Return an error if 'init' is not found in the package
+			}
+			if target, ok = obj.(*object.Function); !ok {
+				return newError("Package 'init' must be a function, got %s", obj.Type())
+			}
 		}
-		// If 'init' is found, evaluate its arguments
-		args = evalArgsExpressions(node, initFn, env)
-	case *object.Struct:
-		// Struct instantiation: User(name = "Alice", age = 30) or User("Alice", 30)
-		return evalStructCall(node, fn, env)
+		args = bindArguments(target, positional, keywords, env)
 	default:
 		// If the function is of unknown type, evaluate the arguments in the default manner
 		args = evalExpressions(node.Arguments, env)
@@ -91,56 +67,118 @@ func evalCall(node *ast.CallExpression, env *object.Environment) object.VintObje
 	return applyFunction(function, args, node.Token.Line)
 }
 
-// evalArgsExpressions evaluates the arguments passed to the function call.
-// It handles both positional arguments and keyword arguments (assigned with `=`).
-func evalArgsExpressions(node *ast.CallExpression, fn *object.Function, env *object.Environment) []object.VintObject {
-	// Initialize an array for positional arguments and a dictionary for keyword arguments
-	argsList := &object.Array{}
-	argsHash := &object.Dict{}
-	argsHash.Pairs = make(map[object.HashKey]object.DictPair)
+// matchOverload returns the overload that takes argCount arguments, or nil
+// if there is none. An overload matches if it has that many parameters, or
+// more with defaults for the rest. A variadic overload matches any count
+// that covers its parameters without defaults, and is only chosen if no
+// other overload matches exactly.
+func matchOverload(overloads []*object.Function, argCount int) *object.Function {
+	var variadic *object.Function
+	for _, fn := range overloads {
+		paramCount := len(fn.Parameters)
+		required := 0
+		for i, param := range fn.Parameters {
+			if fn.Variadic && i == paramCount-1 {
+				break
+			}
+			if _, ok := fn.Defaults[param.Value]; !ok {
+				required = i + 1
+			}
+		}
 
-	// Iterate through the arguments in the function call expression
-	for _, exprr := range node.Arguments {
+		if fn.Variadic {
+			if argCount >= required && variadic == nil {
+				variadic = fn
+			}
+			continue
+		}
+		// Allow match if all missing params have defaults
+		if argCount <= paramCount && argCount >= required {
+			return fn
+		}
+	}
+	return variadic
+}
+
+// evalArguments evaluates the arguments of a call. Positional arguments
+// are returned in order, with ...spread arrays expanded into them, and
+// keyword arguments (assigned with `=`) in a dict by name.
+func evalArguments(arguments []ast.Expression, env *object.Environment) ([]object.VintObject, *object.Dict, object.VintObject) {
+	var positional []object.VintObject
+	keywords := object.NewDict()
+
+	for _, exprr := range arguments {
 		switch exp := exprr.(type) {
 		case *ast.Assign:
 			// If the argument is an assignment (i.e., a keyword argument)
 			val := Eval(exp.Value, env)
 			if isError(val) {
-				return []object.VintObject{val} // Return error if evaluation fails
+				return nil, nil, val // Return error if evaluation fails
 			}
-			var keyHash object.HashKey
 			key := &object.String{Value: exp.Name.Value}
-			keyHash = key.HashKey()
 			// Add the keyword argument to the dictionary
-			pair := object.DictPair{Key: key, Value: val}
-			argsHash.Pairs[keyHash] = pair
+			keywords.SetPair(key.HashKey(), object.DictPair{Key: key, Value: val})
+		case *ast.SpreadExpression:
+			elements, errObj := evalSpread(exp, env)
+			if errObj != nil {
+				return nil, nil, errObj
+			}
+			positional = append(positional, elements...)
 		default:
 			// For regular arguments, evaluate the expression and add to the positional argument list
 			evaluated := Eval(exp, env)
 			if isError(evaluated) {
-				return []object.VintObject{evaluated} // Return error if evaluation fails
+				return nil, nil, evaluated // Return error if evaluation fails
 			}
-			argsList.Elements = append(argsList.Elements, evaluated)
+			positional = append(positional, evaluated)
 		}
 	}
 
+	return positional, keywords, nil
+}
+
+// evalSpread evaluates ...value in a call or array literal, which must be
+// an array, and returns its elements.
+func evalSpread(node *ast.SpreadExpression, env *object.Environment) ([]object.VintObject, object.VintObject) {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return nil, val
+	}
+	arr, ok := val.(*object.Array)
+	if !ok {
+		return nil, newError("Line %d: cannot spread %s, only arrays can be spread into arguments and array literals", node.Token.Line, val.Type())
+	}
+	return arr.Elements, nil
+}
+
+// bindArguments matches the evaluated arguments of a call to the
+// function's parameters: positional arguments first, then keyword
+// arguments, then defaults. The arguments for a variadic parameter are
+// left in place at the end for applyFunction to collect.
+func bindArguments(fn *object.Function, positional []object.VintObject, keywords *object.Dict, env *object.Environment) []object.VintObject {
 	// Prepare the final list of arguments, ensuring they match the function's parameters
 	var result []object.VintObject
 	var params = map[string]bool{}
-	for _, exp := range fn.Parameters {
+	for i, exp := range fn.Parameters {
 		params[exp.Value] = true
-		if len(argsList.Elements) > 0 {
+		if fn.Variadic && i == len(fn.Parameters)-1 {
+			// The variadic parameter takes whatever positional arguments remain
+			result = append(result, positional...)
+			positional = nil
+			break
+		}
+		if len(positional) > 0 {
 			// Use the positional arguments first
-			result = append(result, argsList.Elements[0])
-			argsList.Elements = argsList.Elements[1:]
+			result = append(result, positional[0])
+			positional = positional[1:]
 		} else {
 			// If no more positional arguments, try to use keyword arguments
 			keyParam := &object.String{Value: exp.Value}
 			keyParamHash := keyParam.HashKey()
-			if valParam, ok := argsHash.Pairs[keyParamHash]; ok {
+			if valParam, ok := keywords.Pairs[keyParamHash]; ok {
 				// If a keyword argument is found for the parameter, use it
 				result = append(result, valParam.Value)
-				delete(argsHash.Pairs, keyParamHash)
+				keywords.Delete(keyParamHash)
 			} else {
 				// If no value is found for the parameter, check if a default value is provided
 				if _e, _ok := fn.Defaults[exp.Value]; _ok {
@@ -158,8 +196,11 @@ func evalArgsExpressions(node *ast.CallExpression, fn *object.Function, env *obj
 	}
 
 	// Check if any extra keyword arguments are provided that don't match function parameters
-	for _, pair := range argsHash.Pairs {
+	for _, pair := range keywords.OrderedPairs() {
 		kwName := pair.Key.(*object.String).Value
+		if fn.Variadic && kwName == fn.Parameters[len(fn.Parameters)-1].Value {
+			return []object.VintObject{&object.Error{Message: "Variadic parameter '" + kwName + "' cannot be passed by keyword"}}
+		}
 		if _, ok := params[kwName]; ok {
 			return []object.VintObject{&object.Error{Message: "Multiple arguments for a single parameter"}} // Return error if multiple values are given for a parameter
 		}
@@ -0,0 +1,74 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// evalChain evaluates a property, method, index, slice or call expression,
+// any of whose links may be optional, as in resp?.data.user?.name.
+func evalChain(node ast.Expression, env *object.Environment) object.VintObject {
+	result, _ := evalLink(node, env)
+	return result
+}
+
+// evalLink evaluates one link of a chain, after the links before it. A ?.
+// link whose object is null short-circuits: it and every link after it in
+// the chain give null without being evaluated, so a?.b.c is null when a
+// is. The second result reports whether that happened.
+func evalLink(node ast.Expression, env *object.Environment) (object.VintObject, bool) {
+	var inner ast.Expression
+	var optional bool
+	switch node := node.(type) {
+	case *ast.PropertyExpression:
+		inner, optional = node.Object, node.Optional
+	case *ast.MethodExpression:
+		inner, optional = node.Object, node.Optional
+	case *ast.IndexExpression:
+		inner, optional = node.Left, node.Optional
+	case *ast.SliceExpression:
+		inner, optional = node.Left, node.Optional
+	case *ast.CallExpression:
+		inner, optional = node.Function, node.Optional
+	default:
+		return Eval(node, env), false
+	}
+
+	obj, skipped := evalLink(inner, env)
+	if skipped || (optional && obj == NULL) {
+		return NULL, true
+	}
+	if isError(obj) {
+		return obj, false
+	}
+
+	switch node := node.(type) {
+	case *ast.PropertyExpression:
+		return evalProperty(obj, node), false
+	case *ast.MethodExpression:
+		return evalMethodExpression(obj, node, env), false
+	case *ast.IndexExpression:
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index, false
+		}
+		return evalIndexExpression(obj, index, node.Token.Line), false
+	case *ast.SliceExpression:
+		var start, end object.VintObject
+		if node.Start != nil {
+			start = Eval(node.Start, env)
+			if isError(start) {
+				return start, false
+			}
+		}
+		if node.End != nil {
+			end = Eval(node.End, env)
+			if isError(end) {
+				return end, false
+			}
+		}
+		return evalSliceExpression(obj, start, end, node.Token.Line), false
+	default:
+		return evalCall(node.(*ast.CallExpression), obj, env), false
+	}
+}
@@ -0,0 +1,32 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+)
+
+// Coverage is told which statements run and which branches of if, switch
+// and match expressions are taken.
+//
+// For an if, branch 0 is the consequence and branch 1 the alternative,
+// which is taken when the condition is false even if there is no else.
+// For a switch or match, branch i is the i-th case, and branch
+// len(cases) is taken when no case matches and there is no default.
+type Coverage interface {
+	Statement(stmt ast.Statement)
+	Branch(node ast.Expression, branch int)
+}
+
+var coverage Coverage
+
+// SetCoverage installs the coverage told about statements and branches,
+// or removes it if c is nil. It should be set before the program starts.
+func SetCoverage(c Coverage) {
+	coverage = c
+}
+
+// coverBranch tells the coverage that a branch of node was taken.
+func coverBranch(node ast.Expression, branch int) {
+	if coverage != nil {
+		coverage.Branch(node, branch)
+	}
+}
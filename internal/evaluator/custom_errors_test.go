@@ -125,4 +125,130 @@ func TestErrorParameterValidation(t *testing.T) {
 			t.Errorf("wrong error message. expected=%q, got=%q", tt.expectedError, errObj.Message)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestTryCatchFinally(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			// Typed catch clause receives the custom error with its fields
+			"error NotFound(path); let r = ''; try { throw NotFound('/a') } catch (e: NotFound) { r = e.path }; r",
+			"/a",
+		},
+		{
+			// Clauses are tried in order, untyped catch is the fallback
+			"error NotFound(path); error Denied(user); let r = ''; try { throw Denied('bob') } catch (e: NotFound) { r = 'nf' } catch (e) { r = e.user }; r",
+			"bob",
+		},
+		{
+			// Runtime errors are caught as RuntimeError
+			"let r = ''; try { let x = 1 / 0 } catch (e: RuntimeError) { r = e.name }; r",
+			"RuntimeError",
+		},
+		{
+			// Finally runs on success and after a catch
+			"let r = ''; try { r = r + 'a' } catch { r = r + 'b' } finally { r = r + 'c' }; try { throw 'x' } catch { r = r + 'd' } finally { r = r + 'e' }; r",
+			"acde",
+		},
+		{
+			// Finally runs before the function's deferred calls, even on return
+			"let out = []; let add = func(x) { out.push(x) }; let f = func() { defer add('defer'); try { return 'try' } finally { add('finally') } }; let v = f(); add(v); out.join(',')",
+			"finally,defer,try",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("input %q: expected String, got=%T (%s)", tt.input, evaluated, evaluated.Inspect())
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("input %q: expected=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+}
+
+func TestUncaughtErrorPropagates(t *testing.T) {
+	input := "error NotFound(path); error Denied(user); let r = ''; try { throw NotFound('x') } catch (e: Denied) { r = 'wrong' } finally { r = 'fin' }; r"
+
+	evaluated := testEval(input)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("expected Error, got=%T (%s)", evaluated, evaluated.Inspect())
+	}
+	ce, ok := errObj.Thrown.(*object.CustomError)
+	if !ok || ce.ErrorType.Name != "NotFound" {
+		t.Errorf("expected thrown NotFound to be preserved, got=%v", errObj.Thrown)
+	}
+}
+
+func TestRuntimeErrorLocation(t *testing.T) {
+	input := `let divide = func(a, b) {
+    return a / b
+}
+
+let compute = func(x) {
+    let y = x + 1
+    return divide(y, 0)
+}
+
+compute(3)`
+
+	result := testEval(input)
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected Error, got %T (%+v)", result, result)
+	}
+	if errObj.File != "main.vint" || errObj.Line != 2 || errObj.Column != 14 {
+		t.Errorf("wrong error position: %s:%d:%d", errObj.File, errObj.Line, errObj.Column)
+	}
+
+	expected := []object.StackFrame{
+		{Function: "<main>", File: "main.vint", Line: 10, Column: 1},
+		{Function: "compute", File: "main.vint", Line: 7, Column: 12},
+		{Function: "divide", File: "main.vint", Line: 2, Column: 14},
+	}
+	frames := errObj.Traceback()
+	if len(frames) != len(expected) {
+		t.Fatalf("expected %d frames, got %d: %+v", len(expected), len(frames), frames)
+	}
+	for i, frame := range frames {
+		if frame != expected[i] {
+			t.Errorf("frame %d: expected %+v, got %+v", i, expected[i], frame)
+		}
+	}
+}
+
+func TestStructMethodErrorFrame(t *testing.T) {
+	input := `struct Account {
+    balance: 0
+    func withdraw(amount) {
+        if (amount > this.balance) {
+            throw "insufficient funds"
+        }
+    }
+}
+
+let acc = Account()
+acc.withdraw(10)`
+
+	result := testEval(input)
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected Error, got %T (%+v)", result, result)
+	}
+	frames := errObj.Traceback()
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d: %+v", len(frames), frames)
+	}
+	if frames[0].Function != "<main>" || frames[0].Line != 11 {
+		t.Errorf("unexpected outer frame: %+v", frames[0])
+	}
+	if frames[1].Function != "Account.withdraw" || frames[1].Line != 5 {
+		t.Errorf("unexpected inner frame: %+v", frames[1])
+	}
+}
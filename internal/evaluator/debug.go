@@ -0,0 +1,139 @@
+package evaluator
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// DebugHook is called before each statement runs, with the thread running
+// it and the call stack of that thread, outermost call first. The last
+// frame is positioned at the statement, and its Env is the scope the
+// statement runs in. The stack is a copy the hook may keep.
+//
+// The hook runs on the goroutine evaluating the statement, and the
+// statement waits for it to return, which is how a debugger pauses the
+// program.
+type DebugHook func(thread int, stmt ast.Statement, stack []Frame)
+
+// Frame is a call on the stack a DebugHook is given: the function being
+// run, the position it has reached and the scope it is in. The outermost
+// frame is the top level of the program, called <main>, or for another
+// thread <goroutine> or <async>, positioned where the thread was started.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	Column   int
+	Env      *object.Environment
+}
+
+var (
+	debugHook   DebugHook
+	debugMu     sync.Mutex          // protects debugStacks
+	debugStacks = map[int][]Frame{} // by thread
+)
+
+// SetDebugHook installs the hook called before every statement, or removes
+// it if hook is nil. Without a hook, statements run at full speed.
+func SetDebugHook(hook DebugHook) {
+	debugMu.Lock()
+	debugHook = hook
+	debugStacks = map[int][]Frame{}
+	debugMu.Unlock()
+	restartThreads()
+}
+
+// DebugThreads returns the threads running while a hook is installed,
+// MainThread first.
+func DebugThreads() []int {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	threads := []int{MainThread}
+	for thread := range debugStacks {
+		if thread != MainThread {
+			threads = append(threads, thread)
+		}
+	}
+	sort.Ints(threads)
+	return threads
+}
+
+// statementHooks tells whichever of the debug hook, the profiler and the
+// coverage are installed that stmt is about to run in env.
+func statementHooks(stmt ast.Statement, env *object.Environment) {
+	if coverage != nil {
+		coverage.Statement(stmt)
+	}
+	if profiler != nil {
+		profileStatement(stmt)
+	}
+	if debugHook != nil {
+		debugStatement(stmt, env)
+	}
+}
+
+// debugStatement moves the innermost frame to stmt and calls the hook.
+func debugStatement(stmt ast.Statement, env *object.Environment) {
+	tok, ok := nodeToken(stmt)
+	if !ok {
+		return
+	}
+	thread := currentThread()
+	debugMu.Lock()
+	hook := debugHook
+	if hook == nil {
+		debugMu.Unlock()
+		return
+	}
+	frames := debugStacks[thread]
+	if len(frames) == 0 {
+		frames = append(frames, Frame{Function: "<main>"})
+	}
+	top := &frames[len(frames)-1]
+	top.File, top.Line, top.Column, top.Env = tok.File, tok.Line, tok.Column, env
+	debugStacks[thread] = frames
+	stack := append([]Frame(nil), frames...)
+	debugMu.Unlock()
+
+	hook(thread, stmt, stack)
+}
+
+// debugEnter pushes a frame for a call while a hook is installed, and
+// returns the function that pops it again. The frame gets its position
+// from the first statement of the call.
+func debugEnter(function string) func() {
+	return debugPush(Frame{Function: function})
+}
+
+// debugPush pushes a frame onto the stack of the calling thread, and
+// returns the function that pops it again. A thread other than
+// MainThread is forgotten once its stack is empty.
+func debugPush(frame Frame) func() {
+	if debugHook == nil {
+		return func() {}
+	}
+	thread := currentThread()
+	debugMu.Lock()
+	frames := debugStacks[thread]
+	if len(frames) == 0 && thread == MainThread {
+		frames = append(frames, Frame{Function: "<main>"})
+	}
+	frames = append(frames, frame)
+	debugStacks[thread] = frames
+	depth := len(frames)
+	debugMu.Unlock()
+
+	return func() {
+		debugMu.Lock()
+		if frames := debugStacks[thread]; len(frames) >= depth {
+			debugStacks[thread] = frames[:depth-1]
+		}
+		if len(debugStacks[thread]) == 0 && thread != MainThread {
+			delete(debugStacks, thread)
+		}
+		debugMu.Unlock()
+	}
+}
@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vintlang/vintlang/internal/ast"
+)
+
+func TestDebugHook(t *testing.T) {
+	var steps []string
+	SetDebugHook(func(thread int, stmt ast.Statement, stack []Frame) {
+		var names []string
+		for _, frame := range stack {
+			names = append(names, fmt.Sprintf("%s:%d", frame.Function, frame.Line))
+		}
+		top := stack[len(stack)-1]
+		if _, ok := top.Env.Get("n"); ok && top.Function == "double" {
+			names = append(names, "(n visible)")
+		}
+		steps = append(steps, strings.Join(names, " "))
+	})
+	t.Cleanup(func() { SetDebugHook(nil) })
+
+	testEval(`let double = func(n) {
+    let r = n * 2
+    return r
+}
+let x = double(2)
+if (x > 3) {
+    x = 0
+}`)
+
+	want := []string{
+		"<main>:1",
+		"<main>:5",
+		"<main>:5 double:2 (n visible)",
+		"<main>:5 double:3 (n visible)",
+		"<main>:6",
+		"<main>:7",
+	}
+	if strings.Join(steps, "\n") != strings.Join(want, "\n") {
+		t.Errorf("hook saw\n%s\nwant\n%s", strings.Join(steps, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// recordingProfiler writes down what it is told.
+type recordingProfiler struct{ events []string }
+
+func (r *recordingProfiler) Enter(thread int, function, file string, line int) {
+	r.events = append(r.events, fmt.Sprintf("enter %s:%d", function, line))
+}
+func (r *recordingProfiler) Leave(thread int) { r.events = append(r.events, "leave") }
+func (r *recordingProfiler) Statement(thread int, file string, line int) {
+	r.events = append(r.events, fmt.Sprintf("line %d", line))
+}
+
+func TestProfiler(t *testing.T) {
+	r := &recordingProfiler{}
+	SetProfiler(r)
+	t.Cleanup(func() { SetProfiler(nil) })
+
+	testEval(`import math
+let root = func(n) {
+    return math.sqrt(n)
+}
+let x = len([root(4)])`)
+
+	want := []string{
+		"line 1",
+		"line 2",
+		"line 5",
+		"enter root:2",
+		"line 3",
+		"enter math.sqrt:0",
+		"leave",
+		"leave",
+		"enter len:0",
+		"leave",
+	}
+	if strings.Join(r.events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("profiler saw\n%s\nwant\n%s", strings.Join(r.events, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// recordingCoverage writes down the lines and branches it is told about.
+type recordingCoverage struct{ events []string }
+
+func (r *recordingCoverage) Statement(stmt ast.Statement) {
+	tok, _ := nodeToken(stmt)
+	r.events = append(r.events, fmt.Sprintf("line %d", tok.Line))
+}
+func (r *recordingCoverage) Branch(node ast.Expression, branch int) {
+	tok, _ := nodeToken(node)
+	r.events = append(r.events, fmt.Sprintf("%s %d", tok.Literal, branch))
+}
+
+func TestCoverage(t *testing.T) {
+	r := &recordingCoverage{}
+	SetCoverage(r)
+	t.Cleanup(func() { SetCoverage(nil) })
+
+	testEval(`package p {
+    let x = 1
+}
+if (p.x > 1) {
+    x = 2
+}
+switch (3) {
+    case 1 {
+        x = 1
+    }
+}
+match 2 {
+    1 => { x = 1 }
+    _ => { x = 0 }
+}`)
+
+	want := []string{
+		"line 1",
+		"line 2",
+		"line 4",
+		"if 1",
+		"line 7",
+		"switch 1",
+		"line 12",
+		"match 1",
+		"line 14",
+	}
+	if strings.Join(r.events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("coverage saw\n%s\nwant\n%s", strings.Join(r.events, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,31 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// evalDestructureStatement binds the names in a let or const pattern,
+// using the same pattern binding as match.
+func evalDestructureStatement(node *ast.DestructureStatement, env *object.Environment) object.VintObject {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	bind := env.Define
+	if node.Token.Type == token.CONST {
+		bind = env.DefineConst
+	}
+	return destructure(val, node.Pattern, env, bind)
+}
+
+// destructure binds the names in pattern to the parts of val, or returns
+// an error saying why val does not have the pattern's shape.
+func destructure(val object.VintObject, pattern ast.Expression, env *object.Environment, bind func(string, object.VintObject) object.VintObject) object.VintObject {
+	if reason := bindPattern(val, pattern, env, bind); reason != "" {
+		return newError("cannot destructure %s into %s: %s", val.Type(), pattern.String(), reason)
+	}
+	return val
+}
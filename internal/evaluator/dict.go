@@ -6,11 +6,26 @@ import (
 )
 
 func evalDictLiteral(node *ast.DictLiteral, env *object.Environment) object.VintObject {
-	// Create a map to store key-value pairs for the dictionary
-	pairs := make(map[object.HashKey]object.DictPair)
+	dict := object.NewDict()
 
-	// Iterate over the pairs in the dictionary literal node
-	for keyNode, valueNode := range node.Pairs {
+	// Iterate over the pairs in the dictionary literal node, in source order
+	for _, keyNode := range node.OrderedKeys() {
+		valueNode := node.Pairs[keyNode]
+		if _, ok := keyNode.(*ast.SpreadExpression); ok {
+			// {...other} copies the pairs of another dict, in its order
+			value := Eval(valueNode, env)
+			if isError(value) {
+				return value
+			}
+			other, ok := value.(*object.Dict)
+			if !ok {
+				return newError("Line %d: cannot spread %s, only dicts can be spread into a dict literal", node.Token.Line, value.Type())
+			}
+			for _, pair := range other.OrderedPairs() {
+				dict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
+			}
+			continue
+		}
 		// Evaluate the key and check for errors
 		key := Eval(keyNode, env)
 		if isError(key) {
@@ -29,11 +44,11 @@ func evalDictLiteral(node *ast.DictLiteral, env *object.Environment) object.Vint
 			return value // Return the error if value evaluation fails
 		}
 
-		// Add the key-value pair to the dictionary's map
+		// Add the key-value pair to the dictionary
 		hashed := hashKey.HashKey()
-		pairs[hashed] = object.DictPair{Key: key, Value: value}
+		dict.SetPair(hashed, object.DictPair{Key: key, Value: value})
 	}
 
 	// Return the constructed dictionary object
-	return &object.Dict{Pairs: pairs}
+	return dict
 }
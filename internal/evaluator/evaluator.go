@@ -26,7 +26,18 @@ func init() {
 	})
 }
 
+// Eval evaluates a node. Errors coming out of it are stamped with the
+// source position of the innermost node they surfaced at, and with the
+// call sites they unwind through (see locateError).
 func Eval(node ast.Node, env *object.Environment) object.VintObject {
+	result := evalNode(node, env)
+	if errObj, ok := result.(*object.Error); ok {
+		locateError(errObj, node)
+	}
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.VintObject {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -37,6 +48,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 
+	case *ast.BigIntLiteral:
+		return &object.BigInt{Value: node.Value}
+
 	case *ast.FloatLiteral:
 		return &object.Float{Value: node.Value}
 
@@ -44,6 +58,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 		return nativeBoolToBooleanObject(node.Value)
 
 	case *ast.PrefixExpression:
+		if node.Operator == "&" {
+			return evalAddressOf(node, env)
+		}
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
@@ -76,6 +93,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 		}
 		return &object.ReturnValue{Value: val}
 
+	case *ast.YieldStatement:
+		return evalYield(node, env)
+
 	case *ast.LetStatement:
 		val := Eval(node.Value, env)
 		if isError(val) {
@@ -95,8 +115,7 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 				return val
 			}
 			if !compatible(node.TypeAnnotation.Type, val) {
-				return newTypeError(node.Token.Line,
-					"cannot assign %s to variable '%s' of type %s",
+				return newTypeError("cannot assign %s to variable '%s' of type %s",
 					val.Type(), node.Name.Value, node.TypeAnnotation.String())
 			}
 		} else {
@@ -107,6 +126,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 		}
 		return env.DefineTyped(node.Name.Value, val, node.TypeAnnotation.Type)
 
+	case *ast.DestructureStatement:
+		return evalDestructureStatement(node, env)
+
 	case *ast.ConstStatement:
 		val := Eval(node.Value, env)
 		if isError(val) {
@@ -120,6 +142,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 	case *ast.StructStatement:
 		return evalStructStatement(node, env)
 
+	case *ast.InterfaceStatement:
+		return env.Define(node.Name.Value, &object.Interface{Def: node.Type})
+
 	case *ast.StructLiteral:
 		return evalStructLiteral(node, env)
 
@@ -129,17 +154,20 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 	case *ast.FunctionLiteral:
 		return evalFunction(node, env)
 
+	case *ast.InstantiateExpression:
+		return evalInstantiate(node, env)
+
 	case *ast.TypedFunctionLiteral:
 		return evalTypedFunction(node, env)
 
 	case *ast.MethodExpression:
-		return evalMethodExpression(node, env)
+		return evalChain(node, env)
 
 	case *ast.Import:
 		return evalImport(node, env)
 
 	case *ast.CallExpression:
-		return evalCall(node, env)
+		return evalChain(node, env)
 
 	case *ast.TypeCastExpression:
 		return evalTypeCast(node, env)
@@ -151,8 +179,16 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 		// Type aliases are stored and resolved during parsing for now
 		return NULL
 
+	case *ast.TestStatement:
+		// Test blocks are collected and run by `vint test`
+		return NULL
+
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+	case *ast.Interpolation:
+		return evalInterpolation(node, env)
 	case *ast.At:
 		return evalAt(node, env)
 	case *ast.BuiltinExpression:
@@ -170,34 +206,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 	case *ast.RangeExpression:
 		return evalRangeExpression(node, env)
 	case *ast.IndexExpression:
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
-		index := Eval(node.Index, env)
-		if isError(index) {
-			return index
-		}
-		return evalIndexExpression(left, index, node.Token.Line)
+		return evalChain(node, env)
 	case *ast.SliceExpression:
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
-		var start, end object.VintObject
-		if node.Start != nil {
-			start = Eval(node.Start, env)
-			if isError(start) {
-				return start
-			}
-		}
-		if node.End != nil {
-			end = Eval(node.End, env)
-			if isError(end) {
-				return end
-			}
-		}
-		return evalSliceExpression(left, start, end, node.Token.Line)
+		return evalChain(node, env)
 	case *ast.DictLiteral:
 		return evalDictLiteral(node, env)
 	case *ast.WhileExpression:
@@ -219,7 +230,7 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 	case *ast.Package:
 		return evalPackage(node, env)
 	case *ast.PropertyExpression:
-		return evalPropertyExpression(node, env)
+		return evalChain(node, env)
 	case *ast.PropertyAssignment:
 		val := Eval(node.Value, env)
 		if isError(val) {
@@ -232,6 +243,9 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 		return evalAssignEqual(node, env)
 
 	case *ast.AssignmentExpression:
+		if deref, ok := node.Left.(*ast.PrefixExpression); ok && deref.Operator == "*" {
+			return evalPointerAssignment(node, deref, env)
+		}
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -264,42 +278,11 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 			if isError(obj) {
 				return obj
 			}
-
-			if array, ok := obj.(*object.Array); ok {
-				index := Eval(ie.Index, env)
-				if isError(index) {
-					return index
-				}
-				if idx, ok := index.(*object.Integer); ok {
-					arrayLen := int64(len(array.Elements))
-					actualIdx := idx.Value
-					// Support Python-style negative indexing
-					if actualIdx < 0 {
-						actualIdx = arrayLen + actualIdx
-					}
-					if actualIdx < 0 || actualIdx >= arrayLen {
-						return newError("Line %d: Array index %d out of bounds. Array length is %d", node.Token.Line, idx.Value, arrayLen)
-					}
-					array.Elements[actualIdx] = value
-					return value
-				} else {
-					return newError("Line %d: Array index must be an integer, got %s", node.Token.Line, index.Type())
-				}
-			} else if hash, ok := obj.(*object.Dict); ok {
-				key := Eval(ie.Index, env)
-				if isError(key) {
-					return key
-				}
-				if hashKey, ok := key.(object.Hashable); ok {
-					hashed := hashKey.HashKey()
-					hash.Pairs[hashed] = object.DictPair{Key: key, Value: value}
-					return value
-				} else {
-					return newError("Cannot perform this operation with %T", key)
-				}
-			} else {
-				return newError("%T does not support this operation", obj)
+			index := Eval(ie.Index, env)
+			if isError(index) {
+				return index
 			}
+			return evalIndexAssignment(obj, index, value, node.Token.Line)
 		} else {
 			return newError("Use an identifier instead of %T", node.Left)
 		}
@@ -442,10 +425,14 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 	case *ast.AsyncFunctionLiteral:
 		return &object.AsyncFunction{
 			Parameters: node.Parameters,
+			Variadic:   node.Variadic,
 			Body:       node.Body,
 			Env:        env,
 		}
 
+	case *ast.SpreadExpression:
+		return newError("Line %d: '...' can only be used in calls, array literals and dict literals", node.Token.Line)
+
 	case *ast.AwaitExpression:
 		promise := Eval(node.Value, env)
 		if isError(promise) {
@@ -467,9 +454,7 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 
 	case *ast.GoStatement:
 		// Execute the expression concurrently
-		go func() {
-			Eval(node.Expression, env)
-		}()
+		go runThread("<goroutine>", node.Expression, env)
 		return NULL
 
 	case *ast.ChannelExpression:
@@ -494,6 +479,12 @@ func Eval(node ast.Node, env *object.Environment) object.VintObject {
 
 	case *ast.ThrowStatement:
 		return evalThrowStatement(node, env)
+
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+
+	case *ast.SelectStatement:
+		return evalSelectStatement(node, env)
 	}
 
 	return newError("Unhandled AST node type: %T", node)
@@ -504,6 +495,9 @@ func evalProgram(program *ast.Program, env *object.Environment) object.VintObjec
 
 	// First pass: Execute all statements to define functions and variables
 	for _, statement := range program.Statements {
+		if debugHook != nil || profiler != nil || coverage != nil {
+			statementHooks(statement, env)
+		}
 		result = Eval(statement, env)
 
 		switch result := result.(type) {
@@ -561,10 +555,10 @@ func newLineError(line int, format string, a ...any) *object.Error {
 	return &object.Error{Message: fmt.Sprintf("line %d: %s", line, msg)}
 }
 
-// newTypeError creates a type error with source location.
-func newTypeError(line int, format string, a ...any) *object.Error {
-	msg := fmt.Sprintf(format, a...)
-	return &object.Error{Message: fmt.Sprintf("TypeError at line %d: %s", line, msg)}
+// newTypeError creates a type error. Eval stamps it with the position of
+// the node it surfaced at.
+func newTypeError(format string, a ...any) *object.Error {
+	return &object.Error{Message: "TypeError: " + fmt.Sprintf(format, a...)}
 }
 
 // Helper function to check if an object is an error
@@ -580,6 +574,15 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Vi
 	var result []object.VintObject
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			elements, errObj := evalSpread(spread, env)
+			if errObj != nil {
+				return []object.VintObject{errObj}
+			}
+			result = append(result, elements...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.VintObject{evaluated}
@@ -594,13 +597,19 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Vi
 func applyFunction(fn object.VintObject, args []object.VintObject, line int) object.VintObject {
 	switch fn := fn.(type) {
 	case *object.Function:
-		// Check argument types against parameter types
+		// Check argument types against parameter types, binding the type
+		// parameters of a generic function as they are met
+		bindings := newTypeArgs(fn.TypeParams, fn.TypeArgs)
 		for i, arg := range args {
-			if i < len(fn.ParamTypes) && fn.ParamTypes[i] != nil {
-				if !compatible(fn.ParamTypes[i], arg) {
+			if fn.Variadic && i >= len(fn.ParamTypes) {
+				// Every extra argument is checked against the variadic parameter
+				i = len(fn.ParamTypes) - 1
+			}
+			if i >= 0 && i < len(fn.ParamTypes) && fn.ParamTypes[i] != nil {
+				if !bindings.check(fn.ParamTypes[i], arg) {
 					paramName := fn.Parameters[i].Value
-					return newTypeError(line, "parameter '%s' expects %s, got %s",
-						paramName, fn.ParamTypes[i].String(), arg.Type())
+					return newTypeError("parameter '%s' expects %s, got %s",
+						paramName, bindings.show(fn.ParamTypes[i]), arg.Type())
 				}
 			}
 		}
@@ -608,7 +617,12 @@ func applyFunction(fn object.VintObject, args []object.VintObject, line int) obj
 			fn.Env.Define(fn.Name, fn)
 		}
 		extendedEnv := extendedFunctionEnv(fn, args)
+		if fn.Generator {
+			return newGenerator(functionName(fn), fn.Body, extendedEnv)
+		}
 		extendedEnv.MarkAsFuncScope()
+		defer debugEnter(functionName(fn))()
+		defer profileEnter(fn)()
 		defer func() {
 			for _, dc := range extendedEnv.PopDefers() {
 				applyFunction(dc.Fn, dc.Args, 0)
@@ -616,36 +630,43 @@ func applyFunction(fn object.VintObject, args []object.VintObject, line int) obj
 		}()
 		evaluated := Eval(fn.Body, extendedEnv)
 		result := unwrapReturnValue(evaluated)
+		if errObj, ok := result.(*object.Error); ok {
+			errObj.PushFrame(functionName(fn))
+		}
 		// Check return type (skip if result is an error)
 		if fn.ReturnType != nil && !isError(result) {
-			if !compatible(fn.ReturnType, result) {
-				return newTypeError(line, "function returns %s, but body returned %s",
-					fn.ReturnType.String(), result.Type())
+			if !bindings.check(fn.ReturnType, result) {
+				return newTypeError("function returns %s, but body returned %s",
+					bindings.show(fn.ReturnType), result.Type())
 			}
 		}
 		return result
 	case *object.AsyncFunction:
 		// Execute async function and return a promise
-		return fn.Execute(args, Eval)
+		return fn.Execute(args, func(body ast.Node, env *object.Environment) object.VintObject {
+			return runThread("<async>", body, env)
+		})
 	case *object.Builtin:
 		// Check argument types against declared parameter types
 		for i, arg := range args {
 			if i < len(fn.ParamTypes) && fn.ParamTypes[i] != nil {
 				if !compatible(fn.ParamTypes[i], arg) {
-					return newTypeError(line, "argument %d to builtin '%s' expects %s, got %s",
+					return newTypeError("argument %d to builtin '%s' expects %s, got %s",
 						i+1, "builtin", fn.ParamTypes[i].String(), arg.Type())
 				}
 			}
 		}
 		// Call the builtin function
+		leave := profileEnter(fn)
 		result := fn.Fn(args...)
+		leave()
 		if result == nil {
 			return NULL
 		}
 		// Check return type against declared return type
 		if fn.ReturnType != nil && !isError(result) {
 			if !compatible(fn.ReturnType, result) {
-				return newTypeError(line, "builtin returns %s, but got %s",
+				return newTypeError("builtin returns %s, but got %s",
 					fn.ReturnType.String(), result.Type())
 			}
 		}
@@ -710,6 +731,11 @@ func extendedFunctionEnv(
 ) *object.Environment {
 	env := object.NewEnclosedEnvironment(fn.Env)
 	for i, param := range fn.Parameters {
+		if fn.Variadic && i == len(fn.Parameters)-1 {
+			// The variadic parameter collects the remaining arguments
+			env.Define(param.Value, object.VariadicArgs(args, i))
+			break
+		}
 		if i < len(args) {
 			// Type check argument against parameter type
 			if i < len(fn.ParamTypes) && fn.ParamTypes[i] != nil {
@@ -888,11 +914,9 @@ func evalThrowStatement(node *ast.ThrowStatement, env *object.Environment) objec
 		return errorExpr
 	}
 
-	// If it's a custom error, wrap it in a regular error for propagation
-	if customError, ok := errorExpr.(*object.CustomError); ok {
-		return newError("thrown: %s", customError.Inspect())
-	}
-
-	// If it's any other type, create a regular error
-	return newError("thrown: %s", errorExpr.Inspect())
+	// Wrap the value in a regular error for propagation, keeping the
+	// original so that a catch clause can bind it with its fields intact
+	err := newError("thrown: %s", errorExpr.Inspect())
+	err.Thrown = errorExpr
+	return err
 }
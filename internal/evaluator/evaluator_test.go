@@ -152,6 +152,203 @@ func TestChannels(t *testing.T) {
 	}
 }
 
+func TestGoStatementsWithSync(t *testing.T) {
+	input := `
+import sync
+
+let wg = sync.waitGroup()
+let mu = sync.mutex()
+let hits = sync.atomic()
+let total = 0
+
+let worker = func(n) {
+    for i in range(0, 50) {
+        hits.increment()
+        mu.lock()
+        total = total + 1
+        mu.unlock()
+    }
+    wg.done()
+}
+
+for n in range(0, 8) {
+    wg.add(1)
+    go worker(n)
+}
+wg.wait()
+let result = [hits.get(), total]
+result
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	result := Eval(program, object.NewEnvironment())
+
+	if result.Inspect() != "[400, 400]" {
+		t.Errorf("wrong result. expected=%q, got=%q", "[400, 400]", result.Inspect())
+	}
+}
+
+func TestSelectStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let a = chan
+let b = chan
+go send(b, "from b")
+select {
+    case v = receive(a) { "a: " + v }
+    case v = receive(b) { "b: " + v }
+}`, "b: from b"},
+		{`let a = chan
+select {
+    case receive(a) { "received" }
+    case timeout(10ms) { "timed out" }
+}`, "timed out"},
+		{`let a = chan(1)
+let first = ""
+select {
+    case send(a, 1) { first = "sent" }
+    default { first = "full" }
+}
+select {
+    case send(a, 2) { first + ", sent" }
+    default { first + ", full" }
+}`, "sent, full"},
+		{`let a = chan(1)
+send(a, 7)
+close(a)
+let r = []
+select { case v, ok = receive(a) { r.push([v, ok]) } }
+select { case v, ok = receive(a) { r.push([v, ok]) } }
+r`, "[[7, true], [null, false]]"},
+		{`let never = null
+select {
+    case receive(never) { "never" }
+    default { "default" }
+}`, "default"},
+		{`let a = chan(1)
+let r = [trySend(a, 1), trySend(a, 2), tryReceive(a), tryReceive(a)]
+r`, "[true, false, [1, true], [null, false]]"},
+		{`select { case receive(5) { 1 } }`, "ERROR: select: receive() needs a channel, got INTEGER"},
+		{`let a = chan(1)
+close(a)
+select { case send(a, 1) { 1 } }`, "ERROR: select: send on closed channel"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestDictInsertionOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"zeta": 1, "alpha": 2, "mid": 3}`, "{zeta: 1, alpha: 2, mid: 3}"},
+		{`let d = {"b": 1, "a": 2}; d["c"] = 3; d.keys()`, "[b, a, c]"},
+		{`let d = {"b": 1, "a": 2}; d["b"] = 5; d.values()`, "[5, 2]"},
+		{`let d = {"b": 1, "a": 2, "c": 3}; d.remove("b"); d["b"] = 4; d`, "{a: 2, c: 3, b: 4}"},
+		{`let r = []; for k, v in {"y": 1, "x": 2, "w": 3} { r.push(k) }; r`, "[y, x, w]"},
+		{`{"y": 1, "x": 2}.entries()`, "[[y, 1], [x, 2]]"},
+		{`{"c": 1, "b": 2, "a": 3}.filter(func(k, v) { v != 2 })`, "{c: 1, a: 3}"},
+		{`{"c": 1, "a": 2}.map(func(k, v) { v * 10 })`, "{c: 10, a: 20}"},
+		{`{"b": 1, "a": 2} + {"c": 3, "b": 4}`, "{b: 4, a: 2, c: 3}"},
+		{`{"b": {"y": 1}, "a": 2}.deepMerge({"b": {"x": 2}})`, "{b: {y: 1, x: 2}, a: 2}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [a, b] = [1, 2]; [b, a]`, "[2, 1]"},
+		{`let [first, ...rest] = [1, 2, 3]; [first, rest]`, "[1, [2, 3]]"},
+		{`let [x, ...rest] = [1]; rest`, "[]"},
+		{`let [x, y = 10, z = x + 1] = [5]; [x, y, z]`, "[5, 10, 6]"},
+		{`let [a, [b, c]] = [1, [2, 3]]; a + b + c`, "6"},
+		{`let {name, age: years, ...others} = {"name": "Ana", "age": 30, "city": "Dar"}; [name, years, others]`, "[Ana, 30, {city: Dar}]"},
+		{`let {nick = "none", "full name": full} = {"full name": "Ana B"}; [nick, full]`, "[none, Ana B]"},
+		{`const [c] = [1]; c = 2`, "ERROR: Cannot assign to constant 'c'"},
+		{`let r = []; for [k, v] in [["a", 1], ["b", 2]] { r.push(k + string(v)) }; r`, "[a1, b2]"},
+		{`let r = []; for i, {id} in [{"id": 7}, {"id": 8}] { r.push(i * id) }; r`, "[0, 8]"},
+		{`match [1, 2, 3] { [h, ...t] => { t } }`, "[2, 3]"},
+		{`let [a, b] = [1, 2, 3]`, "ERROR: cannot destructure ARRAY into [a, b]: expected 2 elements, got 3; add ...rest to collect the others"},
+		{`let [a, b, ...c] = [1]`, "ERROR: cannot destructure ARRAY into [a, b, ...c]: expected at least 2 elements, got 1"},
+		{`let [a] = "a"`, "ERROR: cannot destructure STRING into [a]: expected an array, got STRING"},
+		{`let {name} = {"age": 1}`, `ERROR: cannot destructure DICT into {name}: missing key "name"`},
+		{`let {a} = [1]`, "ERROR: cannot destructure ARRAY into {a}: expected a dict, got ARRAY"},
+		{`let a = 1; let [a] = [2]`, "ERROR: cannot destructure ARRAY into [a]: Identifier 'a' has already been declared"},
+		{`for [k, v] in [[1, 2], [3]] { k }`, "ERROR: cannot destructure ARRAY into [k, v]: expected 2 elements, got 1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestVariadicAndSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let f = func(level, ...parts) { [level, parts] }; f("info")`, "[info, []]"},
+		{`let f = func(level, ...parts) { [level, parts] }; f("info", 1, 2)`, "[info, [1, 2]]"},
+		{`let f = func(a, b, ...c) { [a, b, c] }; let xs = [2, 3, 4]; f(1, ...xs)`, "[1, 2, [3, 4]]"},
+		{`let f = func(a, b = 5, ...c) { [a, b, c] }; f(1)`, "[1, 5, []]"},
+		{`let sum = func(...xs: int): int { let t = 0; for x in xs { t += x }; t }; sum(1, 2, 3)`, "6"},
+		{`let sum = func(...xs: int): int { 0 }; sum(1, "2")`, "ERROR: TypeError: parameter 'xs' expects int, got STRING"},
+		{`let f = func(a) { "one" }; let f = func(a, ...rest) { "many" }; [f(1), f(1, 2), f(...[1])]`, "[one, many, one]"},
+		{`let f = func(a, b) { "two" }; f(...[1, 2, 3])`, "ERROR: No matching overload for function 'f' with 3 arguments at line 1. Source: f"},
+		{`let f = func(...xs) { xs }; f(xs = 1)`, "ERROR: Variadic parameter 'xs' cannot be passed by keyword"},
+		{`let g = async func(...xs) { xs }; await g(1, 2)`, "[1, 2]"},
+		{`struct S { func add(base, ...xs: int) { base + len(xs) } }; let s = S(); [s.add(10), s.add(10, ...[1, 2])]`, "[10, 12]"},
+		{`struct S { func add(...xs: int) { xs } }; S().add(1, true)`, "ERROR: TypeError: parameter 'xs' in method 'add' expects int, got BOOLEAN"},
+		{`struct P { x, y }; let p = P(...[1, 2]); p.y`, "2"},
+		{`len(...[[1, 2, 3]])`, "3"},
+		{`let a = [1, 2]; [...a, 0, ...a]`, "[1, 2, 0, 1, 2]"},
+		{`let d = {"a": 1, "b": 2}; {...d, "b": 3, ...{"c": 4}}`, "{a: 1, b: 3, c: 4}"},
+		{`[...5]`, "ERROR: Line 1: cannot spread INTEGER, only arrays can be spread into arguments and array literals"},
+		{`{...[1]}`, "ERROR: Line 1: cannot spread ARRAY, only dicts can be spread into a dict literal"},
+		{`let a = ...[1]`, "ERROR: Line 1: '...' can only be used in calls, array literals and dict literals"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
 func testIntegerObject(t *testing.T, obj object.VintObject, expected int64) bool {
 	result, ok := obj.(*object.Integer)
 	if !ok {
@@ -180,6 +377,284 @@ func testStringObject(t *testing.T, obj object.VintObject, expected string) bool
 	return true
 }
 
+func TestBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`0xFF`, "255"},
+		{`0b1010_0101`, "165"},
+		{`0o755`, "493"},
+		{`1_000_000`, "1000000"},
+		{`12 & 10`, "8"},
+		{`12 | 3`, "15"},
+		{`12 ^ 10`, "6"},
+		{`1 << 10`, "1024"},
+		{`-16 >> 2`, "-4"},
+		{`~0`, "-1"},
+		{`0xFF & ~0x0F`, "240"},
+		{`1 + 2 & 3`, "3"},
+		{`1 | 2 == 3`, "true"},
+		{`let flags = 0; flags |= 1 << 2; flags |= 1; flags &= ~1; flags ^= 0b11; flags <<= 1; flags >>= 2; flags`, "3"},
+		{`let a = [1]; a[0] <<= 4; a`, "[16]"},
+		{`1 << -1`, "ERROR: Line 1: Negative shift count: cannot shift by -1"},
+		{`1.5 & 1`, "ERROR: Line 1: Unsupported numeric operation: '&' operator cannot be used with FLOAT and INTEGER"},
+		{`~"a"`, "ERROR: Line 1: Unknown operation: ~STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestPointers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let x = 1; let p = &x; x = 2; *p`, "2"},
+		{`let x = 1; let p = &x; *p = 5; *p += 1; x`, "6"},
+		{"let x = 1\nlet p = &x\n*p = 7\nx", "7"},
+		{`let inc = func(p: *int) { *p = *p + 1 }; let n = 1; inc(&n); inc(&n); n`, "3"},
+		{`let f = func() { let local = 1; return &local }; let p = f(); *p = 4; *p`, "4"},
+		{`struct U { age: int }; let u = U(age = 1); let p = &u.age; *p = 30; u.age`, "30"},
+		{`let a = [1, 2, 3]; let p = &a[-1]; *p = 9; a[1] = 8; [a, *p]`, "[[1, 8, 9], 9]"},
+		{`let p = &5; *p = 6; *p`, "6"},
+		{`let p: *int = null; p`, "null"},
+		{`let x: int = 1; let p = &x; *p = "s"`, "ERROR: TypeError: cannot assign STRING to variable 'x' of type int"},
+		{`let x = 1; let p: *int = &x; *p = "s"`, "ERROR: TypeError: cannot assign STRING through 'p' of type *int"},
+		{`let s = "a"; let p: *int = &s`, "ERROR: TypeError: cannot assign POINTER to variable 'p' of type : *int"},
+		{`struct U { age: int }; let u = U(age = 1); let p = &u.age; *p = "x"`, "ERROR: TypeError: cannot assign STRING to field 'age' in struct 'U' of type int"},
+		{`const k = 1; let p = &k; *p = 2`, "ERROR: Line 1: Cannot assign to constant 'k'"},
+		{`let p = null; *p`, "ERROR: Line 1: nil pointer dereference"},
+		{`let p: *int = null; *p = 1`, "ERROR: Line 1: nil pointer dereference"},
+		{`let a = [1, 2]; let p = &a[1]; a.pop(); *p`, "ERROR: Line 1: pointer to index 1 of an array that now has length 1"},
+		{`let a = [1]; &a[3]`, "ERROR: Line 1: Array index 3 out of bounds. Array length is 1"},
+		{`let x = 1; *x = 2`, "ERROR: Line 1: cannot assign through non-pointer INTEGER"},
+		{`let calls = 0; let x = 1; let next = func() { calls += 1; return &x }; *next() += 2; [x, calls]`, "[3, 1]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestOptionalChaining(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let resp = {"data": {"user": null}}; resp?.["data"]?.["user"]?.["name"]`, "null"},
+		{`let resp = {"data": {"user": {"name": "Ann"}}}; resp?.["data"]?.["user"]?.["name"]`, "Ann"},
+		{`let resp = {"data": null}; resp["data"]?.["user"] ?? "anonymous"`, "anonymous"},
+		{`let resp = {"data": {"user": null}}; resp?.data?.user?.name ?? "anonymous"`, "anonymous"},
+		{`let resp = {"data": {"user": {"name": "Ann"}}}; resp?.data?.user?.name`, "Ann"},
+		{`let resp = {"data": {}}; resp.data.user`, "null"},
+		{`let a = null; a?.[0]`, "null"},
+		{`let a = null; a?.[1:]`, "null"},
+		{`let a = [1, 2, 3]; a?.[1:]`, "[2, 3]"},
+		{`let f = null; f?.(1)`, "null"},
+		{`let f = func(x) { return x * 2 }; f?.(21)`, "42"},
+		{`let s = null; s?.upper()`, "null"},
+		{`let s = "vint"; s?.upper()`, "VINT"},
+		{`struct U { name: string }; let u = U(name = "Ann"); u?.name`, "Ann"},
+		{`struct U { name: string }; let u = null; u?.name ?? "nobody"`, "nobody"},
+		// A null link skips the rest of the chain, not just the next link
+		{`let a = null; a?.b.c.d()`, "null"},
+		{`let a = null; a?.[0][1].upper()`, "null"},
+		// Nothing after the null link is evaluated
+		{`let calls = 0; let next = func() { calls += 1; return 0 }; let a = null; a?.[next()]; a?.(next()); a?.b(next()); calls`, "0"},
+		// Only null short-circuits, and only at a ?. link
+		{`let a = null; a?.b.c; a.b`, "ERROR: Value b is not valid for null"},
+		{`let a = [null]; a?.[0].upper()`, "ERROR: Sorry, null does not have a function 'upper()'"},
+		{`let n = 0; n?.[0]`, "ERROR: Line 1: This operation is not possible for: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	count := "let count = func(n) { let i = 0; while (i < n) { yield i; i++ } }; "
+	naturals := "let naturals = func() { let n = 1; while (true) { yield n; n++ } }; "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{count + "let out = []; for x in count(3) { out.push(x) }; out", "[0, 1, 2]"},
+		{count + "let out = []; for i, x in count(2) { out.push([i, x]) }; out", "[[0, 0], [1, 1]]"},
+		{count + "let g = count(2); [g.next(), g.next(), g.next()]", "[0, 1, null]"},
+		{count + "count(2)", "generator(count)"},
+		{count + "count(4).toArray()", "[0, 1, 2, 3]"},
+		{count + "count(5).reduce(func(a, b) { return a + b })", "10"},
+		{count + "count(0).reduce(func(a, b) { return a + b }, 7)", "7"},
+		// The body only runs as values are asked for
+		{"let ran = 0; let g = func() { ran += 1; yield 1 }; let gen = g(); let before = ran; gen.next(); [before, ran]", "[0, 1]"},
+		{naturals + "naturals().map(func(x) { return x * x }).filter(func(x) { return x % 2 == 1 }).take(3).toArray()", "[1, 9, 25]"},
+		{naturals + "let out = []; for n in naturals() { if (n > 3) { break }; out.push(n) }; out", "[1, 2, 3]"},
+		// Leaving a loop early closes the generator, running its finally blocks
+		{"let events = []; let g = func() { try { yield 1; yield 2 } finally { events.push(\"closed\") } }; for x in g() { events.push(x); break }; events", "[1, closed]"},
+		{"let events = []; let g = func() { defer func() { events.push(\"deferred\") }(); yield 1; yield 2 }; let gen = g(); gen.next(); gen.close(); [events, gen.next()]", "[[deferred], null]"},
+		// Generators nest, and a yield inside a nested loop suspends the whole body
+		{count + "let pairs = func() { for a in count(2) { for b in count(2) { yield [a, b] } } }; pairs().toArray()", "[[0, 0], [0, 1], [1, 0], [1, 1]]"},
+		{"struct Countdown { from: int; func iter() { let i = this.from; while (i > 0) { yield i; i-- } } }; let out = []; for c in Countdown(from = 3) { out.push(c) }; out", "[3, 2, 1]"},
+		{"struct Box { items: array; func iter() { return this.items } }; let out = []; for x in Box(items = [4, 5]) { out.push(x) }; out", "[4, 5]"},
+		{"struct Pages { page: int; func next() { this.page = this.page + 1; if (this.page > 2) { return null }; return this.page } }; let out = []; for p in Pages(page = 0) { out.push(p) }; out", "[1, 2]"},
+		{count + "let ch = chan(3); go func() { for x in count(3) { send(ch, x * 10) }; close(ch) }(); let out = []; for v in ch { out.push(v) }; out", "[0, 10, 20]"},
+		{"let g = func() { yield 1; throw \"boom\" }; let out = []; for x in g() { out.push(x) }", "ERROR: thrown: boom"},
+		{"let g = func() { yield 1; throw \"boom\" }; g().toArray()", "ERROR: thrown: boom"},
+		{count + "count(2).map(func(x) { return x / 0 }).toArray()", "ERROR: Line 1: Division by zero: cannot divide by zero"},
+		{"struct Plain { x: int }; for v in Plain(x = 1) { }", "ERROR: Line 1: for..in loop requires an iterable object, but struct 'Plain' has no iter() or next() method"},
+		{count + "count(1).take(-1)", "ERROR: Line 1: take() expects a count that is not negative, got -1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestBigNumbers(t *testing.T) {
+	fact := "let fact = func(n) { if (n <= 1) { return 1 }; return n * fact(n - 1) }; "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// Integers that overflow become bigints instead of wrapping around
+		{fact + "fact(25)", "15511210043330985984000000"},
+		{fact + "type(fact(25))", "BIGINT"},
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"let x = 9223372036854775807; x++; x", "9223372036854775808"},
+		{"let x = 4611686018427387904; x *= 4; x", "18446744073709551616"},
+		{"1 << 70", "1180591620717411303424"},
+		{"[2 ** 10, (-3) ** 3, 0 ** 0, 2 ** 62, 2 ** 63, 2 ** 70]", "[1024, -27, 1, 4611686018427387904, 9223372036854775808, 1180591620717411303424]"},
+		{"[type(2 ** 3), type(2 ** 64), 2 ** -2]", "[INTEGER, BIGINT, 0.25]"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"99999999999999999999 - 99999999999999999998", "1"},
+		{"3.factorial() + 0", "6"},
+		// Bigint literals and operators
+		{"123n", "123"},
+		{"type(5n)", "BIGINT"},
+		{"[5n / 2, 6n / 2, 5n % 3, 2n ** 100, 5n * 1.5]", "[2.5, 3, 2, 1267650600228229401496703205376, 7.5]"},
+		{"[5n == 5, 5 < 6n, -5n, ~5n, 6n & 3, 1n << 3]", "[true, true, -5, -6, 2, 8]"},
+		{`{5: "five"}[5n]`, "five"},
+		{"let n: int = 2n ** 64; n", "18446744073709551616"},
+		{"let n: bigint = 5; n", "ERROR: TypeError: cannot assign INTEGER to variable 'n' of type : bigint"},
+		{"5n / 0", "ERROR: Line 1: Division by zero: cannot divide by zero"},
+		// Decimals keep every digit of money amounts
+		{`decimal("0.10") + decimal("0.20")`, "0.30"},
+		{`decimal("19.99") * 3`, "59.97"},
+		{`decimal("19.99") + 0.01`, "20.00"},
+		{`decimal("10") / 4`, "2.5"},
+		{`decimal("1") / 3`, "0.3333333333333333333333333333"},
+		{`[decimal("10") % 3, decimal("1.1") ** 2, decimal("2") ** -2]`, "[1, 1.21, 0.25]"},
+		{`[decimal("19.99") > 19.98, decimal("1.50") == decimal("1.5"), decimal("2") < 3n]`, "[true, true, true]"},
+		{`let total: decimal = decimal("0"); for p in [decimal("0.10"), decimal("0.20")] { total += p }; total`, "0.30"},
+		{`type(decimal("1.5"))`, "DECIMAL"},
+		{`decimal("abc")`, "ERROR: Cannot convert to decimal: 'abc' is not a decimal number"},
+		{`decimal("1") / 0`, "ERROR: Line 1: Division by zero: cannot divide by zero"},
+		{`decimal("2") ** 0.5`, "ERROR: Line 1: The power of a decimal must be an integer, got FLOAT"},
+		// pow() is exact like **, and is_number() knows every kind of number
+		{`[pow(2, 70), pow(2n, 3), pow(decimal("1.5"), 2), pow(2, -1), pow(decimal("2.25"), 0.5)]`, "[1180591620717411303424, 8, 2.25, 0.5, 1.5]"},
+		{`[type(pow(2, 3)), type(pow(2n, 3)), is_number(2n), is_number(decimal("1"))]`, "[INTEGER, BIGINT, true, true]"},
+		// Rounding
+		{`[decimal("2.5").round(), decimal("3.5").round(), decimal("2.5").round(0, "half_up")]`, "[2, 4, 3]"},
+		{`[decimal("19.99").round(1), decimal("19.99").round(1, "down"), decimal("-1.25").round(1, "floor")]`, "[20.0, 19.9, -1.3]"},
+		{`decimal("100").div(3, 2)`, "33.33"},
+		{`let p = decimal("19.99"); "${p:.1f}|${p:8.2f}"`, "20.0|   19.99"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let user = {"name": "Ann"}; let items = [1, 2]; "Hello ${user["name"]}, you have ${len(items)} items"`, "Hello Ann, you have 2 items"},
+		{"let n = 3; `${n} * 2 = ${n * 2}`", "3 * 2 = 6"},
+		{`"${[1, "a"]} ${true} ${null}"`, "[1, a] true null"},
+		{`let price = 3.14159; "${price:.2f}|${price:8.3f}|${2:.1f}"`, "3.14|   3.142|2.0"},
+		{`"[${"ab":-4}] ${255:x} ${5:03d} ${"q":q} ${1.5:6}"`, `[ab  ] ff 005 "q"    1.5`},
+		{`"cost: \${x}"`, "cost: ${x}"},
+		{`let x = 1; "${"nested ${x + 1}"}!"`, "nested 2!"},
+		{`let f = func(s) { "<" + s + ">" }; f("${1}${2}")`, "<12>"},
+		{`"${"x":d}"`, "ERROR: Line 1: cannot format STRING with 'd'"},
+		{`"${1:%d}"`, "ERROR: Line 1: invalid format spec '%d'"},
+		{`"a ${missing} b"`, "ERROR: Line 1: Identifier not recognized: missing"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = "ERROR: " + errObj.Message
+		}
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+
+	// Errors inside an interpolation point at the expression that failed
+	positions := []struct {
+		input        string
+		line, column int
+	}{
+		{"let a = 1\nlet s = \"x ${a + nope}\"", 2, 18},
+		{"let a = 1\nlet s = `x\n  ${a:d} ${\n  1 / \"a\"}`", 4, 5},
+		{"let s = \"x\"\nlet t = \"${s:d}\"", 2, 10},
+	}
+	for _, tt := range positions {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q: expected an error", tt.input)
+			continue
+		}
+		if errObj.Line != tt.line || errObj.Column != tt.column {
+			t.Errorf("%q: error at %d:%d, expected %d:%d (%s)", tt.input, errObj.Line, errObj.Column, tt.line, tt.column, errObj.Message)
+		}
+	}
+}
+
 func TestRangeExpressions(t *testing.T) {
 	tests := []struct {
 		input    string
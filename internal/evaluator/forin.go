@@ -12,12 +12,30 @@ func evalForInExpression(fie *ast.ForIn, env *object.Environment, line int) obje
 		return iterable
 	}
 
+	return loopOver(iterable, env, fie, line)
+}
+
+// loopOver runs a for..in loop over the value of its iterable expression.
+func loopOver(iterable object.VintObject, env *object.Environment, fie *ast.ForIn, line int) object.VintObject {
 	// Check if the iterable object supports iteration
 	switch i := iterable.(type) {
 	case object.Iterable:
 		// Create an isolated iterator to avoid conflicts with nested loops
 		iterator := createIsolatedIterator(i)
 		return loopIterable(iterator.Next, env, fie, line) // Start looping through the iterable
+	case *object.Generator:
+		// Values are taken one at a time, and a loop left early with break
+		// or return closes the generator
+		result := loopIterable(counted(func() (object.VintObject, bool) {
+			return i.Next()
+		}), env, fie, line)
+		i.Close()
+		return result
+	case *object.Channel:
+		// Receives until the channel is closed
+		return loopIterable(counted(i.Receive), env, fie, line)
+	case *object.StructInstance:
+		return loopStruct(i, env, fie, line)
 	default:
 		// Returns an error if the iterable object does not support iteration
 		return newError("Line %d: for..in loop requires an iterable object, but got %s", line, i.Type())
@@ -89,7 +107,11 @@ func loopIterable(
 	for k != nil {
 		loopEnv := object.NewEnclosedEnvironment(env)
 		loopEnv.Define(fi.Key, k)
-		if fi.Value != "" {
+		if fi.Pattern != nil {
+			if err := destructure(v, fi.Pattern, loopEnv, loopEnv.Define); isError(err) {
+				return err
+			}
+		} else if fi.Value != "" {
 			loopEnv.Define(fi.Value, v)
 		}
 		ret = Eval(fi.Block, loopEnv)
@@ -110,5 +132,49 @@ func loopIterable(
 		}
 		k, v = next()
 	}
+	if isError(v) {
+		// Getting the next item failed
+		return v
+	}
 	return NULL
 }
+
+// counted turns a source of values, which reports false when it has no
+// more, into a next function for loopIterable that gives each value with
+// its index. A source that fails returns its error as its last value.
+func counted(source func() (object.VintObject, bool)) func() (object.VintObject, object.VintObject) {
+	index := int64(-1)
+	return func() (object.VintObject, object.VintObject) {
+		value, ok := source()
+		if !ok {
+			return nil, nil
+		}
+		if isError(value) {
+			return nil, value
+		}
+		index++
+		return &object.Integer{Value: index}, value
+	}
+}
+
+// loopStruct loops over a struct instance that is iterable. A struct with
+// an iter() method is looped over by looping over what iter() returns,
+// and one with a next() method by calling next() until it returns null.
+func loopStruct(si *object.StructInstance, env *object.Environment, fie *ast.ForIn, line int) object.VintObject {
+	if _, ok := si.GetMethod("iter"); ok {
+		iterable := callStructMethod(si, "iter", nil, nil, line)
+		if isError(iterable) {
+			return iterable
+		}
+		if iterable != si {
+			return loopOver(iterable, env, fie, line)
+		}
+	}
+	if _, ok := si.GetMethod("next"); !ok {
+		return newError("Line %d: for..in loop requires an iterable object, but struct '%s' has no iter() or next() method", line, si.Struct.Name)
+	}
+	return loopIterable(counted(func() (object.VintObject, bool) {
+		value := callStructMethod(si, "next", nil, nil, line)
+		return value, value != NULL
+	}), env, fie, line)
+}
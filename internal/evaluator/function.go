@@ -10,6 +10,8 @@ func evalFunction(node *ast.FunctionLiteral, env *object.Environment) object.Vin
 		Name:       node.Name,
 		Parameters: node.Parameters,
 		Defaults:   node.Defaults,
+		Variadic:   node.Variadic,
+		Generator:  node.Generator,
 		Body:       node.Body,
 		Env:        env,
 	}
@@ -38,6 +40,9 @@ func evalTypedFunction(node *ast.TypedFunctionLiteral, env *object.Environment)
 		ParamTypes: paramTypes,
 		ReturnType: node.ReturnType,
 		Defaults:   defaults,
+		Variadic:   len(node.Parameters) > 0 && node.Parameters[len(node.Parameters)-1].Variadic,
+		TypeParams: node.TypeParams,
+		Generator:  node.Generator,
 		Body:       node.Body,
 		Env:        env,
 	}
@@ -0,0 +1,159 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// newGenerator returns the generator made by calling a function that
+// yields, called name, with its parameters bound in env. The body does
+// not start until the first value is asked for.
+func newGenerator(name string, body *ast.BlockStatement, env *object.Environment) *object.Generator {
+	env.MarkAsFuncScope()
+	return object.NewBodyGenerator(name, func(yield func(object.VintObject) bool) object.VintObject {
+		env.SetYield(yield)
+		defer func() {
+			for _, dc := range env.PopDefers() {
+				applyFunction(dc.Fn, dc.Args, 0)
+			}
+		}()
+		result := unwrapReturnValue(Eval(body, env))
+		if errObj, ok := result.(*object.Error); ok {
+			errObj.PushFrame(name)
+			return errObj
+		}
+		return nil
+	})
+}
+
+// evalYield hands a value to whoever is iterating over the generator
+// running the statement. If the generator is closed while it waits, the
+// body returns from the yield, running its finally blocks and deferred
+// calls on the way out.
+func evalYield(node *ast.YieldStatement, env *object.Environment) object.VintObject {
+	yield := env.Yield()
+	if yield == nil {
+		return newError("Line %d: yield outside a generator function", node.Token.Line)
+	}
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+	if !yield(value) {
+		return &object.ReturnValue{Value: NULL}
+	}
+	return NULL
+}
+
+// generatorMethod calls the method called name on a generator, or returns
+// nil if there is no such method. map, filter and take return generators
+// that take values from g only as their own values are asked for.
+func generatorMethod(g *object.Generator, name string, args []object.VintObject, line int) object.VintObject {
+	switch name {
+	case "next":
+		if len(args) != 0 {
+			return newError("Line %d: next() takes no arguments, got %d", line, len(args))
+		}
+		value, ok := g.Next()
+		if !ok {
+			return NULL
+		}
+		return value
+	case "toArray":
+		if len(args) != 0 {
+			return newError("Line %d: toArray() takes no arguments, got %d", line, len(args))
+		}
+		elements := []object.VintObject{}
+		for {
+			value, ok := g.Next()
+			if !ok {
+				return &object.Array{Elements: elements}
+			}
+			if isError(value) {
+				return value
+			}
+			elements = append(elements, value)
+		}
+	case "map", "filter":
+		if len(args) != 1 || !isCallable(args[0]) {
+			return newError("Line %d: %s() expects a function", line, name)
+		}
+		fn := args[0]
+		return object.NewGenerator(g.Name, func() (object.VintObject, bool) {
+			for {
+				value, ok := g.Next()
+				if !ok || isError(value) {
+					return value, ok
+				}
+				result := applyFunction(fn, []object.VintObject{value}, line)
+				switch {
+				case isError(result):
+					g.Close()
+					return result, true
+				case name == "map":
+					return result, true
+				case isTruthy(result):
+					return value, true
+				}
+			}
+		}, g.Close)
+	case "take":
+		if len(args) != 1 {
+			return newError("Line %d: take() expects 1 argument, got %d", line, len(args))
+		}
+		n, ok := args[0].(*object.Integer)
+		if !ok || n.Value < 0 {
+			return newError("Line %d: take() expects a count that is not negative, got %s", line, args[0].Inspect())
+		}
+		taken := int64(0)
+		return object.NewGenerator(g.Name, func() (object.VintObject, bool) {
+			if taken == n.Value {
+				g.Close()
+				return nil, false
+			}
+			taken++
+			return g.Next()
+		}, g.Close)
+	case "reduce":
+		if len(args) < 1 || len(args) > 2 || !isCallable(args[0]) {
+			return newError("Line %d: reduce() expects a function and an optional initial value", line)
+		}
+		var acc object.VintObject
+		if len(args) == 2 {
+			acc = args[1]
+		}
+		for {
+			value, ok := g.Next()
+			if !ok {
+				break
+			}
+			if isError(value) {
+				return value
+			}
+			if acc == nil {
+				acc = value
+				continue
+			}
+			if acc = applyFunction(args[0], []object.VintObject{acc, value}, line); isError(acc) {
+				g.Close()
+				return acc
+			}
+		}
+		if acc == nil {
+			return newError("Line %d: reduce() of an empty generator with no initial value", line)
+		}
+		return acc
+	case "close":
+		g.Close()
+		return NULL
+	}
+	return nil
+}
+
+func isCallable(obj object.VintObject) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// typeArgs holds what the type parameters of a generic function or struct
+// stand for in one call or instance. A type parameter is bound to the type
+// of the first value given for it, and later values must have that type.
+// A nil typeArgs is used for functions and structs that are not generic.
+type typeArgs map[string]ast.Type
+
+// newTypeArgs starts the type arguments of a call or an instance from the
+// ones given explicitly, as in first<int>(xs). It returns nil if there are
+// no type parameters.
+func newTypeArgs(params []*ast.TypeParameter, explicit map[string]ast.Type) typeArgs {
+	if len(params) == 0 {
+		return nil
+	}
+	ta := typeArgs{}
+	for name, t := range explicit {
+		ta[name] = t
+	}
+	return ta
+}
+
+// check reports whether obj can be used where declared is expected, binding
+// the type parameters in declared that are not bound yet.
+func (ta typeArgs) check(declared ast.Type, obj object.VintObject) bool {
+	if ta == nil || !hasTypeParams(declared) {
+		return compatible(declared, obj)
+	}
+	switch t := declared.(type) {
+	case *ast.TypeParameter:
+		if bound, ok := ta[t.Name]; ok {
+			return compatible(bound, obj)
+		}
+		if !compatible(t, obj) {
+			return false
+		}
+		ta[t.Name] = inferType(obj)
+		return true
+	case *ast.ArrayType:
+		return ta.checkElements(t.ElementType, obj)
+	case *ast.FixedArrayType:
+		return ta.checkElements(t.ElementType, obj)
+	case *ast.DictType:
+		dict, ok := obj.(*object.Dict)
+		if !ok {
+			return false
+		}
+		for _, pair := range dict.OrderedPairs() {
+			if !ta.check(t.KeyType, pair.Key) || !ta.check(t.ValueType, pair.Value) {
+				return false
+			}
+		}
+		return true
+	case *ast.GenericType:
+		return isInstanceOf(obj, t, ta.match)
+	}
+	return compatible(declared, obj)
+}
+
+func (ta typeArgs) checkElements(elem ast.Type, obj object.VintObject) bool {
+	arr, ok := obj.(*object.Array)
+	if !ok {
+		return false
+	}
+	for _, el := range arr.Elements {
+		if !ta.check(elem, el) {
+			return false
+		}
+	}
+	return true
+}
+
+// match is like check for a type rather than a value, such as a type
+// argument of an instance.
+func (ta typeArgs) match(declared, actual ast.Type) bool {
+	if tp, ok := declared.(*ast.TypeParameter); ok {
+		if _, bound := ta[tp.Name]; !bound {
+			ta[tp.Name] = actual
+			return true
+		}
+	}
+	return sameType(ta.substitute(declared), actual)
+}
+
+// substitute replaces the type parameters in t that are bound. An unbound
+// type parameter is replaced by its constraint, which is what a value for
+// it must satisfy.
+func (ta typeArgs) substitute(t ast.Type) ast.Type {
+	switch t := t.(type) {
+	case *ast.TypeParameter:
+		if bound, ok := ta[t.Name]; ok {
+			return bound
+		}
+		if t.Constraint != nil {
+			return t.Constraint
+		}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Token: t.Token, ElementType: ta.substitute(t.ElementType)}
+	case *ast.FixedArrayType:
+		return &ast.FixedArrayType{Token: t.Token, Size: t.Size, ElementType: ta.substitute(t.ElementType)}
+	case *ast.DictType:
+		return &ast.DictType{Token: t.Token, KeyType: ta.substitute(t.KeyType), ValueType: ta.substitute(t.ValueType)}
+	case *ast.PointerType:
+		return &ast.PointerType{Token: t.Token, BaseType: ta.substitute(t.BaseType)}
+	case *ast.GenericType:
+		generic := &ast.GenericType{Token: t.Token, Name: t.Name}
+		for _, arg := range t.Args {
+			generic.Args = append(generic.Args, ta.substitute(arg))
+		}
+		return generic
+	}
+	return t
+}
+
+// show names declared the way it is expected in an error message.
+func (ta typeArgs) show(declared ast.Type) string {
+	return ta.substitute(declared).String()
+}
+
+// hasTypeParams reports whether t mentions a type parameter.
+func hasTypeParams(t ast.Type) bool {
+	switch t := t.(type) {
+	case *ast.TypeParameter:
+		return true
+	case *ast.ArrayType:
+		return hasTypeParams(t.ElementType)
+	case *ast.FixedArrayType:
+		return hasTypeParams(t.ElementType)
+	case *ast.DictType:
+		return hasTypeParams(t.KeyType) || hasTypeParams(t.ValueType)
+	case *ast.PointerType:
+		return hasTypeParams(t.BaseType)
+	case *ast.GenericType:
+		for _, arg := range t.Args {
+			if hasTypeParams(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isInstanceOf reports whether obj is an instance of the generic struct
+// with type arguments that match the given ones. Type arguments that the
+// instance has not bound yet match anything.
+func isInstanceOf(obj object.VintObject, t *ast.GenericType, match func(declared, actual ast.Type) bool) bool {
+	instance, ok := obj.(*object.StructInstance)
+	if !ok || instance.Struct.Name != t.Name {
+		return false
+	}
+	for i, tp := range instance.Struct.TypeParams {
+		actual, known := instance.TypeArgs[tp.Name]
+		if i < len(t.Args) && known && !match(t.Args[i], actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// evalInstantiate gives a generic function or struct explicit type
+// arguments: first<int> or Box<string>.
+func evalInstantiate(node *ast.InstantiateExpression, env *object.Environment) object.VintObject {
+	target := Eval(node.Function, env)
+	if isError(target) {
+		return target
+	}
+
+	var params []*ast.TypeParameter
+	switch t := target.(type) {
+	case *object.Function:
+		params = t.TypeParams
+	case *object.Struct:
+		params = t.TypeParams
+	}
+	name := node.Function.String()
+	if len(params) == 0 {
+		return newError("Line %d: '%s' is not generic, so it cannot take type arguments", node.Token.Line, name)
+	}
+	if len(node.TypeArgs) != len(params) {
+		plural := "s"
+		if len(params) == 1 {
+			plural = ""
+		}
+		return newError("Line %d: '%s' takes %d type argument%s, got %d", node.Token.Line, name, len(params), plural, len(node.TypeArgs))
+	}
+
+	args := map[string]ast.Type{}
+	for i, tp := range params {
+		arg := node.TypeArgs[i]
+		if tp.Constraint != nil && !satisfiesConstraint(arg, tp.Constraint, env) {
+			return newTypeError("type argument %s for '%s' does not satisfy %s",
+				arg.String(), tp.Name, tp.Constraint.String())
+		}
+		args[tp.Name] = arg
+	}
+
+	switch t := target.(type) {
+	case *object.Function:
+		instantiated := *t
+		instantiated.TypeArgs = args
+		return &instantiated
+	default:
+		instantiated := *t.(*object.Struct)
+		instantiated.TypeArgs = args
+		return &instantiated
+	}
+}
+
+// satisfiesConstraint reports whether the type arg can stand for a type
+// parameter with the given constraint.
+func satisfiesConstraint(arg, constraint ast.Type, env *object.Environment) bool {
+	switch c := constraint.(type) {
+	case *ast.BasicType:
+		switch c.Name {
+		case "any":
+			return true
+		case "number":
+			runtime := typeNameToRuntime[arg.String()]
+			switch runtime {
+			case object.INTEGER_OBJ, object.FLOAT_OBJ, object.BIGINT_OBJ, object.DECIMAL_OBJ:
+				return true
+			}
+			return arg.String() == "number"
+		}
+	case *ast.InterfaceType:
+		if iface, ok := arg.(*ast.InterfaceType); ok {
+			return iface == c
+		}
+		name := arg.String()
+		if generic, ok := arg.(*ast.GenericType); ok {
+			name = generic.Name
+		}
+		def, ok := env.Get(name)
+		if s, isStruct := def.(*object.Struct); ok && isStruct {
+			return structImplements(s, c)
+		}
+		return false
+	}
+	return sameType(arg, constraint)
+}
@@ -13,10 +13,12 @@ func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Vint
 	}
 
 	if isTruthy(condition) {
+		coverBranch(ie, 0)
 		return Eval(ie.Consequence, env)
-	} else if ie.Alternative != nil {
+	}
+	coverBranch(ie, 1)
+	if ie.Alternative != nil {
 		return Eval(ie.Alternative, env)
-	} else {
-		return NULL
 	}
+	return NULL
 }
@@ -81,6 +81,13 @@ func evalImportFile(name string, ident *ast.Identifier, env *object.Environment)
 		return newError(ErrModuleNotFound, name, formattedPaths)
 	}
 
+	// The files of a package fetched with `vint get` can import each other
+	if dir := filepath.Dir(filename); filepath.Base(dir) == ident.Value {
+		addSearchPath(dir)
+	}
+
+	defer debugEnter("<import " + ident.Value + ">")()
+	defer profileCall("<import " + ident.Value + ">")()
 	importedObject, err := evaluateFile(filename)
 	if err != nil {
 		return newError(ErrImportFailed, name, err.Inspect())
@@ -166,6 +173,15 @@ func findFile(name string) string {
 			}
 		}
 	}
+
+	// Packages fetched with `vint get` are directories with a file of the
+	// same name: modules/strutil/strutil.vint
+	for _, path := range searchPaths {
+		file := filepath.Join(path, basename, basename+".vint")
+		if fileExists(file) {
+			return file
+		}
+	}
 	return ""
 }
 
@@ -205,7 +221,7 @@ func evaluateFile(file string) (object.VintObject, object.VintObject) {
 		}
 	}
 
-	l := lexer.New(string(source))
+	l := lexer.NewWithFilename(string(source), file)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
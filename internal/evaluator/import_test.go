@@ -83,3 +83,20 @@ func TestSuccessfulNestedImportChain(t *testing.T) {
 		}
 	}
 }
+
+// TestImportPackageDirectory verifies that a package fetched with `vint get`,
+// modules/<name>/<name>.vint, can be imported by name, and that its files
+// can import each other.
+func TestImportPackageDirectory(t *testing.T) {
+	dir := t.TempDir()
+	addSearchPath(dir)
+	t.Cleanup(func() { searchPaths = nil; importedModules = make(map[string]bool) })
+
+	pkg := filepath.Join(dir, "strutil")
+	os.Mkdir(pkg, 0755)
+	os.WriteFile(filepath.Join(pkg, "strutil.vint"), []byte("import helpers\npackage strutil {\n  let shout = func(s) { return helpers.upper(s) + \"!\" }\n}"), 0644)
+	os.WriteFile(filepath.Join(pkg, "helpers.vint"), []byte("package helpers {\n  let upper = func(s) { return s.upper() }\n}"), 0644)
+
+	result := testEval("import strutil\nstrutil.shout(\"hi\")")
+	testStringObject(t, result, "HI!")
+}
@@ -111,3 +111,34 @@ func evalDictIndexExpression(dict, index object.VintObject, line int) object.Vin
 
 	return pair.Value
 }
+
+// evalIndexAssignment stores value at obj[index] for arrays and dictionaries.
+func evalIndexAssignment(obj, index, value object.VintObject, line int) object.VintObject {
+	if array, ok := obj.(*object.Array); ok {
+		if idx, ok := index.(*object.Integer); ok {
+			arrayLen := int64(len(array.Elements))
+			actualIdx := idx.Value
+			// Support Python-style negative indexing
+			if actualIdx < 0 {
+				actualIdx = arrayLen + actualIdx
+			}
+			if actualIdx < 0 || actualIdx >= arrayLen {
+				return newError("Line %d: Array index %d out of bounds. Array length is %d", line, idx.Value, arrayLen)
+			}
+			array.Elements[actualIdx] = value
+			return value
+		} else {
+			return newError("Line %d: Array index must be an integer, got %s", line, index.Type())
+		}
+	} else if hash, ok := obj.(*object.Dict); ok {
+		if hashKey, ok := index.(object.Hashable); ok {
+			hashed := hashKey.HashKey()
+			hash.SetPair(hashed, object.DictPair{Key: index, Value: value})
+			return value
+		} else {
+			return newError("Cannot perform this operation with %T", index)
+		}
+	} else {
+		return newError("%T does not support this operation", obj)
+	}
+}
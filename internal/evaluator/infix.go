@@ -30,16 +30,13 @@ func evalInfixExpression(operator string, left, right object.VintObject, line in
 		return evalStringInfixExpression(operator, left, right, line)
 
 	case operator == "+" && left.Type() == object.DICT_OBJ && right.Type() == object.DICT_OBJ:
-		leftVal := left.(*object.Dict).Pairs
-		rightVal := right.(*object.Dict).Pairs
-		pairs := make(map[object.HashKey]object.DictPair)
-		for k, v := range leftVal {
-			pairs[k] = v
+		merged := object.NewDict()
+		for _, dict := range []*object.Dict{left.(*object.Dict), right.(*object.Dict)} {
+			for _, pair := range dict.OrderedPairs() {
+				merged.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
+			}
 		}
-		for k, v := range rightVal {
-			pairs[k] = v
-		}
-		return &object.Dict{Pairs: pairs}
+		return merged
 
 	case operator == "+" && left.Type() == object.ARRAY_OBJ && right.Type() == object.ARRAY_OBJ:
 		leftVal := left.(*object.Array).Elements
@@ -86,6 +83,9 @@ func evalInfixExpression(operator string, left, right object.VintObject, line in
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right, line)
 
+	case isBigNumber(left) && isNumber(right) || isNumber(left) && isBigNumber(right):
+		return evalBigNumberInfixExpression(operator, left, right, line)
+
 	case left.Type() == object.FLOAT_OBJ && right.Type() == object.FLOAT_OBJ:
 		return evalFloatInfixExpression(operator, left, right, line)
 
@@ -242,6 +242,11 @@ func evalIntegerInfixExpression(operator string, left, right object.VintObject,
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
+	// Results too large for an Integer are BigInts
+	if promoted := integerOverflow(operator, leftVal, rightVal); promoted != nil {
+		return promoted
+	}
+
 	switch operator {
 	case "+":
 		return &object.Integer{Value: leftVal + rightVal}
@@ -250,7 +255,12 @@ func evalIntegerInfixExpression(operator string, left, right object.VintObject,
 	case "*":
 		return &object.Integer{Value: leftVal * rightVal}
 	case "**":
-		return &object.Float{Value: float64(math.Pow(float64(leftVal), float64(rightVal)))}
+		// A negative power is a fraction
+		if rightVal < 0 {
+			return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
+		}
+		power, _ := integerPower(leftVal, rightVal)
+		return &object.Integer{Value: power}
 	case "/":
 		if rightVal == 0 {
 			return newError("Line %d: Division by zero: cannot divide by zero", line)
@@ -266,6 +276,20 @@ func evalIntegerInfixExpression(operator string, left, right object.VintObject,
 			return newError("Line %d: Division by zero: cannot perform modulo operation (%d %% 0) — the right operand must be non-zero", line, leftVal)
 		}
 		return &object.Integer{Value: leftVal % rightVal}
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<", ">>":
+		if rightVal < 0 {
+			return newError("Line %d: Negative shift count: cannot shift by %d", line, rightVal)
+		}
+		if operator == "<<" {
+			return &object.Integer{Value: leftVal << rightVal}
+		}
+		return &object.Integer{Value: leftVal >> rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case "<=":
@@ -0,0 +1,57 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// implements reports whether obj satisfies the interface: it must be a
+// struct instance whose struct has every method of the interface, taking
+// the same number of parameters. Parameter and return types are compared
+// only where both the interface and the method annotate them.
+func implements(obj object.VintObject, iface *ast.InterfaceType) bool {
+	instance, ok := obj.(*object.StructInstance)
+	return ok && structImplements(instance.Struct, iface)
+}
+
+// structImplements reports whether the instances of s satisfy the interface.
+func structImplements(s *object.Struct, iface *ast.InterfaceType) bool {
+	for _, want := range iface.Methods {
+		method, ok := s.GetMethod(want.Name.Value)
+		if !ok || !sameSignature(method, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameSignature(method *object.StructMethod, want *ast.InterfaceMethod) bool {
+	if len(method.Parameters) != len(want.Parameters) {
+		return false
+	}
+	if len(want.Parameters) > 0 && method.Variadic != want.Parameters[len(want.Parameters)-1].Variadic {
+		return false
+	}
+	for i, param := range want.Parameters {
+		if i < len(method.ParamTypes) && !sameType(method.ParamTypes[i], param.Type) {
+			return false
+		}
+	}
+	return sameType(method.ReturnType, want.ReturnType)
+}
+
+// sameType compares two annotations, treating a missing one as any.
+func sameType(a, b ast.Type) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return canonicalType(a) == canonicalType(b) || canonicalType(a) == "any" || canonicalType(b) == "any"
+}
+
+// canonicalType names a type the same way whichever alias of it is used.
+func canonicalType(t ast.Type) string {
+	if t.String() == "float" {
+		return "float64"
+	}
+	return t.String()
+}
@@ -0,0 +1,49 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/module"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// evalInterpolatedString evaluates each ${...} of a string in the current
+// environment and joins the results with the text around them.
+func evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.VintObject {
+	var out strings.Builder
+	for _, part := range node.Parts {
+		val := Eval(part, env)
+		if isError(val) {
+			return val
+		}
+		out.WriteString(val.(*object.String).Value)
+	}
+	return &object.String{Value: out.String()}
+}
+
+// evalInterpolation evaluates a single ${value} to a string.
+func evalInterpolation(node *ast.Interpolation, env *object.Environment) object.VintObject {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+	return formatInterpolation(val, node.Format, node.Token.Line)
+}
+
+// formatInterpolation turns the value of a ${...} into a string. Strings
+// are used as they are and other values as they print, unless there is a
+// format spec, which is applied the way fmt.sprintf applies it.
+func formatInterpolation(val object.VintObject, format string, line int) object.VintObject {
+	if format != "" {
+		formatted, err := module.FormatValue(val, format)
+		if err != nil {
+			return newError("Line %d: %s", line, err)
+		}
+		return &object.String{Value: formatted}
+	}
+	if str, ok := val.(*object.String); ok {
+		return str
+	}
+	return &object.String{Value: val.Inspect()}
+}
@@ -1,6 +1,8 @@
 package evaluator
 
 import (
+	"fmt"
+
 	"github.com/vintlang/vintlang/internal/ast"
 	"github.com/vintlang/vintlang/internal/object"
 )
@@ -11,7 +13,7 @@ func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) objec
 		return obj
 	}
 
-	for _, matchCase := range me.Cases {
+	for i, matchCase := range me.Cases {
 		if matchCase.Pattern == nil {
 			continue
 		}
@@ -36,17 +38,20 @@ func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) objec
 					continue // Guard failed, try next pattern
 				}
 			}
+			coverBranch(me, i)
 			return evalBlockStatement(matchCase.Block, matchEnv)
 		}
 	}
 
 	// If no specific pattern matched, look for wildcard pattern
-	for _, matchCase := range me.Cases {
+	for i, matchCase := range me.Cases {
 		if ident, ok := matchCase.Pattern.(*ast.Identifier); ok && ident.Value == "_" {
+			coverBranch(me, i)
 			return evalBlockStatement(matchCase.Block, env)
 		}
 	}
 
+	coverBranch(me, len(me.Cases))
 	return NULL
 }
 
@@ -73,7 +78,8 @@ func matchesDictPattern(value object.VintObject, dictPattern *ast.DictLiteral, e
 	}
 
 	// For each key-value pair in the pattern, check if it exists in the dict
-	for patternKey, patternValue := range dictPattern.Pairs {
+	for _, patternKey := range dictPattern.OrderedKeys() {
+		patternValue := dictPattern.Pairs[patternKey]
 		// Evaluate the pattern key
 		keyObj := Eval(patternKey, env)
 		if isError(keyObj) {
@@ -120,73 +126,164 @@ func matchesPatternWithBindings(value object.VintObject, pattern ast.Expression,
 
 // matchesPatternAndBind recursively matches patterns and binds variables
 func matchesPatternAndBind(value object.VintObject, pattern ast.Expression, env *object.Environment) bool {
+	return bindPattern(value, pattern, env, env.Define) == ""
+}
+
+// bindPattern matches value against pattern and calls bind with each name
+// the pattern binds and its value. Defaults are evaluated in env. It
+// returns why value does not match the pattern, or "" if it does.
+func bindPattern(value object.VintObject, pattern ast.Expression, env *object.Environment, bind func(string, object.VintObject) object.VintObject) string {
 	switch p := pattern.(type) {
 	case *ast.ArrayPattern:
-		return matchesArrayPattern(value, p, env)
+		return bindArrayPattern(value, p, env, bind)
+	case *ast.DictPattern:
+		return bindDictPattern(value, p, env, bind)
 	case *ast.DictLiteral:
-		return matchesDictPatternWithBinding(value, p, env)
+		if !matchesDictPatternWithBinding(value, p, env, bind) {
+			return fmt.Sprintf("%s does not match %s", value.Inspect(), p.String())
+		}
+		return ""
 	case *ast.Identifier:
 		// Variable binding - bind the value to the identifier
 		if p.Value != "_" { // Don't bind wildcards
-			env.Define(p.Value, value)
+			return bindName(p.Value, value, bind)
+		}
+		return ""
+	case *ast.TypePattern:
+		// A name with a type, which only matches values of that type
+		if !compatible(p.Type, value) {
+			return fmt.Sprintf("%s is not %s", value.Inspect(), p.Type.String())
+		}
+		if p.Name.Value != "_" {
+			return bindName(p.Name.Value, value, bind)
 		}
-		return true
+		return ""
+	case *ast.Assign:
+		// A name with a default, which is only used for missing values
+		return bindPattern(value, p.Name, env, bind)
 	default:
 		// For other patterns, use the existing logic
-		return matchesPattern(value, pattern, env)
+		if !matchesPattern(value, pattern, env) {
+			return fmt.Sprintf("%s does not match %s", value.Inspect(), pattern.String())
+		}
+		return ""
+	}
+}
+
+func bindName(name string, value object.VintObject, bind func(string, object.VintObject) object.VintObject) string {
+	if result := bind(name, value); isError(result) {
+		return result.(*object.Error).Message
 	}
+	return ""
 }
 
-// matchesArrayPattern checks if a value matches an array pattern
-func matchesArrayPattern(value object.VintObject, pattern *ast.ArrayPattern, env *object.Environment) bool {
+// bindDefault binds the name in a pattern element that has no value to its
+// default, or reports the element as missing if it has none.
+func bindDefault(element ast.Expression, missing string, env *object.Environment, bind func(string, object.VintObject) object.VintObject) string {
+	def, ok := element.(*ast.Assign)
+	if !ok {
+		return missing
+	}
+	value := Eval(def.Value, env)
+	if isError(value) {
+		return value.(*object.Error).Message
+	}
+	return bindName(def.Name.Value, value, bind)
+}
+
+// bindArrayPattern matches an array against [first, second = default, ...rest].
+// Without a rest element the array must have no extra elements.
+func bindArrayPattern(value object.VintObject, pattern *ast.ArrayPattern, env *object.Environment, bind func(string, object.VintObject) object.VintObject) string {
 	// Value must be an array
 	arr, ok := value.(*object.Array)
 	if !ok {
-		return false
+		return fmt.Sprintf("expected an array, got %s", value.Type())
 	}
 
 	elements := arr.Elements
 	patternLen := len(pattern.Elements)
 
-	// If there's no rest pattern, lengths must match exactly
-	if pattern.Rest == nil {
-		if len(elements) != patternLen {
-			return false
+	// Elements after the last one without a default may be missing
+	required := 0
+	for i, element := range pattern.Elements {
+		if _, ok := element.(*ast.Assign); !ok {
+			required = i + 1
 		}
+	}
+	switch {
+	case len(elements) < required && (pattern.Rest != nil || required < patternLen):
+		return fmt.Sprintf("expected at least %d elements, got %d", required, len(elements))
+	case len(elements) < required:
+		return fmt.Sprintf("expected %d elements, got %d", patternLen, len(elements))
+	case pattern.Rest == nil && len(elements) > patternLen:
+		return fmt.Sprintf("expected %d elements, got %d; add ...rest to collect the others", patternLen, len(elements))
+	}
 
-		// Match each element
-		for i, patternElement := range pattern.Elements {
-			if !matchesPatternAndBind(elements[i], patternElement, env) {
-				return false
-			}
+	// Match fixed elements at the beginning
+	for i, element := range pattern.Elements {
+		var reason string
+		if i < len(elements) {
+			reason = bindPattern(elements[i], element, env, bind)
+		} else {
+			reason = bindDefault(element, "", env, bind)
+		}
+		if reason != "" {
+			return reason
 		}
-		return true
 	}
 
-	// With rest pattern, we need at least as many elements as fixed patterns
-	if len(elements) < patternLen {
-		return false
+	// Bind remaining elements to rest variable
+	if pattern.Rest != nil && pattern.Rest.Value != "_" {
+		restElements := []object.VintObject{}
+		if len(elements) > patternLen {
+			restElements = append(restElements, elements[patternLen:]...)
+		}
+		return bindName(pattern.Rest.Value, &object.Array{Elements: restElements}, bind)
 	}
 
-	// Match fixed elements at the beginning
-	for i, patternElement := range pattern.Elements {
-		if !matchesPatternAndBind(elements[i], patternElement, env) {
-			return false
+	return ""
+}
+
+// bindDictPattern matches a dict against {name, age: years = 0, ...others}.
+// Keys without a default must be present; the dict may have others.
+func bindDictPattern(value object.VintObject, pattern *ast.DictPattern, env *object.Environment, bind func(string, object.VintObject) object.VintObject) string {
+	dict, ok := value.(*object.Dict)
+	if !ok {
+		return fmt.Sprintf("expected a dict, got %s", value.Type())
+	}
+
+	named := make(map[object.HashKey]bool, len(pattern.Keys))
+	for i, key := range pattern.Keys {
+		hashKey := (&object.String{Value: key}).HashKey()
+		named[hashKey] = true
+
+		var reason string
+		if pair, exists := dict.Pairs[hashKey]; exists {
+			reason = bindPattern(pair.Value, pattern.Values[i], env, bind)
+		} else {
+			reason = bindDefault(pattern.Values[i], fmt.Sprintf("missing key \"%s\"", key), env, bind)
+		}
+		if reason != "" {
+			return reason
 		}
 	}
 
-	// Bind remaining elements to rest variable
 	if pattern.Rest != nil && pattern.Rest.Value != "_" {
-		restElements := elements[patternLen:]
-		restArray := &object.Array{Elements: restElements}
-		env.Define(pattern.Rest.Value, restArray)
+		rest := object.NewDict()
+		for _, pair := range dict.OrderedPairs() {
+			hashKey := pair.Key.(object.Hashable).HashKey()
+			if !named[hashKey] {
+				rest.SetPair(hashKey, pair)
+			}
+		}
+		return bindName(pattern.Rest.Value, rest, bind)
 	}
 
-	return true
+	return ""
 }
 
 // matchesDictPatternWithBinding matches dictionary patterns with variable binding support
-func matchesDictPatternWithBinding(value object.VintObject, dictPattern *ast.DictLiteral, env *object.Environment) bool {
+func matchesDictPatternWithBinding(value object.VintObject, dictPattern *ast.DictLiteral, env *object.Environment, bind func(string, object.VintObject) object.VintObject) bool {
 	// Value must be a dict
 	dict, ok := value.(*object.Dict)
 	if !ok {
@@ -194,7 +291,8 @@ func matchesDictPatternWithBinding(value object.VintObject, dictPattern *ast.Dic
 	}
 
 	// For each key-value pair in the pattern, check if it exists in the dict
-	for patternKey, patternValue := range dictPattern.Pairs {
+	for _, patternKey := range dictPattern.OrderedKeys() {
+		patternValue := dictPattern.Pairs[patternKey]
 		// Evaluate the pattern key
 		keyObj := Eval(patternKey, env)
 		if isError(keyObj) {
@@ -215,7 +313,9 @@ func matchesDictPatternWithBinding(value object.VintObject, dictPattern *ast.Dic
 		// Handle pattern value - if it's an identifier, bind it
 		if ident, ok := patternValue.(*ast.Identifier); ok && ident.Value != "_" {
 			// Bind the value from the dictionary to this variable
-			env.Define(ident.Value, pair.Value)
+			if isError(bind(ident.Value, pair.Value)) {
+				return false
+			}
 		} else {
 			// Evaluate the pattern value and compare
 			patternValueObj := Eval(patternValue, env)
@@ -5,11 +5,9 @@ import (
 	"github.com/vintlang/vintlang/internal/object"
 )
 
-func evalMethodExpression(node *ast.MethodExpression, env *object.Environment) object.VintObject {
-	obj := Eval(node.Object, env)
-	if isError(obj) {
-		return obj
-	}
+// evalMethodExpression calls the method named by node on obj, the value
+// of node.Object.
+func evalMethodExpression(obj object.VintObject, node *ast.MethodExpression, env *object.Environment) object.VintObject {
 	args := evalExpressions(node.Arguments, env)
 	if len(args) == 1 && isError(args[0]) {
 		return args[0]
@@ -37,6 +35,10 @@ func applyMethod(obj object.VintObject, method ast.Expression, args []object.Vin
 		return obj.Method(method.(*ast.Identifier).Value, args)
 	case *object.Float:
 		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.BigInt:
+		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.Decimal:
+		return obj.Method(method.(*ast.Identifier).Value, args)
 	case *object.Boolean:
 		return obj.Method(method.(*ast.Identifier).Value, args)
 	case *object.Array:
@@ -88,18 +90,20 @@ func applyMethod(obj object.VintObject, method ast.Expression, args []object.Vin
 			for i, arg := range args {
 				if i < len(paramTypes) && paramTypes[i] != nil {
 					if !compatible(paramTypes[i], arg) {
-						return newTypeError(l, "argument %d to %s.%s() expects %s, got %s",
+						return newTypeError("argument %d to %s.%s() expects %s, got %s",
 							i+1, obj.Name, methodName, paramTypes[i].String(), arg.Type())
 					}
 				}
 			}
 		}
 		if fn, ok := obj.Functions[methodName]; ok {
+			leave := profileCall(obj.Name + "." + methodName)
 			result := fn(args, defs)
+			leave()
 			// Check return type if declared
 			if retType, ok := obj.FuncReturns[methodName]; ok && retType != nil && !isError(result) {
 				if !compatible(retType, result) {
-					return newTypeError(l, "%s.%s() returns %s, but got %s",
+					return newTypeError("%s.%s() returns %s, but got %s",
 						obj.Name, methodName, retType.String(), result.Type())
 				}
 			}
@@ -131,12 +135,26 @@ func applyMethod(obj object.VintObject, method ast.Expression, args []object.Vin
 	case *object.StructInstance:
 		methodName := method.(*ast.Identifier).Value
 		return callStructMethod(obj, methodName, args, defs, l)
+	case *object.Generator:
+		if result := generatorMethod(obj, method.(*ast.Identifier).Value, args, l); result != nil {
+			return result
+		}
 	case *object.HTTPRequest:
 		return obj.Method(method.(*ast.Identifier).Value, args)
 	case *object.HTTPResponse:
 		return obj.Method(method.(*ast.Identifier).Value, args)
 	case *object.UploadedFile:
 		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.Mutex:
+		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.RWMutex:
+		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.WaitGroup:
+		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.Once:
+		return obj.Method(method.(*ast.Identifier).Value, args)
+	case *object.AtomicInteger:
+		return obj.Method(method.(*ast.Identifier).Value, args)
 	}
 	return newError("Sorry, %s does not have a function '%s()'", obj.Inspect(), method.(*ast.Identifier).Value)
 }
@@ -281,17 +299,17 @@ func dictFilter(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("filter() argument must be a function")
 	}
 
-	newPairs := make(map[object.HashKey]object.DictPair)
-	for _, pair := range d.Pairs {
+	newDict := object.NewDict()
+	for _, pair := range d.OrderedPairs() {
 		result := evalDictCallback(fn, []object.VintObject{pair.Key, pair.Value})
 		if isError(result) {
 			return result
 		}
 		if isTruthy(result) {
-			newPairs[pair.Key.(object.Hashable).HashKey()] = pair
+			newDict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
 		}
 	}
-	return &object.Dict{Pairs: newPairs}
+	return newDict
 }
 
 func dictMap(d *object.Dict, args []object.VintObject) object.VintObject {
@@ -303,16 +321,16 @@ func dictMap(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("map() argument must be a function")
 	}
 
-	newPairs := make(map[object.HashKey]object.DictPair)
-	for _, pair := range d.Pairs {
+	newDict := object.NewDict()
+	for _, pair := range d.OrderedPairs() {
 		result := evalDictCallback(fn, []object.VintObject{pair.Key, pair.Value})
 		if isError(result) {
 			return result
 		}
 		newPair := object.DictPair{Key: pair.Key, Value: result}
-		newPairs[pair.Key.(object.Hashable).HashKey()] = newPair
+		newDict.SetPair(pair.Key.(object.Hashable).HashKey(), newPair)
 	}
-	return &object.Dict{Pairs: newPairs}
+	return newDict
 }
 
 func dictReduce(d *object.Dict, args []object.VintObject) object.VintObject {
@@ -324,20 +342,19 @@ func dictReduce(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("reduce() first argument must be a function")
 	}
 
+	pairs := d.OrderedPairs()
 	var accumulator object.VintObject
 	if len(args) == 2 {
 		accumulator = args[1]
 	} else {
-		if len(d.Pairs) == 0 {
+		if len(pairs) == 0 {
 			return newError("Cannot reduce empty dictionary without initial value")
 		}
-		for _, pair := range d.Pairs {
-			accumulator = pair.Value
-			break
-		}
+		accumulator = pairs[0].Value
+		pairs = pairs[1:]
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range pairs {
 		result := evalDictCallback(fn, []object.VintObject{accumulator, pair.Key, pair.Value})
 		if isError(result) {
 			return result
@@ -356,7 +373,7 @@ func dictForEach(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("forEach() argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		result := evalDictCallback(fn, []object.VintObject{pair.Key, pair.Value})
 		if isError(result) {
 			return result
@@ -374,7 +391,7 @@ func dictFind(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("find() argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		result := evalDictCallback(fn, []object.VintObject{pair.Key, pair.Value})
 		if isError(result) {
 			return result
@@ -395,7 +412,7 @@ func dictSome(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("some() argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		result := evalDictCallback(fn, []object.VintObject{pair.Key, pair.Value})
 		if isError(result) {
 			return result
@@ -416,7 +433,7 @@ func dictEvery(d *object.Dict, args []object.VintObject) object.VintObject {
 		return newError("every() argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		result := evalDictCallback(fn, []object.VintObject{pair.Key, pair.Value})
 		if isError(result) {
 			return result
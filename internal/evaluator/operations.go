@@ -0,0 +1,50 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// The functions below expose the evaluator's operator semantics to the
+// bytecode VM, so both engines produce the same values and error messages.
+
+// InfixOperation applies a binary operator such as "+" or "in".
+func InfixOperation(operator string, left, right object.VintObject, line int) object.VintObject {
+	return evalInfixExpression(operator, left, right, line)
+}
+
+// PrefixOperation applies a unary operator such as "!" or "-".
+func PrefixOperation(operator string, right object.VintObject, line int) object.VintObject {
+	return evalPrefixExpression(operator, right, line)
+}
+
+// InterpolationString turns the value of a ${value} or ${value:format} in
+// an interpolated string into a string.
+func InterpolationString(val object.VintObject, format string, line int) object.VintObject {
+	return formatInterpolation(val, format, line)
+}
+
+// IndexOperation evaluates left[index].
+func IndexOperation(left, index object.VintObject, line int) object.VintObject {
+	return evalIndexExpression(left, index, line)
+}
+
+// IndexAssignment evaluates obj[index] = value.
+func IndexAssignment(obj, index, value object.VintObject, line int) object.VintObject {
+	return evalIndexAssignment(obj, index, value, line)
+}
+
+// MethodCall calls a method on a value, e.g. "hello".upper().
+func MethodCall(obj object.VintObject, method string, args []object.VintObject, line int) object.VintObject {
+	return applyMethod(obj, &ast.Identifier{Value: method}, args, map[string]object.VintObject{}, line)
+}
+
+// ApplyFunction calls a builtin or any other callable value with evaluated arguments.
+func ApplyFunction(fn object.VintObject, args []object.VintObject, line int) object.VintObject {
+	return applyFunction(fn, args, line)
+}
+
+// IsTruthy reports whether a value counts as true in a condition.
+func IsTruthy(obj object.VintObject) bool {
+	return isTruthy(obj)
+}
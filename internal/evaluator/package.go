@@ -36,6 +36,9 @@ func evalPackageContents(block *ast.BlockStatement, pkg *object.Package) object.
 	var result object.VintObject
 
 	for _, statement := range block.Statements {
+		if debugHook != nil || profiler != nil || coverage != nil {
+			statementHooks(statement, pkg.Scope)
+		}
 		result = Eval(statement, pkg.Scope)
 		if result != nil && result.Type() == object.ERROR_OBJ {
 			return result
@@ -0,0 +1,123 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// evalAddressOf evaluates &operand. A variable, a struct field or an array
+// element gives a pointer to it, through which it can be read and
+// written. Any other value gives a pointer to the value.
+func evalAddressOf(node *ast.PrefixExpression, env *object.Environment) object.VintObject {
+	line := node.Token.Line
+	switch target := node.Right.(type) {
+	case *ast.Identifier:
+		if owner := env.Owner(target.Value); owner != nil {
+			return &object.Pointer{Slot: &object.VariableSlot{Env: owner, Name: target.Value}}
+		}
+	case *ast.PropertyExpression:
+		obj := Eval(target.Object, env)
+		if isError(obj) {
+			return obj
+		}
+		si, ok := obj.(*object.StructInstance)
+		name, isIdent := target.Property.(*ast.Identifier)
+		if !ok || !isIdent {
+			return evalPrefixExpression("&", evalProperty(obj, target), line)
+		}
+		if _, ok := si.GetField(name.Value); !ok {
+			return newError("Line %d: Struct '%s' has no field '%s'", line, si.Struct.Name, name.Value)
+		}
+		return &object.Pointer{Slot: &object.FieldSlot{Instance: si, Name: name.Value}}
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(target.Index, env)
+		if isError(index) {
+			return index
+		}
+		array, ok := left.(*object.Array)
+		if !ok {
+			return evalPrefixExpression("&", evalIndexExpression(left, index, line), line)
+		}
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return newError("Line %d: Array index must be an integer, got %s", line, index.Type())
+		}
+		length := int64(len(array.Elements))
+		i := idx.Value
+		if i < 0 {
+			i += length
+		}
+		if i < 0 || i >= length {
+			return newError("Line %d: Array index %d out of bounds. Array length is %d", line, idx.Value, length)
+		}
+		return &object.Pointer{Slot: &object.ElementSlot{Array: array, Index: int(i)}}
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+	return evalPrefixExpression("&", right, line)
+}
+
+// evalPointerAssignment evaluates *target = value, and *target += value
+// and the like. The value must match the type declared for the pointer,
+// as in p: *int, and the one declared for the variable or field it points
+// at.
+func evalPointerAssignment(node *ast.AssignmentExpression, target *ast.PrefixExpression, env *object.Environment) object.VintObject {
+	line := node.Token.Line
+	ptr := Eval(target.Right, env)
+	if isError(ptr) {
+		return ptr
+	}
+	if ptr == NULL {
+		return newError("Line %d: nil pointer dereference", line)
+	}
+	p, ok := ptr.(*object.Pointer)
+	if !ok {
+		return newError("Line %d: cannot assign through non-pointer %s", line, ptr.Type())
+	}
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+	if op := node.Token.Literal; op != "=" {
+		current, err := p.Load()
+		if err != nil {
+			return newError("Line %d: %s", line, err.Error())
+		}
+		if value = evalInfixExpression(strings.TrimSuffix(op, "="), current, value, line); isError(value) {
+			return value
+		}
+	}
+
+	if ident, ok := target.Right.(*ast.Identifier); ok {
+		if declared, ok := env.GetDeclaredType(ident.Value); ok {
+			if pt, ok := declared.(*ast.PointerType); ok && !compatible(pt.BaseType, value) {
+				return newTypeError("cannot assign %s through '%s' of type %s", value.Type(), ident.Value, pt.String())
+			}
+		}
+	}
+	if p.Slot != nil {
+		if declared := p.Slot.DeclaredType(); declared != nil {
+			var bindings typeArgs
+			if field, ok := p.Slot.(*object.FieldSlot); ok {
+				bindings = typeArgs(field.Instance.TypeArgs)
+			}
+			if !bindings.check(declared, value) {
+				return newTypeError("cannot assign %s to %s of type %s", value.Type(), p.Slot, bindings.show(declared))
+			}
+		}
+	}
+
+	if err := p.Store(value); err != nil {
+		return newError("Line %d: %s", line, err.Error())
+	}
+	return value
+}
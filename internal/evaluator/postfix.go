@@ -22,9 +22,8 @@ func evalPostfixExpression(env *object.Environment, operator string, node *ast.P
 	switch operator {
 	case "++":
 		switch arg := val.(type) {
-		case *object.Integer:
-			v := arg.Value + 1
-			return assign(&object.Integer{Value: v})
+		case *object.Integer, *object.BigInt, *object.Decimal:
+			return assign(evalInfixExpression("+", arg, &object.Integer{Value: 1}, node.Token.Line))
 		case *object.Float:
 			v := arg.Value + 1
 			return assign(&object.Float{Value: v})
@@ -33,9 +32,8 @@ func evalPostfixExpression(env *object.Environment, operator string, node *ast.P
 		}
 	case "--":
 		switch arg := val.(type) {
-		case *object.Integer:
-			v := arg.Value - 1
-			return assign(&object.Integer{Value: v})
+		case *object.Integer, *object.BigInt, *object.Decimal:
+			return assign(evalInfixExpression("-", arg, &object.Integer{Value: 1}, node.Token.Line))
 		case *object.Float:
 			v := arg.Value - 1
 			return assign(&object.Float{Value: v})
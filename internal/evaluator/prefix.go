@@ -1,6 +1,9 @@
 package evaluator
 
 import (
+	"math"
+	"math/big"
+
 	"github.com/vintlang/vintlang/internal/object"
 )
 
@@ -8,11 +11,20 @@ func evalMinusPrefixOperatorExpression(right object.VintObject, line int) object
 	switch obj := right.(type) {
 
 	case *object.Integer:
+		if obj.Value == math.MinInt64 {
+			return &object.BigInt{Value: new(big.Int).Neg(big.NewInt(obj.Value))}
+		}
 		return &object.Integer{Value: -obj.Value}
 
 	case *object.Float:
 		return &object.Float{Value: -obj.Value}
 
+	case *object.BigInt:
+		return &object.BigInt{Value: new(big.Int).Neg(obj.Value)}
+
+	case *object.Decimal:
+		return obj.Neg()
+
 	default:
 		return newError("Line %d: Unknown operation: -%s", line, right.Type())
 	}
@@ -26,11 +38,24 @@ func evalPlusPrefixOperatorExpression(right object.VintObject, line int) object.
 	case *object.Float:
 		return &object.Float{Value: obj.Value}
 
+	case *object.BigInt, *object.Decimal:
+		return obj
+
 	default:
 		return newError("Line %d: Unknown operation: +%s", line, right.Type())
 	}
 }
 
+func evalTildePrefixOperatorExpression(right object.VintObject, line int) object.VintObject {
+	switch obj := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^obj.Value}
+	case *object.BigInt:
+		return &object.BigInt{Value: new(big.Int).Not(obj.Value)}
+	}
+	return newError("Line %d: Unknown operation: ~%s", line, right.Type())
+}
+
 func evalPrefixExpression(operator string, right object.VintObject, line int) object.VintObject {
 	switch operator {
 	case "!":
@@ -39,15 +64,21 @@ func evalPrefixExpression(operator string, right object.VintObject, line int) ob
 		return evalMinusPrefixOperatorExpression(right, line)
 	case "+":
 		return evalPlusPrefixOperatorExpression(right, line)
+	case "~":
+		return evalTildePrefixOperatorExpression(right, line)
 	case "*":
 		if right == nil {
 			return newError("Line %d: cannot dereference nil", line)
 		}
+		if right == NULL {
+			return newError("Line %d: nil pointer dereference", line)
+		}
 		if p, ok := right.(*object.Pointer); ok {
-			if p.Ref == nil {
-				return newError("Line %d: pointer is nil", line)
+			value, err := p.Load()
+			if err != nil {
+				return newError("Line %d: %s", line, err.Error())
 			}
-			return p.Ref
+			return value
 		}
 		return newError("Line %d: cannot dereference non-pointer", line)
 	case "&":
@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// Profiler is told when calls begin and end and when statements begin,
+// which is enough to measure where a program spends its time. Calls of
+// Vint functions, builtins and module functions are reported; file and
+// line are where a Vint function is defined, and empty for the others.
+//
+// Each goroutine running Vint code is a thread with a call stack of its
+// own, and every method is told which thread it is about; the program
+// itself is MainThread. The methods run on the goroutine doing the work,
+// so a Profiler used with programs that start goroutines must be safe for
+// concurrent use.
+type Profiler interface {
+	Enter(thread int, function, file string, line int)
+	Leave(thread int)
+	Statement(thread int, file string, line int)
+}
+
+var (
+	profiler     Profiler
+	builtinNames map[*object.Builtin]string
+)
+
+// SetProfiler installs the profiler told about calls and statements, or
+// removes it if p is nil. It should be set before the program starts.
+func SetProfiler(p Profiler) {
+	profiler = p
+	restartThreads()
+	if p != nil && builtinNames == nil {
+		builtinNames = make(map[*object.Builtin]string, len(builtins))
+		for name, builtin := range builtins {
+			builtinNames[builtin] = name
+		}
+	}
+}
+
+// profileStatement tells the profiler where the program has got to.
+func profileStatement(stmt ast.Statement) {
+	if tok, ok := nodeToken(stmt); ok {
+		profiler.Statement(currentThread(), tok.File, tok.Line)
+	}
+}
+
+// profileEnter tells the profiler a call of fn has begun, and returns the
+// function that tells it the call has ended.
+func profileEnter(fn object.VintObject) func() {
+	p := profiler
+	if p == nil {
+		return func() {}
+	}
+	thread := currentThread()
+	switch fn := fn.(type) {
+	case *object.Function:
+		tok := fn.Body.Token
+		p.Enter(thread, functionName(fn), tok.File, tok.Line)
+	case *object.Builtin:
+		name, ok := builtinNames[fn]
+		if !ok {
+			name = "<builtin>"
+		}
+		p.Enter(thread, name, "", 0)
+	default:
+		return func() {}
+	}
+	return func() { p.Leave(thread) }
+}
+
+// profileCall is profileEnter for calls that are not function values,
+// such as math.sqrt or the running of an imported file.
+func profileCall(name string) func() {
+	p := profiler
+	if p == nil {
+		return func() {}
+	}
+	thread := currentThread()
+	p.Enter(thread, name, "", 0)
+	return func() { p.Leave(thread) }
+}
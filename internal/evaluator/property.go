@@ -5,11 +5,9 @@ import (
 	"github.com/vintlang/vintlang/internal/object"
 )
 
-func evalPropertyExpression(node *ast.PropertyExpression, env *object.Environment) object.VintObject {
-	left := Eval(node.Object, env)
-	if isError(left) {
-		return left
-	}
+// evalProperty reads the property named by node from left, the value of
+// node.Object.
+func evalProperty(left object.VintObject, node *ast.PropertyExpression) object.VintObject {
 	switch left.(type) {
 	case *object.Instance:
 		obj := left.(*object.Instance)
@@ -55,6 +53,18 @@ func evalPropertyExpression(node *ast.PropertyExpression, env *object.Environmen
 			return newError("'%s' is a method of struct '%s', use %s.%s() to call it", prop, si.Struct.Name, node.Object.String(), prop)
 		}
 		return newError("Struct '%s' has no field '%s'", si.Struct.Name, prop)
+	case *object.Dict:
+		// d.name reads the key "name", as d["name"] does, so that
+		// resp?.data.user reads a parsed JSON response
+		prop := &object.String{Value: node.Property.(*ast.Identifier).Value}
+		return evalDictIndexExpression(left, prop, node.Token.Line)
+	case *object.CustomError:
+		ce := left.(*object.CustomError)
+		prop := node.Property.(*ast.Identifier).Value
+		if val, ok := ce.GetField(prop); ok {
+			return val
+		}
+		return newError("Error '%s' has no field '%s'", ce.ErrorType.Name, prop)
 	}
 	return newError("Value %s is not valid for %s", node.Property.(*ast.Identifier).Value, left.Inspect())
 }
@@ -86,10 +96,10 @@ func evalPropertyAssignment(name *ast.PropertyExpression, val object.VintObject,
 		prop := name.Property.(*ast.Identifier).Value
 		// Check declared field type
 		if fieldType := si.Struct.GetFieldType(prop); fieldType != nil {
-			if !compatible(fieldType, val) {
-				return newTypeError(name.Token.Line,
-					"field '%s' in struct '%s' expects %s, got %s",
-					prop, si.Struct.Name, fieldType.String(), val.Type())
+			bindings := typeArgs(si.TypeArgs)
+			if !bindings.check(fieldType, val) {
+				return newTypeError("field '%s' in struct '%s' expects %s, got %s",
+					prop, si.Struct.Name, bindings.show(fieldType), val.Type())
 			}
 		}
 		if err := si.SetField(prop, val); err != nil {
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+var timeoutUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// evalSelectStatement evaluates every channel and value once, in source
+// order, then waits with reflect.Select until one of the cases can proceed
+// and runs its block. A case whose channel is null never proceeds.
+func evalSelectStatement(node *ast.SelectStatement, env *object.Environment) object.VintObject {
+	cases := make([]reflect.SelectCase, len(node.Cases))
+
+	for i, sc := range node.Cases {
+		switch sc.Op {
+		case "default":
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectDefault}
+
+		case "receive", "send":
+			value := Eval(sc.Channel, env)
+			if isError(value) {
+				return value
+			}
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv}
+			if sc.Op == "send" {
+				cases[i].Dir = reflect.SelectSend
+				toSend := Eval(sc.Send, env)
+				if isError(toSend) {
+					return toSend
+				}
+				cases[i].Send = reflect.ValueOf(&toSend).Elem()
+			}
+
+			if value.Type() == object.NULL_OBJ {
+				continue
+			}
+			ch, ok := value.(*object.Channel)
+			if !ok {
+				return newError("select: %s() needs a channel, got %s", sc.Op, value.Type())
+			}
+			if sc.Op == "send" && ch.IsClosed() {
+				return newError("select: send on closed channel")
+			}
+			cases[i].Chan = reflect.ValueOf(ch.Chan())
+
+		case "timeout":
+			d, errObj := selectTimeout(sc, env)
+			if errObj != nil {
+				return errObj
+			}
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(time.After(d))}
+		}
+	}
+
+	chosen, received, ok, errObj := runSelect(cases)
+	if errObj != nil {
+		return errObj
+	}
+
+	sc := node.Cases[chosen]
+	caseEnv := object.NewEnclosedEnvironment(env)
+	if sc.Op == "receive" {
+		var value object.VintObject = NULL
+		if ok {
+			value = received.Interface().(object.VintObject)
+		}
+		if sc.Value != nil && sc.Value.Value != "_" {
+			caseEnv.Define(sc.Value.Value, value)
+		}
+		if sc.Ok != nil && sc.Ok.Value != "_" {
+			caseEnv.Define(sc.Ok.Value, nativeBoolToBooleanObject(ok))
+		}
+	}
+	return Eval(sc.Block, caseEnv)
+}
+
+// runSelect wraps reflect.Select, which panics when sending on a channel
+// that another goroutine closed while we were waiting.
+func runSelect(cases []reflect.SelectCase) (chosen int, received reflect.Value, ok bool, errObj *object.Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			errObj = newError("select: %v", r)
+		}
+	}()
+	chosen, received, ok = reflect.Select(cases)
+	return chosen, received, ok, nil
+}
+
+// selectTimeout works out how long a timeout case waits. A bare number is
+// read in the unit written after it (milliseconds if there is none); a
+// duration from the time module is used as is.
+func selectTimeout(sc *ast.SelectCase, env *object.Environment) (time.Duration, *object.Error) {
+	value := Eval(sc.Timeout, env)
+	if errObj, ok := value.(*object.Error); ok {
+		return 0, errObj
+	}
+
+	unit := time.Millisecond
+	if sc.Unit != "" {
+		unit = timeoutUnits[sc.Unit]
+	}
+
+	switch value := value.(type) {
+	case *object.Integer:
+		return time.Duration(value.Value) * unit, nil
+	case *object.Float:
+		return time.Duration(value.Value * float64(unit)), nil
+	case *object.Duration:
+		if sc.Unit != "" {
+			return 0, newError("select: timeout(%s%s) cannot put a unit after a duration", value.Inspect(), sc.Unit)
+		}
+		return value.Value, nil
+	}
+	return 0, newError("select: timeout() needs a number or a duration, got %s", value.Type())
+}
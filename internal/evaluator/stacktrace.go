@@ -0,0 +1,63 @@
+package evaluator
+
+import (
+	"reflect"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// locateError records where an error surfaced. The first node to see the
+// error is the innermost one and becomes its origin; once the error has left
+// a function (PushFrame), the next node up is the call site in the caller.
+func locateError(errObj *object.Error, node ast.Node) {
+	tok, ok := nodeToken(node)
+	if !ok {
+		return
+	}
+	errObj.Locate(tok.File, tok.Line, tok.Column)
+}
+
+// nodeToken returns the token that best describes where a node sits in the
+// source. Calls point at the callee rather than at the opening parenthesis.
+// This only runs on the error path and while debugging, so reflection is
+// cheap enough here.
+func nodeToken(node ast.Node) (token.Token, bool) {
+	switch n := node.(type) {
+	case nil:
+		return token.Token{}, false
+	case *ast.CallExpression:
+		if tok, ok := nodeToken(n.Function); ok {
+			return tok, true
+		}
+		return n.Token, true
+	case *ast.MethodExpression:
+		if tok, ok := nodeToken(n.Method); ok {
+			return tok, true
+		}
+		return n.Token, true
+	}
+
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return token.Token{}, false
+	}
+	field := v.Elem().FieldByName("Token")
+	if !field.IsValid() {
+		return token.Token{}, false
+	}
+	tok, ok := field.Interface().(token.Token)
+	if !ok || tok.Line == 0 {
+		return token.Token{}, false
+	}
+	return tok, true
+}
+
+// functionName returns the name a function is shown with in stack traces.
+func functionName(fn *object.Function) string {
+	if fn.Name != "" {
+		return fn.Name
+	}
+	return "<anonymous>"
+}
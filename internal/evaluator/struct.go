@@ -1,6 +1,8 @@
 package evaluator
 
 import (
+	"sort"
+
 	"github.com/vintlang/vintlang/internal/ast"
 	"github.com/vintlang/vintlang/internal/object"
 )
@@ -16,10 +18,57 @@ func evalStructStatement(node *ast.StructStatement, env *object.Environment) obj
 		Fields:  make([]object.StructField, 0, len(node.Fields)),
 		Methods: make(map[string]*object.StructMethod),
 		Env:     env,
+
+		TypeParams: node.TypeParams,
 	}
 
-	// Register fields
+	// Names the struct declares itself, which override promoted ones
+	own := map[string]bool{}
 	for _, f := range node.Fields {
+		if embeddedStruct(f, env) == nil {
+			own[f.Name.Value] = true
+		}
+	}
+	for _, m := range node.Methods {
+		own[m.Name.Value] = true
+	}
+
+	// Register fields, promoting the fields and methods of embedded structs
+	promotedFrom := map[string]string{}
+	for _, f := range node.Fields {
+		if embedded := embeddedStruct(f, env); embedded != nil {
+			for _, ef := range embedded.Fields {
+				if own[ef.Name] {
+					continue
+				}
+				if from, ok := promotedFrom[ef.Name]; ok {
+					return newError("Line %d: struct '%s' gets field '%s' from both '%s' and '%s'",
+						f.Name.Token.Line, structDef.Name, ef.Name, from, embedded.Name)
+				}
+				promotedFrom[ef.Name] = embedded.Name
+				if ef.Env == nil {
+					ef.Env = embedded.Env
+				}
+				structDef.Fields = append(structDef.Fields, ef)
+			}
+			for _, name := range sortedMethodNames(embedded) {
+				em := embedded.Methods[name]
+				if own[name] {
+					continue
+				}
+				if from, ok := promotedFrom[name+"()"]; ok {
+					return newError("Line %d: struct '%s' gets method '%s' from both '%s' and '%s'",
+						f.Name.Token.Line, structDef.Name, name, from, embedded.Name)
+				}
+				promotedFrom[name+"()"] = embedded.Name
+				promoted := *em
+				if promoted.Env == nil {
+					promoted.Env = embedded.Env
+				}
+				structDef.Methods[name] = &promoted
+			}
+			continue
+		}
 		field := object.StructField{
 			Name:    f.Name.Value,
 			Type:    f.Type,
@@ -36,6 +85,8 @@ func evalStructStatement(node *ast.StructStatement, env *object.Environment) obj
 			ParamTypes: m.ParamTypes,
 			ReturnType: m.ReturnType,
 			Defaults:   m.Defaults,
+			Variadic:   m.Variadic,
+			Generator:  m.Generator,
 			Body:       m.Body,
 		}
 		structDef.Methods[m.Name.Value] = method
@@ -45,22 +96,53 @@ func evalStructStatement(node *ast.StructStatement, env *object.Environment) obj
 	return env.Define(node.Name.Value, structDef)
 }
 
+// sortedMethodNames returns the names of a struct's methods in order, so
+// that conflicts between embedded structs are reported the same each run.
+func sortedMethodNames(s *object.Struct) []string {
+	names := make([]string, 0, len(s.Methods))
+	for name := range s.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// embeddedStruct returns the struct a field embeds, or nil if it is an
+// ordinary field. A field embeds a struct when it is just the struct's
+// name, with no type or default: struct Admin { User; level: int }
+func embeddedStruct(f ast.StructField, env *object.Environment) *object.Struct {
+	if f.Type != nil || f.Default != nil {
+		return nil
+	}
+	obj, ok := env.Get(f.Name.Value)
+	if !ok {
+		return nil
+	}
+	embedded, _ := obj.(*object.Struct)
+	return embedded
+}
+
 // instantiateStruct creates a new instance of a struct with the given field values
 func instantiateStruct(structDef *object.Struct, fieldArgs map[string]object.VintObject, line int) object.VintObject {
 	instanceEnv := object.NewEnvironment()
+	bindings := newTypeArgs(structDef.TypeParams, structDef.TypeArgs)
 
 	// Initialize all fields with defaults first, then override with provided values
 	for _, field := range structDef.Fields {
 		if val, ok := fieldArgs[field.Name]; ok {
 			// User provided a value for this field
-			if field.Type != nil && !compatible(field.Type, val) {
-				return newTypeError(line, "field '%s' in struct '%s' expects %s, got %s",
-					field.Name, structDef.Name, field.Type.String(), val.Type())
+			if field.Type != nil && !bindings.check(field.Type, val) {
+				return newTypeError("field '%s' in struct '%s' expects %s, got %s",
+					field.Name, structDef.Name, bindings.show(field.Type), val.Type())
 			}
 			instanceEnv.Define(field.Name, val)
 		} else if field.Default != nil {
 			// Use the default value
-			defaultVal := Eval(field.Default, structDef.Env)
+			defaultEnv := structDef.Env
+			if field.Env != nil {
+				defaultEnv = field.Env
+			}
+			defaultVal := Eval(field.Default, defaultEnv)
 			if isError(defaultVal) {
 				return defaultVal
 			}
@@ -81,8 +163,9 @@ func instantiateStruct(structDef *object.Struct, fieldArgs map[string]object.Vin
 	}
 
 	instance := &object.StructInstance{
-		Struct: structDef,
-		Fields: instanceEnv,
+		Struct:   structDef,
+		Fields:   instanceEnv,
+		TypeArgs: bindings,
 	}
 
 	return instance
@@ -96,26 +179,40 @@ func callStructMethod(instance *object.StructInstance, methodName string, args [
 			line, instance.Struct.Name, methodName)
 	}
 
-	// Check argument types
+	// Check argument types. The type parameters of a generic struct that
+	// the instance has not bound yet are bound for good.
+	bindings := typeArgs(instance.TypeArgs)
 	for i, arg := range args {
-		if i < len(method.ParamTypes) && method.ParamTypes[i] != nil {
-			if !compatible(method.ParamTypes[i], arg) {
-				return newTypeError(line, "parameter '%s' in method '%s' expects %s, got %s",
-					method.Parameters[i].Value, methodName, method.ParamTypes[i].String(), arg.Type())
+		if method.Variadic && i >= len(method.ParamTypes) {
+			// Every extra argument is checked against the variadic parameter
+			i = len(method.ParamTypes) - 1
+		}
+		if i >= 0 && i < len(method.ParamTypes) && method.ParamTypes[i] != nil {
+			if !bindings.check(method.ParamTypes[i], arg) {
+				return newTypeError("parameter '%s' in method '%s' expects %s, got %s",
+					method.Parameters[i].Value, methodName, bindings.show(method.ParamTypes[i]), arg.Type())
 			}
 		}
 	}
 
 	// Create a new environment for the method execution
 	// The method's environment encloses the struct definition's environment
-	methodEnv := object.NewEnclosedEnvironment(instance.Struct.Env)
+	// (or the embedded struct's, for a promoted method)
+	definedIn := instance.Struct.Env
+	if method.Env != nil {
+		definedIn = method.Env
+	}
+	methodEnv := object.NewEnclosedEnvironment(definedIn)
 
 	// Bind 'this' to the struct instance
 	methodEnv.Define("this", instance)
 
 	// Bind parameters
 	for i, param := range method.Parameters {
-		if i < len(args) {
+		if method.Variadic && i == len(method.Parameters)-1 {
+			// The variadic parameter collects the remaining arguments
+			methodEnv.Define(param.Value, object.VariadicArgs(args, i))
+		} else if i < len(args) {
 			methodEnv.Define(param.Value, args[i])
 		} else if defVal, ok := method.Defaults[param.Value]; ok {
 			evaluated := Eval(defVal, methodEnv)
@@ -129,15 +226,22 @@ func callStructMethod(instance *object.StructInstance, methodName string, args [
 		}
 	}
 
+	if method.Generator {
+		return newGenerator(instance.Struct.Name+"."+methodName, method.Body, methodEnv)
+	}
+
 	// Execute the method body
 	result := Eval(method.Body, methodEnv)
 	returnValue := unwrapReturnValue(result)
+	if errObj, ok := returnValue.(*object.Error); ok {
+		errObj.PushFrame(instance.Struct.Name + "." + methodName)
+	}
 
 	// Check return type
 	if method.ReturnType != nil && !isError(returnValue) {
-		if !compatible(method.ReturnType, returnValue) {
-			return newTypeError(line, "method '%s' returns %s, but body returned %s",
-				methodName, method.ReturnType.String(), returnValue.Type())
+		if !bindings.check(method.ReturnType, returnValue) {
+			return newTypeError("method '%s' returns %s, but body returned %s",
+				methodName, bindings.show(method.ReturnType), returnValue.Type())
 		}
 	}
 
@@ -149,31 +253,21 @@ func callStructMethod(instance *object.StructInstance, methodName string, args [
 func evalStructCall(node *ast.CallExpression, structDef *object.Struct, env *object.Environment) object.VintObject {
 	fieldArgs := make(map[string]object.VintObject)
 
-	positionalIndex := 0
-
-	for _, exprr := range node.Arguments {
-		switch exp := exprr.(type) {
-		case *ast.Assign:
-			// Keyword argument: name = "Alice"
-			val := Eval(exp.Value, env)
-			if isError(val) {
-				return val
-			}
-			fieldArgs[exp.Name.Value] = val
-		default:
-			// Positional argument: matched to fields by order
-			evaluated := Eval(exp, env)
-			if isError(evaluated) {
-				return evaluated
-			}
-			if positionalIndex < len(structDef.Fields) {
-				fieldArgs[structDef.Fields[positionalIndex].Name] = evaluated
-				positionalIndex++
-			} else {
-				return newError("Line %d: Too many arguments for struct '%s'",
-					node.Token.Line, structDef.Name)
-			}
-		}
+	// Keyword arguments (name = "Alice") set fields by name, positional
+	// arguments (including ...spread arrays) are matched to fields by order
+	positional, keywords, errObj := evalArguments(node.Arguments, env)
+	if errObj != nil {
+		return errObj
+	}
+	for _, pair := range keywords.OrderedPairs() {
+		fieldArgs[pair.Key.(*object.String).Value] = pair.Value
+	}
+	if len(positional) > len(structDef.Fields) {
+		return newError("Line %d: Too many arguments for struct '%s'",
+			node.Token.Line, structDef.Name)
+	}
+	for i, val := range positional {
+		fieldArgs[structDef.Fields[i].Name] = val
 	}
 
 	return instantiateStruct(structDef, fieldArgs, node.Token.Line)
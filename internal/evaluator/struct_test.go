@@ -43,6 +43,11 @@ func TestStructParsing(t *testing.T) {
 		{"struct Empty { }", false},
 		// Struct with multiple methods
 		{"struct Multi { x: 0\nfunc a() { return 1 }\nfunc b() { return 2 } }", false},
+		// Typed fields on their own lines, and ';' separators
+		{"struct Typed {\n x: int\n y: float\n func a() { return 1 }\n}", false},
+		{"struct Semi { x: int; func a() { return 1 }; y: 0 }", false},
+		// Embedded struct
+		{"struct User { name: \"\" }\nstruct Admin { User; level: int }", false},
 	}
 
 	for _, tt := range tests {
@@ -566,6 +571,233 @@ func TestStructTooManyPositionalArgs(t *testing.T) {
 	}
 }
 
+// ================================
+// Interfaces and Embedding
+// ================================
+
+const shapes = `
+interface Named { name(): string }
+interface Shape {
+	Named
+	area(): float
+}
+struct Circle {
+	r: float
+	func area(): float { return 3.0 * this.r * this.r }
+	func name(): string { return "circle" }
+}
+struct Square {
+	s: float
+	func area(): int { return 1 }
+	func name(): string { return "square" }
+}
+`
+
+func TestInterfaceSatisfaction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"Circle(1.0) is Shape", true},
+		{"Circle(1.0) is Named", true},
+		// area() returns int, not float
+		{"Square(1.0) is Shape", false},
+		{"Square(1.0) is Named", true},
+		{"5 is Shape", false},
+		{"interface Sized { area(k: float): float }\nCircle(1.0) is Sized", false},
+		{"interface Loose { area() }\nSquare(1.0) is Loose", true},
+	}
+
+	for _, tt := range tests {
+		testStructBooleanObject(t, testEval(shapes+tt.input), tt.expected)
+	}
+}
+
+func TestInterfaceAnnotations(t *testing.T) {
+	input := shapes + `
+	let total = func(...xs: Shape): float {
+		let sum = 0.0
+		for x in xs { sum += x.area() }
+		return sum
+	}
+	let s: Shape = Circle(1.0)
+	string(total(s, Circle(2.0))) + " " + s.name()
+	`
+	testStructStringObject(t, testEval(input), "15 circle")
+
+	result := testEval(shapes + "let bad: Shape = Square(1.0)")
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected error, got=%T (%+v)", result, result)
+	}
+	if !strings.Contains(errObj.Message, "cannot assign STRUCT_INSTANCE to variable 'bad'") {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestInterfaceMatchPattern(t *testing.T) {
+	input := shapes + `
+	let describe = func(v) {
+		return match v {
+			c: Circle => "circle " + string(c.r)
+			n: Named => "named " + n.name()
+			_: int => "int"
+			_ => "other"
+		}
+	}
+	describe(Circle(2.0)) + ", " + describe(Square(1.0)) + ", " + describe(1) + ", " + describe("x")
+	`
+	testStructStringObject(t, testEval(input), "circle 2, named square, int, other")
+}
+
+func TestStructEmbedding(t *testing.T) {
+	input := `
+	struct User {
+		name: string
+		greeting: string = "Hi"
+		func hello(): string { return this.greeting + " " + this.name }
+		func role(): string { return "user" }
+	}
+	struct Admin {
+		User
+		level: int
+		func role(): string { return "admin " + string(this.level) }
+	}
+	interface Greeter { hello(): string }
+	let a = Admin(name = "Ann", level = 3)
+	a.name = "Bo"
+	let results = [a.hello(), a.role(), string(a is Greeter), string(a is User), a.greeting]
+	results
+	`
+
+	result := testEval(input)
+	arr, ok := result.(*object.Array)
+	if !ok {
+		t.Fatalf("result is not Array. got=%T (%+v)", result, result)
+	}
+	expected := []string{"Hi Bo", "admin 3", "true", "false", "Hi"}
+	for i, want := range expected {
+		testStructStringObject(t, arr.Elements[i], want)
+	}
+
+	input = `
+	struct User { name: "" }
+	struct Pet { name: "" }
+	struct Owner { User; Pet }
+	`
+	result = testEval(input)
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected error for a field promoted twice, got=%T (%+v)", result, result)
+	}
+	if errObj.Message != "Line 4: struct 'Owner' gets field 'name' from both 'User' and 'Pet'" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+// ================================
+// Generics
+// ================================
+
+func TestGenericFunctions(t *testing.T) {
+	input := `
+	let first = func<T>(xs: [T]): T { return xs[0] }
+	let sum = func<T: number>(...xs: T): T {
+		let total = xs[0]
+		for x in xs[1:] { total += x }
+		return total
+	}
+	let pick = func<K, V>(d: {K: V}, k: K): V { return d[k] }
+	let results = [first(["a", "b"]), string(sum(1, 2, 3)), string(sum(0.5, 1.5)), pick({"x": 1}, "x"), first<int>([7])]
+	results
+	`
+
+	result := testEval(input)
+	arr, ok := result.(*object.Array)
+	if !ok {
+		t.Fatalf("result is not Array. got=%T (%+v)", result, result)
+	}
+	testStructStringObject(t, arr.Elements[0], "a")
+	testStructStringObject(t, arr.Elements[1], "6")
+	testStructStringObject(t, arr.Elements[2], "2")
+	testStructIntegerObject(t, arr.Elements[3], 1)
+	testStructIntegerObject(t, arr.Elements[4], 7)
+}
+
+func TestGenericStructs(t *testing.T) {
+	input := `
+	struct Stack<T> {
+		items: [T] = []
+		func push(x: T) { this.items.push(x) }
+		func pop(): T { return this.items.pop() }
+	}
+	let s = Stack<int>()
+	s.push(1)
+	s.push(2)
+	let b = Stack(items = ["x"])
+	let results = [string(s.pop()), b.pop(), string(s is Stack<int>), string(s is Stack<string>), string(s is Stack)]
+	results
+	`
+
+	result := testEval(input)
+	arr, ok := result.(*object.Array)
+	if !ok {
+		t.Fatalf("result is not Array. got=%T (%+v)", result, result)
+	}
+	expected := []string{"2", "x", "true", "false", "true"}
+	for i, want := range expected {
+		testStructStringObject(t, arr.Elements[i], want)
+	}
+}
+
+func TestGenericErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"let pair = func<T>(a: T, b: T) { return [a, b] }\npair(1, \"x\")",
+			"parameter 'b' expects int, got STRING",
+		},
+		{
+			"let sum = func<T: number>(a: T): T { return a }\nsum(\"x\")",
+			"parameter 'a' expects number, got STRING",
+		},
+		{
+			"let id = func<T>(a: T): T { return a }\nid<string>(1)",
+			"parameter 'a' expects string, got INTEGER",
+		},
+		{
+			"let sum = func<T: number>(a: T): T { return a }\nsum<string>",
+			"type argument string for 'T' does not satisfy number",
+		},
+		{
+			"let id = func<T>(a: T): T { return a }\nid<int, int>",
+			"'id' takes 1 type argument, got 2",
+		},
+		{
+			"struct Box<T> { value: T; func set(v: T) { this.value = v } }\nlet b = Box(value = 1)\nb.set(\"x\")",
+			"parameter 'v' in method 'set' expects int, got STRING",
+		},
+		{
+			"struct Box<T> { value: T }\nBox<string>(value = 1)",
+			"field 'value' in struct 'Box' expects string, got INTEGER",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		errObj, ok := result.(*object.Error)
+		if !ok {
+			t.Errorf("expected error for %q, got=%T (%+v)", tt.input, result, result)
+			continue
+		}
+		if !strings.Contains(errObj.Message, tt.expected) {
+			t.Errorf("wrong error message. got=%q, want it to contain %q", errObj.Message, tt.expected)
+		}
+	}
+}
+
 // ================================
 // Struct with main() function
 // ================================
@@ -11,7 +11,7 @@ func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) obje
 		return obj
 	}
 
-	for _, opt := range se.Choices {
+	for i, opt := range se.Choices {
 		if opt.Default {
 			continue
 		}
@@ -29,10 +29,12 @@ func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) obje
 					return guardResult
 				}
 				if isTruthy(guardResult) {
+					coverBranch(se, i)
 					return evalBlockStatement(opt.Block, caseEnv)
 				}
 			} else {
 				// No guard, always match
+				coverBranch(se, i)
 				return evalBlockStatement(opt.Block, caseEnv)
 			}
 		} else {
@@ -53,6 +55,7 @@ func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) obje
 							continue // Guard failed, try next case
 						}
 					}
+					coverBranch(se, i)
 					return evalBlockStatement(opt.Block, env)
 				}
 			}
@@ -60,11 +63,13 @@ func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) obje
 	}
 
 	// Handle default cases
-	for _, opt := range se.Choices {
+	for i, opt := range se.Choices {
 		if opt.Default {
+			coverBranch(se, i)
 			return evalBlockStatement(opt.Block, env)
 		}
 	}
 
+	coverBranch(se, len(se.Choices))
 	return NULL
 }
@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// Goroutines started by `go` and by async functions are threads to the
+// debugger and the profiler, each with a call stack of its own. The
+// program itself is MainThread, and so is code that modules call back
+// into from goroutines of their own.
+
+// MainThread is the thread of the program itself.
+const MainThread = 1
+
+var (
+	threadMu    sync.Mutex
+	threads     = map[int64]int{} // thread numbers by goroutine id
+	lastThread  = MainThread
+	liveThreads atomic.Int32 // threads other than MainThread that are running
+)
+
+// currentThread returns the thread the calling goroutine runs. Go has no
+// cheap way to tell goroutines apart, so the goroutine is only looked up
+// while a thread other than MainThread is running.
+func currentThread() int {
+	if liveThreads.Load() == 0 {
+		return MainThread
+	}
+	id := goroutineID()
+	threadMu.Lock()
+	defer threadMu.Unlock()
+	if thread, ok := threads[id]; ok {
+		return thread
+	}
+	return MainThread
+}
+
+// restartThreads numbers threads from MainThread again, for a debugger
+// or profiler that was just installed, unless some are still running.
+func restartThreads() {
+	threadMu.Lock()
+	if liveThreads.Load() == 0 {
+		lastThread = MainThread
+	}
+	threadMu.Unlock()
+}
+
+// runThread evaluates node on a goroutine just started to run it, as a
+// thread of its own whose calls begin with a frame called root.
+func runThread(root string, node ast.Node, env *object.Environment) object.VintObject {
+	if debugHook == nil && profiler == nil {
+		return Eval(node, env)
+	}
+
+	id := goroutineID()
+	threadMu.Lock()
+	lastThread++
+	threads[id] = lastThread
+	threadMu.Unlock()
+	liveThreads.Add(1)
+	defer func() {
+		liveThreads.Add(-1)
+		threadMu.Lock()
+		delete(threads, id)
+		threadMu.Unlock()
+	}()
+
+	frame := Frame{Function: root, Env: env}
+	if tok, ok := nodeToken(node); ok {
+		frame.File, frame.Line, frame.Column = tok.File, tok.Line, tok.Column
+	}
+	defer debugPush(frame)()
+	defer profileCall(root)()
+	return Eval(node, env)
+}
+
+// goroutineID reads the id of the calling goroutine from the first line
+// of its stack trace, "goroutine 18 [running]:".
+func goroutineID() int64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,80 @@
+package evaluator
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// evalTryStatement runs the try block, hands any error to the first catch
+// clause whose type filter matches, and always runs the finally block.
+// Deferred calls are untouched: they are still scoped to the enclosing
+// function and run after the whole try statement (finally included) is done.
+func evalTryStatement(node *ast.TryStatement, env *object.Environment) object.VintObject {
+	result := Eval(node.Block, object.NewEnclosedEnvironment(env))
+
+	if errObj, ok := result.(*object.Error); ok {
+		caught := caughtValue(errObj)
+		for _, clause := range node.CatchClauses {
+			if !catchMatches(clause, caught, env) {
+				continue
+			}
+			catchEnv := object.NewEnclosedEnvironment(env)
+			if clause.Param != nil && clause.Param.Value != "_" {
+				catchEnv.Define(clause.Param.Value, caught)
+			}
+			result = Eval(clause.Block, catchEnv)
+			break
+		}
+	}
+
+	if node.Finally != nil {
+		finalResult := Eval(node.Finally, object.NewEnclosedEnvironment(env))
+		if finalResult != nil {
+			switch finalResult.Type() {
+			case object.ERROR_OBJ, object.RETURN_VALUE_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+				// Control flow in finally overrides the try/catch outcome
+				return finalResult
+			}
+		}
+	}
+
+	return result
+}
+
+// caughtValue returns the value a catch clause binds. Thrown values are
+// handed over as-is so custom errors keep their fields; runtime errors are
+// wrapped in a RuntimeError(message) so they can be inspected without
+// re-raising when used as a value.
+func caughtValue(errObj *object.Error) object.VintObject {
+	if errObj.Thrown != nil {
+		return errObj.Thrown
+	}
+	return &object.CustomError{
+		ErrorType: object.RuntimeErrorType,
+		Arguments: []object.VintObject{&object.String{Value: errObj.Message}},
+	}
+}
+
+// catchMatches reports whether a catch clause handles the caught value.
+// Untyped clauses and the 'error'/'any' filters catch everything; otherwise
+// the filter must name the error type declared with `error Name(...)`.
+func catchMatches(clause *ast.CatchClause, caught object.VintObject, env *object.Environment) bool {
+	if clause.ErrorType == nil {
+		return true
+	}
+	name := clause.ErrorType.Value
+	if name == "error" || name == "any" {
+		return true
+	}
+
+	ce, isCustom := caught.(*object.CustomError)
+	if val, ok := env.Get(name); ok {
+		if et, ok := val.(*object.ErrorType); ok {
+			return isCustom && (ce.ErrorType == et || ce.ErrorType.Name == et.Name)
+		}
+	}
+	if isCustom {
+		return ce.ErrorType.Name == name
+	}
+	return compatible(&ast.BasicType{Token: clause.ErrorType.Token, Name: name}, caught)
+}
@@ -14,6 +14,14 @@ func convertToInteger(obj object.VintObject) object.VintObject {
 		return obj
 	case *object.Float:
 		return &object.Integer{Value: int64(obj.Value)}
+	case *object.BigInt:
+		if !obj.Value.IsInt64() {
+			return newError("Cannot convert %s to INTEGER: it is too large", obj.Inspect())
+		}
+		return &object.Integer{Value: obj.Value.Int64()}
+	case *object.Decimal:
+		// Drop the fraction, as for floats
+		return convertToInteger(&object.BigInt{Value: obj.Truncate()})
 	case *object.String:
 		// Parse the string to an integer
 		i, err := strconv.ParseInt(obj.Value, 10, 64)
@@ -41,6 +49,10 @@ func convertToFloat(obj object.VintObject) object.VintObject {
 	case *object.Integer:
 		// Convert integer to float
 		return &object.Float{Value: float64(obj.Value)}
+	case *object.BigInt:
+		return toFloat(obj)
+	case *object.Decimal:
+		return &object.Float{Value: obj.Float64()}
 	case *object.String:
 		// Parse the string to a float
 		f, err := strconv.ParseFloat(obj.Value, 64)
@@ -60,6 +72,24 @@ func convertToFloat(obj object.VintObject) object.VintObject {
 	}
 }
 
+// Converts an object to a BigInt, if possible
+func convertToBigInt(obj object.VintObject) object.VintObject {
+	b, err := object.ToBigInt(obj)
+	if err != nil {
+		return newError("Cannot convert to BIGINT: %s", err)
+	}
+	return b
+}
+
+// Converts an object to a Decimal, if possible
+func convertToDecimal(obj object.VintObject) object.VintObject {
+	d, err := object.ToDecimal(obj)
+	if err != nil {
+		return newError("Cannot convert to DECIMAL: %s", err)
+	}
+	return d
+}
+
 // Converts an object to a string
 func convertToString(obj object.VintObject) object.VintObject {
 	// Simply return the string representation of the object
@@ -78,6 +108,10 @@ func convertToBoolean(obj object.VintObject) object.VintObject {
 	case *object.Float:
 		// Convert float to boolean: non-zero -> true, zero -> false
 		return &object.Boolean{Value: obj.Value != 0}
+	case *object.BigInt:
+		return &object.Boolean{Value: obj.Value.Sign() != 0}
+	case *object.Decimal:
+		return &object.Boolean{Value: obj.Coef.Sign() != 0}
 	case *object.String:
 		// Convert string to boolean: empty string -> false, non-empty -> true
 		return &object.Boolean{Value: len(obj.Value) > 0}
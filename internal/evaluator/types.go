@@ -1,6 +1,8 @@
 package evaluator
 
 import (
+	"math/big"
+
 	"github.com/vintlang/vintlang/internal/ast"
 	"github.com/vintlang/vintlang/internal/object"
 )
@@ -17,8 +19,11 @@ var typeNameToRuntime = map[string]object.VintObjectType{
 	"uint32":  object.INTEGER_OBJ,
 	"uint64":  object.INTEGER_OBJ,
 	"byte":    object.INTEGER_OBJ,
+	"float":   object.FLOAT_OBJ,
 	"float32": object.FLOAT_OBJ,
 	"float64": object.FLOAT_OBJ,
+	"bigint":  object.BIGINT_OBJ,
+	"decimal": object.DECIMAL_OBJ,
 	"string":  object.STRING_OBJ,
 	"bool":    object.BOOLEAN_OBJ,
 	"nil":     object.NULL_OBJ,
@@ -35,6 +40,13 @@ func compatible(declared ast.Type, obj object.VintObject) bool {
 		if t.Name == "any" {
 			return true
 		}
+		if t.Name == "number" {
+			return isNumber(obj)
+		}
+		// Integers that outgrow int64 become BigInts, and are still ints
+		if t.Name == "int" && obj.Type() == object.BIGINT_OBJ {
+			return true
+		}
 		// Struct instances match by struct name
 		if si, ok := obj.(*object.StructInstance); ok {
 			return si.Struct.Name == t.Name
@@ -60,7 +72,16 @@ func compatible(declared ast.Type, obj object.VintObject) bool {
 	case *ast.DictType:
 		return obj.Type() == object.DICT_OBJ
 	case *ast.PointerType:
-		return obj.Type() == object.POINTER_OBJ
+		// null is the nil pointer of every pointer type
+		if obj == NULL {
+			return true
+		}
+		p, ok := obj.(*object.Pointer)
+		if !ok {
+			return false
+		}
+		value, err := p.Load()
+		return err != nil || compatible(t.BaseType, value)
 	case *ast.ChannelType:
 		return obj.Type() == object.CHANNEL_OBJ
 	case *ast.FunctionType:
@@ -68,6 +89,13 @@ func compatible(declared ast.Type, obj object.VintObject) bool {
 	case *ast.MultiReturnType:
 		// Multi-return checks each component
 		return true
+	case *ast.InterfaceType:
+		return implements(obj, t)
+	case *ast.TypeParameter:
+		// Outside a call that binds it, a type parameter only has its constraint
+		return t.Constraint == nil || compatible(t.Constraint, obj)
+	case *ast.GenericType:
+		return isInstanceOf(obj, t, sameType)
 	default:
 		return false
 	}
@@ -80,6 +108,10 @@ func inferType(obj object.VintObject) ast.Type {
 		return &ast.BasicType{Name: "int"}
 	case *object.Float:
 		return &ast.BasicType{Name: "float64"}
+	case *object.BigInt:
+		return &ast.BasicType{Name: "bigint"}
+	case *object.Decimal:
+		return &ast.BasicType{Name: "decimal"}
 	case *object.String:
 		return &ast.BasicType{Name: "string"}
 	case *object.Boolean:
@@ -91,15 +123,29 @@ func inferType(obj object.VintObject) ast.Type {
 	case *object.CustomError:
 		return &ast.BasicType{Name: "error"}
 	case *object.StructInstance:
+		if len(obj.Struct.TypeParams) > 0 {
+			// Box<int>, with any for the type arguments not known yet
+			generic := &ast.GenericType{Name: obj.Struct.Name}
+			for _, tp := range obj.Struct.TypeParams {
+				arg, ok := obj.TypeArgs[tp.Name]
+				if !ok {
+					arg = &ast.BasicType{Name: "any"}
+				}
+				generic.Args = append(generic.Args, arg)
+			}
+			return generic
+		}
 		return &ast.BasicType{Name: obj.Struct.Name}
 	case *object.Enum:
 		return &ast.BasicType{Name: obj.Name}
 	case *object.Array:
-		return &ast.BasicType{Name: "[]any"}
+		return &ast.ArrayType{ElementType: &ast.BasicType{Name: "any"}}
 	case *object.Dict:
-		return &ast.BasicType{Name: "{any: any}"}
+		return &ast.DictType{KeyType: &ast.BasicType{Name: "any"}, ValueType: &ast.BasicType{Name: "any"}}
 	case *object.Pointer:
-		return &ast.BasicType{Name: "*any"}
+		return &ast.PointerType{BaseType: &ast.BasicType{Name: "any"}}
+	case *object.Function, *object.Builtin:
+		return &ast.FunctionType{ReturnType: &ast.BasicType{Name: "any"}}
 	default:
 		return &ast.BasicType{Name: "any"}
 	}
@@ -117,8 +163,12 @@ func evalTypeCast(node *ast.TypeCastExpression, env *object.Environment) object.
 		case "int", "int8", "int16", "int32", "int64",
 			"uint", "uint8", "uint16", "uint32", "uint64", "byte":
 			return convertToInteger(val)
-		case "float32", "float64":
+		case "float", "float32", "float64":
 			return convertToFloat(val)
+		case "bigint":
+			return convertToBigInt(val)
+		case "decimal":
+			return convertToDecimal(val)
 		case "string":
 			return convertToString(val)
 		case "bool":
@@ -148,8 +198,12 @@ func zeroValueFromType(t ast.Type) object.VintObject {
 		case "int", "int8", "int16", "int32", "int64",
 			"uint", "uint8", "uint16", "uint32", "uint64", "byte":
 			return &object.Integer{Value: 0}
-		case "float32", "float64":
+		case "float", "float32", "float64":
 			return &object.Float{Value: 0.0}
+		case "bigint":
+			return &object.BigInt{Value: new(big.Int)}
+		case "decimal":
+			return &object.Decimal{Coef: new(big.Int)}
 		case "string":
 			return &object.String{Value: ""}
 		case "bool":
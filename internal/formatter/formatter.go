@@ -0,0 +1,499 @@
+// Package formatter implements `vint fmt`. It reformats source code from
+// its tokens, comments included, so that nothing but whitespace changes:
+//
+//   - lines are indented by four spaces per open multi-line bracket, and
+//     continuation lines by one more level
+//   - spacing between tokens on a line follows fixed rules (one space
+//     around binary operators and after commas, none inside parentheses)
+//   - a block of statements in braces is kept on one line, with a space
+//     inside each brace, if it was written on one line and has a single
+//     statement; otherwise each brace ends or begins a line of its own
+//   - other line breaks are kept as written, except that runs of blank
+//     lines become one and blank lines at the start and end of a block go
+//     away
+//
+// Formatting is idempotent: formatting formatted code changes nothing.
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+const indentUnit = "    "
+
+// item is a token with the exact text it was written as and the layout
+// around it in the source.
+type item struct {
+	tok      token.Token
+	text     string
+	newlines int  // line breaks between the previous token and this one
+	spaced   bool // whether whitespace precedes it on the same line
+	angle    bool // a '<' or '>' around type parameters or type arguments
+
+	block      bool // a '{' that begins a block of statements
+	statements int  // how many statements the block has
+}
+
+// Format returns src formatted. Source that does not parse is returned
+// with an error, since its structure cannot be trusted.
+func Format(src, filename string) (string, error) {
+	p := parser.New(lexer.NewWithFilename(src, filename))
+	p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		return "", fmt.Errorf("%s does not parse:\n%s", filename, strings.Join(errs, "\n"))
+	}
+
+	items := scan(src, filename)
+	markTypeBrackets(items, p.TypeBrackets())
+	markBlocks(items, p.Blocks())
+	layoutBlocks(items)
+	formatted := render(items)
+
+	// The formatter must only ever change whitespace. Check that by
+	// comparing tokens, so that a bug cannot corrupt anyone's code.
+	if !sameTokens(items, scan(formatted, filename)) {
+		return "", fmt.Errorf("%s: formatting would change the meaning of the code; please report this as a bug", filename)
+	}
+	return formatted, nil
+}
+
+func scan(src, filename string) []item {
+	runes := []rune(src)
+	l := lexer.NewWithComments(src, filename)
+	var items []item
+	prevEnd := 0
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			break
+		}
+		start, end := l.Span()
+		gap := string(runes[prevEnd:start])
+		items = append(items, item{
+			tok:      tok,
+			text:     string(runes[start:end]),
+			newlines: strings.Count(gap, "\n"),
+			spaced:   gap != "",
+		})
+		prevEnd = end
+	}
+	return items
+}
+
+// markTypeBrackets marks the items that the parser read as the brackets
+// of type parameters or type arguments, which the tokens alone do not
+// tell apart from comparisons.
+func markTypeBrackets(items []item, brackets []token.Token) {
+	at := map[[2]int]bool{}
+	for _, b := range brackets {
+		at[[2]int{b.Line, b.Column}] = true
+	}
+	for i := range items {
+		items[i].angle = at[[2]int{items[i].tok.Line, items[i].tok.Column}]
+	}
+}
+
+// markBlocks marks the items that the parser read as the opening brace of
+// a block of statements, rather than of a dict literal.
+func markBlocks(items []item, blocks []*ast.BlockStatement) {
+	at := map[[2]int]*ast.BlockStatement{}
+	for _, b := range blocks {
+		at[[2]int{b.Token.Line, b.Token.Column}] = b
+	}
+	for i := range items {
+		if b, ok := at[[2]int{items[i].tok.Line, items[i].tok.Column}]; ok && items[i].tok.Type == token.LBRACE {
+			items[i].block, items[i].statements = true, len(b.Statements)
+		}
+	}
+}
+
+// layoutBlocks sets out the braces of blocks, so that {return 1} and
+// { return 1 } come out the same. A block with more than one statement,
+// or a line break anywhere inside it, gets each brace and each statement
+// on a line of its own. Any other block stays on one line, with a space
+// inside each brace.
+func layoutBlocks(items []item) {
+	for i := range items {
+		if !items[i].block {
+			continue
+		}
+		end := closer(items, i)
+		if end <= i+1 {
+			continue // {}
+		}
+		multiLine := items[i].statements > 1
+		for j := i + 1; j <= end && !multiLine; j++ {
+			multiLine = items[j].newlines > 0
+		}
+		if !multiLine {
+			items[i+1].spaced, items[end].spaced = true, true
+			continue
+		}
+		// A comment after the brace stays on its line.
+		first := i + 1
+		for first < end && items[first].tok.Type == token.COMMENT && items[first].newlines == 0 {
+			first++
+		}
+		items[first].newlines = max(items[first].newlines, 1)
+		items[end].newlines = max(items[end].newlines, 1)
+
+		// Statements after a semicolon start a line too.
+		depth := 0
+		for j := i + 1; j < end; j++ {
+			switch t := items[j].tok.Type; {
+			case isOpener(t):
+				depth++
+			case isCloser(t):
+				depth--
+			case t == token.SEMICOLON && depth == 0 && items[j+1].tok.Type != token.COMMENT:
+				items[j+1].newlines = max(items[j+1].newlines, 1)
+			}
+		}
+	}
+}
+
+// closer returns the index of the bracket that closes the one at i, or -1.
+func closer(items []item, i int) int {
+	depth := 0
+	for j := i; j < len(items); j++ {
+		switch {
+		case isOpener(items[j].tok.Type):
+			depth++
+		case isCloser(items[j].tok.Type):
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+func sameTokens(a, b []item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].tok.Type != b[i].tok.Type || strings.TrimSpace(a[i].text) != strings.TrimSpace(b[i].text) {
+			return false
+		}
+	}
+	return true
+}
+
+// bracket is an open (, [ or {. Its contents are indented if it is
+// multi-line, meaning a line break follows it.
+type bracket struct {
+	tok       token.TokenType
+	multiLine bool
+}
+
+// line is one line of output. A comment that ends a line of code is kept
+// apart so that comments on neighbouring lines can be aligned.
+type line struct {
+	code    string
+	comment string
+}
+
+func render(items []item) string {
+	var lines []line
+	var cur strings.Builder
+	var stack []bracket
+	lineStart := true
+	ended := false // a trailing comment has already ended the line
+	lastCode := -1 // index of the last token that is not a comment
+
+	endLine := func() {
+		lines = append(lines, line{code: cur.String()})
+		cur.Reset()
+	}
+
+	for i, it := range items {
+		if isCloser(it.tok.Type) && len(stack) > 0 {
+			stack = stack[:len(stack)-1]
+		}
+
+		if i > 0 && it.newlines > 0 {
+			breaks := min(it.newlines, 2)
+			if isOpener(items[i-1].tok.Type) || isCloser(it.tok.Type) {
+				breaks = 1
+			}
+			if ended {
+				breaks--
+			}
+			for ; breaks > 0; breaks-- {
+				endLine()
+			}
+			lineStart = true
+		}
+
+		trailing := it.tok.Type == token.COMMENT && !lineStart &&
+			(i+1 == len(items) || items[i+1].newlines > 0)
+
+		switch {
+		case lineStart:
+			depth := 0
+			for _, b := range stack {
+				if b.multiLine {
+					depth++
+				}
+			}
+			if isContinuation(items, i, lastCode, stack) {
+				depth++
+			}
+			cur.WriteString(strings.Repeat(indentUnit, depth))
+		case trailing:
+		case it.tok.Type == token.COMMENT || items[i-1].tok.Type == token.COMMENT:
+			cur.WriteString(" ")
+		case lastCode >= 0 && space(items, lastCode, i, stack):
+			cur.WriteString(" ")
+		}
+
+		text := strings.TrimRight(it.text, " \t")
+		if trailing {
+			lines = append(lines, line{code: cur.String(), comment: text})
+			cur.Reset()
+		} else {
+			cur.WriteString(text)
+		}
+		lineStart, ended = false, trailing
+
+		if isOpener(it.tok.Type) {
+			// A comment after the bracket does not make it multi-line.
+			next := i + 1
+			for next < len(items) && items[next].tok.Type == token.COMMENT && items[next].newlines == 0 {
+				next++
+			}
+			stack = append(stack, bracket{tok: it.tok.Type, multiLine: next < len(items) && items[next].newlines > 0})
+		}
+		if it.tok.Type != token.COMMENT {
+			lastCode = i
+		}
+	}
+	if cur.Len() > 0 {
+		endLine()
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	alignComments(lines)
+	var out strings.Builder
+	for _, l := range lines {
+		out.WriteString(l.code)
+		if l.comment != "" {
+			out.WriteString(" " + l.comment)
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// alignComments lines up the comments that end consecutive lines of code.
+func alignComments(lines []line) {
+	for i := 0; i < len(lines); {
+		if lines[i].comment == "" {
+			i++
+			continue
+		}
+		j, width := i, 0
+		for ; j < len(lines) && lines[j].comment != ""; j++ {
+			width = max(width, utf8.RuneCountInString(lines[j].code))
+		}
+		for ; i < j; i++ {
+			lines[i].code += strings.Repeat(" ", width-utf8.RuneCountInString(lines[i].code))
+		}
+	}
+}
+
+// isContinuation reports whether the line starting at items[i] continues
+// the statement on the line before, as after a trailing operator or before
+// a leading dot.
+func isContinuation(items []item, i, lastCode int, stack []bracket) bool {
+	if lastCode < 0 || isCloser(items[i].tok.Type) {
+		return false
+	}
+	cur := items[i].tok.Type
+	if isDot(cur) || isBinary(cur) && !isUnary(items, i) {
+		return true
+	}
+	prev := items[lastCode].tok.Type
+	if prev == token.COMMA {
+		// Elements of a multi-line list are not continuation lines, but
+		// arguments wrapped inside a one-line call are.
+		return len(stack) > 0 && !stack[len(stack)-1].multiLine
+	}
+	return isBinary(prev) && !isUnary(items, lastCode) || isDot(prev)
+}
+
+// isDot reports whether t is . or ?., which take no spaces around them.
+func isDot(t token.TokenType) bool {
+	return t == token.DOT || t == token.QUESTION_DOT
+}
+
+func isOpener(t token.TokenType) bool {
+	return t == token.LPAREN || t == token.LBRACKET || t == token.LBRACE
+}
+
+func isCloser(t token.TokenType) bool {
+	return t == token.RPAREN || t == token.RBRACKET || t == token.RBRACE
+}
+
+var binaryOperators = map[token.TokenType]bool{
+	token.ASSIGN: true, token.PLUS: true, token.MINUS: true, token.ASTERISK: true,
+	token.SLASH: true, token.MODULUS: true, token.POW: true, token.AMPERSAND: true,
+	token.LT: true, token.LTE: true, token.GT: true, token.GTE: true,
+	token.EQ: true, token.NOT_EQ: true, token.AND: true, token.OR: true,
+	token.NULL_COALESCE: true, token.PLUS_ASSIGN: true, token.MINUS_ASSIGN: true,
+	token.ASTERISK_ASSIGN: true, token.SLASH_ASSIGN: true, token.MODULUS_ASSIGN: true,
+	token.ARROW: true, token.PIPE: true, token.CARET: true, token.SHIFT_LEFT: true, token.SHIFT_RIGHT: true,
+	token.AMPERSAND_ASSIGN: true, token.PIPE_ASSIGN: true, token.CARET_ASSIGN: true,
+	token.SHIFT_LEFT_ASSIGN: true, token.SHIFT_RIGHT_ASSIGN: true,
+}
+
+func isBinary(t token.TokenType) bool {
+	return binaryOperators[t]
+}
+
+// endsOperand reports whether a token can end an operand, in which case an
+// operator after it is binary rather than unary.
+func endsOperand(t token.TokenType) bool {
+	switch t {
+	case token.IDENT, token.INT, token.BIGINT, token.FLOAT, token.STRING, token.TEMPLATE, token.TRUE, token.FALSE, token.NULL,
+		token.RPAREN, token.RBRACKET, token.RBRACE, token.PLUS_PLUS, token.MINUS_MINUS:
+		return true
+	}
+	return false
+}
+
+// prevCode returns the index of the last non-comment token before i, or -1.
+func prevCode(items []item, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if items[j].tok.Type != token.COMMENT {
+			return j
+		}
+	}
+	return -1
+}
+
+// isUnary reports whether the operator at i is a prefix operator. Like
+// the parser, it takes ++, -- and * at the start of a line to begin a new
+// statement, as in *p = 1.
+func isUnary(items []item, i int) bool {
+	switch t := items[i].tok.Type; t {
+	case token.BANG, token.TILDE:
+		return true
+	case token.MINUS, token.PLUS, token.ASTERISK, token.AMPERSAND, token.PLUS_PLUS, token.MINUS_MINUS:
+		p := prevCode(items, i)
+		return p < 0 || !endsOperand(items[p].tok.Type) || items[i].newlines > 0 && (isIncDec(t) || t == token.ASTERISK)
+	}
+	return false
+}
+
+// declaresType reports whether the name at i is the one a struct or
+// interface declaration gives its type.
+func declaresType(items []item, i int) bool {
+	if items[i].tok.Type != token.IDENT {
+		return false
+	}
+	k := prevCode(items, i)
+	return k >= 0 && (items[k].tok.Type == token.STRUCT || items[k].tok.Type == token.IDENT && items[k].text == "interface")
+}
+
+func isIncDec(t token.TokenType) bool {
+	return t == token.PLUS_PLUS || t == token.MINUS_MINUS
+}
+
+// isWordKeyword reports whether t is a keyword other than true, false and
+// null, which behave like values.
+func isWordKeyword(t token.TokenType) bool {
+	switch t {
+	case token.TRUE, token.FALSE, token.NULL, token.AT:
+		return false
+	}
+	return token.IsKeyword(t)
+}
+
+// space reports whether a space goes between items[p] and items[c] when
+// they are on the same line.
+func space(items []item, p, c int, stack []bracket) bool {
+	prev, cur := items[p].tok.Type, items[c].tok.Type
+	written := items[c].spaced
+
+	switch {
+	case cur == token.COMMA || cur == token.SEMICOLON || cur == token.RPAREN || cur == token.RBRACKET:
+		return false
+	case prev == token.LPAREN || prev == token.LBRACKET:
+		return false
+	case prev == token.LBRACE && cur == token.RBRACE:
+		return false
+	case prev == token.LBRACE || cur == token.RBRACE:
+		// {a: 1} and { return 1 } are both common; keep what was written.
+		return written
+	case isDot(cur) || isDot(prev) || cur == token.RANGE || prev == token.RANGE:
+		return false
+	case prev == token.ELLIPSIS || prev == token.DOUBLECOLON || prev == token.AT:
+		return false
+	case items[c].angle:
+		return prev == token.GT // func<T> and Box<int>, but > > must not become >>
+	case items[p].angle && prev == token.LT:
+		return false
+	case items[p].angle:
+		return cur != token.LPAREN // Box<int>(value = 1)
+	case prev == token.COMMA || prev == token.SEMICOLON:
+		return true
+	case cur == token.COLON:
+		return false
+	case prev == token.COLON:
+		return len(stack) == 0 || stack[len(stack)-1].tok != token.LBRACKET // a[1:2]
+	}
+
+	if isIncDec(cur) && !isUnary(items, c) {
+		return false // x++
+	}
+	if isUnary(items, p) {
+		// Keep - -x apart so it does not become --x.
+		return written && strings.HasPrefix(items[c].text, items[p].text[:1])
+	}
+
+	switch cur {
+	case token.LPAREN:
+		switch prev {
+		case token.IDENT, token.RPAREN, token.RBRACKET, token.FUNCTION, token.CHAN:
+			return false // calls, func(...), chan(n)
+		case token.IF, token.WHILE, token.FOR, token.SWITCH, token.MATCH, token.CATCH:
+			return true
+		}
+		return isBinary(prev) || written // throw(e) and throw (e) both work
+	case token.LBRACKET:
+		switch prev {
+		case token.IDENT, token.RPAREN, token.RBRACKET, token.STRING, token.TEMPLATE:
+			return false // indexing
+		}
+		return true
+	case token.LBRACE:
+		if items[c].block || declaresType(items, p) {
+			return true // func(): T {, struct Point { and interface Shape {
+		}
+		switch prev {
+		case token.IDENT, token.RBRACKET:
+			return written // Point{x: 1} and []{string: int}
+		}
+		return true
+	}
+
+	if isBinary(cur) && !isUnary(items, c) || isBinary(prev) {
+		return true
+	}
+	if isWordKeyword(prev) || isWordKeyword(cur) {
+		return true
+	}
+	return written
+}
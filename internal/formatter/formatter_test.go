@@ -0,0 +1,195 @@
+package formatter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"spacing",
+			"let x=1+2*3\nlet y = add( x,2 )\nlet z=-x\nlet s=nums[1:3]\n",
+			"let x = 1 + 2 * 3\nlet y = add(x, 2)\nlet z = -x\nlet s = nums[1:3]\n",
+		},
+		{
+			"indentation",
+			"let f = func(a){\nif(a>1){\n  return a\n}\n        return 0\n}\n",
+			"let f = func(a) {\n    if (a > 1) {\n        return a\n    }\n    return 0\n}\n",
+		},
+		{
+			"multi-line literals",
+			"let d = {\n  \"a\": 1,\n      \"b\": [\n 1,\n 2\n]\n}\n",
+			"let d = {\n    \"a\": 1,\n    \"b\": [\n        1,\n        2\n    ]\n}\n",
+		},
+		{
+			"continuation lines",
+			"let total = a +\nb +\nc\nlet s = text\n.trim()\n.upper()\n",
+			"let total = a +\n    b +\n    c\nlet s = text\n    .trim()\n    .upper()\n",
+		},
+		{
+			"blank lines",
+			"\n\nlet a = 1\n\n\n\nlet b = 2\nif (a) {\n\n    print(a)\n\n}\n\n\n",
+			"let a = 1\n\nlet b = 2\nif (a) {\n    print(a)\n}\n",
+		},
+		{
+			"comments",
+			"// header\nlet a = 1  // one\nlet bb = 22 // two\n\n/* block\n   comment */\nlet c = func() { /* inline */ return 3 }\n",
+			"// header\nlet a = 1   // one\nlet bb = 22 // two\n\n/* block\n   comment */\nlet c = func() { /* inline */ return 3 }\n",
+		},
+		{
+			"builtins and literals",
+			"::println( \"hi\" )\nlet p = Point{x: 1}\nlet xs: []int = []\nx++\n",
+			"::println(\"hi\")\nlet p = Point{x: 1}\nlet xs: []int = []\nx++\n",
+		},
+		{
+			"interpolated strings are kept as written",
+			"let s=\"a ${x+1}\"+`b\n  ${ y }`\nlet c = `x`[0]\n",
+			"let s = \"a ${x+1}\" + `b\n  ${ y }`\nlet c = `x`[0]\n",
+		},
+		{
+			"type parameters and type arguments",
+			"let id = func < T : number > (x: T): T { return x }\nstruct Box<T>{ value: T }\nlet b: Box< Box<int> > = Box<Box<int>>(value=Box(value=1))\nlet c = a<b && x>>1 > 0\n",
+			"let id = func<T: number>(x: T): T { return x }\nstruct Box<T> { value: T }\nlet b: Box<Box<int> > = Box<Box<int>>(value = Box(value = 1))\nlet c = a < b && x >> 1 > 0\n",
+		},
+		{
+			"return types",
+			"let first = func<T>(xs: [T]): T{return xs[0]}\nlet all = func(): [int]{ return [1] }\nlet p = Point{x: 1}\n",
+			"let first = func<T>(xs: [T]): T { return xs[0] }\nlet all = func(): [int] { return [1] }\nlet p = Point{x: 1}\n",
+		},
+		{
+			"struct and interface declarations",
+			"struct Point{x: int}\ninterface Shape{\narea(): float\n}\ninterface Sh{area(): float}\nlet p = Point{x: 1}\n",
+			"struct Point {x: int}\ninterface Shape {\n    area(): float\n}\ninterface Sh {area(): float}\nlet p = Point{x: 1}\n",
+		},
+		{
+			"optional chaining",
+			"let n = resp ?. [\"user\"] ?. name ?? \"anon\"\nlet r = f ?. (1) ?. trim()\nlet s = a\n?.b\n",
+			"let n = resp?.[\"user\"]?.name ?? \"anon\"\nlet r = f?.(1)?.trim()\nlet s = a\n    ?.b\n",
+		},
+		{
+			"blocks",
+			"let a = func() {return 1}\nlet b = func() { return 1 }\nlet c = func(x) {\nreturn x }\nif (a) { let y = 1; print(y) } else {}\nlet d = {\"k\": [1]}\n",
+			"let a = func() { return 1 }\nlet b = func() { return 1 }\nlet c = func(x) {\n    return x\n}\nif (a) {\n    let y = 1;\n    print(y)\n} else {}\nlet d = {\"k\": [1]}\n",
+		},
+		{
+			"dereference at the start of a line",
+			"let x = 1\nlet p = &x\n*p = 2\n  * (&x) = 3\nlet y = x * *p\n",
+			"let x = 1\nlet p = &x\n*p = 2\n*(&x) = 3\nlet y = x * *p\n",
+		},
+		{
+			"empty",
+			"\n\n",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.input, "test.vint")
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.expected)
+			}
+			again, err := Format(got, "test.vint")
+			if err != nil {
+				t.Fatalf("formatting the output failed: %v", err)
+			}
+			if again != got {
+				t.Errorf("not idempotent, second pass gave:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestFormatParseError(t *testing.T) {
+	if _, err := Format("let = 5\n", "bad.vint"); err == nil || !strings.Contains(err.Error(), "bad.vint") {
+		t.Errorf("expected a parse error naming the file, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	expected := `--- a/x.vint
++++ b/x.vint
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -7,3 +7,4 @@
+ g
+ h
+ i
++j
+`
+	if got := Diff("x.vint", before, after); got != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+	if got := Diff("x.vint", before, before); got != "" {
+		t.Errorf("expected no diff for equal input, got:\n%s", got)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	messy := write("lib/messy.vint", "let x=1\n")
+	write("clean.vint", "let y = 2\n")
+	write("notes.txt", "let  z=3\n")
+
+	var out bytes.Buffer
+	if code := Main([]string{"--check", dir}, &out); code != 1 {
+		t.Errorf("--check exit code %d, want 1", code)
+	}
+	if got := strings.TrimSpace(out.String()); got != messy {
+		t.Errorf("--check listed %q, want %q", got, messy)
+	}
+
+	out.Reset()
+	if code := Main([]string{"--diff", dir}, &out); code != 0 {
+		t.Errorf("--diff exit code %d, want 0", code)
+	}
+	if !strings.Contains(out.String(), "-let x=1\n+let x = 1\n") {
+		t.Errorf("--diff output:\n%s", out.String())
+	}
+
+	if code := Main([]string{dir}, &bytes.Buffer{}); code != 0 {
+		t.Errorf("exit code %d, want 0", code)
+	}
+	if content, _ := os.ReadFile(messy); string(content) != "let x = 1\n" {
+		t.Errorf("file not formatted: %q", content)
+	}
+	if code := Main([]string{"--check", dir}, &bytes.Buffer{}); code != 0 {
+		t.Errorf("--check after formatting exit code %d, want 0", code)
+	}
+
+	broken := write("broken.vint", "let = 5\n")
+	if code := Main([]string{broken}, &bytes.Buffer{}); code != 1 {
+		t.Errorf("exit code %d for a file that does not parse, want 1", code)
+	}
+	if content, _ := os.ReadFile(broken); string(content) != "let = 5\n" {
+		t.Errorf("file that does not parse was changed: %q", content)
+	}
+}
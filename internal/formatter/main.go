@@ -0,0 +1,127 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/styles"
+)
+
+// Main runs `vint fmt` and returns the exit code. Files are formatted in
+// place, and directories are searched for .vint files. With --check
+// nothing is written and the files that need formatting are listed; with
+// --diff nothing is written and the changes are printed instead.
+func Main(args []string, out io.Writer) int {
+	var paths []string
+	check, diff := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--check", "-check":
+			check = true
+		case "--diff", "-diff", "-d":
+			diff = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown flag %s", arg)))
+				return 2
+			}
+			paths = append(paths, arg)
+		}
+	}
+
+	files, err := Discover(paths)
+	if err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+
+	failed, unformatted := false, 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			failed = true
+			continue
+		}
+		formatted, err := Format(string(src), file)
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			failed = true
+			continue
+		}
+		if formatted == string(src) {
+			continue
+		}
+		unformatted++
+
+		switch {
+		case diff:
+			fmt.Fprint(out, Diff(filepath.ToSlash(file), string(src), formatted))
+		case check:
+			fmt.Fprintln(out, file)
+		default:
+			if err := os.WriteFile(file, []byte(formatted), 0o644); err != nil {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				failed = true
+				continue
+			}
+			fmt.Fprintln(out, styles.HelpStyle.Render("Formatted", file))
+		}
+	}
+
+	if failed || check && unformatted > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Discover returns the .vint files named by paths, searching directories
+// recursively and skipping hidden ones. No paths means the current
+// directory.
+func Discover(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !strings.HasSuffix(path, ".vint") {
+				return nil, fmt.Errorf("%s is not a .vint file", path)
+			}
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if p != path && (strings.HasPrefix(name, ".") || name == "node_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(p, ".vint") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -16,13 +16,22 @@ type Lexer struct {
 	column       int
 	filename     string
 	errors       []string
+
+	// keepComments makes the lexer return comments as COMMENT tokens
+	// instead of skipping them, for tools that must not lose them.
+	keepComments bool
+	start, end   int // rune offsets of the last token in input
+
+	// parent is the lexer whose source this one's input is embedded in,
+	// see NewEmbedded.
+	parent *Lexer
 }
 
 func New(input string) *Lexer {
 	l := &Lexer{
 		input:    []rune(input),
 		line:     1,
-		column:   1,
+		column:   0,
 		filename: "main.vint", // default filename
 	}
 	l.readChar()
@@ -33,13 +42,28 @@ func NewWithFilename(input string, filename string) *Lexer {
 	l := &Lexer{
 		input:    []rune(input),
 		line:     1,
-		column:   1,
+		column:   0,
 		filename: filename,
 	}
 	l.readChar()
 	return l
 }
 
+// NewWithComments returns a lexer that also returns comments, as COMMENT
+// tokens whose literal is the comment text including its delimiters. The
+// parser does not accept them; this is for tools such as the formatter.
+func NewWithComments(input string, filename string) *Lexer {
+	l := NewWithFilename(input, filename)
+	l.keepComments = true
+	return l
+}
+
+// Span returns the rune offsets of the source text of the last token read,
+// so that the text can be recovered exactly as written.
+func (l *Lexer) Span() (start, end int) {
+	return l.start, l.end
+}
+
 func (l *Lexer) readChar() {
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
@@ -58,18 +82,42 @@ func (l *Lexer) readChar() {
 	}
 }
 
+// NextToken returns the next token, stamped with the source file and the
+// column it starts at so that runtime errors can point back to it.
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+	tok := l.nextToken()
+	tok.File = l.filename
+	l.end = l.position
+	return tok
+}
+
+func (l *Lexer) nextToken() (tok token.Token) {
 	l.skipWhitespace()
+	if l.keepComments && l.atComment() {
+		return l.readComment()
+	}
 	if l.ch == rune('/') && l.peekChar() == rune('/') {
 		l.skipSingleLineComment()
-		return l.NextToken()
+		return l.nextToken()
 	}
 	if l.ch == rune('/') && l.peekChar() == rune('*') {
 		l.skipMultiLineComment()
-		return l.NextToken()
+		return l.nextToken()
 	}
 
+	// Tokens are stamped with where they start: reading a token can move the
+	// lexer onto the next line before the token is built.
+	l.start = l.position
+	startLine, startColumn := l.line, l.column
+	defer func() {
+		if tok.Type != token.EOF {
+			tok.Line = startLine
+		}
+		if tok.Column == 0 {
+			tok.Column = startColumn
+		}
+	}()
+
 	switch l.ch {
 	case rune('='):
 		if l.peekChar() == rune('=') {
@@ -152,6 +200,9 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.LTE, Literal: string(ch) + string(l.ch), Line: l.line}
+		} else if l.peekChar() == rune('<') {
+			l.readChar()
+			tok = l.readShift(token.SHIFT_LEFT, token.SHIFT_LEFT_ASSIGN)
 		} else {
 			tok = newToken(token.LT, l.line, l.ch)
 		}
@@ -160,14 +211,23 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.GTE, Literal: string(ch) + string(l.ch), Line: l.line}
+		} else if l.peekChar() == rune('>') {
+			l.readChar()
+			tok = l.readShift(token.SHIFT_RIGHT, token.SHIFT_RIGHT_ASSIGN)
 		} else {
 			tok = newToken(token.GT, l.line, l.ch)
 		}
 
 	case rune('"'):
+		if l.hasInterpolation() {
+			tok = token.Token{Type: token.TEMPLATE, Literal: l.readTemplate('"')}
+			break
+		}
 		tok.Type = token.STRING
 		tok.Literal = l.readString()
 		tok.Line = l.line
+	case rune('`'):
+		tok = token.Token{Type: token.TEMPLATE, Literal: l.readTemplate('`')}
 	case rune('\''):
 		tok = token.Token{Type: token.STRING, Literal: l.readSingleQuoteString(), Line: l.line}
 	case rune('['):
@@ -204,6 +264,10 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.AND, Literal: string(ch) + string(l.ch), Line: l.line}
+		} else if l.peekChar() == rune('=') {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.AMPERSAND_ASSIGN, Literal: string(ch) + string(l.ch), Line: l.line}
 		} else {
 			tok = newToken(token.AMPERSAND, l.line, l.ch)
 		}
@@ -212,9 +276,23 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.OR, Literal: string(ch) + string(l.ch), Line: l.line}
+		} else if l.peekChar() == rune('=') {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.PIPE_ASSIGN, Literal: string(ch) + string(l.ch), Line: l.line}
 		} else {
 			tok = newToken(token.PIPE, l.line, l.ch)
 		}
+	case rune('^'):
+		if l.peekChar() == rune('=') {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.CARET_ASSIGN, Literal: string(ch) + string(l.ch), Line: l.line}
+		} else {
+			tok = newToken(token.CARET, l.line, l.ch)
+		}
+	case rune('~'):
+		tok = newToken(token.TILDE, l.line, l.ch)
 	case rune('%'):
 		if l.peekChar() == rune('=') {
 			ch := l.ch
@@ -228,13 +306,17 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.NULL_COALESCE, Literal: string(ch) + string(l.ch), Line: l.line}
+		} else if l.peekChar() == rune('.') {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.QUESTION_DOT, Literal: string(ch) + string(l.ch), Line: l.line}
 		} else {
-			tok = l.createIllegalToken(l.ch, "- single '?' is not a valid operator, did you mean '??'?")
+			tok = l.createIllegalToken(l.ch, "- single '?' is not a valid operator, did you mean '??' or '?.'?")
 		}
 	case rune('#'):
 		if l.peekChar() == rune('!') && l.line == 1 {
 			l.skipSingleLineComment()
-			return l.NextToken()
+			return l.nextToken()
 		}
 		tok = l.createIllegalToken(l.ch, "- '#' is not a valid token, did you mean '::' for builtins?")
 	case 0:
@@ -257,7 +339,10 @@ func (l *Lexer) NextToken() token.Token {
 			}
 			tok.Line = l.line
 			return tok
-		} else if isDigit(l.ch) && isLetter(l.peekChar()) {
+		} else if l.ch == '0' && isBasePrefix(l.peekChar()) {
+			tok = l.readBasedInteger()
+			return tok
+		} else if isDigit(l.ch) && isLetter(l.peekChar()) && l.peekChar() != '_' && !l.atBigIntSuffix() {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
 			tok.Line = l.line
@@ -294,6 +379,9 @@ func (l *Lexer) addError(msg string) {
 
 // getSourceLine returns the source line for error context
 func (l *Lexer) getSourceLine(lineNum int) string {
+	if l.parent != nil {
+		return l.parent.getSourceLine(lineNum)
+	}
 	lines := strings.Split(string(l.input), "\n")
 	if lineNum > 0 && lineNum <= len(lines) {
 		return lines[lineNum-1]
@@ -373,14 +461,37 @@ func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+// readNumber reads decimal digits, which may be separated by underscores:
+// 1_000_000.
 func (l *Lexer) readNumber() string {
 	position := l.position
-	for isDigit(l.ch) {
+	for isDigit(l.ch) || l.ch == '_' && (isDigit(l.peekChar()) || l.peekChar() == '_') {
 		l.readChar()
 	}
 	return string(l.input[position:l.position])
 }
 
+func isBasePrefix(ch rune) bool {
+	switch ch {
+	case 'x', 'X', 'b', 'B', 'o', 'O':
+		return true
+	}
+	return false
+}
+
+// readBasedInteger reads a hexadecimal, binary or octal literal: 0xFF,
+// 0b1010, 0o755. The literal keeps its prefix and underscores; the parser
+// converts it and reports digits that are not valid in its base.
+func (l *Lexer) readBasedInteger() token.Token {
+	position := l.position
+	l.readChar() // 0
+	l.readChar() // x, b or o
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	return token.Token{Type: token.INT, Literal: string(l.input[position:l.position]), Line: l.line}
+}
+
 func (l *Lexer) readDecimal() token.Token {
 	integer := l.readNumber()
 	if l.ch == '.' && isDigit(l.peekChar()) {
@@ -388,9 +499,36 @@ func (l *Lexer) readDecimal() token.Token {
 		fraction := l.readNumber()
 		return token.Token{Type: token.FLOAT, Literal: integer + "." + fraction, Line: l.line}
 	}
+	if l.ch == 'n' && !isLetter(l.peekChar()) && !isDigit(l.peekChar()) {
+		l.readChar()
+		return token.Token{Type: token.BIGINT, Literal: integer + "n", Line: l.line}
+	}
 	return token.Token{Type: token.INT, Literal: integer, Line: l.line}
 }
 
+// atBigIntSuffix reports whether the digit at l.ch is followed by the n
+// that ends a bigint literal, as in 5n, rather than by an identifier.
+func (l *Lexer) atBigIntSuffix() bool {
+	if l.peekChar() != 'n' {
+		return false
+	}
+	next := rune(0)
+	if l.readPosition+1 < len(l.input) {
+		next = l.input[l.readPosition+1]
+	}
+	return !isLetter(next) && !isDigit(next)
+}
+
+// readShift reads the second character of << or >>, and = if the shift is
+// a compound assignment.
+func (l *Lexer) readShift(shift, assign token.TokenType) token.Token {
+	if l.peekChar() == rune('=') {
+		l.readChar()
+		return token.Token{Type: assign, Literal: string(assign), Line: l.line}
+	}
+	return token.Token{Type: shift, Literal: string(shift), Line: l.line}
+}
+
 func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return rune(0)
@@ -414,6 +552,40 @@ func (l *Lexer) skipSingleLineComment() {
 	l.skipWhitespace()
 }
 
+func (l *Lexer) atComment() bool {
+	if l.ch == '/' {
+		return l.peekChar() == '/' || l.peekChar() == '*'
+	}
+	return l.ch == '#' && l.peekChar() == '!' && l.line == 1
+}
+
+// readComment reads a comment the lexer is at. Line comments end before the
+// newline; block comments include the closing */.
+func (l *Lexer) readComment() token.Token {
+	l.start = l.position
+	tok := token.Token{Type: token.COMMENT, Line: l.line, Column: l.column}
+	if l.ch == '/' && l.peekChar() == '*' {
+		l.readChar()
+		for {
+			l.readChar()
+			if l.ch == 0 {
+				break
+			}
+			if l.ch == '*' && l.peekChar() == '/' {
+				l.readChar()
+				l.readChar()
+				break
+			}
+		}
+	} else {
+		for l.ch != '\n' && l.ch != 0 {
+			l.readChar()
+		}
+	}
+	tok.Literal = strings.TrimRight(string(l.input[l.start:l.position]), "\r")
+	return tok
+}
+
 func (l *Lexer) skipMultiLineComment() {
 	endFound := false
 
@@ -443,75 +615,84 @@ func (l *Lexer) readString() string {
 			l.addError(fmt.Sprintf("Line %d: Unterminated string literal started on line %d", l.line, startLine))
 			break
 		} else if l.ch == '\\' {
-			switch l.peekChar() {
-			case 'n':
-				l.readChar()
-				str.WriteByte('\n')
-			case 'r':
-				l.readChar()
-				str.WriteByte('\r')
-			case 't':
-				l.readChar()
-				str.WriteByte('\t')
-			case '"':
-				l.readChar()
-				str.WriteByte('"')
-			case '\\':
-				l.readChar()
-				str.WriteByte('\\')
-			case '0':
-				l.readChar()
-				str.WriteByte('\x00')
-			case 'x':
-				// Handle hex escape sequences \xHH
-				l.readChar() // consume 'x'
-				l.readChar() // get first hex digit
-				h1 := l.ch
-				l.readChar() // get second hex digit
-				h2 := l.ch
-				if isHexDigit(h1) && isHexDigit(h2) {
-					value := hexValue(h1)*16 + hexValue(h2)
-					str.WriteByte(byte(value))
-				} else {
-					// Invalid hex sequence, just include as-is
-					str.WriteString("\\x")
-					str.WriteRune(h1)
-					str.WriteRune(h2)
-				}
-			case 'u':
-				// Handle Unicode escape sequences \uHHHH
-				l.readChar() // consume 'u'
-				var hexDigits [4]rune
-				for i := 0; i < 4; i++ {
-					l.readChar()
-					hexDigits[i] = l.ch
-					if !isHexDigit(l.ch) {
-						// Invalid Unicode sequence, include as-is
-						str.WriteString("\\u")
-						for j := 0; j <= i; j++ {
-							str.WriteRune(hexDigits[j])
-						}
-						goto continueLoop
-					}
-				}
-				// Convert to Unicode code point
-				value := hexValue(hexDigits[0])*4096 + hexValue(hexDigits[1])*256 +
-					hexValue(hexDigits[2])*16 + hexValue(hexDigits[3])
-				str.WriteRune(rune(value))
-			default:
-				// Unknown escape sequence, keep the backslash
-				str.WriteByte('\\')
-				str.WriteRune(l.peekChar())
-				l.readChar()
-			}
+			l.readEscape(&str)
 		} else {
 			str.WriteRune(l.ch)
 		}
-	continueLoop:
 	}
 	return str.String()
 }
 
+// readEscape reads the escape sequence that starts at the backslash under
+// the lexer into str, leaving the lexer on its last character.
+func (l *Lexer) readEscape(str *strings.Builder) {
+	switch l.peekChar() {
+	case 'n':
+		l.readChar()
+		str.WriteByte('\n')
+	case 'r':
+		l.readChar()
+		str.WriteByte('\r')
+	case 't':
+		l.readChar()
+		str.WriteByte('\t')
+	case '"':
+		l.readChar()
+		str.WriteByte('"')
+	case '\\':
+		l.readChar()
+		str.WriteByte('\\')
+	case '$', '`':
+		// \${ is a literal ${ rather than an interpolation
+		l.readChar()
+		str.WriteRune(l.ch)
+	case '0':
+		l.readChar()
+		str.WriteByte('\x00')
+	case 'x':
+		// Handle hex escape sequences \xHH
+		l.readChar() // consume 'x'
+		l.readChar() // get first hex digit
+		h1 := l.ch
+		l.readChar() // get second hex digit
+		h2 := l.ch
+		if isHexDigit(h1) && isHexDigit(h2) {
+			value := hexValue(h1)*16 + hexValue(h2)
+			str.WriteByte(byte(value))
+		} else {
+			// Invalid hex sequence, just include as-is
+			str.WriteString("\\x")
+			str.WriteRune(h1)
+			str.WriteRune(h2)
+		}
+	case 'u':
+		// Handle Unicode escape sequences \uHHHH
+		l.readChar() // consume 'u'
+		var hexDigits [4]rune
+		for i := 0; i < 4; i++ {
+			l.readChar()
+			hexDigits[i] = l.ch
+			if !isHexDigit(l.ch) {
+				// Invalid Unicode sequence, include as-is
+				str.WriteString("\\u")
+				for j := 0; j <= i; j++ {
+					str.WriteRune(hexDigits[j])
+				}
+				return
+			}
+		}
+		// Convert to Unicode code point
+		value := hexValue(hexDigits[0])*4096 + hexValue(hexDigits[1])*256 +
+			hexValue(hexDigits[2])*16 + hexValue(hexDigits[3])
+		str.WriteRune(rune(value))
+	default:
+		// Unknown escape sequence, keep the backslash
+		str.WriteByte('\\')
+		str.WriteRune(l.peekChar())
+		l.readChar()
+	}
+}
+
 func (l *Lexer) readSingleQuoteString() string {
 	startLine := l.line
 	var str string
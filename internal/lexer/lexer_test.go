@@ -291,6 +291,8 @@ func TestStringTokens(t *testing.T) {
 		{`"hello\rworld"`, "hello\rworld"},
 		{`"hello\x41world"`, "helloAworld"}, // \x41 = 'A'
 		{`"hello\u0041world"`, "helloAworld"}, // \u0041 = 'A'
+		{`"cost: \${x}"`, "cost: ${x}"},
+		{`"$5 {x}"`, "$5 {x}"},
 		{`""`, ""},
 	}
 
@@ -320,6 +322,11 @@ func TestNumberTokens(t *testing.T) {
 		{"3.14", token.FLOAT, "3.14"},
 		{"0.5", token.FLOAT, "0.5"},
 		{"123.456", token.FLOAT, "123.456"},
+		{"0xFF", token.INT, "0xFF"},
+		{"0b1010_0101", token.INT, "0b1010_0101"},
+		{"0o755", token.INT, "0o755"},
+		{"1_000_000", token.INT, "1_000_000"},
+		{"1_000.5", token.FLOAT, "1_000.5"},
 	}
 
 	for _, tt := range tests {
@@ -336,6 +343,80 @@ func TestNumberTokens(t *testing.T) {
 	}
 }
 
+func TestBitwiseTokens(t *testing.T) {
+	input := `a & b | c ^ d << 1 >> 2 ~e &= |= ^= <<= >>= && ||`
+	expected := []token.TokenType{
+		token.IDENT, token.AMPERSAND, token.IDENT, token.PIPE, token.IDENT, token.CARET, token.IDENT,
+		token.SHIFT_LEFT, token.INT, token.SHIFT_RIGHT, token.INT, token.TILDE, token.IDENT,
+		token.AMPERSAND_ASSIGN, token.PIPE_ASSIGN, token.CARET_ASSIGN, token.SHIFT_LEFT_ASSIGN,
+		token.SHIFT_RIGHT_ASSIGN, token.AND, token.OR, token.EOF,
+	}
+
+	l := New(input)
+	for i, want := range expected {
+		tok := l.NextToken()
+		if tok.Type != want {
+			t.Fatalf("tests[%d] - token type wrong. expected=%q, got=%q (%q)", i, want, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestTemplateTokens(t *testing.T) {
+	input := "\"a ${b[\"}\"]} c\" `x\n${\"${y}\"}` + 'no ${z}'"
+	expected := []struct {
+		typ     token.TokenType
+		literal string
+	}{
+		{token.TEMPLATE, `a ${b["}"]} c`},
+		{token.TEMPLATE, "x\n${\"${y}\"}"},
+		{token.PLUS, "+"},
+		{token.STRING, "no ${z}"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+	for i, want := range expected {
+		tok := l.NextToken()
+		if tok.Type != want.typ || tok.Literal != want.literal {
+			t.Fatalf("tests[%d] - expected %q %q, got %q %q", i, want.typ, want.literal, tok.Type, tok.Literal)
+		}
+	}
+	if errs := l.Errors(); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	l = New(`"a ${b"`)
+	l.NextToken()
+	if len(l.Errors()) != 1 {
+		t.Errorf("expected an error for an unterminated interpolation, got %v", l.Errors())
+	}
+}
+
+func TestSplitTemplate(t *testing.T) {
+	input := "let s = \"Hi ${name}!\\n${\n  price:.2f}\""
+	l := NewWithFilename(input, "test.vint")
+	tok := l.NextToken()
+	for tok.Type != token.TEMPLATE && tok.Type != token.EOF {
+		tok = l.NextToken()
+	}
+
+	expected := []TemplatePart{
+		{Text: "Hi ", Line: 1, Column: 10},
+		{Text: "name", Expr: true, Line: 1, Column: 13},
+		{Text: "!\n", Line: 1, Column: 20},
+		{Text: "\n  price", Expr: true, Format: ".2f", Line: 1, Column: 23},
+	}
+	parts := SplitTemplate(tok)
+	if len(parts) != len(expected) {
+		t.Fatalf("expected %d parts, got %d: %+v", len(expected), len(parts), parts)
+	}
+	for i, want := range expected {
+		if parts[i] != want {
+			t.Errorf("parts[%d] - expected %+v, got %+v", i, want, parts[i])
+		}
+	}
+}
+
 func TestLineNumbers(t *testing.T) {
 	input := `let x = 5;
 let y = 10;
@@ -376,4 +457,55 @@ let y = 10;`
 				i, expectedType, tok.Type)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "let ab = 1\nfoo.bar(x)\n"
+
+	expected := []struct {
+		literal      string
+		line, column int
+	}{
+		{"let", 1, 1}, {"ab", 1, 5}, {"=", 1, 8}, {"1", 1, 10},
+		{"foo", 2, 1}, {".", 2, 4}, {"bar", 2, 5}, {"(", 2, 8}, {"x", 2, 9}, {")", 2, 10},
+	}
+
+	l := New(input)
+	for i, want := range expected {
+		tok := l.NextToken()
+		if tok.Literal != want.literal || tok.Line != want.line || tok.Column != want.column {
+			t.Fatalf("tests[%d] - got %q at %d:%d, want %q at %d:%d",
+				i, tok.Literal, tok.Line, tok.Column, want.literal, want.line, want.column)
+		}
+	}
+}
+
+func TestCommentTokens(t *testing.T) {
+	input := "let x = 5 // five\n/* block\n   comment */ x"
+
+	expected := []struct {
+		tokenType token.TokenType
+		literal   string
+		line      int
+	}{
+		{token.LET, "let", 1}, {token.IDENT, "x", 1}, {token.ASSIGN, "=", 1}, {token.INT, "5", 1},
+		{token.COMMENT, "// five", 1}, {token.COMMENT, "/* block\n   comment */", 2}, {token.IDENT, "x", 3},
+		{token.EOF, "", 3},
+	}
+
+	l := NewWithComments(input, "test.vint")
+	runes := []rune(input)
+	for i, want := range expected {
+		tok := l.NextToken()
+		if tok.Type != want.tokenType || tok.Literal != want.literal || tok.Line != want.line {
+			t.Fatalf("tests[%d] - got %s %q on line %d, want %s %q on line %d",
+				i, tok.Type, tok.Literal, tok.Line, want.tokenType, want.literal, want.line)
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+		if start, end := l.Span(); string(runes[start:end]) != want.literal {
+			t.Fatalf("tests[%d] - span is %q, want %q", i, string(runes[start:end]), want.literal)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// TemplatePart is a piece of a TEMPLATE token: either literal text, with
+// its escapes applied, or the source of an embedded ${...} expression.
+type TemplatePart struct {
+	Text   string
+	Expr   bool
+	Format string // the spec after ':' in ${value:spec}, if any
+
+	// Where the part starts. For an expression, this is its '$'.
+	Line, Column int
+}
+
+// NewEmbedded returns a lexer for source code embedded in a token that
+// parent read, such as an expression inside an interpolated string. Its
+// first character is at line and column, so its tokens, and errors about
+// them, point into the parent's source.
+func NewEmbedded(parent *Lexer, input string, line, column int) *Lexer {
+	l := &Lexer{
+		input:    []rune(input),
+		line:     line,
+		column:   column - 1,
+		filename: parent.filename,
+		parent:   parent,
+	}
+	l.readChar()
+	return l
+}
+
+// SplitTemplate splits a TEMPLATE token into its literal and expression
+// parts, in order.
+func SplitTemplate(tok token.Token) []TemplatePart {
+	l := &Lexer{input: []rune(tok.Literal), line: tok.Line, column: tok.Column, filename: tok.File}
+	l.readChar()
+
+	var parts []TemplatePart
+	var text strings.Builder
+	line, column := l.line, l.column
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, TemplatePart{Text: text.String(), Line: line, Column: column})
+			text.Reset()
+		}
+	}
+
+	for l.ch != 0 {
+		if text.Len() == 0 {
+			line, column = l.line, l.column
+		}
+		switch {
+		case l.ch == '\\':
+			l.readEscape(&text)
+		case l.ch == '$' && l.peekChar() == '{':
+			flush()
+			part := TemplatePart{Expr: true, Line: l.line, Column: l.column}
+			l.readChar()
+			start := l.readPosition
+			colon, _ := l.skipInterpolation()
+			end := l.position
+			if colon >= 0 {
+				part.Format = string(l.input[colon+1 : end])
+				end = colon
+			}
+			part.Text = string(l.input[start:end])
+			parts = append(parts, part)
+		default:
+			text.WriteRune(l.ch)
+		}
+		l.readChar()
+	}
+	flush()
+	return parts
+}
+
+// hasInterpolation reports whether the double-quoted string that starts
+// at the lexer contains a ${...}, without reading it.
+func (l *Lexer) hasInterpolation() bool {
+	for i := l.readPosition; i < len(l.input); i++ {
+		switch l.input[i] {
+		case '\\':
+			i++
+		case '"':
+			return false
+		case '$':
+			if i+1 < len(l.input) && l.input[i+1] == '{' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readTemplate reads a string up to the closing quote and returns the
+// source text between the quotes. Escapes are left as written and
+// interpolations are skipped over whole, since they can contain quotes
+// and braces of their own; SplitTemplate takes the text apart later.
+func (l *Lexer) readTemplate(quote rune) string {
+	startLine := l.line
+	start := l.readPosition
+	if !l.skipTemplate(quote) {
+		l.addError(fmt.Sprintf("Line %d: Unterminated string literal started on line %d", l.line, startLine))
+	}
+	return string(l.input[start:l.position])
+}
+
+// skipTemplate moves the lexer from an opening quote to the closing one,
+// and reports whether there was one.
+func (l *Lexer) skipTemplate(quote rune) bool {
+	for {
+		l.readChar()
+		switch {
+		case l.ch == 0:
+			return false
+		case l.ch == quote:
+			return true
+		case l.ch == '\\':
+			l.readChar()
+		case l.ch == '$' && l.peekChar() == '{':
+			l.readChar()
+			if _, ok := l.skipInterpolation(); !ok {
+				return false
+			}
+		}
+	}
+}
+
+// skipInterpolation moves the lexer from the '{' of a ${ to the '}' that
+// closes it, stepping over nested brackets and strings. It returns the
+// offset of the ':' that starts a format spec, or -1 if there is none, and
+// whether the interpolation was closed.
+func (l *Lexer) skipInterpolation() (colon int, ok bool) {
+	colon = -1
+	depth := 0
+	for {
+		l.readChar()
+		switch l.ch {
+		case 0:
+			return colon, false
+		case '(', '[', '{':
+			depth++
+		case ')', ']':
+			depth--
+		case '}':
+			if depth == 0 {
+				return colon, true
+			}
+			depth--
+		case '"', '`':
+			if !l.skipTemplate(l.ch) {
+				return colon, false
+			}
+		case '\'':
+			for l.readChar(); l.ch != '\''; l.readChar() {
+				if l.ch == 0 {
+					return colon, false
+				}
+				if l.ch == '\\' {
+					l.readChar()
+				}
+			}
+		case ':':
+			if l.peekChar() == ':' {
+				l.readChar() // ::println
+			} else if depth == 0 && colon < 0 {
+				colon = l.position
+			}
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package linter
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/evaluator/builtins"
+	"github.com/vintlang/vintlang/internal/module"
+	"github.com/vintlang/vintlang/internal/utils"
+)
+
+func (l *linter) expr(expr ast.Expression) {
+	if isNil(expr) {
+		return
+	}
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		l.use(e.Value)
+	case *ast.Assign:
+		l.expr(e.Value)
+		l.assign(e.Name.Value)
+	case *ast.AssignEqual:
+		// x += 1 reads x as well
+		l.expr(e.Value)
+		l.use(e.Left.Value)
+	case *ast.PostfixExpression:
+		// i++ names the variable by its token
+		l.use(e.Token.Literal)
+	case *ast.PropertyExpression:
+		l.expr(e.Object)
+		if _, ok := e.Property.(*ast.Identifier); !ok {
+			l.expr(e.Property)
+		}
+	case *ast.MethodExpression:
+		l.moduleCall(e)
+		l.expr(e.Object)
+		l.exprs(e.Arguments)
+		for _, name := range utils.SortedKeys(e.Defaults) {
+			l.expr(e.Defaults[name])
+		}
+	case *ast.CallExpression:
+		l.builtinCall(e)
+		l.expr(e.Function)
+		l.exprs(e.Arguments)
+	case *ast.FunctionLiteral:
+		l.function(e.Parameters, e.Defaults, e.Body)
+	case *ast.AsyncFunctionLiteral:
+		l.function(e.Parameters, e.Defaults, e.Body)
+	case *ast.TypedFunctionLiteral:
+		params := make([]*ast.Identifier, len(e.Parameters))
+		defaults := map[string]ast.Expression{}
+		for i, p := range e.Parameters {
+			params[i] = p.Identifier
+			if p.Default != nil {
+				defaults[p.Identifier.Value] = p.Default
+			}
+		}
+		l.function(params, defaults, e.Body)
+	case *ast.IfExpression:
+		l.condition(e.Condition, false)
+		l.expr(e.Condition)
+		l.body(e.Consequence)
+		l.body(e.Alternative)
+	case *ast.WhileExpression:
+		l.condition(e.Condition, true)
+		l.expr(e.Condition)
+		l.body(e.Consequence)
+	case *ast.ForIn:
+		l.expr(e.Iterable)
+		l.push()
+		if e.Key != "" {
+			l.declare(e.Key, e.Token, kindLoop)
+		}
+		if e.Pattern != nil {
+			l.pattern(e.Pattern, kindLoop)
+		} else {
+			l.declare(e.Value, e.Token, kindLoop)
+		}
+		l.body(e.Block)
+		l.pop()
+	case *ast.SwitchExpression:
+		l.expr(e.Value)
+		for _, c := range e.Choices {
+			if c.Variable != nil {
+				l.push()
+				l.declareIdent(c.Variable, kindParameter)
+			}
+			l.exprs(c.Expr)
+			l.expr(c.Guard)
+			l.body(c.Block)
+			if c.Variable != nil {
+				l.pop()
+			}
+		}
+	case *ast.MatchExpression:
+		l.expr(e.Value)
+		for _, c := range e.Cases {
+			l.push()
+			l.pattern(c.Pattern, kindParameter)
+			l.expr(c.Guard)
+			l.body(c.Block)
+			l.pop()
+		}
+	case *ast.DictLiteral:
+		for _, key := range e.OrderedKeys() {
+			l.expr(key)
+			l.expr(e.Pairs[key])
+		}
+	case *ast.Import:
+		for _, ident := range e.Identifiers {
+			l.declareIdent(ident, kindImport)
+		}
+	case *ast.Package:
+		l.declareIdent(e.Name, kindPackage)
+		l.push()
+		l.body(e.Block)
+		l.pop()
+	case ast.Statement:
+		// Nodes that are both, such as repeat and error declarations
+		l.statement(e)
+	default:
+		l.children(expr)
+	}
+}
+
+func (l *linter) exprs(exprs []ast.Expression) {
+	for _, e := range exprs {
+		l.expr(e)
+	}
+}
+
+// condition reports an if or while condition that is made only of
+// literals, and so always takes the same branch. `while (true)` is left
+// alone, as it is how a loop that ends with break is written.
+func (l *linter) condition(cond ast.Expression, loop bool) {
+	value, ok := fold(cond)
+	if !ok {
+		return
+	}
+	if b, isBool := cond.(*ast.Boolean); loop && isBool && b.Value {
+		return
+	}
+	// An infix expression's token is its operator; report where it starts
+	start := cond
+	for infix, ok := start.(*ast.InfixExpression); ok; infix, ok = start.(*ast.InfixExpression) {
+		start = infix.Left
+	}
+	if tok, ok := tokenOf(start); ok {
+		l.report(ConstantCondition, tok, "condition is always %t", truthy(value))
+	}
+}
+
+// compound is the value of an array or dict literal, which fold only
+// knows to be truthy.
+type compound struct{}
+
+// fold works out the value of an expression made only of literals, as the
+// evaluator would: nil for null, or a bool, float64 (for numbers of either
+// kind), string or compound. It reports false for anything else, and for
+// operations the evaluator would fail on.
+func fold(expr ast.Expression) (any, bool) {
+	switch e := expr.(type) {
+	case *ast.Boolean:
+		return e.Value, true
+	case *ast.Null:
+		return nil, true
+	case *ast.IntegerLiteral:
+		return float64(e.Value), true
+	case *ast.FloatLiteral:
+		return e.Value, true
+	case *ast.StringLiteral:
+		return e.Value, true
+	case *ast.ArrayLiteral, *ast.DictLiteral:
+		return compound{}, true
+	case *ast.PrefixExpression:
+		right, ok := fold(e.Right)
+		if !ok {
+			return nil, false
+		}
+		switch e.Operator {
+		case "!":
+			return !truthy(right), true
+		case "-":
+			n, ok := right.(float64)
+			return -n, ok
+		}
+	case *ast.InfixExpression:
+		left, ok := fold(e.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := fold(e.Right)
+		if !ok {
+			return nil, false
+		}
+		return foldInfix(e.Operator, left, right)
+	}
+	return nil, false
+}
+
+func foldInfix(operator string, left, right any) (any, bool) {
+	if operator == "??" {
+		if left == nil {
+			return right, true
+		}
+		return left, true
+	}
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch operator {
+			case "+":
+				return l + r, true
+			case "-":
+				return l - r, true
+			case "*":
+				return l * r, true
+			case "<":
+				return l < r, true
+			case "<=":
+				return l <= r, true
+			case ">":
+				return l > r, true
+			case ">=":
+				return l >= r, true
+			case "==":
+				return l == r, true
+			case "!=":
+				return l != r, true
+			}
+			return nil, false
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			switch operator {
+			case "+":
+				return l + r, true
+			case "==":
+				return l == r, true
+			case "!=":
+				return l != r, true
+			}
+			return nil, false
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			switch operator {
+			case "&&":
+				return l && r, true
+			case "||":
+				return l || r, true
+			case "==":
+				return l == r, true
+			case "!=":
+				return l != r, true
+			}
+			return nil, false
+		}
+	}
+	// Values of different types, null and array or dict literals are
+	// compared by identity: only null is ever the same as itself
+	switch operator {
+	case "==":
+		return left == nil && right == nil, true
+	case "!=":
+		return left != nil || right != nil, true
+	}
+	return nil, false
+}
+
+// truthy reports whether a folded value counts as true. Only null and
+// false do not.
+func truthy(value any) bool {
+	b, isBool := value.(bool)
+	return value != nil && (!isBool || b)
+}
+
+// moduleCall reports a call to a function that a builtin module does not
+// have, such as math.sqr(2) after import math.
+func (l *linter) moduleCall(e *ast.MethodExpression) {
+	obj, ok := e.Object.(*ast.Identifier)
+	if !ok {
+		return
+	}
+	method, ok := e.Method.(*ast.Identifier)
+	if !ok {
+		return
+	}
+	if b := l.scope.lookup(obj.Value); b == nil || b.kind != kindImport {
+		return
+	}
+	mod, ok := module.Mapper[obj.Value]
+	if !ok {
+		// A module written in Vint
+		return
+	}
+	if _, ok := mod.Functions[method.Value]; !ok {
+		l.report(UnknownModuleFunction, method.Token, "module '%s' has no function '%s'", obj.Value, method.Value)
+	}
+}
+
+// builtinCall reports a call to a builtin with a number of arguments it
+// does not accept. Calls that spread an array or pass arguments by name
+// are not counted.
+func (l *linter) builtinCall(e *ast.CallExpression) {
+	ident, ok := e.Function.(*ast.Identifier)
+	if !ok || l.scope.lookup(ident.Value) != nil {
+		return
+	}
+	arity, ok := builtins.Arities[ident.Value]
+	if !ok {
+		return
+	}
+	for _, arg := range e.Arguments {
+		switch arg.(type) {
+		case *ast.SpreadExpression, *ast.Assign:
+			return
+		}
+	}
+	if n := len(e.Arguments); !arity.Accepts(n) {
+		l.report(BuiltinArity, ident.Token, "%s() takes %s, got %d", ident.Value, describe(arity), n)
+	}
+}
+
+func describe(a builtins.Arity) string {
+	switch {
+	case a.Max < 0:
+		return "at least " + arguments(a.Min)
+	case a.Min == a.Max:
+		return arguments(a.Min)
+	default:
+		return fmt.Sprintf("%d to %d arguments", a.Min, a.Max)
+	}
+}
+
+func arguments(n int) string {
+	switch n {
+	case 0:
+		return "no arguments"
+	case 1:
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+// children walks the nodes directly below node. The syntax tree has many
+// node types that declare nothing, so they are walked through reflection
+// rather than listed.
+func (l *linter) children(node ast.Node) {
+	if isNil(node) {
+		return
+	}
+	v := reflect.ValueOf(node)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		l.fields(v)
+	}
+}
+
+func (l *linter) fields(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).IsExported() {
+			l.value(v.Field(i))
+		}
+	}
+}
+
+func (l *linter) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if node, ok := v.Interface().(ast.Node); ok {
+			switch n := node.(type) {
+			case ast.Type:
+				// Types name no variables
+			case ast.Statement:
+				l.statement(n)
+			case ast.Expression:
+				l.expr(n)
+			default:
+				l.children(n)
+			}
+			return
+		}
+		if v.Kind() == reflect.Interface {
+			l.value(v.Elem())
+		} else if v.Elem().Kind() == reflect.Struct {
+			l.fields(v.Elem())
+		}
+	case reflect.Struct:
+		l.fields(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			l.value(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			l.value(iter.Key())
+			l.value(iter.Value())
+		}
+	}
+}
+
+func isNil(node ast.Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
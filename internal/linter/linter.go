@@ -0,0 +1,469 @@
+// Package linter implements `vint lint`, which finds code that parses but
+// is probably a mistake. Every finding belongs to one of these rules:
+//
+//   - unused-variable: a variable in a function that is never read
+//   - unused-import: an imported module that is never used
+//   - shadowed-name: a declaration that hides an outer one or a builtin
+//   - unreachable-code: statements after return, throw, break or continue
+//   - constant-condition: an if or while whose condition is a literal
+//   - unknown-module-function: a call to a function a module does not have
+//   - builtin-arity: a builtin called with the wrong number of arguments
+//
+// The "lint" key of vintconfig.json sets a rule to "error", "warning" or
+// "off", and comments can suppress findings, see suppress.go.
+package linter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/evaluator/builtins"
+	"github.com/vintlang/vintlang/internal/token"
+	"github.com/vintlang/vintlang/internal/utils"
+)
+
+// The rules.
+const (
+	UnusedVariable        = "unused-variable"
+	UnusedImport          = "unused-import"
+	ShadowedName          = "shadowed-name"
+	UnreachableCode       = "unreachable-code"
+	ConstantCondition     = "constant-condition"
+	UnknownModuleFunction = "unknown-module-function"
+	BuiltinArity          = "builtin-arity"
+)
+
+// The severities a rule can have.
+const (
+	Error   = "error"
+	Warning = "warning"
+	Off     = "off"
+)
+
+// Rules holds the default severity of each rule. The rules that find code
+// which fails when it runs are errors.
+var Rules = map[string]string{
+	UnusedVariable:        Warning,
+	UnusedImport:          Warning,
+	ShadowedName:          Warning,
+	UnreachableCode:       Warning,
+	ConstantCondition:     Warning,
+	UnknownModuleFunction: Error,
+	BuiltinArity:          Error,
+}
+
+// Config holds the severity of the rules that do not have their default.
+type Config map[string]string
+
+// NewConfig checks the "lint" settings of a vintconfig.json.
+func NewConfig(settings map[string]string) (Config, error) {
+	config := Config{}
+	for rule, severity := range settings {
+		if _, ok := Rules[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule '%s'", rule)
+		}
+		switch severity {
+		case Error, Warning, Off:
+			config[rule] = severity
+		default:
+			return nil, fmt.Errorf("lint rule '%s' must be \"error\", \"warning\" or \"off\", not %q", rule, severity)
+		}
+	}
+	return config, nil
+}
+
+func (c Config) severity(rule string) string {
+	if severity, ok := c[rule]; ok {
+		return severity
+	}
+	return Rules[rule]
+}
+
+// Finding is something a rule found at a position in a file.
+type Finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s (%s)", f.File, f.Line, f.Column, f.Severity, f.Message, f.Rule)
+}
+
+// Lint returns the findings in program, in source order. source is the
+// text program was parsed from, which is searched for comments that
+// suppress findings.
+func Lint(program *ast.Program, source string, config Config) []Finding {
+	l := &linter{scope: newScope(nil)}
+	l.statements(program.Statements)
+	l.pop()
+
+	ignored := parseSuppressions(source)
+	var findings []Finding
+	for _, f := range l.findings {
+		f.Severity = config.severity(f.Rule)
+		if f.Severity != Off && !ignored.ignores(f) {
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return findings
+}
+
+type linter struct {
+	scope    *scope
+	funcs    int // how many function bodies the walk is in
+	findings []Finding
+}
+
+// What a name was declared as, for messages.
+const (
+	kindVariable  = "variable"
+	kindConstant  = "constant"
+	kindFunction  = "function"
+	kindParameter = "parameter"
+	kindImport    = "import"
+	kindLoop      = "loop variable"
+	kindType      = "type"
+	kindPackage   = "package"
+)
+
+type binding struct {
+	kind     string
+	tok      token.Token
+	report   bool // report it if it is never used
+	used     bool
+	assigned bool
+}
+
+// scope follows the environments of the interpreter: functions, loops and
+// the cases of switch, match, select and try have their own, but the
+// bodies of if and while share the one around them.
+type scope struct {
+	outer *scope
+	names map[string]*binding
+
+	// pending holds the names used in this scope that were not declared
+	// yet. A function may use a name declared after it, so they are
+	// looked up again when the scope ends.
+	pending []string
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{outer: outer, names: map[string]*binding{}}
+}
+
+func (s *scope) lookup(name string) *binding {
+	for ; s != nil; s = s.outer {
+		if b, ok := s.names[name]; ok {
+			return b
+		}
+	}
+	return nil
+}
+
+func (l *linter) push() { l.scope = newScope(l.scope) }
+
+// pop ends the current scope and reports the names in it that were never
+// used.
+func (l *linter) pop() {
+	s := l.scope
+	for _, name := range s.pending {
+		if b, ok := s.names[name]; ok {
+			b.used = true
+		} else if s.outer != nil {
+			s.outer.pending = append(s.outer.pending, name)
+		}
+	}
+	for name, b := range s.names {
+		if !b.report || b.used || strings.HasPrefix(name, "_") {
+			continue
+		}
+		switch {
+		case b.kind == kindImport:
+			l.report(UnusedImport, b.tok, "'%s' is imported but never used", name)
+		case b.assigned:
+			l.report(UnusedVariable, b.tok, "'%s' is assigned but never used", name)
+		default:
+			l.report(UnusedVariable, b.tok, "'%s' is declared but never used", name)
+		}
+	}
+	l.scope = s.outer
+}
+
+func (l *linter) report(rule string, tok token.Token, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		File:    tok.File,
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// declare adds name to the current scope, reporting it if it hides a name
+// from an outer scope or a builtin. Names starting with an underscore are
+// never reported.
+func (l *linter) declare(name string, tok token.Token, kind string) {
+	if name == "" || name == "_" {
+		return
+	}
+	if _, ok := l.scope.names[name]; ok {
+		// Another overload, or an error the interpreter reports
+		return
+	}
+	if !strings.HasPrefix(name, "_") {
+		if outer := l.scope.outer.lookup(name); outer != nil {
+			l.report(ShadowedName, tok, "'%s' shadows the %s declared on line %d", name, outer.kind, outer.tok.Line)
+		} else if _, ok := builtins.GetBuiltin(name); ok {
+			l.report(ShadowedName, tok, "'%s' shadows the builtin %s()", name, name)
+		}
+	}
+	local := l.funcs > 0 && (kind == kindVariable || kind == kindConstant || kind == kindFunction)
+	l.scope.names[name] = &binding{kind: kind, tok: tok, report: local || kind == kindImport}
+}
+
+func (l *linter) declareIdent(ident *ast.Identifier, kind string) {
+	if ident != nil {
+		l.declare(ident.Value, ident.Token, kind)
+	}
+}
+
+func (l *linter) use(name string) {
+	if b := l.scope.lookup(name); b != nil {
+		b.used = true
+		return
+	}
+	l.scope.pending = append(l.scope.pending, name)
+}
+
+func (l *linter) assign(name string) {
+	if b := l.scope.lookup(name); b != nil {
+		b.assigned = true
+	}
+}
+
+// statements walks a list of statements in the current scope, and reports
+// the first statement that follows one the list cannot get past.
+func (l *linter) statements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		l.statement(stmt)
+	}
+	for i, stmt := range stmts {
+		if after := terminator(stmt); after != "" {
+			if i+1 < len(stmts) {
+				if tok, ok := tokenOf(stmts[i+1]); ok {
+					l.report(UnreachableCode, tok, "unreachable code after %s", after)
+				}
+			}
+			return
+		}
+	}
+}
+
+// terminator returns the keyword of a statement after which the rest of a
+// block never runs, or "". Inside blocks some of them are wrapped in an
+// expression statement.
+func terminator(node ast.Node) string {
+	switch s := node.(type) {
+	case *ast.ReturnStatement:
+		return "return"
+	case *ast.ThrowStatement:
+		return "throw"
+	case *ast.Break:
+		return "break"
+	case *ast.Continue:
+		return "continue"
+	case *ast.ExpressionStatement:
+		return terminator(s.Expression)
+	}
+	return ""
+}
+
+func (l *linter) block(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	l.push()
+	l.statements(block.Statements)
+	l.pop()
+}
+
+// body walks block in the current scope.
+func (l *linter) body(block *ast.BlockStatement) {
+	if block != nil {
+		l.statements(block.Statements)
+	}
+}
+
+func (l *linter) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		l.expr(s.Value)
+		l.declareIdent(s.Name, kindVariable)
+	case *ast.TypedLetStatement:
+		l.expr(s.Value)
+		l.declareIdent(s.Name, kindVariable)
+	case *ast.ConstStatement:
+		l.expr(s.Value)
+		l.declareIdent(s.Name, kindConstant)
+	case *ast.DestructureStatement:
+		l.expr(s.Value)
+		l.pattern(s.Pattern, kindVariable)
+	case *ast.ExpressionStatement:
+		// func name() {} at statement level declares name. It is declared
+		// first so that the function can call itself.
+		switch fn := s.Expression.(type) {
+		case *ast.FunctionLiteral:
+			l.declare(fn.Name, fn.Token, kindFunction)
+		case *ast.TypedFunctionLiteral:
+			l.declare(fn.Name, fn.Token, kindFunction)
+		case *ast.AsyncFunctionLiteral:
+			l.declare(fn.Name, fn.Token, kindFunction)
+		}
+		l.expr(s.Expression)
+	case *ast.BlockStatement:
+		l.block(s)
+	case *ast.StructStatement:
+		l.declareIdent(s.Name, kindType)
+		for _, f := range s.Fields {
+			l.expr(f.Default)
+		}
+		for _, m := range s.Methods {
+			l.function(m.Parameters, m.Defaults, m.Body)
+		}
+	case *ast.InterfaceStatement:
+		l.declareIdent(s.Name, kindType)
+	case *ast.TypeAliasStatement:
+		l.declareIdent(s.Name, kindType)
+	case *ast.EnumStatement:
+		for _, name := range utils.SortedKeys(s.Values) {
+			l.expr(s.Values[name])
+		}
+		l.declareIdent(s.Name, kindType)
+	case *ast.ErrorDeclaration:
+		l.declareIdent(s.Name, kindType)
+	case *ast.PackageBlock:
+		l.push()
+		l.statements(s.Statements)
+		l.pop()
+	case *ast.TestStatement:
+		l.funcs++
+		l.block(s.Body)
+		l.funcs--
+	case *ast.RepeatStatement:
+		l.expr(s.Count)
+		l.push()
+		if s.VarName != "" {
+			l.declare(s.VarName, s.Token, kindLoop)
+		} else {
+			l.scope.names["i"] = &binding{kind: kindLoop, tok: s.Token}
+		}
+		l.body(s.Block)
+		l.pop()
+	case *ast.TryStatement:
+		l.block(s.Block)
+		for _, clause := range s.CatchClauses {
+			if clause.ErrorType != nil {
+				l.use(clause.ErrorType.Value)
+			}
+			l.push()
+			l.declareIdent(clause.Param, kindParameter)
+			l.body(clause.Block)
+			l.pop()
+		}
+		l.block(s.Finally)
+	case *ast.SelectStatement:
+		for _, sc := range s.Cases {
+			l.expr(sc.Channel)
+			l.expr(sc.Send)
+			l.expr(sc.Timeout)
+			l.push()
+			l.declareIdent(sc.Value, kindParameter)
+			l.declareIdent(sc.Ok, kindParameter)
+			l.body(sc.Block)
+			l.pop()
+		}
+	default:
+		l.children(stmt)
+	}
+}
+
+// function walks a function literal or method. Defaults are evaluated
+// where the function is defined, and the body in a scope of its own.
+func (l *linter) function(params []*ast.Identifier, defaults map[string]ast.Expression, body *ast.BlockStatement) {
+	for _, p := range params {
+		l.expr(defaults[p.Value])
+	}
+	l.push()
+	l.funcs++
+	for _, p := range params {
+		l.declareIdent(p, kindParameter)
+	}
+	l.body(body)
+	l.funcs--
+	l.pop()
+}
+
+// pattern declares the names a destructuring or match pattern binds. The
+// parts of a match pattern that are values to compare with are walked
+// like any expression.
+func (l *linter) pattern(pattern ast.Expression, kind string) {
+	switch p := pattern.(type) {
+	case *ast.Identifier:
+		l.declareIdent(p, kind)
+	case *ast.Assign:
+		l.expr(p.Value)
+		l.declareIdent(p.Name, kind)
+	case *ast.TypePattern:
+		l.declareIdent(p.Name, kind)
+	case *ast.ArrayPattern:
+		for _, e := range p.Elements {
+			l.pattern(e, kind)
+		}
+		l.declareIdent(p.Rest, kind)
+	case *ast.DictPattern:
+		for _, v := range p.Values {
+			l.pattern(v, kind)
+		}
+		l.declareIdent(p.Rest, kind)
+	case *ast.DictLiteral:
+		for _, key := range p.OrderedKeys() {
+			l.expr(key)
+			if ident, ok := p.Pairs[key].(*ast.Identifier); ok {
+				l.declareIdent(ident, kind)
+			} else {
+				l.expr(p.Pairs[key])
+			}
+		}
+	default:
+		l.expr(pattern)
+	}
+}
+
+// tokenOf returns the Token a node starts with, if it has one with a
+// position.
+func tokenOf(node ast.Node) (token.Token, bool) {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return token.Token{}, false
+	}
+	field := v.Elem().FieldByName("Token")
+	if !field.IsValid() {
+		return token.Token{}, false
+	}
+	tok, ok := field.Interface().(token.Token)
+	if !ok || tok.Line == 0 {
+		return token.Token{}, false
+	}
+	return tok, true
+}
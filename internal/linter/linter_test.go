@@ -0,0 +1,240 @@
+package linter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vintlang/vintlang/internal/evaluator/builtins"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+)
+
+func lint(t *testing.T, src string, config Config) []string {
+	t.Helper()
+	p := parser.New(lexer.NewWithFilename(src, "test.vint"))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("parse errors: %v", errs)
+	}
+	var got []string
+	for _, f := range Lint(program, src, config) {
+		got = append(got, f.String())
+	}
+	return got
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			"unused variables",
+			"let top = 1\nlet f = func(unusedParam) {\n    let a = 1\n    let b = 2\n    b = 3\n    let _c = 4\n    let d = 5\n    return d\n}\nf(1)\n",
+			[]string{
+				"test.vint:3:9: warning: 'a' is declared but never used (unused-variable)",
+				"test.vint:4:9: warning: 'b' is assigned but never used (unused-variable)",
+			},
+		},
+		{
+			"used later or from a closure",
+			"let f = func() {\n    let first = func() { return second() }\n    let second = func() { return 1 }\n    let n = 0\n    let inc = func() { n += 1 }\n    inc()\n    return first()\n}\n",
+			nil,
+		},
+		{
+			"unused imports",
+			"import math, time, uuid\nlet f = func() { return time.now() }\nprint(uuid.generate())\n",
+			[]string{
+				"test.vint:1:8: warning: 'math' is imported but never used (unused-import)",
+			},
+		},
+		{
+			"shadowed names",
+			"let total = 0\nlet f = func(total) {\n    let keys = []\n    for i in [1] {\n        print(func(i) { return i })\n        let _total = 3\n    }\n    return keys\n}\n",
+			[]string{
+				"test.vint:2:14: warning: 'total' shadows the variable declared on line 1 (shadowed-name)",
+				"test.vint:3:9: warning: 'keys' shadows the builtin keys() (shadowed-name)",
+				"test.vint:5:20: warning: 'i' shadows the loop variable declared on line 4 (shadowed-name)",
+			},
+		},
+		{
+			"unreachable code",
+			"let f = func(x) {\n    if (x) {\n        throw \"bad\"\n        print(1)\n    }\n    for y in x {\n        continue\n        print(y)\n    }\n    return 1\n    print(2)\n    print(3)\n}\n",
+			[]string{
+				"test.vint:4:9: warning: unreachable code after throw (unreachable-code)",
+				"test.vint:8:9: warning: unreachable code after continue (unreachable-code)",
+				"test.vint:11:5: warning: unreachable code after return (unreachable-code)",
+			},
+		},
+		{
+			"constant conditions",
+			"if (true) { print(1) }\nif (!0) { print(2) }\nwhile (null) { print(3) }\nwhile (true) { break }\nlet x = 1\nif (x > 0) { print(x) }\n",
+			[]string{
+				"test.vint:1:5: warning: condition is always true (constant-condition)",
+				"test.vint:2:5: warning: condition is always false (constant-condition)",
+				"test.vint:3:8: warning: condition is always false (constant-condition)",
+			},
+		},
+		{
+			"constant operators",
+			"if (true == true) { print(1) }\nif (1 + 1 == 2) { print(2) }\nwhile (\"a\" != \"a\") { print(3) }\nif (null ?? false) { print(4) }\nif (!(2 > 1 && 3 < 1)) { print(5) }\nlet x = 1\nif (x == 1) { print(x) }\nif (1 && 2) { print(6) }\nif ([1] == [1]) { print(7) }\n",
+			[]string{
+				"test.vint:1:5: warning: condition is always true (constant-condition)",
+				"test.vint:2:5: warning: condition is always true (constant-condition)",
+				"test.vint:3:8: warning: condition is always false (constant-condition)",
+				"test.vint:4:5: warning: condition is always false (constant-condition)",
+				"test.vint:5:5: warning: condition is always true (constant-condition)",
+				"test.vint:9:5: warning: condition is always false (constant-condition)",
+			},
+		},
+		{
+			"module functions",
+			"import math, shapes\nmath.sqrt(4)\nmath.sqr(4)\nshapes.area(1)\nlet f = func(math) { return math.anything() }\n",
+			[]string{
+				"test.vint:3:6: error: module 'math' has no function 'sqr' (unknown-module-function)",
+				"test.vint:5:14: warning: 'math' shadows the import declared on line 1 (shadowed-name)",
+			},
+		},
+		{
+			"builtin arity",
+			"len([1], [2])\nrange()\nappend([])\nprint()\nlet xs = [[1]]\nlen(...xs)\nargs(1)\nlet f = func(len) { return len(1, 2) }\n",
+			[]string{
+				"test.vint:1:1: error: len() takes 1 argument, got 2 (builtin-arity)",
+				"test.vint:2:1: error: range() takes 1 to 3 arguments, got 0 (builtin-arity)",
+				"test.vint:3:1: error: append() takes at least 2 arguments, got 1 (builtin-arity)",
+				"test.vint:7:1: error: args() takes no arguments, got 1 (builtin-arity)",
+				"test.vint:8:14: warning: 'len' shadows the builtin len() (shadowed-name)",
+			},
+		},
+		{
+			"scopes of match, catch and packages",
+			"package shapes {\n    let area = func(w, h) { return w * h }\n}\nlet f = func(v) {\n    let r = match v {\n        {\"n\": n} => n\n        _ => 0\n    }\n    try { throw \"x\" } catch (e) { print(e) }\n    return r\n}\n",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lint(t, tt.input, nil)
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("got:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestSuppressions(t *testing.T) {
+	src := `// vint:ignore-file constant-condition
+let f = func() {
+    let a = 1 // vint:ignore
+    // vint:ignore unused-variable -- kept for the old API
+    let b = 2
+    /* vint:ignore shadowed-name */
+    let c = 3
+    if (true) { return len(1, 2) } // vint:ignore unused-variable
+}
+`
+	got := lint(t, src, nil)
+	expected := []string{
+		"test.vint:7:9: warning: 'c' is declared but never used (unused-variable)",
+		"test.vint:8:24: error: len() takes 1 argument, got 2 (builtin-arity)",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestConfig(t *testing.T) {
+	config, err := NewConfig(map[string]string{ShadowedName: Off, UnusedVariable: Error})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := lint(t, "let f = func(len) {\n    let a = 1\n}\n", config)
+	expected := "test.vint:2:9: error: 'a' is declared but never used (unused-variable)"
+	if strings.Join(got, "\n") != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", strings.Join(got, "\n"), expected)
+	}
+
+	for _, settings := range []map[string]string{{"no-such-rule": Off}, {UnusedImport: "fatal"}} {
+		if _, err := NewConfig(settings); err == nil {
+			t.Errorf("NewConfig(%v) gave no error", settings)
+		}
+	}
+}
+
+func TestArities(t *testing.T) {
+	for name := range builtins.Arities {
+		if _, ok := builtins.GetBuiltin(name); !ok {
+			t.Errorf("%s has an arity but is not a builtin", name)
+		}
+	}
+	for name := range builtins.GetAllBuiltins() {
+		if _, ok := builtins.Arities[name]; !ok {
+			t.Errorf("builtin %s has no arity", name)
+		}
+	}
+}
+
+func TestLintCommand(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"vintconfig.json": `{"name": "app", "lint": {"unused-import": "error"}}`,
+		"src/main.vint":   "import time\nlet x = len(1, 2)\n",
+		"src/clean.vint":  "print(1)\n",
+		"broken/bad.vint": "let = 1\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if code := Main([]string{"--format", "json", filepath.Join(dir, "src")}, &out); code != 1 {
+		t.Errorf("exit code %d", code)
+	}
+	var findings []Finding
+	if err := json.Unmarshal(out.Bytes(), &findings); err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	if len(findings) != 2 {
+		t.Fatalf("findings: %+v", findings)
+	}
+	if f := findings[0]; f.Line != 1 || f.Column != 8 || f.Rule != UnusedImport || f.Severity != Error {
+		t.Errorf("first finding: %+v", f)
+	}
+	if f := findings[1]; f.Line != 2 || f.Column != 9 || f.Rule != BuiltinArity {
+		t.Errorf("second finding: %+v", f)
+	}
+
+	out.Reset()
+	if code := Main([]string{filepath.Join(dir, "src", "clean.vint")}, &out); code != 0 {
+		t.Errorf("exit code %d\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "No problems in 1 file(s)") {
+		t.Errorf("output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := Main([]string{"--format=json", filepath.Join(dir, "broken")}, &out); code != 1 {
+		t.Errorf("exit code %d", code)
+	}
+	if !strings.Contains(out.String(), `"rule": "syntax-error"`) || !strings.Contains(out.String(), `"line": 1`) {
+		t.Errorf("output:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{{"--fix"}, {"--format", "xml"}, {"--format"}} {
+		if code := Main(args, &out); code != 2 {
+			t.Errorf("Main(%v) exit code %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/formatter"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/toolkit"
+)
+
+// SyntaxError is the rule of the findings for files that do not parse,
+// which cannot be turned off.
+const SyntaxError = "syntax-error"
+
+// Main runs `vint lint` and returns the exit code: 0 if no finding is an
+// error, 1 if one is or a file does not parse, and 2 for bad usage.
+// Directories are searched for .vint files.
+//
+//	vint lint [--format text|json] [files or directories]
+//
+// Each file is linted with the rules of the vintconfig.json in its
+// directory or the nearest one above it.
+func Main(args []string, out io.Writer) int {
+	format := "text"
+	var paths []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-format":
+			if i+1 == len(args) {
+				fmt.Fprintln(out, styles.ErrorStyle.Render("Error: --format needs a value"))
+				return 2
+			}
+			i++
+			format = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown flag %s", arg)))
+			return 2
+		default:
+			paths = append(paths, arg)
+		}
+	}
+	if format != "text" && format != "json" {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown format %s, want text or json", format)))
+		return 2
+	}
+
+	files, err := formatter.Discover(paths)
+	if err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+
+	configs := map[string]Config{} // by directory
+	var findings []Finding
+	sources := map[string][]string{}
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		config, ok := configs[dir]
+		if !ok {
+			if config, err = loadConfig(dir); err != nil {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				return 1
+			}
+			configs[dir] = config
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return 1
+		}
+		sources[file] = strings.Split(string(src), "\n")
+		p := parser.New(lexer.NewWithFilename(string(src), file))
+		program := p.ParseProgram()
+		if errs := p.Errors(); len(errs) > 0 {
+			for _, msg := range errs {
+				findings = append(findings, syntaxFinding(file, msg))
+			}
+			continue
+		}
+		findings = append(findings, Lint(program, string(src), config)...)
+	}
+
+	errors, warnings := 0, 0
+	for _, f := range findings {
+		if f.Severity == Error {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	if format == "json" {
+		if findings == nil {
+			findings = []Finding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return 1
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		for _, f := range findings {
+			style := styles.WarningStyle
+			if f.Severity == Error {
+				style = styles.ErrorStyle
+			}
+			fmt.Fprintln(out, style.Render(f.context(sources[f.File])))
+		}
+		switch {
+		case len(findings) > 0:
+			summary := fmt.Sprintf("%s (%s, %s)", plural(len(findings), "problem"), plural(errors, "error"), plural(warnings, "warning"))
+			if errors > 0 {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(summary))
+			} else {
+				fmt.Fprintln(out, styles.WarningStyle.Render(summary))
+			}
+		default:
+			fmt.Fprintln(out, styles.SuccessStyle.Render(fmt.Sprintf("No problems in %d file(s)", len(files))))
+		}
+	}
+
+	if errors > 0 {
+		return 1
+	}
+	return 0
+}
+
+// loadConfig returns the lint settings for the files in dir.
+func loadConfig(dir string) (Config, error) {
+	cfg, path, err := toolkit.FindConfig(dir)
+	if err != nil || cfg == nil {
+		return Config{}, err
+	}
+	config, err := NewConfig(cfg.Lint)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return config, nil
+}
+
+var (
+	errorPosition = regexp.MustCompile(`^(\d+):(?:(\d+):)? `)
+	errorLine     = regexp.MustCompile(`^Line (\d+): `)
+)
+
+// syntaxFinding turns a parse error, which starts with its position, into
+// a finding.
+func syntaxFinding(file, err string) Finding {
+	f := Finding{File: file, Line: 1, Column: 1, Rule: SyntaxError, Severity: Error}
+	msg := strings.TrimPrefix(strings.SplitN(err, "\n", 2)[0], file+":")
+	if m := errorPosition.FindStringSubmatch(msg); m != nil {
+		f.Line, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			f.Column, _ = strconv.Atoi(m[2])
+		}
+		msg = msg[len(m[0]):]
+	} else if m := errorLine.FindStringSubmatch(msg); m != nil {
+		f.Line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+	f.Message = msg
+	return f
+}
+
+// context formats f like a parse error, with the line of source it is on
+// and a caret under its column.
+func (f Finding) context(lines []string) string {
+	if f.Line < 1 || f.Line > len(lines) || f.Column < 1 {
+		return f.String()
+	}
+	src := strings.TrimRight(lines[f.Line-1], "\r")
+	return fmt.Sprintf("%s\n    %s\n    %s^", f, src, strings.Repeat(" ", f.Column-1))
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
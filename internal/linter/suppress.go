@@ -0,0 +1,94 @@
+package linter
+
+import (
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// suppressions holds the rules that comments turn off, by line. Line 0 is
+// the whole file, and a nil list stands for every rule.
+//
+// A `// vint:ignore` comment after code turns rules off for its own line,
+// and one on a line of its own for the next line with code. A
+// `// vint:ignore-file` comment turns them off for the whole file. Either
+// can name the rules, separated by commas, and end with a reason after
+// "--":
+//
+//	let _ = risky() // vint:ignore
+//	// vint:ignore shadowed-name, unused-variable -- kept for the old API
+//	let len = 0
+type suppressions map[int][]string
+
+func parseSuppressions(source string) suppressions {
+	s := suppressions{}
+	l := lexer.NewWithComments(source, "")
+	last := 0              // line of the last token that was not a comment
+	var waiting [][]string // comments waiting for the next line with code
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Type != token.COMMENT {
+			if tok.Line != last {
+				for _, rules := range waiting {
+					s.add(tok.Line, rules)
+				}
+				waiting = nil
+			}
+			last = tok.Line
+			continue
+		}
+		directive, rules, ok := parseDirective(tok.Literal)
+		switch {
+		case !ok:
+		case directive == "vint:ignore-file":
+			s.add(0, rules)
+		case tok.Line == last:
+			s.add(tok.Line, rules)
+		default:
+			waiting = append(waiting, rules)
+		}
+	}
+	return s
+}
+
+// parseDirective reads the directive and rules of a suppression comment,
+// and reports whether it is one.
+func parseDirective(comment string) (directive string, rules []string, ok bool) {
+	text := strings.TrimPrefix(comment, "//")
+	if strings.HasPrefix(comment, "/*") {
+		text = strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+	}
+	text, _, _ = strings.Cut(text, "--")
+	fields := strings.Fields(strings.ReplaceAll(text, ",", " "))
+	if len(fields) == 0 || fields[0] != "vint:ignore" && fields[0] != "vint:ignore-file" {
+		return "", nil, false
+	}
+	return fields[0], fields[1:], true
+}
+
+func (s suppressions) add(line int, rules []string) {
+	existing, ok := s[line]
+	if len(rules) == 0 || ok && existing == nil {
+		s[line] = nil
+		return
+	}
+	s[line] = append(existing, rules...)
+}
+
+func (s suppressions) ignores(f Finding) bool {
+	for _, line := range []int{0, f.Line} {
+		rules, ok := s[line]
+		if !ok {
+			continue
+		}
+		if rules == nil {
+			return true
+		}
+		for _, rule := range rules {
+			if rule == f.Rule {
+				return true
+			}
+		}
+	}
+	return false
+}
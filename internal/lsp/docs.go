@@ -0,0 +1,204 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/docs"
+	"github.com/vintlang/vintlang/internal/evaluator/builtins"
+	"github.com/vintlang/vintlang/internal/module"
+)
+
+// Hover text for builtins and module functions comes from the markdown
+// documentation that ships with vint: builtins.md for builtins and
+// <module>.md for modules.
+
+// builtinDoc returns the hover text for a builtin, or "" if there is no
+// builtin with that name.
+func builtinDoc(name string) string {
+	b, ok := builtins.GetBuiltin(name)
+	if !ok {
+		return ""
+	}
+	if doc := docSection("builtins.md", "`"+name+"(", "`"+name+"`"); doc != "" {
+		return doc
+	}
+	return codeBlock(name+signature(b.ParamTypes, b.ReturnType)) + "Builtin function"
+}
+
+// moduleDoc returns the hover text for an imported module.
+func moduleDoc(name string) string {
+	mod, ok := module.Mapper[name]
+	if !ok {
+		return ""
+	}
+	doc := mod.Doc
+	if doc == "" {
+		doc = docIntro(name + ".md")
+	}
+	return strings.TrimSpace(codeBlock("import "+name) + doc)
+}
+
+// moduleMemberDoc returns the hover text for name in a builtin module, or
+// "" if the module has no such member.
+func moduleMemberDoc(modName, name string) string {
+	mod, ok := module.Mapper[modName]
+	if !ok {
+		return ""
+	}
+	if _, ok := mod.Functions[name]; !ok {
+		if _, ok := mod.Variables[name]; ok {
+			return codeBlock(modName + "." + name)
+		}
+		return ""
+	}
+
+	file := modName + ".md"
+	if doc := docSection(file, "`"+name+"(", "`"+name+"`", "`"+modName+"."+name+"(", "`"+modName+"."+name+"`"); doc != "" {
+		return doc
+	}
+	usage := codeBlock(modName + "." + name + signature(mod.FuncTypes[name], mod.FuncReturns[name]))
+	if row := docTableRow(file, "`"+name+"(", "`"+modName+"."+name+"("); row != "" {
+		return usage + row
+	}
+	if example := docExample(file, modName+"."+name+"("); example != "" {
+		return usage + "Example:\n" + codeBlock(example)
+	}
+	return usage + fmt.Sprintf("Function in the %s module", modName)
+}
+
+// moduleMembers returns the functions, variables and submodules of a
+// builtin module, sorted by name.
+func moduleMembers(modName string) []CompletionItem {
+	mod, ok := module.Mapper[modName]
+	if !ok {
+		return nil
+	}
+	var items []CompletionItem
+	detail := modName + " module"
+	for name := range mod.Functions {
+		items = append(items, CompletionItem{Label: name, Kind: completionFunction, Detail: detail})
+	}
+	for name := range mod.Variables {
+		items = append(items, CompletionItem{Label: name, Kind: completionConstant, Detail: detail})
+	}
+	for name := range mod.Submodules {
+		items = append(items, CompletionItem{Label: name, Kind: completionModule, Detail: detail})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+func signature(params []ast.Type, ret ast.Type) string {
+	if params == nil && ret == nil {
+		return "(...)"
+	}
+	types := make([]string, len(params))
+	for i, t := range params {
+		types[i] = t.String()
+	}
+	sig := "(" + strings.Join(types, ", ") + ")"
+	if ret != nil {
+		sig += ": " + ret.String()
+	}
+	return sig
+}
+
+func codeBlock(code string) string {
+	return "```vint\n" + code + "\n```\n"
+}
+
+func readDoc(file string) []string {
+	content, err := docs.Docs.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(content), "\n")
+}
+
+func isHeading(line string) bool {
+	return strings.HasPrefix(line, "#")
+}
+
+// docSection finds the first heading in file that contains one of the
+// patterns and returns it with the text under it. The heading becomes the
+// signature at the top of the hover.
+func docSection(file string, patterns ...string) string {
+	lines := readDoc(file)
+	for i, line := range lines {
+		if !isHeading(line) || !containsAny(line, patterns) {
+			continue
+		}
+		title := strings.TrimSpace(strings.TrimLeft(line, "#"))
+		if start := strings.Index(title, "`"); start >= 0 {
+			if end := strings.Index(title[start+1:], "`"); end >= 0 {
+				title = title[start+1 : start+1+end]
+			}
+		}
+
+		var body []string
+		for _, next := range lines[i+1:] {
+			if isHeading(next) || strings.TrimSpace(next) == "---" {
+				break
+			}
+			body = append(body, next)
+		}
+		return strings.TrimSpace(codeBlock(title) + strings.TrimSpace(strings.Join(body, "\n")))
+	}
+	return ""
+}
+
+// docTableRow returns the description of a function listed in a markdown
+// table, as in | `fn(x)` | what it does |.
+func docTableRow(file string, patterns ...string) string {
+	for _, line := range readDoc(file) {
+		if !strings.HasPrefix(line, "|") || !containsAny(line, patterns) {
+			continue
+		}
+		cells := strings.Split(strings.Trim(line, "| "), "|")
+		var texts []string
+		for _, cell := range cells[1:] {
+			if cell = strings.TrimSpace(cell); cell != "" {
+				texts = append(texts, cell)
+			}
+		}
+		return strings.Join(texts, " — ")
+	}
+	return ""
+}
+
+// docExample returns the first line of example code that uses pattern.
+func docExample(file, pattern string) string {
+	for _, line := range readDoc(file) {
+		if strings.Contains(line, pattern) && !isHeading(line) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// docIntro returns the first paragraph after the title of file.
+func docIntro(file string) string {
+	var paragraph []string
+	for _, line := range readDoc(file) {
+		if isHeading(line) || strings.TrimSpace(line) == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	return strings.Join(paragraph, "\n")
+}
+
+func containsAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
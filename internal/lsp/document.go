@@ -0,0 +1,496 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// document is a parsed source file: an open editor buffer or a file read
+// from disk to resolve an import.
+type document struct {
+	uri     string
+	path    string
+	lines   [][]rune
+	program *ast.Program
+	errors  []string
+	tokens  []token.Token
+	symbols []*symbol
+	imports map[string]*ast.Identifier // imported name -> where it is imported
+}
+
+// symbol is a declaration found in a document.
+type symbol struct {
+	name     string
+	kind     int // SymbolKind
+	detail   string
+	line     int // 1-based, like token positions
+	column   int
+	children []*symbol
+
+	// assigned is set for names introduced by a plain assignment, which
+	// only declares the name if nothing before it did.
+	assigned bool
+}
+
+func parseDocument(uri, path, text string) *document {
+	d := &document{
+		uri:     uri,
+		path:    path,
+		imports: map[string]*ast.Identifier{},
+	}
+	for _, line := range strings.Split(text, "\n") {
+		d.lines = append(d.lines, []rune(strings.TrimSuffix(line, "\r")))
+	}
+
+	filename := filepath.Base(path)
+	func() {
+		// The parser is written for complete programs; a buffer that is
+		// being edited must not take the server down with it.
+		defer func() {
+			if r := recover(); r != nil {
+				d.program = &ast.Program{}
+				d.errors = append(d.errors, fmt.Sprintf("%s:1: parser failed: %v", filename, r))
+			}
+		}()
+		p := parser.New(lexer.NewWithFilename(text, filename))
+		d.program = p.ParseProgram()
+		d.errors = p.Errors()
+	}()
+
+	l := lexer.NewWithFilename(text, filename)
+	for i := 0; i <= len(text); i++ {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			break
+		}
+		d.tokens = append(d.tokens, tok)
+	}
+
+	d.symbols = d.collect(d.program.Statements)
+	return d
+}
+
+// Parser and lexer errors start with "file:line:col: " or "file:line: ",
+// or with "Line N: " for a few older messages.
+var (
+	errorPosition = regexp.MustCompile(`^(\d+):(?:(\d+):)? `)
+	errorLine     = regexp.MustCompile(`^Line (\d+): `)
+)
+
+func (d *document) diagnostics() []Diagnostic {
+	prefix := filepath.Base(d.path) + ":"
+	diagnostics := []Diagnostic{}
+	for _, err := range d.errors {
+		msg := strings.SplitN(err, "\n", 2)[0]
+		line, col := 1, 0
+		msg = strings.TrimPrefix(msg, prefix)
+		if m := errorPosition.FindStringSubmatch(msg); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			if m[2] != "" {
+				col, _ = strconv.Atoi(m[2])
+			}
+			msg = msg[len(m[0]):]
+		} else if m := errorLine.FindStringSubmatch(msg); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			msg = msg[len(m[0]):]
+		}
+
+		var r Range
+		if col > 0 {
+			r = d.rangeAt(line, col, 1)
+		} else {
+			r = d.rangeAt(line, 1, len(d.line(line)))
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    r,
+			Severity: severityError,
+			Source:   "vint",
+			Message:  msg,
+		})
+	}
+	return diagnostics
+}
+
+func (d *document) line(n int) []rune {
+	if n < 1 || n > len(d.lines) {
+		return nil
+	}
+	return d.lines[n-1]
+}
+
+// rangeAt converts a 1-based line and rune column, as used by the lexer,
+// to an LSP range, which counts UTF-16 code units from 0.
+func (d *document) rangeAt(line, column, length int) Range {
+	text := d.line(line)
+	start := utf16Offset(text, column-1)
+	return Range{
+		Start: Position{Line: line - 1, Character: start},
+		End:   Position{Line: line - 1, Character: start + utf16Offset(text[min(column-1, len(text)):], length)},
+	}
+}
+
+func (d *document) symbolRange(sym *symbol) Range {
+	return d.rangeAt(sym.line, sym.column, len([]rune(sym.name)))
+}
+
+// utf16Offset returns how many UTF-16 code units the first n runes of text
+// take up.
+func utf16Offset(text []rune, n int) int {
+	offset := 0
+	for i := 0; i < n; i++ {
+		if i >= len(text) {
+			offset += n - i
+			break
+		}
+		offset += len(utf16.Encode([]rune{text[i]}))
+	}
+	return offset
+}
+
+// runeColumn converts an LSP position to a 0-based rune column.
+func (d *document) runeColumn(pos Position) int {
+	text := d.line(pos.Line + 1)
+	units := 0
+	for i, r := range text {
+		if units >= pos.Character {
+			return i
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return len(text)
+}
+
+// identAt returns the index of the identifier token under pos, or -1.
+func (d *document) identAt(pos Position) int {
+	line, col := pos.Line+1, d.runeColumn(pos)+1
+	for i, tok := range d.tokens {
+		if tok.Line != line || tok.Type != token.IDENT {
+			continue
+		}
+		if col >= tok.Column && col <= tok.Column+len([]rune(tok.Literal)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// qualifier returns the name before the dot when the token at i is the
+// member in x.member.
+func (d *document) qualifier(i int) string {
+	if i >= 2 && d.tokens[i-1].Type == token.DOT && d.tokens[i-2].Type == token.IDENT {
+		return d.tokens[i-2].Literal
+	}
+	return ""
+}
+
+// collect returns the declarations in stmts. Declarations nested in blocks
+// such as if and while bodies belong to the enclosing level; function and
+// method bodies become children of their symbol.
+func (d *document) collect(stmts []ast.Statement) []*symbol {
+	var symbols []*symbol
+	seen := map[string]bool{}
+	for _, stmt := range stmts {
+		for _, sym := range d.statementSymbols(stmt) {
+			if sym.assigned && seen[sym.name] {
+				continue
+			}
+			seen[sym.name] = true
+			symbols = append(symbols, sym)
+		}
+	}
+	return symbols
+}
+
+func (d *document) statementSymbols(stmt ast.Statement) []*symbol {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		return d.valueSymbol("let", s.Name, s.Value)
+	case *ast.TypedLetStatement:
+		return d.valueSymbol("let", s.Name, s.Value)
+	case *ast.ConstStatement:
+		return d.valueSymbol("const", s.Name, s.Value)
+	case *ast.DestructureStatement:
+		var symbols []*symbol
+		for _, name := range ast.PatternNames(s.Pattern) {
+			symbols = append(symbols, d.valueSymbol(s.Token.Literal, name, nil)...)
+		}
+		return symbols
+	case *ast.StructStatement:
+		if s.Name == nil {
+			return nil
+		}
+		sym := newSymbol(s.Name, symbolStruct, "struct "+s.Name.Value)
+		for _, f := range s.Fields {
+			if f.Name != nil {
+				sym.children = append(sym.children, newSymbol(f.Name, symbolField, f.Name.Value))
+			}
+		}
+		for _, m := range s.Methods {
+			if m.Name == nil {
+				continue
+			}
+			method := newSymbol(m.Name, symbolMethod, fmt.Sprintf("func %s(%s)", m.Name.Value, identifierList(m.Parameters, m.Variadic)))
+			if m.Body != nil {
+				method.children = d.collect(m.Body.Statements)
+			}
+			sym.children = append(sym.children, method)
+		}
+		return []*symbol{sym}
+	case *ast.InterfaceStatement:
+		if s.Name == nil {
+			return nil
+		}
+		sym := newSymbol(s.Name, symbolInterface, "interface "+s.Name.Value)
+		for _, m := range s.Methods {
+			sym.children = append(sym.children, newSymbol(m.Name, symbolMethod, "func "+m.String()))
+		}
+		return []*symbol{sym}
+	case *ast.EnumStatement:
+		if s.Name == nil {
+			return nil
+		}
+		return []*symbol{newSymbol(s.Name, symbolEnum, "enum "+s.Name.Value)}
+	case *ast.ErrorDeclaration:
+		if s.Name == nil {
+			return nil
+		}
+		return []*symbol{newSymbol(s.Name, symbolClass, fmt.Sprintf("error %s(%s)", s.Name.Value, identifierList(s.Parameters, false)))}
+	case *ast.BlockStatement:
+		if s == nil {
+			return nil
+		}
+		return d.collect(s.Statements)
+	case *ast.ExpressionStatement:
+		return d.expressionSymbols(s.Expression)
+	}
+	return nil
+}
+
+func (d *document) expressionSymbols(exp ast.Expression) []*symbol {
+	switch e := exp.(type) {
+	case *ast.Import:
+		for name, ident := range e.Identifiers {
+			d.imports[name] = ident
+		}
+	case *ast.Package:
+		if e.Name == nil {
+			return nil
+		}
+		sym := newSymbol(e.Name, symbolPackage, "package "+e.Name.Value)
+		if e.Block != nil {
+			sym.children = d.collect(e.Block.Statements)
+		}
+		return []*symbol{sym}
+	case *ast.FunctionLiteral:
+		if e.Name != "" {
+			return d.namedFunction(e.Token, e.Name, identifierList(e.Parameters, e.Variadic), e.Body)
+		}
+	case *ast.TypedFunctionLiteral:
+		if e.Name != "" {
+			return d.namedFunction(e.Token, e.Name, typedParameterList(e.Parameters), e.Body)
+		}
+	case *ast.Assign:
+		if e.Name != nil {
+			sym := newSymbol(e.Name, symbolVariable, e.Name.Value)
+			sym.assigned = true
+			return []*symbol{sym}
+		}
+	case *ast.IfExpression:
+		return append(d.statementSymbols(e.Consequence), d.statementSymbols(e.Alternative)...)
+	case *ast.WhileExpression:
+		return d.statementSymbols(e.Consequence)
+	case *ast.ForIn:
+		return d.statementSymbols(e.Block)
+	}
+	return nil
+}
+
+// valueSymbol is the symbol for let and const declarations. A declaration
+// whose value is a function is reported as a function.
+func (d *document) valueSymbol(keyword string, name *ast.Identifier, value ast.Expression) []*symbol {
+	if name == nil {
+		return nil
+	}
+	kind := symbolVariable
+	if keyword == "const" {
+		kind = symbolConstant
+	}
+	sym := newSymbol(name, kind, keyword+" "+name.Value)
+
+	switch fn := value.(type) {
+	case *ast.FunctionLiteral:
+		sym.kind = symbolFunction
+		sym.detail = fmt.Sprintf("%s %s = func(%s)", keyword, name.Value, identifierList(fn.Parameters, fn.Variadic))
+		if fn.Body != nil {
+			sym.children = d.collect(fn.Body.Statements)
+		}
+	case *ast.TypedFunctionLiteral:
+		sym.kind = symbolFunction
+		sym.detail = fmt.Sprintf("%s %s = func(%s)", keyword, name.Value, typedParameterList(fn.Parameters))
+		if fn.ReturnType != nil {
+			sym.detail += ": " + fn.ReturnType.String()
+		}
+		if fn.Body != nil {
+			sym.children = d.collect(fn.Body.Statements)
+		}
+	}
+	return []*symbol{sym}
+}
+
+// namedFunction is the symbol for `func name() {}`. The AST only records
+// where the func keyword is, so the name is taken from the token after it.
+func (d *document) namedFunction(funcTok token.Token, name, params string, body *ast.BlockStatement) []*symbol {
+	line, column := funcTok.Line, funcTok.Column
+	for i, tok := range d.tokens {
+		if tok.Line == funcTok.Line && tok.Column == funcTok.Column && i+1 < len(d.tokens) {
+			line, column = d.tokens[i+1].Line, d.tokens[i+1].Column
+			break
+		}
+	}
+	sym := &symbol{
+		name:   name,
+		kind:   symbolFunction,
+		detail: fmt.Sprintf("func %s(%s)", name, params),
+		line:   line,
+		column: column,
+	}
+	if body != nil {
+		sym.children = d.collect(body.Statements)
+	}
+	return []*symbol{sym}
+}
+
+func newSymbol(name *ast.Identifier, kind int, detail string) *symbol {
+	return &symbol{
+		name:   name.Value,
+		kind:   kind,
+		detail: detail,
+		line:   name.Token.Line,
+		column: name.Token.Column,
+	}
+}
+
+// identifierList joins parameter names; if variadic, the last one is
+// written ...name.
+func identifierList(idents []*ast.Identifier, variadic bool) string {
+	names := make([]string, len(idents))
+	for i, ident := range idents {
+		names[i] = ident.Value
+	}
+	if variadic && len(names) > 0 {
+		names[len(names)-1] = "..." + names[len(names)-1]
+	}
+	return strings.Join(names, ", ")
+}
+
+func typedParameterList(params []*ast.TypedParameter) string {
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// allSymbols returns every symbol in the document, nested ones included.
+func (d *document) allSymbols() []*symbol {
+	var all []*symbol
+	var walk func([]*symbol)
+	walk = func(symbols []*symbol) {
+		for _, sym := range symbols {
+			all = append(all, sym)
+			walk(sym.children)
+		}
+	}
+	walk(d.symbols)
+	return all
+}
+
+// lookup finds the declaration a name on the given line refers to: the
+// closest declaration above it, or failing that the first one below it,
+// since functions may be called before the code that defines them.
+func (d *document) lookup(name string, line int) *symbol {
+	var before, after *symbol
+	for _, sym := range d.allSymbols() {
+		if sym.name != name || sym.kind == symbolField || sym.kind == symbolMethod {
+			continue
+		}
+		if sym.line <= line {
+			if before == nil || sym.line > before.line {
+				before = sym
+			}
+		} else if after == nil || sym.line < after.line {
+			after = sym
+		}
+	}
+	if before != nil {
+		return before
+	}
+	return after
+}
+
+// members returns the symbols that can be reached as name.member: the
+// contents of a package, or the fields and methods of a struct.
+func (sym *symbol) members() []*symbol {
+	if sym.kind == symbolPackage || sym.kind == symbolStruct {
+		return sym.children
+	}
+	return nil
+}
+
+// exports is what another file gets when it imports this one: the public
+// members of its package, or its top-level declarations if it has none.
+func (d *document) exports() []*symbol {
+	symbols := d.symbols
+	for _, sym := range d.symbols {
+		if sym.kind == symbolPackage {
+			symbols = sym.children
+			break
+		}
+	}
+	var public []*symbol
+	for _, sym := range symbols {
+		if !strings.HasPrefix(sym.name, "_") {
+			public = append(public, sym)
+		}
+	}
+	sort.SliceStable(public, func(i, j int) bool { return public[i].name < public[j].name })
+	return public
+}
+
+func findSymbol(symbols []*symbol, name string) *symbol {
+	for _, sym := range symbols {
+		if sym.name == name {
+			return sym
+		}
+	}
+	return nil
+}
+
+func (d *document) documentSymbols(symbols []*symbol) []DocumentSymbol {
+	result := []DocumentSymbol{}
+	for _, sym := range symbols {
+		r := d.symbolRange(sym)
+		ds := DocumentSymbol{
+			Name:           sym.name,
+			Detail:         sym.detail,
+			Kind:           sym.kind,
+			Range:          r,
+			SelectionRange: r,
+		}
+		if len(sym.children) > 0 {
+			ds.Children = d.documentSymbols(sym.children)
+		}
+		result = append(result, ds)
+	}
+	return result
+}
@@ -0,0 +1,417 @@
+// Package lsp implements `vint lsp`, a Language Server Protocol server that
+// talks JSON-RPC over stdin and stdout. It parses documents with the same
+// lexer and parser as the interpreter and provides:
+//
+//   - diagnostics for syntax errors
+//   - hover documentation for builtins, module functions and declarations
+//   - go to definition, including into imported files
+//   - completion of module members after `module.`
+//   - document symbols
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/config"
+	"github.com/vintlang/vintlang/internal/module"
+)
+
+// Server is a language server for one client connection.
+type Server struct {
+	in          *bufio.Reader
+	out         io.Writer
+	root        string
+	docs        map[string]*document // open documents by URI
+	initialized bool
+	shutdown    bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:   bufio.NewReader(in),
+		out:  out,
+		docs: map[string]*document{},
+	}
+}
+
+// Main runs `vint lsp` and returns the exit code. Editors usually start the
+// server with --stdio, which is the only transport and so is accepted and
+// ignored.
+func Main(args []string, in io.Reader, out io.Writer) int {
+	for _, arg := range args {
+		if arg != "--stdio" {
+			fmt.Fprintf(os.Stderr, "vint lsp: unknown argument %q\n", arg)
+			return 2
+		}
+	}
+	return NewServer(in, out).Serve()
+}
+
+// Serve handles messages until the client sends exit or closes the
+// connection. It returns 0 if the client shut the server down properly.
+func (s *Server) Serve() int {
+	for {
+		msg, err := readMessage(s.in)
+		if errors.Is(err, errMalformed) {
+			s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()})
+			continue
+		}
+		if err != nil {
+			return s.exitCode()
+		}
+		if msg.Method == "exit" {
+			return s.exitCode()
+		}
+		s.handle(msg)
+	}
+}
+
+func (s *Server) exitCode() int {
+	if s.shutdown {
+		return 0
+	}
+	return 1
+}
+
+func (s *Server) handle(msg *message) {
+	if msg.ID == nil {
+		s.notification(msg.Method, msg.Params)
+		return
+	}
+
+	var result any
+	var rerr *responseError
+	switch {
+	case msg.Method == "initialize":
+		result, rerr = s.initialize(msg.Params)
+	case !s.initialized:
+		rerr = &responseError{Code: codeServerNotInitialized, Message: "server not initialized"}
+	case s.shutdown:
+		rerr = &responseError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	default:
+		result, rerr = s.request(msg.Method, msg.Params)
+	}
+	s.reply(msg.ID, result, rerr)
+}
+
+func (s *Server) request(method string, params json.RawMessage) (any, *responseError) {
+	switch method {
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/hover":
+		var p textDocumentPositionParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.hover(p), nil
+	case "textDocument/definition":
+		var p textDocumentPositionParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.definition(p), nil
+	case "textDocument/completion":
+		var p textDocumentPositionParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.completion(p), nil
+	case "textDocument/documentSymbol":
+		var p documentSymbolParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		d := s.docs[p.TextDocument.URI]
+		if d == nil {
+			return []DocumentSymbol{}, nil
+		}
+		return d.documentSymbols(d.symbols), nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + method}
+}
+
+func (s *Server) notification(method string, params json.RawMessage) {
+	switch method {
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if json.Unmarshal(params, &p) == nil {
+			s.update(p.TextDocument.URI, p.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		// The server asks for full document sync, so the last change
+		// holds the whole text.
+		var p didChangeParams
+		if json.Unmarshal(params, &p) == nil && len(p.ContentChanges) > 0 {
+			s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+		}
+	case "textDocument/didClose":
+		var p didCloseParams
+		if json.Unmarshal(params, &p) == nil {
+			delete(s.docs, p.TextDocument.URI)
+			s.publishDiagnostics(p.TextDocument.URI, []Diagnostic{})
+		}
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, *responseError) {
+	var p initializeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.root = p.RootPath
+	if p.RootURI != "" {
+		s.root = uriToPath(p.RootURI)
+	}
+	s.initialized = true
+
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync":       1, // full
+			"hoverProvider":          true,
+			"definitionProvider":     true,
+			"documentSymbolProvider": true,
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"."},
+			},
+		},
+		"serverInfo": map[string]any{
+			"name":    "vint",
+			"version": config.VINT_VERSION,
+		},
+	}, nil
+}
+
+func (s *Server) update(uri, text string) {
+	d := parseDocument(uri, uriToPath(uri), text)
+	s.docs[uri] = d
+	s.publishDiagnostics(uri, d.diagnostics())
+}
+
+func (s *Server) publishDiagnostics(uri string, diagnostics []Diagnostic) {
+	s.send(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/publishDiagnostics",
+		"params":  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+func (s *Server) reply(id *json.RawMessage, result any, rerr *responseError) {
+	response := map[string]any{"jsonrpc": "2.0", "id": id}
+	if rerr != nil {
+		response["error"] = rerr
+	} else {
+		response["result"] = result
+	}
+	s.send(response)
+}
+
+func (s *Server) send(v any) {
+	if err := writeMessage(s.out, v); err != nil {
+		fmt.Fprintf(os.Stderr, "vint lsp: %v\n", err)
+	}
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+func (s *Server) hover(p textDocumentPositionParams) any {
+	d := s.docs[p.TextDocument.URI]
+	if d == nil {
+		return nil
+	}
+	i := d.identAt(p.Position)
+	if i < 0 {
+		return nil
+	}
+	tok := d.tokens[i]
+	name, qualifier := tok.Literal, d.qualifier(i)
+
+	var text string
+	if qualifier != "" {
+		if target, sym := s.member(d, qualifier, name, tok.Line); sym != nil {
+			text = codeBlock(sym.detail) + inFile(target, d)
+		} else {
+			text = moduleMemberDoc(qualifier, name)
+		}
+	} else if sym := d.lookup(name, tok.Line); sym != nil {
+		text = codeBlock(sym.detail)
+	} else if _, imported := d.imports[name]; imported {
+		text = moduleDoc(name)
+		if text == "" {
+			if target := s.importedDocument(d, name); target != nil {
+				text = codeBlock("import "+name) + inFile(target, d)
+			}
+		}
+	} else {
+		text = builtinDoc(name)
+	}
+	if text == "" {
+		return nil
+	}
+
+	r := d.rangeAt(tok.Line, tok.Column, len([]rune(name)))
+	return Hover{Contents: MarkupContent{Kind: "markdown", Value: strings.TrimSpace(text)}, Range: &r}
+}
+
+// inFile notes where a declaration from another file comes from.
+func inFile(target, from *document) string {
+	if target == nil || target == from {
+		return ""
+	}
+	return "Defined in " + filepath.Base(target.path)
+}
+
+func (s *Server) definition(p textDocumentPositionParams) any {
+	d := s.docs[p.TextDocument.URI]
+	if d == nil {
+		return nil
+	}
+	i := d.identAt(p.Position)
+	if i < 0 {
+		return nil
+	}
+	tok := d.tokens[i]
+	name, qualifier := tok.Literal, d.qualifier(i)
+
+	if qualifier != "" {
+		if target, sym := s.member(d, qualifier, name, tok.Line); sym != nil {
+			return Location{URI: target.uri, Range: target.symbolRange(sym)}
+		}
+		return nil
+	}
+	if sym := d.lookup(name, tok.Line); sym != nil {
+		return Location{URI: d.uri, Range: d.symbolRange(sym)}
+	}
+	if _, imported := d.imports[name]; imported {
+		if target := s.importedDocument(d, name); target != nil {
+			for _, sym := range target.symbols {
+				if sym.kind == symbolPackage {
+					return Location{URI: target.uri, Range: target.symbolRange(sym)}
+				}
+			}
+			return Location{URI: target.uri}
+		}
+	}
+	return nil
+}
+
+// member resolves qualifier.name, where qualifier is an imported file or
+// a package or struct declared in d. It returns the document the member is
+// declared in along with the member.
+func (s *Server) member(d *document, qualifier, name string, line int) (*document, *symbol) {
+	if _, imported := d.imports[qualifier]; imported {
+		if target := s.importedDocument(d, qualifier); target != nil {
+			return target, findSymbol(target.exports(), name)
+		}
+		return nil, nil
+	}
+	if sym := d.lookup(qualifier, line); sym != nil {
+		return d, findSymbol(sym.members(), name)
+	}
+	return nil, nil
+}
+
+var memberAccess = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Za-z0-9_]*$`)
+
+func (s *Server) completion(p textDocumentPositionParams) any {
+	items := []CompletionItem{}
+	d := s.docs[p.TextDocument.URI]
+	if d == nil {
+		return items
+	}
+	line := d.line(p.Position.Line + 1)
+	before := string(line[:min(d.runeColumn(p.Position), len(line))])
+	m := memberAccess.FindStringSubmatch(before)
+	if m == nil {
+		return items
+	}
+	qualifier := m[1]
+
+	var members []*symbol
+	if _, imported := d.imports[qualifier]; imported && module.Mapper[qualifier] == nil {
+		if target := s.importedDocument(d, qualifier); target != nil {
+			members = target.exports()
+		}
+	} else if _, ok := module.Mapper[qualifier]; ok {
+		return append(items, moduleMembers(qualifier)...)
+	} else if sym := d.lookup(qualifier, p.Position.Line+1); sym != nil {
+		members = sym.members()
+	}
+
+	for _, sym := range members {
+		items = append(items, CompletionItem{
+			Label:  sym.name,
+			Kind:   completionKind(sym.kind),
+			Detail: sym.detail,
+		})
+	}
+	return items
+}
+
+func completionKind(kind int) int {
+	switch kind {
+	case symbolFunction, symbolMethod:
+		return completionFunction
+	case symbolConstant:
+		return completionConstant
+	case symbolPackage:
+		return completionModule
+	}
+	return completionVariable
+}
+
+// importedDocument finds the file that `import name` in d refers to. Like
+// the interpreter, it looks next to the importing file and in a modules
+// directory, and also in the workspace root. Open documents are used in
+// preference to what is on disk.
+func (s *Server) importedDocument(d *document, name string) *document {
+	dirs := []string{filepath.Dir(d.path), filepath.Join(filepath.Dir(d.path), "modules")}
+	if s.root != "" {
+		dirs = append(dirs, s.root, filepath.Join(s.root, "modules"))
+	}
+	for _, dir := range dirs {
+		// A package fetched with `vint get` is a directory of its own
+		for _, path := range []string{filepath.Join(dir, name+".vint"), filepath.Join(dir, name, name+".vint")} {
+			uri := pathToURI(path)
+			if open, ok := s.docs[uri]; ok {
+				return open
+			}
+			if content, err := os.ReadFile(path); err == nil {
+				return parseDocument(uri, path, string(content))
+			}
+		}
+	}
+	return nil
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	// file:///C:/dir on Windows
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,305 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// client drives a Server over pipes the way an editor would.
+type client struct {
+	t             *testing.T
+	in            io.WriteCloser
+	out           *bufio.Reader
+	nextID        int
+	notifications []message
+	exitCode      chan int
+}
+
+func startServer(t *testing.T) *client {
+	t.Helper()
+	clientToServer, serverIn := io.Pipe()
+	serverOut, serverToClient := io.Pipe()
+
+	c := &client{t: t, in: serverIn, out: bufio.NewReader(serverOut), exitCode: make(chan int, 1)}
+	go func() {
+		c.exitCode <- NewServer(clientToServer, serverToClient).Serve()
+		serverToClient.Close()
+	}()
+	t.Cleanup(func() { serverIn.Close() })
+	return c
+}
+
+func (c *client) send(v any) {
+	c.t.Helper()
+	if err := writeMessage(c.in, v); err != nil {
+		c.t.Fatalf("write failed: %v", err)
+	}
+}
+
+func (c *client) notify(method string, params any) {
+	c.t.Helper()
+	c.send(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// call sends a request and waits for its response, keeping any
+// notifications that arrive first.
+func (c *client) call(method string, params any, result any) *responseError {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	for {
+		msg := c.read()
+		if msg.ID == nil {
+			c.notifications = append(c.notifications, *msg)
+			continue
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("%s: bad result %s: %v", method, msg.Result, err)
+			}
+		}
+		return nil
+	}
+}
+
+func (c *client) read() *message {
+	c.t.Helper()
+	done := make(chan *message, 1)
+	go func() {
+		msg, err := readMessage(c.out)
+		if err != nil {
+			done <- nil
+			return
+		}
+		done <- msg
+	}()
+	select {
+	case msg := <-done:
+		if msg == nil {
+			c.t.Fatal("server closed the connection")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timed out waiting for the server")
+	}
+	return nil
+}
+
+// diagnostics waits for the next publishDiagnostics notification for uri.
+func (c *client) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	for {
+		var msg message
+		if len(c.notifications) > 0 {
+			msg, c.notifications = c.notifications[0], c.notifications[1:]
+		} else {
+			msg = *c.read()
+		}
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var p publishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			c.t.Fatal(err)
+		}
+		if p.URI == uri {
+			return p.Diagnostics
+		}
+	}
+}
+
+func (c *client) open(uri, text string) []Diagnostic {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "vint", "version": 1, "text": text},
+	})
+	return c.diagnostics(uri)
+}
+
+func position(uri string, line, character int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": character},
+	}
+}
+
+const utilsSource = `package utils {
+    let greet = func(name) {
+        return "Hello, " + name
+    }
+    const VERSION = "1.0"
+    let _secret = 42
+}
+`
+
+const mainSource = `import json
+import utils
+
+let add = func(a, b) {
+    return a + b
+}
+
+struct User {
+    name: "",
+    func hello() { return "hi" }
+}
+
+print(add(1, 2))
+print(utils.greet("vint"))
+let data = json.decode("{}")
+`
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "utils.vint"), []byte(utilsSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mainURI := pathToURI(filepath.Join(dir, "main.vint"))
+	utilsURI := pathToURI(filepath.Join(dir, "utils.vint"))
+
+	c := startServer(t)
+
+	if err := c.call("textDocument/hover", position(mainURI, 0, 0), nil); err == nil || err.Code != codeServerNotInitialized {
+		t.Errorf("expected a not initialized error before initialize, got %v", err)
+	}
+
+	var init struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	if err := c.call("initialize", map[string]any{"rootUri": pathToURI(dir)}, &init); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	for _, capability := range []string{"hoverProvider", "definitionProvider", "completionProvider", "documentSymbolProvider"} {
+		if init.Capabilities[capability] == nil {
+			t.Errorf("capability %s not advertised", capability)
+		}
+	}
+	c.notify("initialized", map[string]any{})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		uri := pathToURI(filepath.Join(dir, "broken.vint"))
+		diagnostics := c.open(uri, "let x = 1\nlet = 5\n")
+		if len(diagnostics) == 0 {
+			t.Fatal("expected diagnostics for a syntax error")
+		}
+		if diagnostics[0].Range.Start.Line != 1 {
+			t.Errorf("diagnostic on line %d, want 1: %+v", diagnostics[0].Range.Start.Line, diagnostics[0])
+		}
+
+		c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   map[string]any{"uri": uri, "version": 2},
+			"contentChanges": []map[string]any{{"text": "let x = 1\nlet y = 5\n"}},
+		})
+		if diagnostics := c.diagnostics(uri); len(diagnostics) != 0 {
+			t.Errorf("expected no diagnostics after the fix, got %+v", diagnostics)
+		}
+	})
+
+	if diagnostics := c.open(mainURI, mainSource); len(diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", diagnostics)
+	}
+
+	t.Run("hover", func(t *testing.T) {
+		tests := []struct {
+			line, character int
+			contains        string
+		}{
+			{12, 1, "Prints messages"},         // print
+			{14, 17, "decode"},                 // json.decode
+			{12, 7, "let add = func(a, b)"},    // add
+			{13, 13, "let greet = func(name)"}, // utils.greet
+			{7, 8, "struct User"},              // User
+		}
+		for _, tt := range tests {
+			var hover Hover
+			if err := c.call("textDocument/hover", position(mainURI, tt.line, tt.character), &hover); err != nil {
+				t.Fatalf("hover failed: %v", err)
+			}
+			if !strings.Contains(hover.Contents.Value, tt.contains) {
+				t.Errorf("hover at %d:%d = %q, want it to contain %q", tt.line, tt.character, hover.Contents.Value, tt.contains)
+			}
+		}
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		tests := []struct {
+			line, character int
+			uri             string
+			targetLine      int
+		}{
+			{12, 7, mainURI, 3},   // add
+			{13, 13, utilsURI, 1}, // utils.greet
+			{13, 7, utilsURI, 0},  // utils
+		}
+		for _, tt := range tests {
+			var loc Location
+			if err := c.call("textDocument/definition", position(mainURI, tt.line, tt.character), &loc); err != nil {
+				t.Fatalf("definition failed: %v", err)
+			}
+			if loc.URI != tt.uri || loc.Range.Start.Line != tt.targetLine {
+				t.Errorf("definition at %d:%d = %s:%d, want %s:%d", tt.line, tt.character, loc.URI, loc.Range.Start.Line, tt.uri, tt.targetLine)
+			}
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		labels := func(line, character int) []string {
+			var items []CompletionItem
+			if err := c.call("textDocument/completion", position(mainURI, line, character), &items); err != nil {
+				t.Fatalf("completion failed: %v", err)
+			}
+			var names []string
+			for _, item := range items {
+				names = append(names, item.Label)
+			}
+			return names
+		}
+
+		jsonMembers := strings.Join(labels(14, 16), ",")
+		if !strings.Contains(jsonMembers, "decode") || !strings.Contains(jsonMembers, "encode") {
+			t.Errorf("json members = %s", jsonMembers)
+		}
+		if got := strings.Join(labels(13, 12), ","); got != "VERSION,greet" {
+			t.Errorf("utils members = %s, want VERSION,greet", got)
+		}
+	})
+
+	t.Run("document symbols", func(t *testing.T) {
+		var symbols []DocumentSymbol
+		if err := c.call("textDocument/documentSymbol", map[string]any{"textDocument": map[string]any{"uri": mainURI}}, &symbols); err != nil {
+			t.Fatalf("documentSymbol failed: %v", err)
+		}
+		var got []string
+		for _, sym := range symbols {
+			got = append(got, sym.Name)
+		}
+		if strings.Join(got, ",") != "add,User,data" {
+			t.Errorf("symbols = %v", got)
+		}
+		if len(symbols) > 1 && len(symbols[1].Children) != 2 {
+			t.Errorf("struct children = %+v", symbols[1].Children)
+		}
+	})
+
+	if err := c.call("shutdown", nil, nil); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	c.notify("exit", nil)
+	select {
+	case code := <-c.exitCode:
+		if code != 0 {
+			t.Errorf("exit code %d, want 0", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
@@ -49,7 +49,7 @@ func newParser(args []object.VintObject, defs map[string]object.VintObject) obje
 
 	// Create a new parser
 	parserID := name.Value
-	parsers[parserID] = &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	parsers[parserID] = object.NewDict()
 	parserArgs[parserID] = []argDef{}
 	parserFlags[parserID] = []argDef{}
 
@@ -227,23 +227,23 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 	}
 
 	// Parse arguments
-	result := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	result := object.NewDict()
 
 	// Set default values
 	for _, arg := range parserArgs[parserID.Value] {
 		hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-		result.Pairs[hashKey] = object.DictPair{
+		result.SetPair(hashKey, object.DictPair{
 			Key:   &object.String{Value: arg.name},
 			Value: arg.defaultVal,
-		}
+		})
 	}
 
 	for _, flag := range parserFlags[parserID.Value] {
 		hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-		result.Pairs[hashKey] = object.DictPair{
+		result.SetPair(hashKey, object.DictPair{
 			Key:   &object.String{Value: flag.name},
 			Value: flag.defaultVal,
-		}
+		})
 	}
 
 	// Process arguments
@@ -285,10 +285,10 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 			if flagDef.valueType == "boolean" {
 				// Boolean flag
 				hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-				result.Pairs[hashKey] = object.DictPair{
+				result.SetPair(hashKey, object.DictPair{
 					Key:   &object.String{Value: flagDef.name},
 					Value: &object.Boolean{Value: true},
-				}
+				})
 			} else {
 				// Flag with value
 				if i+1 >= len(cliArgs) {
@@ -314,10 +314,10 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 				}
 
 				hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-				result.Pairs[hashKey] = object.DictPair{
+				result.SetPair(hashKey, object.DictPair{
 					Key:   &object.String{Value: flagDef.name},
 					Value: objValue,
-				}
+				})
 			}
 		} else if strings.HasPrefix(arg, "-") {
 			// Short flag
@@ -339,10 +339,10 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 			// Handle flag value (same logic as for long flags)
 			if flagDef.valueType == "boolean" {
 				hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-				result.Pairs[hashKey] = object.DictPair{
+				result.SetPair(hashKey, object.DictPair{
 					Key:   &object.String{Value: flagDef.name},
 					Value: &object.Boolean{Value: true},
-				}
+				})
 			} else {
 				if i+1 >= len(cliArgs) {
 					return ErrorMessage("argparse", "parse", fmt.Sprintf("flag -%s requires a value", shortName), "", "")
@@ -366,10 +366,10 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 				}
 
 				hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-				result.Pairs[hashKey] = object.DictPair{
+				result.SetPair(hashKey, object.DictPair{
 					Key:   &object.String{Value: flagDef.name},
 					Value: objValue,
-				}
+				})
 			}
 		} else {
 			// Positional argument
@@ -395,10 +395,10 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 			}
 
 			hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(result.Pairs))}
-			result.Pairs[hashKey] = object.DictPair{
+			result.SetPair(hashKey, object.DictPair{
 				Key:   &object.String{Value: argDef.name},
 				Value: objValue,
-			}
+			})
 
 			positionalIndex++
 		}
@@ -408,7 +408,7 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 	for _, arg := range parserArgs[parserID.Value] {
 		if arg.required {
 			found := false
-			for _, pair := range result.Pairs {
+			for _, pair := range result.OrderedPairs() {
 				if pair.Key.(*object.String).Value == arg.name {
 					if _, ok := pair.Value.(*object.Null); !ok {
 						found = true
@@ -427,7 +427,7 @@ func parse(args []object.VintObject, defs map[string]object.VintObject) object.V
 	for _, flag := range parserFlags[parserID.Value] {
 		if flag.required {
 			found := false
-			for _, pair := range result.Pairs {
+			for _, pair := range result.OrderedPairs() {
 				if pair.Key.(*object.String).Value == flag.name {
 					if _, ok := pair.Value.(*object.Null); !ok {
 						found = true
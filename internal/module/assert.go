@@ -0,0 +1,317 @@
+package module
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+var AssertFunctions = map[string]object.ModuleFunction{}
+
+func init() {
+	AssertFunctions["equal"] = assertEqual
+	AssertFunctions["deepEqual"] = assertDeepEqual
+	AssertFunctions["throws"] = assertThrows
+	AssertFunctions["contains"] = assertContains
+	AssertFunctions["near"] = assertNear
+}
+
+// Every assertion returns null when it holds and an error otherwise, so a
+// failing assertion stops the test it is in. All of them accept an optional
+// message, either as an extra argument or as message="...".
+
+func assertEqual(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) < 2 || len(args) > 3 {
+		return ErrorMessage(
+			"assert", "equal",
+			"2 arguments: actual, expected, and an optional message",
+			formatArgs(args),
+			`assert.equal(add(1, 2), 3)`,
+		)
+	}
+	if !shallowEqual(args[0], args[1]) {
+		return assertionFailed("equal", args[2:], defs,
+			"expected %s, got %s", args[1].Inspect(), args[0].Inspect())
+	}
+	return &object.Null{}
+}
+
+func assertDeepEqual(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) < 2 || len(args) > 3 {
+		return ErrorMessage(
+			"assert", "deepEqual",
+			"2 arguments: actual, expected, and an optional message",
+			formatArgs(args),
+			`assert.deepEqual(user, {"name": "Ana", "tags": ["admin"]})`,
+		)
+	}
+	if !deepEqual(args[0], args[1]) {
+		return assertionFailed("deepEqual", args[2:], defs,
+			"expected %s, got %s", args[1].Inspect(), args[0].Inspect())
+	}
+	return &object.Null{}
+}
+
+func assertThrows(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) < 1 || len(args) > 3 {
+		return ErrorMessage(
+			"assert", "throws",
+			"a function, an optional expected error message, and an optional message",
+			formatArgs(args),
+			`assert.throws(func() { divide(1, 0) }, "division by zero")`,
+		)
+	}
+	fn, ok := args[0].(*object.Function)
+	if !ok {
+		return ErrorMessage(
+			"assert", "throws",
+			"a function as the first argument",
+			formatArgs(args),
+			`assert.throws(func() { divide(1, 0) }, "division by zero")`,
+		)
+	}
+
+	result := object.CallFunction(fn, []object.VintObject{})
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		return assertionFailed("throws", optionalArgs(args, 2), defs,
+			"expected the function to throw, but it returned %s", result.Inspect())
+	}
+
+	if len(args) > 1 {
+		want, ok := args[1].(*object.String)
+		if !ok {
+			return ErrorMessage(
+				"assert", "throws",
+				"the expected error message to be a string",
+				formatArgs(args),
+				`assert.throws(func() { divide(1, 0) }, "division by zero")`,
+			)
+		}
+		if !strings.Contains(thrownMessage(errObj), want.Value) {
+			return assertionFailed("throws", optionalArgs(args, 2), defs,
+				"expected an error containing %q, got %q", want.Value, thrownMessage(errObj))
+		}
+	}
+	return &object.Null{}
+}
+
+func assertContains(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) < 2 || len(args) > 3 {
+		return ErrorMessage(
+			"assert", "contains",
+			"2 arguments: a string, array or dict, the item to look for, and an optional message",
+			formatArgs(args),
+			`assert.contains([1, 2, 3], 2)`,
+		)
+	}
+
+	found := false
+	switch container := args[0].(type) {
+	case *object.String:
+		item, ok := args[1].(*object.String)
+		if !ok {
+			return ErrorMessage(
+				"assert", "contains",
+				"a string to look for in a string",
+				formatArgs(args),
+				`assert.contains("hello world", "world")`,
+			)
+		}
+		found = strings.Contains(container.Value, item.Value)
+	case *object.Array:
+		for _, el := range container.Elements {
+			if deepEqual(el, args[1]) {
+				found = true
+				break
+			}
+		}
+	case *object.Dict:
+		key, ok := args[1].(object.Hashable)
+		if ok {
+			_, found = container.Pairs[key.HashKey()]
+		}
+	default:
+		return ErrorMessage(
+			"assert", "contains",
+			"a string, array or dict as the first argument",
+			formatArgs(args),
+			`assert.contains([1, 2, 3], 2)`,
+		)
+	}
+
+	if !found {
+		return assertionFailed("contains", args[2:], defs,
+			"%s does not contain %s", args[0].Inspect(), args[1].Inspect())
+	}
+	return &object.Null{}
+}
+
+func assertNear(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) < 2 || len(args) > 4 {
+		return ErrorMessage(
+			"assert", "near",
+			"2 numbers, an optional tolerance (default 1e-9), and an optional message",
+			formatArgs(args),
+			`assert.near(0.1 + 0.2, 0.3)`,
+		)
+	}
+
+	actual, ok1 := toFloat(args[0])
+	expected, ok2 := toFloat(args[1])
+	if !ok1 || !ok2 {
+		return ErrorMessage(
+			"assert", "near",
+			"numbers to compare",
+			formatArgs(args),
+			`assert.near(0.1 + 0.2, 0.3)`,
+		)
+	}
+
+	tolerance := 1e-9
+	if len(args) > 2 {
+		if t, ok := toFloat(args[2]); ok {
+			tolerance = t
+		} else if _, isString := args[2].(*object.String); !isString || len(args) == 4 {
+			return ErrorMessage(
+				"assert", "near",
+				"the tolerance to be a number",
+				formatArgs(args),
+				`assert.near(3.14159, 3.14, 0.01)`,
+			)
+		}
+	}
+
+	if math.Abs(actual-expected) > tolerance {
+		return assertionFailed("near", optionalArgs(args, 2), defs,
+			"expected %s to be within %g of %s", args[0].Inspect(), tolerance, args[1].Inspect())
+	}
+	return &object.Null{}
+}
+
+// assertionFailed builds the error returned by a failing assertion. extra
+// holds any arguments after the ones the assertion needs; a trailing string
+// among them is the caller's message.
+func assertionFailed(name string, extra []object.VintObject, defs map[string]object.VintObject, format string, a ...interface{}) *object.Error {
+	msg := fmt.Sprintf("assert.%s failed: %s", name, fmt.Sprintf(format, a...))
+
+	var note object.VintObject
+	if len(extra) > 0 {
+		note = extra[len(extra)-1]
+	}
+	if m, ok := defs["message"]; ok {
+		note = m
+	}
+	if s, ok := note.(*object.String); ok {
+		msg = s.Value + ": " + msg
+	}
+	return &object.Error{Message: msg}
+}
+
+func optionalArgs(args []object.VintObject, from int) []object.VintObject {
+	if len(args) <= from {
+		return nil
+	}
+	return args[from:]
+}
+
+func thrownMessage(errObj *object.Error) string {
+	if errObj.Thrown != nil {
+		if s, ok := errObj.Thrown.(*object.String); ok {
+			return s.Value
+		}
+		return errObj.Thrown.Inspect()
+	}
+	return errObj.Message
+}
+
+func toFloat(obj object.VintObject) (float64, bool) {
+	switch n := obj.(type) {
+	case *object.Integer:
+		return float64(n.Value), true
+	case *object.Float:
+		return n.Value, true
+	case *object.BigInt:
+		f, _ := new(big.Float).SetInt(n.Value).Float64()
+		return f, true
+	case *object.Decimal:
+		return n.Float64(), true
+	}
+	return 0, false
+}
+
+// numbersEqual compares two numbers like the == operator: exactly when
+// either is a decimal or both are integers of any size, and as floats
+// otherwise.
+func numbersEqual(a, b object.VintObject) bool {
+	switch {
+	case a.Type() == object.DECIMAL_OBJ || b.Type() == object.DECIMAL_OBJ:
+		x, err1 := object.ToDecimal(a)
+		y, err2 := object.ToDecimal(b)
+		return err1 == nil && err2 == nil && x.Cmp(y) == 0
+	case a.Type() == object.FLOAT_OBJ || b.Type() == object.FLOAT_OBJ:
+		x, _ := toFloat(a)
+		y, _ := toFloat(b)
+		return x == y
+	}
+	x, _ := object.ToBigInt(a)
+	y, _ := object.ToBigInt(b)
+	return x.Value.Cmp(y.Value) == 0
+}
+
+// shallowEqual compares like the == operator: numbers, strings, booleans
+// and null by value, everything else by identity.
+func shallowEqual(a, b object.VintObject) bool {
+	if _, ok := toFloat(a); ok {
+		_, ok := toFloat(b)
+		return ok && numbersEqual(a, b)
+	}
+	switch a := a.(type) {
+	case *object.String:
+		other, ok := b.(*object.String)
+		return ok && a.Value == other.Value
+	case *object.Boolean:
+		other, ok := b.(*object.Boolean)
+		return ok && a.Value == other.Value
+	case *object.Null:
+		return b.Type() == object.NULL_OBJ
+	}
+	return a == b
+}
+
+// deepEqual compares arrays and dicts element by element.
+func deepEqual(a, b object.VintObject) bool {
+	switch a := a.(type) {
+	case *object.Array:
+		other, ok := b.(*object.Array)
+		if !ok || len(a.Elements) != len(other.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !deepEqual(a.Elements[i], other.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.Dict:
+		other, ok := b.(*object.Dict)
+		if !ok || len(a.Pairs) != len(other.Pairs) {
+			return false
+		}
+		for key, pair := range a.Pairs {
+			otherPair, ok := other.Pairs[key]
+			if !ok || !deepEqual(pair.Value, otherPair.Value) {
+				return false
+			}
+		}
+		return true
+	}
+	if shallowEqual(a, b) {
+		return true
+	}
+	// Structs, errors and other values compare by their contents
+	return a.Type() == b.Type() && a.Inspect() == b.Inspect()
+}
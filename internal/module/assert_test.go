@@ -0,0 +1,76 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+func TestAssertions(t *testing.T) {
+	array := func(values ...int64) *object.Array {
+		arr := &object.Array{}
+		for _, v := range values {
+			arr.Elements = append(arr.Elements, &object.Integer{Value: v})
+		}
+		return arr
+	}
+	str := func(s string) *object.String { return &object.String{Value: s} }
+	bigint := func(s string) *object.BigInt {
+		b, _ := object.ParseBigInt(s)
+		return b
+	}
+	decimal := func(s string) *object.Decimal {
+		d, _ := object.ParseDecimal(s)
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		fn     object.ModuleFunction
+		args   []object.VintObject
+		passes bool
+	}{
+		{"equal ints", assertEqual, []object.VintObject{&object.Integer{Value: 3}, &object.Integer{Value: 3}}, true},
+		{"equal int and float", assertEqual, []object.VintObject{&object.Integer{Value: 2}, &object.Float{Value: 2}}, true},
+		{"equal bigints", assertEqual, []object.VintObject{bigint("18446744073709551616"), bigint("18446744073709551616")}, true},
+		{"equal bigints differ", assertEqual, []object.VintObject{bigint("18446744073709551616"), bigint("18446744073709551617")}, false},
+		{"equal bigint and int", assertEqual, []object.VintObject{bigint("7"), &object.Integer{Value: 7}}, true},
+		{"equal decimals", assertEqual, []object.VintObject{decimal("1.5"), decimal("1.50")}, true},
+		{"equal decimals differ", assertEqual, []object.VintObject{decimal("0.3"), decimal("0.30000000000000004")}, false},
+		{"equal decimal and float", assertEqual, []object.VintObject{decimal("0.1"), &object.Float{Value: 0.1}}, true},
+		{"equal strings differ", assertEqual, []object.VintObject{str("a"), str("b")}, false},
+		{"equal arrays are compared by identity", assertEqual, []object.VintObject{array(1), array(1)}, false},
+		{"deepEqual arrays", assertDeepEqual, []object.VintObject{array(1, 2), array(1, 2)}, true},
+		{"deepEqual arrays differ", assertDeepEqual, []object.VintObject{array(1, 2), array(2, 1)}, false},
+		{"contains in array", assertContains, []object.VintObject{array(1, 2, 3), &object.Integer{Value: 2}}, true},
+		{"contains in string", assertContains, []object.VintObject{str("vintlang"), str("lang")}, true},
+		{"contains missing", assertContains, []object.VintObject{array(1), &object.Integer{Value: 5}}, false},
+		{"near within tolerance", assertNear, []object.VintObject{&object.Float{Value: 0.30000000000000004}, &object.Float{Value: 0.3}}, true},
+		{"near with custom tolerance", assertNear, []object.VintObject{&object.Float{Value: 3.14159}, &object.Float{Value: 3.14}, &object.Float{Value: 0.01}}, true},
+		{"near decimals", assertNear, []object.VintObject{decimal("3.14159"), decimal("3.14"), &object.Float{Value: 0.01}}, true},
+		{"near bigints", assertNear, []object.VintObject{bigint("18446744073709551616"), &object.Float{Value: 18446744073709551616}}, true},
+		{"near too far", assertNear, []object.VintObject{&object.Float{Value: 3.2}, &object.Float{Value: 3.14}}, false},
+	}
+
+	for _, tt := range tests {
+		result := tt.fn(tt.args, map[string]object.VintObject{})
+		_, failed := result.(*object.Error)
+		if failed == tt.passes {
+			t.Errorf("%s: expected passes=%t, got %s", tt.name, tt.passes, result.Inspect())
+		}
+	}
+}
+
+func TestAssertionMessage(t *testing.T) {
+	args := []object.VintObject{&object.Integer{Value: 4}, &object.Integer{Value: 5}, &object.String{Value: "two plus two"}}
+	result := assertEqual(args, map[string]object.VintObject{})
+
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected an error, got %s", result.Inspect())
+	}
+	want := "two plus two: assert.equal failed: expected 5, got 4"
+	if errObj.Message != want {
+		t.Errorf("wrong message. want=%q, got=%q", want, errObj.Message)
+	}
+}
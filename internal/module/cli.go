@@ -59,7 +59,7 @@ func getFlags(args []object.VintObject, defs map[string]object.VintObject) objec
 		)
 	}
 
-	flags := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	flags := object.NewDict()
 	cliArgs := toolkit.GetCliArgs()
 
 	for i := 0; i < len(cliArgs); i++ {
@@ -75,10 +75,10 @@ func getFlags(args []object.VintObject, defs map[string]object.VintObject) objec
 			}
 
 			hashKey := object.HashKey{Type: object.STRING_OBJ, Value: uint64(len(flags.Pairs))}
-			flags.Pairs[hashKey] = object.DictPair{
+			flags.SetPair(hashKey, object.DictPair{
 				Key:   &object.String{Value: key},
 				Value: value,
-			}
+			})
 		}
 	}
 
@@ -403,7 +403,7 @@ func argsParse(args []object.VintObject, defs map[string]object.VintObject) obje
 	cliArgs := toolkit.GetCliArgs()
 
 	// Create a dictionary with parsed arguments and helper methods
-	result := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	result := object.NewDict()
 
 	// Add flags to the result
 	flags := make(map[string]object.VintObject)
@@ -433,53 +433,53 @@ func argsParse(args []object.VintObject, defs map[string]object.VintObject) obje
 
 	// Add flags to result dict
 	flagsHashKey := object.HashKey{Type: object.STRING_OBJ, Value: 0}
-	result.Pairs[flagsHashKey] = object.DictPair{
+	result.SetPair(flagsHashKey, object.DictPair{
 		Key:   &object.String{Value: "flags"},
 		Value: createDictFromMap(flags),
-	}
+	})
 
 	// Add positional arguments
 	posArgsArray := &object.Array{Elements: positionalArgs}
 	posHashKey := object.HashKey{Type: object.STRING_OBJ, Value: 1}
-	result.Pairs[posHashKey] = object.DictPair{
+	result.SetPair(posHashKey, object.DictPair{
 		Key:   &object.String{Value: "positional"},
 		Value: posArgsArray,
-	}
+	})
 
 	// Add has method
 	hasHashKey := object.HashKey{Type: object.STRING_OBJ, Value: 2}
-	result.Pairs[hasHashKey] = object.DictPair{
+	result.SetPair(hasHashKey, object.DictPair{
 		Key:   &object.String{Value: "has"},
 		Value: &object.Builtin{Fn: createHasFunction(flags)},
-	}
+	})
 
 	// Add get method
 	getHashKey := object.HashKey{Type: object.STRING_OBJ, Value: 3}
-	result.Pairs[getHashKey] = object.DictPair{
+	result.SetPair(getHashKey, object.DictPair{
 		Key:   &object.String{Value: "get"},
 		Value: &object.Builtin{Fn: createGetFunction(flags)},
-	}
+	})
 
 	// Add positional method
 	positionalHashKey := object.HashKey{Type: object.STRING_OBJ, Value: 4}
-	result.Pairs[positionalHashKey] = object.DictPair{
+	result.SetPair(positionalHashKey, object.DictPair{
 		Key:   &object.String{Value: "positional"},
 		Value: &object.Builtin{Fn: createPositionalFunction(positionalArgs)},
-	}
+	})
 
 	return result
 }
 
 // Helper function to create a dictionary from a map
 func createDictFromMap(flags map[string]object.VintObject) *object.Dict {
-	dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	dict := object.NewDict()
 	i := uint64(0)
 	for key, value := range flags {
 		hashKey := object.HashKey{Type: object.STRING_OBJ, Value: i}
-		dict.Pairs[hashKey] = object.DictPair{
+		dict.SetPair(hashKey, object.DictPair{
 			Key:   &object.String{Value: key},
 			Value: value,
-		}
+		})
 		i++
 	}
 	return dict
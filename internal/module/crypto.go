@@ -253,7 +253,7 @@ func generateRSAKeyPair(args []object.VintObject, defs map[string]object.VintObj
 	})
 
 	// Return as dictionary
-	pairs := make(map[object.HashKey]object.DictPair)
+	pairs := object.NewDict()
 
 	privateKeyStr := &object.String{Value: string(privateKeyPEM)}
 	publicKeyStr := &object.String{Value: string(publicKeyPEM)}
@@ -261,16 +261,16 @@ func generateRSAKeyPair(args []object.VintObject, defs map[string]object.VintObj
 	privateKeyKey := &object.String{Value: "private"}
 	publicKeyKey := &object.String{Value: "public"}
 
-	pairs[privateKeyKey.HashKey()] = object.DictPair{
+	pairs.SetPair(privateKeyKey.HashKey(), object.DictPair{
 		Key:   privateKeyKey,
 		Value: privateKeyStr,
-	}
-	pairs[publicKeyKey.HashKey()] = object.DictPair{
+	})
+	pairs.SetPair(publicKeyKey.HashKey(), object.DictPair{
 		Key:   publicKeyKey,
 		Value: publicKeyStr,
-	}
+	})
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // encryptRSA encrypts data using RSA public key encryption.
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/utils"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -854,13 +855,14 @@ func excelGetFileInfo(args []object.VintObject, defs map[string]object.VintObjec
 	result["sheetCount"] = &object.Integer{Value: int64(len(sheets))}
 
 	// Convert map to Dict
-	pairs := make(map[object.HashKey]object.DictPair)
-	for k, v := range result {
+	pairs := object.NewDict()
+	for _, k := range utils.SortedKeys(result) {
+		v := result[k]
 		key := &object.String{Value: k}
 		hashKey := key.HashKey()
-		pairs[hashKey] = object.DictPair{Key: key, Value: v}
+		pairs.SetPair(hashKey, object.DictPair{Key: key, Value: v})
 	}
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // Stub implementations for remaining functions (can be expanded)
@@ -2,6 +2,8 @@ package module
 
 import (
 	"fmt"
+	"math/big"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -51,6 +53,11 @@ func VintObjectToInterface(obj object.VintObject) interface{} {
 		return o.Value
 	case *object.Float:
 		return o.Value
+	case *object.BigInt:
+		return o.Value
+	case *object.Decimal:
+		// Decimals format themselves, without going through a float
+		return o
 	case *object.Boolean:
 		return o.Value
 	case *object.Null:
@@ -60,6 +67,41 @@ func VintObjectToInterface(obj object.VintObject) interface{} {
 	}
 }
 
+// formatSpec matches the spec of an interpolation such as ${price:.2f}:
+// the flags, width, precision and verb of a sprintf directive without its
+// '%'. The verb can be left out.
+var formatSpec = regexp.MustCompile(`^[-+# 0]*[0-9]*(\.[0-9]+)?([a-zA-Z]?)$`)
+
+// FormatValue formats obj the way sprintf formats it with "%"+spec. It is
+// used by interpolated strings: "${price:.2f}", "${name:-10}", "${n:08b}".
+// A spec without a verb uses %v, and integers can be formatted as floats.
+func FormatValue(obj object.VintObject, spec string) (string, error) {
+	match := formatSpec.FindStringSubmatch(spec)
+	if spec == "" || match == nil {
+		return "", fmt.Errorf("invalid format spec '%s'", spec)
+	}
+	verb := match[2]
+	if verb == "" {
+		verb = "v"
+		spec += verb
+	}
+
+	value := VintObjectToInterface(obj)
+	if strings.Contains("eEfFgG", verb) {
+		switch i := obj.(type) {
+		case *object.Integer:
+			value = float64(i.Value)
+		case *object.BigInt:
+			value = new(big.Float).SetInt(i.Value)
+		}
+	}
+	result := fmt.Sprintf("%"+spec, value)
+	if strings.HasPrefix(strings.TrimSpace(result), "%!"+verb+"(") {
+		return "", fmt.Errorf("cannot format %s with '%s'", obj.Type(), spec)
+	}
+	return result, nil
+}
+
 // sprintf formats a string using Go's fmt.Sprintf
 func sprintf(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
 	if len(args) < 1 || args[0].Type() != object.STRING_OBJ {
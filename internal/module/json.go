@@ -3,6 +3,7 @@ package module
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/vintlang/vintlang/internal/object"
@@ -19,14 +20,22 @@ func init() {
 	JsonFunctions["get"] = get
 }
 
+// decode parses a JSON string. Integers become integers, or bigints if
+// they are too large, and other numbers become floats, or decimals with
+// decimal=true so that amounts like 19.99 stay exact.
 func decode(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
-	if len(defs) != 0 {
-		return ErrorMessage(
-			"json", "decode",
-			"no definitions allowed",
-			fmt.Sprintf("%d definitions provided", len(defs)),
-			`json.decode("{\"key\": \"value\"}")`,
-		)
+	decimals := false
+	for name, value := range defs {
+		flag, ok := value.(*object.Boolean)
+		if name != "decimal" || !ok {
+			return ErrorMessage(
+				"json", "decode",
+				"only decimal=true or decimal=false",
+				fmt.Sprintf("%s=%s", name, value.Inspect()),
+				`json.decode("{\"price\": 19.99}", decimal=true)`,
+			)
+		}
+		decimals = flag.Value
 	}
 	if len(args) != 1 {
 		return ErrorMessage(
@@ -46,19 +55,27 @@ func decode(args []object.VintObject, defs map[string]object.VintObject) object.
 		)
 	}
 
-	var i any
-
 	input := args[0].(*object.String).Value
-	err := json.Unmarshal([]byte(input), &i)
+	i, err := decodeOrderedJSON([]byte(input))
 	if err != nil {
 		return &object.Error{Message: "This data is not valid JSON"}
 	}
 
-	return convertWhateverToObject(i)
+	return convertWhateverToObject(i, decimals)
 }
 
-func convertWhateverToObject(i any) object.VintObject {
+func convertWhateverToObject(i any, decimals bool) object.VintObject {
 	switch v := i.(type) {
+	case *orderedMap:
+		dict := object.NewDict()
+		for _, k := range v.keys {
+			pair := object.DictPair{
+				Key:   &object.String{Value: k},
+				Value: convertWhateverToObject(v.values[k], decimals),
+			}
+			dict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
+		}
+		return dict
 	case map[string]any:
 		dict := &object.Dict{}
 		dict.Pairs = make(map[object.HashKey]object.DictPair)
@@ -66,21 +83,23 @@ func convertWhateverToObject(i any) object.VintObject {
 		for k, v := range v {
 			pair := object.DictPair{
 				Key:   &object.String{Value: k},
-				Value: convertWhateverToObject(v),
+				Value: convertWhateverToObject(v, decimals),
 			}
-			dict.Pairs[pair.Key.(object.Hashable).HashKey()] = pair
+			dict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
 		}
 
 		return dict
 	case []any:
 		list := &object.Array{}
 		for _, e := range v {
-			list.Elements = append(list.Elements, convertWhateverToObject(e))
+			list.Elements = append(list.Elements, convertWhateverToObject(e, decimals))
 		}
 
 		return list
 	case string:
 		return &object.String{Value: v}
+	case json.Number:
+		return convertJSONNumber(v, decimals)
 	case int64:
 		return &object.Integer{Value: v}
 	case float64:
@@ -95,6 +114,28 @@ func convertWhateverToObject(i any) object.VintObject {
 	return &object.Null{}
 }
 
+// convertJSONNumber converts a number from JSON to the smallest type that
+// holds it exactly, except that a number with a fraction is a float unless
+// decimals is set.
+func convertJSONNumber(n json.Number, decimals bool) object.VintObject {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := n.Int64(); err == nil {
+			return &object.Integer{Value: i}
+		}
+		if b, ok := new(big.Int).SetString(s, 10); ok {
+			return &object.BigInt{Value: b}
+		}
+	}
+	if decimals {
+		if d, err := object.ParseDecimal(s); err == nil {
+			return d
+		}
+	}
+	f, _ := n.Float64()
+	return &object.Float{Value: f}
+}
+
 func encode(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
 	if len(defs) != 0 {
 		return ErrorMessage(
@@ -151,10 +192,9 @@ func encode(args []object.VintObject, defs map[string]object.VintObject) object.
 func convertObjectToWhatever(obj object.VintObject) any {
 	switch v := obj.(type) {
 	case *object.Dict:
-		m := make(map[string]any)
-		for _, pair := range v.Pairs {
-			key := pair.Key.(*object.String).Value
-			m[key] = convertObjectToWhatever(pair.Value)
+		m := newOrderedMap()
+		for _, pair := range v.OrderedPairs() {
+			m.Set(dictKey(pair.Key), convertObjectToWhatever(pair.Value))
 		}
 		return m
 	case *object.Array:
@@ -169,6 +209,11 @@ func convertObjectToWhatever(obj object.VintObject) any {
 		return v.Value
 	case *object.Float:
 		return v.Value
+	case *object.BigInt:
+		// Numbers are written with every digit, not rounded to a float
+		return json.Number(v.Value.String())
+	case *object.Decimal:
+		return json.Number(v.Inspect())
 	case *object.Boolean:
 		return v.Value
 	case *object.Null:
@@ -183,9 +228,8 @@ func pretty(args []object.VintObject, defs map[string]object.VintObject) object.
 		return &object.Error{Message: "Expect a single string argument"}
 	}
 
-	var i any
 	input := args[0].(*object.String).Value
-	err := json.Unmarshal([]byte(input), &i)
+	i, err := decodeOrderedJSON([]byte(input))
 	if err != nil {
 		return &object.Error{Message: "Invalid JSON input"}
 	}
@@ -206,15 +250,15 @@ func merge(args []object.VintObject, defs map[string]object.VintObject) object.V
 
 	obj1 := args[0]
 	obj2 := args[1]
-	map1, ok1 := convertObjectToWhatever(obj1).(map[string]any)
-	map2, ok2 := convertObjectToWhatever(obj2).(map[string]any)
+	map1, ok1 := convertObjectToWhatever(obj1).(*orderedMap)
+	map2, ok2 := convertObjectToWhatever(obj2).(*orderedMap)
 	if !ok1 || !ok2 {
 		return &object.Error{Message: "Arguments must be JSON objects"}
 	}
 
 	// Merging maps
-	for k, v := range map2 {
-		map1[k] = v
+	for _, k := range map2.keys {
+		map1.Set(k, map2.values[k])
 	}
 
 	mergedJSON, err := json.Marshal(map1)
@@ -237,15 +281,15 @@ func get(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 		return &object.Error{Message: "Key must be a string"}
 	}
 
-	mapObj, ok := convertObjectToWhatever(obj).(map[string]any)
+	mapObj, ok := convertObjectToWhatever(obj).(*orderedMap)
 	if !ok {
 		return &object.Error{Message: "First argument must be a JSON object"}
 	}
 
-	val, exists := mapObj[key.(*object.String).Value]
+	val, exists := mapObj.Get(key.(*object.String).Value)
 	if !exists {
 		return &object.Null{}
 	}
 
-	return convertWhateverToObject(val)
+	return convertWhateverToObject(val, false)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/utils"
 )
 
 // JwtFunctions is a map that holds the available functions in the JWT module.
@@ -43,7 +44,7 @@ func createJWT(args []object.VintObject, defs map[string]object.VintObject) obje
 
 	// Convert payload to jwt.MapClaims
 	claims := jwt.MapClaims{}
-	for _, pair := range payload.Pairs {
+	for _, pair := range payload.OrderedPairs() {
 		key := pair.Key.Inspect()
 		value := convertToGoValue(pair.Value)
 		claims[key] = value
@@ -84,7 +85,7 @@ func createJWTHS256(args []object.VintObject, defs map[string]object.VintObject)
 
 	// Convert payload to jwt.MapClaims
 	claims := jwt.MapClaims{}
-	for _, pair := range payload.Pairs {
+	for _, pair := range payload.OrderedPairs() {
 		key := pair.Key.Inspect()
 		value := convertToGoValue(pair.Value)
 		claims[key] = value
@@ -248,22 +249,22 @@ func decodeJWT(args []object.VintObject, defs map[string]object.VintObject) obje
 	}
 
 	// Create result dict with header and payload
-	pairs := make(map[object.HashKey]object.DictPair)
+	pairs := object.NewDict()
 
 	// Add header
 	headerHash := convertMapToHash(token.Header)
 	headerKey := (&object.String{Value: "header"}).HashKey()
-	pairs[headerKey] = object.DictPair{Key: &object.String{Value: "header"}, Value: headerHash}
+	pairs.SetPair(headerKey, object.DictPair{Key: &object.String{Value: "header"}, Value: headerHash})
 
 	// Add payload
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if ok {
 		payloadHash := convertClaimsToHash(claims)
 		payloadKey := (&object.String{Value: "payload"}).HashKey()
-		pairs[payloadKey] = object.DictPair{Key: &object.String{Value: "payload"}, Value: payloadHash}
+		pairs.SetPair(payloadKey, object.DictPair{Key: &object.String{Value: "payload"}, Value: payloadHash})
 	}
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // Helper function to convert VintLang object to Go value
@@ -284,9 +285,10 @@ func convertToGoValue(obj object.VintObject) any {
 
 // Helper function to convert jwt.MapClaims to VintLang Dict
 func convertClaimsToHash(claims jwt.MapClaims) *object.Dict {
-	pairs := make(map[object.HashKey]object.DictPair)
+	pairs := object.NewDict()
 
-	for key, value := range claims {
+	for _, key := range utils.SortedKeys(claims) {
+		value := claims[key]
 		keyObj := &object.String{Value: key}
 		var valueObj object.VintObject
 
@@ -309,17 +311,18 @@ func convertClaimsToHash(claims jwt.MapClaims) *object.Dict {
 		}
 
 		hashKey := keyObj.HashKey()
-		pairs[hashKey] = object.DictPair{Key: keyObj, Value: valueObj}
+		pairs.SetPair(hashKey, object.DictPair{Key: keyObj, Value: valueObj})
 	}
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // Helper function to convert map[string]any to VintLang Dict
 func convertMapToHash(m map[string]any) *object.Dict {
-	pairs := make(map[object.HashKey]object.DictPair)
+	pairs := object.NewDict()
 
-	for key, value := range m {
+	for _, key := range utils.SortedKeys(m) {
+		value := m[key]
 		keyObj := &object.String{Value: key}
 		var valueObj object.VintObject
 
@@ -341,8 +344,8 @@ func convertMapToHash(m map[string]any) *object.Dict {
 		}
 
 		hashKey := keyObj.HashKey()
-		pairs[hashKey] = object.DictPair{Key: keyObj, Value: valueObj}
+		pairs.SetPair(hashKey, object.DictPair{Key: keyObj, Value: valueObj})
 	}
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/utils"
 )
 
 // KvFunctions contains all KV module functions
@@ -608,7 +609,7 @@ func kvMset(args []object.VintObject, defs map[string]object.VintObject) object.
 	globalStore.mutex.Lock()
 	defer globalStore.mutex.Unlock()
 
-	for _, pair := range pairsDict.Pairs {
+	for _, pair := range pairsDict.OrderedPairs() {
 		if pair.Key.Type() != object.STRING_OBJ {
 			return ErrorMessage(
 				"kv", "mset",
@@ -792,18 +793,19 @@ func kvDump(args []object.VintObject, defs map[string]object.VintObject) object.
 	globalStore.mutex.RLock()
 	defer globalStore.mutex.RUnlock()
 
-	pairs := make(map[object.HashKey]object.DictPair)
-	for key, item := range globalStore.data {
+	pairs := object.NewDict()
+	for _, key := range utils.SortedKeys(globalStore.data) {
+		item := globalStore.data[key]
 		// Skip expired items
 		if item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt) {
 			continue
 		}
 		keyObj := &object.String{Value: key}
 		hashKey := keyObj.HashKey()
-		pairs[hashKey] = object.DictPair{Key: keyObj, Value: item.Value}
+		pairs.SetPair(hashKey, object.DictPair{Key: keyObj, Value: item.Value})
 	}
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // kvStats returns statistics about the KV store
@@ -835,19 +837,19 @@ func kvStats(args []object.VintObject, defs map[string]object.VintObject) object
 
 	activeKeys := totalKeys - expiredKeys
 
-	stats := make(map[object.HashKey]object.DictPair)
+	stats := object.NewDict()
 
 	totalKeysObj := &object.String{Value: "total_keys"}
-	stats[totalKeysObj.HashKey()] = object.DictPair{Key: totalKeysObj, Value: &object.Integer{Value: totalKeys}}
+	stats.SetPair(totalKeysObj.HashKey(), object.DictPair{Key: totalKeysObj, Value: &object.Integer{Value: totalKeys}})
 
 	activeKeysObj := &object.String{Value: "active_keys"}
-	stats[activeKeysObj.HashKey()] = object.DictPair{Key: activeKeysObj, Value: &object.Integer{Value: activeKeys}}
+	stats.SetPair(activeKeysObj.HashKey(), object.DictPair{Key: activeKeysObj, Value: &object.Integer{Value: activeKeys}})
 
 	expiredKeysObj := &object.String{Value: "expired_keys"}
-	stats[expiredKeysObj.HashKey()] = object.DictPair{Key: expiredKeysObj, Value: &object.Integer{Value: expiredKeys}}
+	stats.SetPair(expiredKeysObj.HashKey(), object.DictPair{Key: expiredKeysObj, Value: &object.Integer{Value: expiredKeys}})
 
 	keysWithTTLObj := &object.String{Value: "keys_with_ttl"}
-	stats[keysWithTTLObj.HashKey()] = object.DictPair{Key: keysWithTTLObj, Value: &object.Integer{Value: keysWithTTL}}
+	stats.SetPair(keysWithTTLObj.HashKey(), object.DictPair{Key: keysWithTTLObj, Value: &object.Integer{Value: keysWithTTL}})
 
-	return &object.Dict{Pairs: stats}
+	return stats
 }
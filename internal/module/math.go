@@ -3,6 +3,7 @@ package module
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"math/rand"
 	"time"
 
@@ -62,7 +63,8 @@ var MathFunctions = map[string]object.ModuleFunction{
 	// Complex number functions
 	"complex": complexNum,
 	// Big integer functions
-	"bigint": bigint,
+	"bigint":         bigint,
+	"decimalContext": decimalContext,
 	// Linear algebra operations
 	"dot":       dot,
 	"cross":     cross,
@@ -170,7 +172,10 @@ func abs(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 		}
 	}
 
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if result, ok := bigNumberMethod(args[0], "abs"); ok {
+		return result
+	}
+	if !isNumber(args[0]) {
 		return ErrorMessage(
 			"math", "abs",
 			"numeric argument (integer, float, or complex number)",
@@ -211,6 +216,9 @@ func sign(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 			"math.sign(5) or math.sign(-3.5)",
 		)
 	}
+	if result, ok := bigNumberMethod(args[0], "sign"); ok {
+		return result
+	}
 	switch arg := args[0].(type) {
 	case *object.Integer:
 		if arg.Value == 0 {
@@ -255,7 +263,10 @@ func ceil(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 			"math.ceil(4.3)",
 		)
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if result, ok := bigNumberMethod(args[0], "ceil"); ok {
+		return result
+	}
+	if !isNumber(args[0]) {
 		return ErrorMessage(
 			"math", "ceil",
 			"number argument (integer or float)",
@@ -280,7 +291,10 @@ func floor(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This operation requires exactly one argument."}
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if result, ok := bigNumberMethod(args[0], "floor"); ok {
+		return result
+	}
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "The argument must be a number."}
 	}
 	switch arg := args[0].(type) {
@@ -300,17 +314,10 @@ func sqrt(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This operation requires exactly one argument."}
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a number."}
-	}
-	switch arg := args[0].(type) {
-	case *object.Integer:
-		return &object.Float{Value: math.Sqrt(float64(arg.Value))}
-	case *object.Float:
-		return &object.Float{Value: math.Sqrt(arg.Value)}
-	default:
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "The argument must be a number."}
 	}
+	return &object.Float{Value: math.Sqrt(extractFloatValue(args[0]))}
 }
 
 func cbrt(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -320,17 +327,10 @@ func cbrt(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This operation requires exactly one argument."}
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a number."}
-	}
-	switch arg := args[0].(type) {
-	case *object.Integer:
-		return &object.Float{Value: math.Cbrt(float64(arg.Value))}
-	case *object.Float:
-		return &object.Float{Value: math.Cbrt(arg.Value)}
-	default:
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "The argument must be a number."}
 	}
+	return &object.Float{Value: math.Cbrt(extractFloatValue(args[0]))}
 }
 
 func root(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -340,16 +340,13 @@ func root(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 2 {
 		return &object.Error{Message: "This operation requires exactly two arguments."}
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "The first argument must be a number."}
 	}
 	if args[1].Type() != object.INTEGER_OBJ {
 		return &object.Error{Message: "The second argument must be a number."}
 	}
-	base, ok := args[0].(*object.Float)
-	if !ok {
-		base = &object.Float{Value: float64(args[0].(*object.Integer).Value)}
-	}
+	base := &object.Float{Value: extractFloatValue(args[0])}
 	exp := args[1].(*object.Integer).Value
 
 	if exp == 0 {
@@ -375,15 +372,11 @@ func hypot(args []object.VintObject, defs map[string]object.VintObject) object.V
 	}
 	var sumOfSquares float64
 	for _, arg := range args {
-		if arg.Type() != object.INTEGER_OBJ && arg.Type() != object.FLOAT_OBJ {
+		if !isNumber(arg) {
 			return &object.Error{Message: "Arguments must be numbers."}
 		}
-		switch num := arg.(type) {
-		case *object.Integer:
-			sumOfSquares += float64(num.Value) * float64(num.Value)
-		case *object.Float:
-			sumOfSquares += num.Value * num.Value
-		}
+		num := extractFloatValue(arg)
+		sumOfSquares += num * num
 	}
 	return &object.Float{Value: math.Sqrt(sumOfSquares)}
 }
@@ -402,11 +395,8 @@ func factorial(args []object.VintObject, defs map[string]object.VintObject) obje
 	if n < 0 {
 		return &object.Error{Message: "The argument must be a non-negative integer"}
 	}
-	result := int64(1)
-	for i := int64(2); i <= n; i++ {
-		result *= i
-	}
-	return &object.Integer{Value: result}
+	// Past 20! the result no longer fits in an integer
+	return object.IntegerFromBig(new(big.Int).MulRange(1, n))
 }
 func round(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
 	if len(defs) != 0 {
@@ -415,11 +405,19 @@ func round(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if d, ok := args[0].(*object.Decimal); ok {
+		// Decimals round the way the decimal context says
+		_, mode := object.DecimalContext()
+		return object.IntegerFromBig(d.Round(0, mode).Coef)
+	}
+	if b, ok := args[0].(*object.BigInt); ok {
+		return b
+	}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
 
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Integer{Value: int64(num + 0.5)}
 }
 
@@ -440,10 +438,14 @@ func max(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 		return &object.Error{Message: "The array cannot be empty."}
 	}
 
+	if hasBigNumber(arg.Elements) {
+		return extremeNumber(arg.Elements, 1)
+	}
+
 	var maxNum float64
 
 	for _, element := range arg.Elements {
-		if element.Type() != object.INTEGER_OBJ && element.Type() != object.FLOAT_OBJ {
+		if !isNumber(element) {
 			return &object.Error{Message: "All elements in the array must be numbers."}
 		}
 
@@ -481,10 +483,14 @@ func min(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 		return &object.Error{Message: "The array cannot be empty."}
 	}
 
+	if hasBigNumber(arg.Elements) {
+		return extremeNumber(arg.Elements, -1)
+	}
+
 	minNum := math.MaxFloat64
 
 	for _, element := range arg.Elements {
-		if element.Type() != object.INTEGER_OBJ && element.Type() != object.FLOAT_OBJ {
+		if !isNumber(element) {
 			return &object.Error{Message: "All elements in the array must be numbers."}
 		}
 
@@ -512,10 +518,10 @@ func exp(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Exp(num)}
 }
 
@@ -526,10 +532,10 @@ func expm1(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Expm1(num)}
 }
 
@@ -554,10 +560,10 @@ func log10(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Log10(num)}
 }
 
@@ -568,7 +574,7 @@ func log2(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "The argument must be a number."}
 	}
 
@@ -587,11 +593,63 @@ func extractFloatValue(obj object.VintObject) float64 {
 		return float64(obj.Value)
 	case *object.Float:
 		return obj.Value
+	case *object.BigInt:
+		f, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return f
+	case *object.Decimal:
+		return obj.Float64()
 	default:
 		return 0
 	}
 }
 
+// bigNumberMethod calls the method called name on a BigInt or a Decimal,
+// which know their own abs, sign, floor and ceil. It reports false for
+// other values. A BigInt is its own floor and ceil.
+func bigNumberMethod(obj object.VintObject, name string) (object.VintObject, bool) {
+	switch n := obj.(type) {
+	case *object.BigInt:
+		if name == "floor" || name == "ceil" {
+			return n, true
+		}
+		return n.Method(name, nil), true
+	case *object.Decimal:
+		return n.Method(name, nil), true
+	}
+	return nil, false
+}
+
+// hasBigNumber reports whether any of elements is a BigInt or a Decimal.
+func hasBigNumber(elements []object.VintObject) bool {
+	for _, e := range elements {
+		if e.Type() == object.BIGINT_OBJ || e.Type() == object.DECIMAL_OBJ {
+			return true
+		}
+	}
+	return false
+}
+
+// extremeNumber returns the largest of elements if want is 1, and the
+// smallest if it is -1. The numbers are compared exactly, as decimals,
+// and the one returned keeps its type.
+func extremeNumber(elements []object.VintObject, want int) object.VintObject {
+	var best object.VintObject
+	var bestValue *object.Decimal
+	for _, e := range elements {
+		if !isNumber(e) {
+			return &object.Error{Message: "All elements in the array must be numbers."}
+		}
+		value, err := object.ToDecimal(e)
+		if err != nil {
+			return &object.Error{Message: err.Error()}
+		}
+		if best == nil || value.Cmp(bestValue) == want {
+			best, bestValue = e, value
+		}
+	}
+	return best
+}
+
 func log1p(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
 	if len(defs) != 0 {
 		return &object.Error{Message: "This function does not accept keyword arguments."}
@@ -599,10 +657,10 @@ func log1p(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Log1p(num)}
 }
 
@@ -613,10 +671,10 @@ func cos(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Cos(num)}
 }
 
@@ -627,10 +685,10 @@ func sin(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Sin(num)}
 }
 
@@ -641,10 +699,10 @@ func tan(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Tan(num)}
 }
 
@@ -655,10 +713,10 @@ func acos(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Acos(num)}
 }
 
@@ -669,10 +727,10 @@ func asin(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Asin(num)}
 }
 
@@ -683,10 +741,10 @@ func atan(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Atan(num)}
 }
 
@@ -697,10 +755,10 @@ func cosh(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Cosh(num)}
 }
 
@@ -711,10 +769,10 @@ func sinh(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Sinh(num)}
 }
 
@@ -725,10 +783,10 @@ func tanh(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Tanh(num)}
 }
 
@@ -739,10 +797,10 @@ func acosh(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Acosh(num)}
 }
 
@@ -753,10 +811,10 @@ func asinh(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Asinh(num)}
 }
 
@@ -767,10 +825,10 @@ func atan2(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 2 {
 		return &object.Error{Message: "This function requires exactly two arguments."}
 	}
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "Arguments must be numbers."}
 	}
-	if args[1].Type() != object.INTEGER_OBJ && args[1].Type() != object.FLOAT_OBJ {
+	if !isNumber(args[1]) {
 		return &object.Error{Message: "Arguments must be numbers."}
 	}
 
@@ -787,10 +845,10 @@ func atanh(args []object.VintObject, defs map[string]object.VintObject) object.V
 	if len(args) != 1 {
 		return &object.Error{Message: "This function requires exactly one argument."}
 	}
-	if args[0].Type() != object.FLOAT_OBJ {
-		return &object.Error{Message: "The argument must be a float."}
+	if !isNumber(args[0]) {
+		return &object.Error{Message: "The argument must be a number."}
 	}
-	num := args[0].(*object.Float).Value
+	num := extractFloatValue(args[0])
 	return &object.Float{Value: math.Atanh(num)}
 }
 
@@ -830,7 +888,7 @@ func mean(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 
 	var sum float64
 	for _, element := range arr.Elements {
-		if element.Type() != object.INTEGER_OBJ && element.Type() != object.FLOAT_OBJ {
+		if !isNumber(element) {
 			return &object.Error{Message: "All elements in the array must be numbers."}
 		}
 		sum += extractFloatValue(element)
@@ -859,7 +917,7 @@ func variance(args []object.VintObject, defs map[string]object.VintObject) objec
 	// Calculate mean first
 	var sum float64
 	for _, element := range arr.Elements {
-		if element.Type() != object.INTEGER_OBJ && element.Type() != object.FLOAT_OBJ {
+		if !isNumber(element) {
 			return &object.Error{Message: "All elements in the array must be numbers."}
 		}
 		sum += extractFloatValue(element)
@@ -906,7 +964,7 @@ func median(args []object.VintObject, defs map[string]object.VintObject) object.
 	// Extract and validate numbers
 	numbers := make([]float64, len(arr.Elements))
 	for i, element := range arr.Elements {
-		if element.Type() != object.INTEGER_OBJ && element.Type() != object.FLOAT_OBJ {
+		if !isNumber(element) {
 			return &object.Error{Message: "All elements in the array must be numbers."}
 		}
 		numbers[i] = extractFloatValue(element)
@@ -939,10 +997,10 @@ func complexNum(args []object.VintObject, defs map[string]object.VintObject) obj
 		return &object.Error{Message: "This function requires exactly two arguments (real, imaginary)."}
 	}
 
-	if args[0].Type() != object.INTEGER_OBJ && args[0].Type() != object.FLOAT_OBJ {
+	if !isNumber(args[0]) {
 		return &object.Error{Message: "Both arguments must be numbers."}
 	}
-	if args[1].Type() != object.INTEGER_OBJ && args[1].Type() != object.FLOAT_OBJ {
+	if !isNumber(args[1]) {
 		return &object.Error{Message: "Both arguments must be numbers."}
 	}
 
@@ -950,19 +1008,19 @@ func complexNum(args []object.VintObject, defs map[string]object.VintObject) obj
 	imag := extractFloatValue(args[1])
 
 	// Return as a dict with real and imag properties
-	dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	dict := object.NewDict()
 	
 	realKey := &object.String{Value: "real"}
 	imagKey := &object.String{Value: "imag"}
 	
-	dict.Pairs[realKey.HashKey()] = object.DictPair{
+	dict.SetPair(realKey.HashKey(), object.DictPair{
 		Key:   realKey,
 		Value: &object.Float{Value: real},
-	}
-	dict.Pairs[imagKey.HashKey()] = object.DictPair{
+	})
+	dict.SetPair(imagKey.HashKey(), object.DictPair{
 		Key:   imagKey,
 		Value: &object.Float{Value: imag},
-	}
+	})
 
 	return dict
 }
@@ -1005,6 +1063,51 @@ func bigint(args []object.VintObject, defs map[string]object.VintObject) object.
 	return dict
 }
 
+// decimalContext returns the places that decimal division rounds to and
+// the rounding mode decimals use by default. Given places or rounding, it
+// changes them first.
+func decimalContext(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) != 0 {
+		return ErrorMessage(
+			"math", "decimalContext",
+			"only the keyword arguments places and rounding",
+			fmt.Sprintf("%d arguments", len(args)),
+			`math.decimalContext(places=2, rounding="half_up")`,
+		)
+	}
+	places, rounding := object.DecimalContext()
+	for name, value := range defs {
+		switch name {
+		case "places":
+			n, ok := value.(*object.Integer)
+			if !ok || n.Value < 0 || n.Value > 1000 {
+				return &object.Error{Message: fmt.Sprintf("places must be an integer from 0 to 1000, got %s", value.Inspect())}
+			}
+			places = int32(n.Value)
+		case "rounding":
+			s, ok := value.(*object.String)
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("rounding must be a string, got %s", value.Type())}
+			}
+			mode, err := object.ParseRounding(s.Value)
+			if err != nil {
+				return &object.Error{Message: err.Error()}
+			}
+			rounding = mode
+		default:
+			return &object.Error{Message: fmt.Sprintf("unknown keyword argument '%s', expected places or rounding", name)}
+		}
+	}
+	object.SetDecimalContext(places, rounding)
+
+	dict := object.NewDict()
+	placesKey := &object.String{Value: "places"}
+	roundingKey := &object.String{Value: "rounding"}
+	dict.SetPair(placesKey.HashKey(), object.DictPair{Key: placesKey, Value: &object.Integer{Value: int64(places)}})
+	dict.SetPair(roundingKey.HashKey(), object.DictPair{Key: roundingKey, Value: &object.String{Value: string(rounding)}})
+	return dict
+}
+
 // Linear algebra operations
 
 func dot(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -1032,10 +1135,10 @@ func dot(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 
 	var result float64
 	for i := 0; i < len(arr1.Elements); i++ {
-		if arr1.Elements[i].Type() != object.INTEGER_OBJ && arr1.Elements[i].Type() != object.FLOAT_OBJ {
+		if !isNumber(arr1.Elements[i]) {
 			return &object.Error{Message: "All elements must be numbers."}
 		}
-		if arr2.Elements[i].Type() != object.INTEGER_OBJ && arr2.Elements[i].Type() != object.FLOAT_OBJ {
+		if !isNumber(arr2.Elements[i]) {
 			return &object.Error{Message: "All elements must be numbers."}
 		}
 		result += extractFloatValue(arr1.Elements[i]) * extractFloatValue(arr2.Elements[i])
@@ -1066,10 +1169,10 @@ func cross(args []object.VintObject, defs map[string]object.VintObject) object.V
 	// Extract components
 	var a1, a2, a3, b1, b2, b3 float64
 	for i := 0; i < 3; i++ {
-		if arr1.Elements[i].Type() != object.INTEGER_OBJ && arr1.Elements[i].Type() != object.FLOAT_OBJ {
+		if !isNumber(arr1.Elements[i]) {
 			return &object.Error{Message: "All elements must be numbers."}
 		}
-		if arr2.Elements[i].Type() != object.INTEGER_OBJ && arr2.Elements[i].Type() != object.FLOAT_OBJ {
+		if !isNumber(arr2.Elements[i]) {
 			return &object.Error{Message: "All elements must be numbers."}
 		}
 	}
@@ -1107,7 +1210,7 @@ func magnitude(args []object.VintObject, defs map[string]object.VintObject) obje
 
 	var sumOfSquares float64
 	for _, element := range arr.Elements {
-		if element.Type() != object.INTEGER_OBJ && element.Type() != object.FLOAT_OBJ {
+		if !isNumber(element) {
 			return &object.Error{Message: "All elements must be numbers."}
 		}
 		val := extractFloatValue(element)
@@ -1127,9 +1230,14 @@ func gcd(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 		return &object.Error{Message: "This function requires exactly two arguments (two integers)."}
 	}
 
-	if args[0].Type() != object.INTEGER_OBJ || args[1].Type() != object.INTEGER_OBJ {
+	if !isInteger(args[0]) || !isInteger(args[1]) {
 		return &object.Error{Message: "Both arguments must be integers."}
 	}
+	if args[0].Type() == object.BIGINT_OBJ || args[1].Type() == object.BIGINT_OBJ {
+		a, _ := object.ToBigInt(args[0])
+		b, _ := object.ToBigInt(args[1])
+		return &object.BigInt{Value: new(big.Int).GCD(nil, nil, new(big.Int).Abs(a.Value), new(big.Int).Abs(b.Value))}
+	}
 
 	a := args[0].(*object.Integer).Value
 	b := args[1].(*object.Integer).Value
@@ -1163,23 +1271,38 @@ func lcm(args []object.VintObject, defs map[string]object.VintObject) object.Vin
 		return gcdResult
 	}
 
-	a := args[0].(*object.Integer).Value
-	b := args[1].(*object.Integer).Value
-	gcdVal := gcdResult.(*object.Integer).Value
+	a, _ := object.ToBigInt(args[0])
+	b, _ := object.ToBigInt(args[1])
+	gcdVal, _ := object.ToBigInt(gcdResult)
 
-	if gcdVal == 0 {
+	if gcdVal.Value.Sign() == 0 {
 		return &object.Integer{Value: 0}
 	}
 
-	// Make positive
-	if a < 0 {
-		a = -a
+	// The product can be too large for an integer even when the result
+	// is not, so it is worked out as a bigint
+	result := new(big.Int).Mul(new(big.Int).Abs(a.Value), new(big.Int).Abs(b.Value))
+	result.Quo(result, gcdVal.Value)
+	if args[0].Type() == object.BIGINT_OBJ || args[1].Type() == object.BIGINT_OBJ {
+		return &object.BigInt{Value: result}
 	}
-	if b < 0 {
-		b = -b
+	return object.IntegerFromBig(result)
+}
+
+// isNumber reports whether obj is a number of any kind. Functions that
+// work on floats convert a BigInt or a Decimal with extractFloatValue, so
+// it keeps only the precision of a float.
+func isNumber(obj object.VintObject) bool {
+	switch obj.Type() {
+	case object.INTEGER_OBJ, object.FLOAT_OBJ, object.BIGINT_OBJ, object.DECIMAL_OBJ:
+		return true
 	}
+	return false
+}
 
-	return &object.Integer{Value: (a * b) / gcdVal}
+// isInteger reports whether obj is an integer of either size.
+func isInteger(obj object.VintObject) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
 }
 
 func clamp(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -1191,7 +1314,7 @@ func clamp(args []object.VintObject, defs map[string]object.VintObject) object.V
 	}
 
 	for i := 0; i < 3; i++ {
-		if args[i].Type() != object.INTEGER_OBJ && args[i].Type() != object.FLOAT_OBJ {
+		if !isNumber(args[i]) {
 			return &object.Error{Message: "All arguments must be numbers."}
 		}
 	}
@@ -1222,7 +1345,7 @@ func lerp(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	}
 
 	for i := 0; i < 3; i++ {
-		if args[i].Type() != object.INTEGER_OBJ && args[i].Type() != object.FLOAT_OBJ {
+		if !isNumber(args[i]) {
 			return &object.Error{Message: "All arguments must be numbers."}
 		}
 	}
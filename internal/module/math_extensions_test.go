@@ -1,6 +1,7 @@
 package module
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/vintlang/vintlang/internal/object"
@@ -464,3 +465,55 @@ func TestBigint(t *testing.T) {
 		})
 	}
 }
+
+func TestBigNumberArguments(t *testing.T) {
+	huge, _ := object.ParseBigInt("1180591620717411303424") // 2 ** 70
+	dec, _ := object.ParseDecimal("2.25")
+	none := map[string]object.VintObject{}
+
+	tests := []struct {
+		name     string
+		fn       object.ModuleFunction
+		args     []object.VintObject
+		expected string
+	}{
+		// Functions that work in floats convert big numbers to floats
+		{"sqrt of a bigint", sqrt, []object.VintObject{huge}, "34359738368"},
+		{"sqrt of a decimal", sqrt, []object.VintObject{dec}, "1.5"},
+		{"log2 of a bigint", log2, []object.VintObject{huge}, "70"},
+		{"log10 of a decimal", log10, []object.VintObject{&object.Decimal{Coef: big.NewInt(10000), Scale: 1}}, "3"},
+		{"hypot of decimals", hypot, []object.VintObject{dec, dec}, "3.181980515339464"},
+		{"mean of big numbers", mean, []object.VintObject{&object.Array{Elements: []object.VintObject{dec, &object.Integer{Value: 1}}}}, "1.625"},
+		// Rounding keeps a bigint exact
+		{"round of a bigint", round, []object.VintObject{huge}, "1180591620717411303424"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.fn(tt.args, none)
+			if result.Inspect() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result.Inspect())
+			}
+		})
+	}
+}
+
+func TestDecimalContext(t *testing.T) {
+	defer object.SetDecimalContext(object.DecimalContext())
+
+	result := decimalContext(nil, map[string]object.VintObject{
+		"places":   &object.Integer{Value: 2},
+		"rounding": &object.String{Value: "half_up"},
+	})
+	if got := result.Inspect(); got != "{places: 2, rounding: half_up}" {
+		t.Fatalf("unexpected context: %s", got)
+	}
+	if places, rounding := object.DecimalContext(); places != 2 || rounding != object.RoundHalfUp {
+		t.Errorf("context not changed: %d %s", places, rounding)
+	}
+
+	result = decimalContext(nil, map[string]object.VintObject{"rounding": &object.String{Value: "sideways"}})
+	if result.Type() != object.ERROR_OBJ {
+		t.Errorf("expected an error for an unknown rounding mode, got %s", result.Inspect())
+	}
+}
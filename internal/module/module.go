@@ -59,6 +59,8 @@ func init() {
 	Mapper["excel"] = &object.Module{Name: "excel", Functions: ExcelFunctions}
 	Mapper["fmt"] = &object.Module{Name: "fmt", Functions: FmtFunctions}
 	Mapper["make"] = &object.Module{Name: "make", Functions: MakeFunctions}
+	Mapper["assert"] = &object.Module{Name: "assert", Functions: AssertFunctions}
+	Mapper["sync"] = &object.Module{Name: "sync", Functions: SyncFunctions}
 }
 
 // ErrorMessage formats an error message for module functions
@@ -121,11 +121,11 @@ func fetchAllMySQL(args []object.VintObject, defs map[string]object.VintObject)
 			return &object.Error{Message: fmt.Sprintf("Failed to scan row: %s", err)}
 		}
 
-		row := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+		row := object.NewDict()
 		for i, col := range cols {
 			key := &object.String{Value: col}
 			value := convertMySQLToObject(values[i])
-			row.Pairs[key.HashKey()] = object.DictPair{Key: key, Value: value}
+			row.SetPair(key.HashKey(), object.DictPair{Key: key, Value: value})
 		}
 
 		result = append(result, row)
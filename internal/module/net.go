@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/utils"
 )
 
 var NetFunctions = map[string]object.ModuleFunction{}
@@ -108,7 +109,7 @@ func handleRequest(method string, args []object.VintObject, defs map[string]obje
 	}
 
 	if headers != nil {
-		for _, val := range headers.Pairs {
+		for _, val := range headers.OrderedPairs() {
 			req.Header.Set(val.Key.Inspect(), val.Value.Inspect())
 		}
 	}
@@ -185,7 +186,7 @@ func getRequest(args []object.VintObject, defs map[string]object.VintObject) obj
 		}
 
 		if headers != nil {
-			for _, val := range headers.Pairs {
+			for _, val := range headers.OrderedPairs() {
 				req.Header.Set(val.Key.Inspect(), val.Value.Inspect())
 			}
 		}
@@ -287,7 +288,7 @@ func postRequest(args []object.VintObject, defs map[string]object.VintObject) ob
 			return &object.Error{Message: "Failed to make the request"}
 		}
 		if headers != nil {
-			for _, val := range headers.Pairs {
+			for _, val := range headers.OrderedPairs() {
 				req.Header.Set(val.Key.Inspect(), val.Value.Inspect())
 			}
 		}
@@ -364,7 +365,7 @@ func putRequest(args []object.VintObject, defs map[string]object.VintObject) obj
 			return &object.Error{Message: "Failed to make the request"}
 		}
 		if headers != nil {
-			for _, val := range headers.Pairs {
+			for _, val := range headers.OrderedPairs() {
 				req.Header.Set(val.Key.Inspect(), val.Value.Inspect())
 			}
 		}
@@ -463,7 +464,7 @@ func fetchRequest(args []object.VintObject, defs map[string]object.VintObject) o
 	}
 
 	if headers != nil {
-		for _, val := range headers.Pairs {
+		for _, val := range headers.OrderedPairs() {
 			req.Header.Set(val.Key.Inspect(), val.Value.Inspect())
 		}
 	}
@@ -481,11 +482,12 @@ func fetchRequest(args []object.VintObject, defs map[string]object.VintObject) o
 	}
 
 	// Build response headers dict
-	respHeaderPairs := make(map[object.HashKey]object.DictPair)
-	for key, vals := range resp.Header {
+	respHeaderPairs := object.NewDict()
+	for _, key := range utils.SortedKeys(resp.Header) {
+		vals := resp.Header[key]
 		k := &object.String{Value: key}
 		v := &object.String{Value: strings.Join(vals, ", ")}
-		respHeaderPairs[k.HashKey()] = object.DictPair{Key: k, Value: v}
+		respHeaderPairs.SetPair(k.HashKey(), object.DictPair{Key: k, Value: v})
 	}
 
 	// Build result dict with status, headers, body
@@ -493,19 +495,19 @@ func fetchRequest(args []object.VintObject, defs map[string]object.VintObject) o
 	headersKey := &object.String{Value: "headers"}
 	bodyKey := &object.String{Value: "body"}
 
-	resultPairs := make(map[object.HashKey]object.DictPair)
-	resultPairs[statusKey.HashKey()] = object.DictPair{
+	resultPairs := object.NewDict()
+	resultPairs.SetPair(statusKey.HashKey(), object.DictPair{
 		Key:   statusKey,
 		Value: &object.Integer{Value: int64(resp.StatusCode)},
-	}
-	resultPairs[headersKey.HashKey()] = object.DictPair{
+	})
+	resultPairs.SetPair(headersKey.HashKey(), object.DictPair{
 		Key:   headersKey,
-		Value: &object.Dict{Pairs: respHeaderPairs},
-	}
-	resultPairs[bodyKey.HashKey()] = object.DictPair{
+		Value: respHeaderPairs,
+	})
+	resultPairs.SetPair(bodyKey.HashKey(), object.DictPair{
 		Key:   bodyKey,
 		Value: &object.String{Value: string(respBody)},
-	}
+	})
 
-	return &object.Dict{Pairs: resultPairs}
+	return resultPairs
 }
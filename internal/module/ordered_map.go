@@ -0,0 +1,239 @@
+package module
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vintlang/vintlang/internal/object"
+	"gopkg.in/yaml.v3"
+)
+
+// orderedMap is a JSON/YAML object that keeps its keys in order. Dicts are
+// converted to it before encoding, and documents are decoded into it, so a
+// dict's keys are written in insertion order and read back in document
+// order instead of being sorted or shuffled by a Go map.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]any)}
+}
+
+// Set adds or replaces a key. A replaced key keeps its position.
+func (m *orderedMap) Set(key string, value any) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) Get(key string) (any, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *orderedMap) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range m.keys {
+		keyNode, valueNode := &yaml.Node{}, &yaml.Node{}
+		if err := keyNode.Encode(key); err != nil {
+			return nil, err
+		}
+		if err := valueNode.Encode(m.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node, nil
+}
+
+// dictKey is the object key a dict key is written under
+func dictKey(key object.VintObject) string {
+	if s, ok := key.(*object.String); ok {
+		return s.Value
+	}
+	return key.Inspect()
+}
+
+// decodeOrderedJSON works like json.Unmarshal into an any, except that
+// objects become *orderedMap and numbers stay json.Number, so that large
+// integers and decimals keep every digit.
+func decodeOrderedJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value")
+	}
+	return value, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		m := newOrderedMap()
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return nil, fmt.Errorf("object key must be a string")
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(key, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	return tok, nil
+}
+
+// decodeOrderedYAML works like yaml.Unmarshal into an any, except that
+// mappings become *orderedMap with their keys turned into strings.
+func decodeOrderedYAML(data []byte) (any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	return yamlNodeValue(&node)
+}
+
+func yamlNodeValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlNodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case yaml.MappingNode:
+		m := newOrderedMap()
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Tag == "!!merge" {
+				if err := mergeYAMLNode(m, node.Content[i+1]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			var key any
+			if err := node.Content[i].Decode(&key); err != nil {
+				return nil, err
+			}
+			value, err := yamlNodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m.Set(yamlKeyString(key), value)
+		}
+		return m, nil
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// mergeYAMLNode applies a "<<" merge key: keys from the merged mapping (or
+// sequence of mappings) are added unless the mapping already has them.
+func mergeYAMLNode(m *orderedMap, node *yaml.Node) error {
+	value, err := yamlNodeValue(node)
+	if err != nil {
+		return err
+	}
+	sources := []any{value}
+	if list, ok := value.([]any); ok {
+		sources = list
+	}
+	for _, source := range sources {
+		merged, ok := source.(*orderedMap)
+		if !ok {
+			return fmt.Errorf("merge key must refer to a mapping")
+		}
+		for _, key := range merged.keys {
+			if _, exists := m.values[key]; !exists {
+				m.Set(key, merged.values[key])
+			}
+		}
+	}
+	return nil
+}
+
+func yamlKeyString(key any) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case int, int64:
+		return fmt.Sprintf("%d", k)
+	case float64:
+		return fmt.Sprintf("%g", k)
+	}
+	return fmt.Sprintf("%v", key)
+}
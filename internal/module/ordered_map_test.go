@@ -0,0 +1,71 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+func TestJSONKeepsKeyOrder(t *testing.T) {
+	input := `{"zeta":1,"alpha":{"y":true,"x":[1,{"q":"a","b":null}]},"mid":"m"}`
+	decoded := decode([]object.VintObject{&object.String{Value: input}}, map[string]object.VintObject{})
+	if got := decoded.Inspect(); got != "{zeta: 1, alpha: {y: true, x: [1, {q: a, b: null}]}, mid: m}" {
+		t.Fatalf("decode lost order: got %q", got)
+	}
+
+	encoded := encode([]object.VintObject{decoded}, map[string]object.VintObject{})
+	if got := encoded.Inspect(); got != input {
+		t.Errorf("round trip changed output: got %q, want %q", got, input)
+	}
+
+	trailing := decode([]object.VintObject{&object.String{Value: `{"a":1} x`}}, map[string]object.VintObject{})
+	if _, ok := trailing.(*object.Error); !ok {
+		t.Errorf("expected error for trailing data, got %s", trailing.Inspect())
+	}
+}
+
+func TestYAMLKeepsKeyOrder(t *testing.T) {
+	input := "zeta: 1\nalpha:\n    \"y\": true\n    x:\n        - 1\n        - q: a\nmid: m\n"
+	decoded := yamlDecode([]object.VintObject{&object.String{Value: input}}, map[string]object.VintObject{})
+	if got := decoded.Inspect(); got != "{zeta: 1, alpha: {y: true, x: [1, {q: a}]}, mid: m}" {
+		t.Fatalf("decode lost order: got %q", got)
+	}
+
+	encoded := yamlEncode([]object.VintObject{decoded}, map[string]object.VintObject{})
+	if got := encoded.Inspect(); got != input {
+		t.Errorf("round trip changed output: got %q, want %q", got, input)
+	}
+
+	merged := yamlDecode([]object.VintObject{&object.String{Value: "base: &b\n  z: 1\n  a: 2\nchild:\n  <<: *b\n  a: 3\n"}}, map[string]object.VintObject{})
+	if got := merged.Inspect(); got != "{base: {z: 1, a: 2}, child: {z: 1, a: 3}}" {
+		t.Errorf("merge key wrong: got %q", got)
+	}
+}
+
+func TestJSONNumbers(t *testing.T) {
+	input := `{"count":3,"id":123456789012345678901234567890,"price":19.99}`
+	decoded := decode([]object.VintObject{&object.String{Value: input}}, map[string]object.VintObject{})
+	dict := decoded.(*object.Dict)
+	types := map[string]object.VintObjectType{"count": object.INTEGER_OBJ, "id": object.BIGINT_OBJ, "price": object.FLOAT_OBJ}
+	for _, pair := range dict.OrderedPairs() {
+		if want := types[pair.Key.Inspect()]; pair.Value.Type() != want {
+			t.Errorf("%s decoded as %s, want %s", pair.Key.Inspect(), pair.Value.Type(), want)
+		}
+	}
+
+	decimals := decode([]object.VintObject{&object.String{Value: input}}, map[string]object.VintObject{"decimal": &object.Boolean{Value: true}})
+	price := decimals.(*object.Dict).OrderedPairs()[2].Value
+	if price.Type() != object.DECIMAL_OBJ || price.Inspect() != "19.99" {
+		t.Errorf("expected decimal 19.99, got %s %s", price.Type(), price.Inspect())
+	}
+
+	encoded := encode([]object.VintObject{decimals}, map[string]object.VintObject{})
+	if got := encoded.Inspect(); got != input {
+		t.Errorf("round trip changed numbers: got %q, want %q", got, input)
+	}
+
+	bad := decode([]object.VintObject{&object.String{Value: input}}, map[string]object.VintObject{"exact": &object.Boolean{Value: true}})
+	if _, ok := bad.(*object.Error); !ok {
+		t.Errorf("expected error for an unknown keyword argument, got %s", bad.Inspect())
+	}
+}
@@ -1078,12 +1078,11 @@ func lookupEnv(args []object.VintObject, defs map[string]object.VintObject) obje
 		Value: &object.Boolean{Value: exists},
 	}
 
-	pairs := map[object.HashKey]object.DictPair{
-		(&object.String{Value: "value"}).HashKey():  valuePair,
-		(&object.String{Value: "exists"}).HashKey(): existsPair,
-	}
+	pairs := object.NewDict()
+	pairs.SetPair((&object.String{Value: "value"}).HashKey(), valuePair)
+	pairs.SetPair((&object.String{Value: "exists"}).HashKey(), existsPair)
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // expand expands variables in string using provided mapping function
@@ -1170,15 +1169,14 @@ func stat(args []object.VintObject, defs map[string]object.VintObject) object.Vi
 	modTimePair := object.DictPair{Key: &object.String{Value: "modTime"}, Value: &object.Integer{Value: info.ModTime().Unix()}}
 	isDirPair := object.DictPair{Key: &object.String{Value: "isDir"}, Value: &object.Boolean{Value: info.IsDir()}}
 
-	pairs := map[object.HashKey]object.DictPair{
-		(&object.String{Value: "name"}).HashKey():    namePair,
-		(&object.String{Value: "size"}).HashKey():    sizePair,
-		(&object.String{Value: "mode"}).HashKey():    modePair,
-		(&object.String{Value: "modTime"}).HashKey(): modTimePair,
-		(&object.String{Value: "isDir"}).HashKey():   isDirPair,
-	}
+	pairs := object.NewDict()
+	pairs.SetPair((&object.String{Value: "name"}).HashKey(), namePair)
+	pairs.SetPair((&object.String{Value: "size"}).HashKey(), sizePair)
+	pairs.SetPair((&object.String{Value: "mode"}).HashKey(), modePair)
+	pairs.SetPair((&object.String{Value: "modTime"}).HashKey(), modTimePair)
+	pairs.SetPair((&object.String{Value: "isDir"}).HashKey(), isDirPair)
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // lstat returns file info (doesn't follow symlinks)
@@ -1213,15 +1211,14 @@ func lstat(args []object.VintObject, defs map[string]object.VintObject) object.V
 	modTimePair := object.DictPair{Key: &object.String{Value: "modTime"}, Value: &object.Integer{Value: info.ModTime().Unix()}}
 	isDirPair := object.DictPair{Key: &object.String{Value: "isDir"}, Value: &object.Boolean{Value: info.IsDir()}}
 
-	pairs := map[object.HashKey]object.DictPair{
-		(&object.String{Value: "name"}).HashKey():    namePair,
-		(&object.String{Value: "size"}).HashKey():    sizePair,
-		(&object.String{Value: "mode"}).HashKey():    modePair,
-		(&object.String{Value: "modTime"}).HashKey(): modTimePair,
-		(&object.String{Value: "isDir"}).HashKey():   isDirPair,
-	}
+	pairs := object.NewDict()
+	pairs.SetPair((&object.String{Value: "name"}).HashKey(), namePair)
+	pairs.SetPair((&object.String{Value: "size"}).HashKey(), sizePair)
+	pairs.SetPair((&object.String{Value: "mode"}).HashKey(), modePair)
+	pairs.SetPair((&object.String{Value: "modTime"}).HashKey(), modTimePair)
+	pairs.SetPair((&object.String{Value: "isDir"}).HashKey(), isDirPair)
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // readDir reads directory contents
@@ -1257,22 +1254,21 @@ func readDir(args []object.VintObject, defs map[string]object.VintObject) object
 		namePair := object.DictPair{Key: &object.String{Value: "name"}, Value: &object.String{Value: entry.Name()}}
 		isDirPair := object.DictPair{Key: &object.String{Value: "isDir"}, Value: &object.Boolean{Value: entry.IsDir()}}
 
-		pairs := map[object.HashKey]object.DictPair{
-			(&object.String{Value: "name"}).HashKey():  namePair,
-			(&object.String{Value: "isDir"}).HashKey(): isDirPair,
-		}
+		pairs := object.NewDict()
+		pairs.SetPair((&object.String{Value: "name"}).HashKey(), namePair)
+		pairs.SetPair((&object.String{Value: "isDir"}).HashKey(), isDirPair)
 
 		if info != nil {
 			sizePair := object.DictPair{Key: &object.String{Value: "size"}, Value: &object.Integer{Value: info.Size()}}
 			modePair := object.DictPair{Key: &object.String{Value: "mode"}, Value: &object.Integer{Value: int64(info.Mode())}}
 			modTimePair := object.DictPair{Key: &object.String{Value: "modTime"}, Value: &object.Integer{Value: info.ModTime().Unix()}}
 
-			pairs[(&object.String{Value: "size"}).HashKey()] = sizePair
-			pairs[(&object.String{Value: "mode"}).HashKey()] = modePair
-			pairs[(&object.String{Value: "modTime"}).HashKey()] = modTimePair
+			pairs.SetPair((&object.String{Value: "size"}).HashKey(), sizePair)
+			pairs.SetPair((&object.String{Value: "mode"}).HashKey(), modePair)
+			pairs.SetPair((&object.String{Value: "modTime"}).HashKey(), modTimePair)
 		}
 
-		entryObjects[i] = &object.Dict{Pairs: pairs}
+		entryObjects[i] = pairs
 	}
 
 	return &object.Array{Elements: entryObjects}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/utils"
 )
 
 var RedisFunctions = map[string]object.ModuleFunction{}
@@ -716,13 +717,14 @@ func redisHGetAll(args []object.VintObject, defs map[string]object.VintObject) o
 		return &object.Error{Message: fmt.Sprintf("Redis HGETALL failed: %s", err)}
 	}
 
-	pairs := make(map[object.HashKey]object.DictPair)
-	for field, value := range result {
+	pairs := object.NewDict()
+	for _, field := range utils.SortedKeys(result) {
+		value := result[field]
 		fieldKey := (&object.String{Value: field}).HashKey()
-		pairs[fieldKey] = object.DictPair{Key: &object.String{Value: field}, Value: &object.String{Value: value}}
+		pairs.SetPair(fieldKey, object.DictPair{Key: &object.String{Value: field}, Value: &object.String{Value: value}})
 	}
 
-	return &object.Dict{Pairs: pairs}
+	return pairs
 }
 
 // redisHDel deletes one or more hash fields
@@ -110,11 +110,11 @@ func fetchAll(args []object.VintObject, defs map[string]object.VintObject) objec
 		if err := rows.Scan(scanArgs...); err != nil {
 			return &object.Error{Message: fmt.Sprintf("Failed to scan row: %s", err)}
 		}
-		row := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+		row := object.NewDict()
 		for i, col := range cols {
 			key := &object.String{Value: col}
 			value := convertToObject(values[i])
-			row.Pairs[key.HashKey()] = object.DictPair{Key: key, Value: value}
+			row.SetPair(key.HashKey(), object.DictPair{Key: key, Value: value})
 		}
 		result = append(result, row)
 	}
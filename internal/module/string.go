@@ -34,7 +34,7 @@ func init() {
 
 	StringFunctions["replace"] = replace
 	StringFuncTypes["replace"] = []ast.Type{bt("string"), bt("string"), bt("string")}
-	StringFuncReturns["length"] = bt("string")
+	StringFuncReturns["replace"] = bt("string")
 
 	StringFunctions["split"] = split
 	StringFuncTypes["split"] = []ast.Type{bt("string"), bt("string")}
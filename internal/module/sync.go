@@ -0,0 +1,58 @@
+package module
+
+import (
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+var SyncFunctions = map[string]object.ModuleFunction{}
+
+func init() {
+	SyncFunctions["mutex"] = newMutex
+	SyncFunctions["rwMutex"] = newRWMutex
+	SyncFunctions["waitGroup"] = newWaitGroup
+	SyncFunctions["once"] = newOnce
+	SyncFunctions["atomic"] = newAtomic
+}
+
+func newMutex(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) != 0 {
+		return ErrorMessage("sync", "mutex", "No arguments", formatArgs(args), `let mu = sync.mutex()`)
+	}
+	return &object.Mutex{}
+}
+
+func newRWMutex(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) != 0 {
+		return ErrorMessage("sync", "rwMutex", "No arguments", formatArgs(args), `let mu = sync.rwMutex()`)
+	}
+	return &object.RWMutex{}
+}
+
+func newWaitGroup(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) != 0 {
+		return ErrorMessage("sync", "waitGroup", "No arguments", formatArgs(args), `let wg = sync.waitGroup()`)
+	}
+	return &object.WaitGroup{}
+}
+
+func newOnce(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) != 0 {
+		return ErrorMessage("sync", "once", "No arguments", formatArgs(args), `let setup = sync.once()`)
+	}
+	return &object.Once{}
+}
+
+func newAtomic(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
+	if len(args) > 1 {
+		return ErrorMessage("sync", "atomic", "an optional initial integer", formatArgs(args), `let counter = sync.atomic(0)`)
+	}
+	initial := int64(0)
+	if len(args) == 1 {
+		n, ok := args[0].(*object.Integer)
+		if !ok {
+			return ErrorMessage("sync", "atomic", "an optional initial integer", formatArgs(args), `let counter = sync.atomic(0)`)
+		}
+		initial = n.Value
+	}
+	return object.NewAtomicInteger(initial)
+}
@@ -62,31 +62,31 @@ func getMemInfo(args []object.VintObject, defs map[string]object.VintObject) obj
 		return &object.Error{Message: fmt.Sprintf("Failed to get memory info: %v", err)}
 	}
 
-	memInfoMap := make(map[object.HashKey]object.DictPair)
+	memInfoMap := object.NewDict()
 
 	// Add memory information to the map
-	memInfoMap[(&object.String{Value: "total"}).HashKey()] = object.DictPair{
+	memInfoMap.SetPair((&object.String{Value: "total"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "total"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(memStat.Total)/1024/1024/1024)},
-	}
-	memInfoMap[(&object.String{Value: "available"}).HashKey()] = object.DictPair{
+	})
+	memInfoMap.SetPair((&object.String{Value: "available"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "available"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(memStat.Available)/1024/1024/1024)},
-	}
-	memInfoMap[(&object.String{Value: "used"}).HashKey()] = object.DictPair{
+	})
+	memInfoMap.SetPair((&object.String{Value: "used"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "used"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(memStat.Used)/1024/1024/1024)},
-	}
-	memInfoMap[(&object.String{Value: "free"}).HashKey()] = object.DictPair{
+	})
+	memInfoMap.SetPair((&object.String{Value: "free"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "free"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(memStat.Free)/1024/1024/1024)},
-	}
-	memInfoMap[(&object.String{Value: "percent"}).HashKey()] = object.DictPair{
+	})
+	memInfoMap.SetPair((&object.String{Value: "percent"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "percent"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f", memStat.UsedPercent)},
-	}
+	})
 
-	return &object.Dict{Pairs: memInfoMap}
+	return memInfoMap
 }
 
 func getCPUInfo(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -109,31 +109,31 @@ func getCPUInfo(args []object.VintObject, defs map[string]object.VintObject) obj
 		return &object.Error{Message: fmt.Sprintf("Failed to get CPU usage: %v", err)}
 	}
 
-	cpuInfoMap := make(map[object.HashKey]object.DictPair)
+	cpuInfoMap := object.NewDict()
 
 	if len(cpuInfo) > 0 {
-		cpuInfoMap[(&object.String{Value: "cores"}).HashKey()] = object.DictPair{
+		cpuInfoMap.SetPair((&object.String{Value: "cores"}).HashKey(), object.DictPair{
 			Key:   &object.String{Value: "cores"},
 			Value: &object.String{Value: strconv.Itoa(int(cpuInfo[0].Cores))},
-		}
-		cpuInfoMap[(&object.String{Value: "model"}).HashKey()] = object.DictPair{
+		})
+		cpuInfoMap.SetPair((&object.String{Value: "model"}).HashKey(), object.DictPair{
 			Key:   &object.String{Value: "model"},
 			Value: &object.String{Value: cpuInfo[0].ModelName},
-		}
-		cpuInfoMap[(&object.String{Value: "frequency"}).HashKey()] = object.DictPair{
+		})
+		cpuInfoMap.SetPair((&object.String{Value: "frequency"}).HashKey(), object.DictPair{
 			Key:   &object.String{Value: "frequency"},
 			Value: &object.String{Value: fmt.Sprintf("%.2f MHz", cpuInfo[0].Mhz)},
-		}
+		})
 	}
 
 	if len(cpuPercent) > 0 {
-		cpuInfoMap[(&object.String{Value: "usage"}).HashKey()] = object.DictPair{
+		cpuInfoMap.SetPair((&object.String{Value: "usage"}).HashKey(), object.DictPair{
 			Key:   &object.String{Value: "usage"},
 			Value: &object.String{Value: fmt.Sprintf("%.2f", cpuPercent[0])},
-		}
+		})
 	}
 
-	return &object.Dict{Pairs: cpuInfoMap}
+	return cpuInfoMap
 }
 
 func getDiskInfo(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -151,26 +151,26 @@ func getDiskInfo(args []object.VintObject, defs map[string]object.VintObject) ob
 		return &object.Error{Message: fmt.Sprintf("Failed to get disk info: %v", err)}
 	}
 
-	diskInfoMap := make(map[object.HashKey]object.DictPair)
+	diskInfoMap := object.NewDict()
 
-	diskInfoMap[(&object.String{Value: "total"}).HashKey()] = object.DictPair{
+	diskInfoMap.SetPair((&object.String{Value: "total"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "total"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(diskStat.Total)/1024/1024/1024)},
-	}
-	diskInfoMap[(&object.String{Value: "used"}).HashKey()] = object.DictPair{
+	})
+	diskInfoMap.SetPair((&object.String{Value: "used"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "used"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(diskStat.Used)/1024/1024/1024)},
-	}
-	diskInfoMap[(&object.String{Value: "free"}).HashKey()] = object.DictPair{
+	})
+	diskInfoMap.SetPair((&object.String{Value: "free"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "free"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f GB", float64(diskStat.Free)/1024/1024/1024)},
-	}
-	diskInfoMap[(&object.String{Value: "percent"}).HashKey()] = object.DictPair{
+	})
+	diskInfoMap.SetPair((&object.String{Value: "percent"}).HashKey(), object.DictPair{
 		Key:   &object.String{Value: "percent"},
 		Value: &object.String{Value: fmt.Sprintf("%.2f", diskStat.UsedPercent)},
-	}
+	})
 
-	return &object.Dict{Pairs: diskInfoMap}
+	return diskInfoMap
 }
 
 func getNetInfo(args []object.VintObject, defs map[string]object.VintObject) object.VintObject {
@@ -192,24 +192,24 @@ func getNetInfo(args []object.VintObject, defs map[string]object.VintObject) obj
 
 	for _, iface := range interfaces {
 		if len(iface.Addrs) > 0 {
-			ifaceMap := make(map[object.HashKey]object.DictPair)
+			ifaceMap := object.NewDict()
 
-			ifaceMap[(&object.String{Value: "name"}).HashKey()] = object.DictPair{
+			ifaceMap.SetPair((&object.String{Value: "name"}).HashKey(), object.DictPair{
 				Key:   &object.String{Value: "name"},
 				Value: &object.String{Value: iface.Name},
-			}
+			})
 
 			var addrs []object.VintObject
 			for _, addr := range iface.Addrs {
 				addrs = append(addrs, &object.String{Value: addr.Addr})
 			}
 
-			ifaceMap[(&object.String{Value: "addrs"}).HashKey()] = object.DictPair{
+			ifaceMap.SetPair((&object.String{Value: "addrs"}).HashKey(), object.DictPair{
 				Key:   &object.String{Value: "addrs"},
 				Value: &object.Array{Elements: addrs},
-			}
+			})
 
-			networkInterfaces = append(networkInterfaces, &object.Dict{Pairs: ifaceMap})
+			networkInterfaces = append(networkInterfaces, ifaceMap)
 		}
 	}
 
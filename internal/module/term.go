@@ -241,7 +241,7 @@ func termStyle(args []object.VintObject, defs map[string]object.VintObject) obje
 			return &object.Error{Message: "style options must be a dictionary"}
 		}
 
-		for _, pair := range options.Pairs {
+		for _, pair := range options.OrderedPairs() {
 			key := pair.Key.(*object.String).Value
 			value := pair.Value.(*object.String).Value
 
@@ -323,18 +323,12 @@ func termGetSize(args []object.VintObject, defs map[string]object.VintObject) ob
 	width := 80  // Default width
 	height := 24 // Default height
 
-	return &object.Dict{
-		Pairs: map[object.HashKey]object.DictPair{
-			{Type: object.STRING_OBJ, Value: 0}: {
-				Key:   &object.String{Value: "width"},
-				Value: &object.Integer{Value: int64(width)},
-			},
-			{Type: object.STRING_OBJ, Value: 1}: {
-				Key:   &object.String{Value: "height"},
-				Value: &object.Integer{Value: int64(height)},
-			},
-		},
-	}
+	size := object.NewDict()
+	widthKey := &object.String{Value: "width"}
+	heightKey := &object.String{Value: "height"}
+	size.SetPair(widthKey.HashKey(), object.DictPair{Key: widthKey, Value: &object.Integer{Value: int64(width)}})
+	size.SetPair(heightKey.HashKey(), object.DictPair{Key: heightKey, Value: &object.Integer{Value: int64(height)}})
+	return size
 }
 
 // termInput gets user input with a prompt
@@ -773,7 +767,7 @@ func termLayout(args []object.VintObject, defs map[string]object.VintObject) obj
 
 	// Parse layout configuration
 	layout := lipgloss.NewStyle()
-	for _, pair := range config.Pairs {
+	for _, pair := range config.OrderedPairs() {
 		key := pair.Key.(*object.String).Value
 		value := pair.Value.(*object.String).Value
 
@@ -813,7 +807,7 @@ func termGrid(args []object.VintObject, defs map[string]object.VintObject) objec
 
 	// Parse grid configuration
 	columns := 3 // default
-	for _, pair := range config.Pairs {
+	for _, pair := range config.OrderedPairs() {
 		key := pair.Key.(*object.String).Value
 		if key == "columns" {
 			columns = int(pair.Value.(*object.Integer).Value)
@@ -851,7 +845,7 @@ func termTabs(args []object.VintObject, defs map[string]object.VintObject) objec
 
 	// Create tabs
 	var tabNames []string
-	for _, pair := range tabs.Pairs {
+	for _, pair := range tabs.OrderedPairs() {
 		tabNames = append(tabNames, pair.Key.(*object.String).Value)
 	}
 
@@ -876,7 +870,7 @@ func termAccordion(args []object.VintObject, defs map[string]object.VintObject)
 
 	// Create sections
 	var content []string
-	for _, pair := range sections.Pairs {
+	for _, pair := range sections.OrderedPairs() {
 		title := pair.Key.(*object.String).Value
 		content = append(content, fmt.Sprintf("▼ %s", title))
 		content = append(content, pair.Value.Inspect())
@@ -903,10 +897,10 @@ func termTree(args []object.VintObject, defs map[string]object.VintObject) objec
 
 	// Create tree structure
 	var content []string
-	for _, pair := range tree.Pairs {
+	for _, pair := range tree.OrderedPairs() {
 		content = append(content, fmt.Sprintf("├─ %s", pair.Key.(*object.String).Value))
 		if subTree, ok := pair.Value.(*object.Dict); ok {
-			for _, subPair := range subTree.Pairs {
+			for _, subPair := range subTree.OrderedPairs() {
 				content = append(content, fmt.Sprintf("│  └─ %s", subPair.Key.(*object.String).Value))
 			}
 		}
@@ -1015,7 +1009,7 @@ func termCalendar(args []object.VintObject, defs map[string]object.VintObject) o
 	year := now.Year()
 	month := now.Month()
 
-	for _, pair := range config.Pairs {
+	for _, pair := range config.OrderedPairs() {
 		key := pair.Key.(*object.String).Value
 		if key == "year" {
 			year = int(pair.Value.(*object.Integer).Value)
@@ -1063,7 +1057,7 @@ func termTimeline(args []object.VintObject, defs map[string]object.VintObject) o
 	for i, event := range events.Elements {
 		if eventDict, ok := event.(*object.Dict); ok {
 			var title, time string
-			for _, pair := range eventDict.Pairs {
+			for _, pair := range eventDict.OrderedPairs() {
 				key := pair.Key.(*object.String).Value
 				if key == "title" {
 					title = pair.Value.(*object.String).Value
@@ -1094,7 +1088,7 @@ func termKanban(args []object.VintObject, defs map[string]object.VintObject) obj
 
 	// Create kanban board
 	var board []string
-	for _, pair := range columns.Pairs {
+	for _, pair := range columns.OrderedPairs() {
 		title := pair.Key.(*object.String).Value
 		items := pair.Value.(*object.Array)
 
@@ -1154,7 +1148,7 @@ func termModal(args []object.VintObject, defs map[string]object.VintObject) obje
 
 	// Create modal content
 	var title, content string
-	for _, pair := range config.Pairs {
+	for _, pair := range config.OrderedPairs() {
 		key := pair.Key.(*object.String).Value
 		if key == "title" {
 			title = pair.Value.(*object.String).Value
@@ -1249,7 +1243,7 @@ func termCard(args []object.VintObject, defs map[string]object.VintObject) objec
 
 	// Create card content
 	var title, content string
-	for _, pair := range config.Pairs {
+	for _, pair := range config.OrderedPairs() {
 		key := pair.Key.(*object.String).Value
 		if key == "title" {
 			title = pair.Value.(*object.String).Value
@@ -1306,7 +1300,7 @@ func termForm(args []object.VintObject, defs map[string]object.VintObject) objec
 
 	// Create form fields
 	var form []string
-	for _, pair := range config.Pairs {
+	for _, pair := range config.OrderedPairs() {
 		field := pair.Key.(*object.String).Value
 		form = append(form, fmt.Sprintf("%s: [          ]", field))
 	}
@@ -1359,7 +1353,7 @@ func termDashboard(args []object.VintObject, defs map[string]object.VintObject)
 
 	// Create dashboard widgets
 	var dashboard []string
-	for _, pair := range widgets.Pairs {
+	for _, pair := range widgets.OrderedPairs() {
 		title := pair.Key.(*object.String).Value
 		content := pair.Value.Inspect()
 		dashboard = append(dashboard, fmt.Sprintf("=== %s ===\n%s", title, content))
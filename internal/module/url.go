@@ -226,7 +226,7 @@ func urlBuild(args []object.VintObject, defs map[string]object.VintObject) objec
 	u := &url.URL{}
 
 	// Extract components from dictionary
-	for _, pair := range dict.Pairs {
+	for _, pair := range dict.OrderedPairs() {
 		key := pair.Key.Inspect()
 		value := pair.Value
 
@@ -44,9 +44,8 @@ func yamlDecode(args []object.VintObject, defs map[string]object.VintObject) obj
 		)
 	}
 
-	var i any
 	input := args[0].(*object.String).Value
-	err := yaml.Unmarshal([]byte(input), &i)
+	i, err := decodeOrderedYAML([]byte(input))
 	if err != nil {
 		return &object.Error{Message: fmt.Sprintf("Invalid YAML: %s", err.Error())}
 	}
@@ -108,20 +107,20 @@ func yamlMerge(args []object.VintObject, defs map[string]object.VintObject) obje
 	obj2 := args[1]
 
 	// Convert objects to any for merging
-	map1, ok1 := convertObjectToYAML(obj1).(map[string]any)
-	map2, ok2 := convertObjectToYAML(obj2).(map[string]any)
+	map1, ok1 := convertObjectToYAML(obj1).(*orderedMap)
+	map2, ok2 := convertObjectToYAML(obj2).(*orderedMap)
 
 	if !ok1 || !ok2 {
 		return &object.Error{Message: "Arguments must be dictionary-like objects"}
 	}
 
 	// Create a new map and merge
-	merged := make(map[string]any)
-	for k, v := range map1 {
-		merged[k] = v
+	merged := newOrderedMap()
+	for _, k := range map1.keys {
+		merged.Set(k, map1.values[k])
 	}
-	for k, v := range map2 {
-		merged[k] = v
+	for _, k := range map2.keys {
+		merged.Set(k, map2.values[k])
 	}
 
 	return convertYAMLToObject(merged)
@@ -158,12 +157,12 @@ func yamlGet(args []object.VintObject, defs map[string]object.VintObject) object
 		)
 	}
 
-	mapObj, ok := convertObjectToYAML(obj).(map[string]any)
+	mapObj, ok := convertObjectToYAML(obj).(*orderedMap)
 	if !ok {
 		return &object.Error{Message: "First argument must be a dictionary-like object"}
 	}
 
-	val, exists := mapObj[key.(*object.String).Value]
+	val, exists := mapObj.Get(key.(*object.String).Value)
 	if !exists {
 		return &object.Null{}
 	}
@@ -174,6 +173,17 @@ func yamlGet(args []object.VintObject, defs map[string]object.VintObject) object
 // convertYAMLToObject converts a Go any from YAML parsing to a Vint object
 func convertYAMLToObject(i any) object.VintObject {
 	switch v := i.(type) {
+	case *orderedMap:
+		dict := object.NewDict()
+		for _, k := range v.keys {
+			pair := object.DictPair{
+				Key:   &object.String{Value: k},
+				Value: convertYAMLToObject(v.values[k]),
+			}
+			dict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
+		}
+		return dict
+
 	case map[string]any:
 		dict := &object.Dict{}
 		dict.Pairs = make(map[object.HashKey]object.DictPair)
@@ -183,7 +193,7 @@ func convertYAMLToObject(i any) object.VintObject {
 				Key:   &object.String{Value: k},
 				Value: convertYAMLToObject(v),
 			}
-			dict.Pairs[pair.Key.(object.Hashable).HashKey()] = pair
+			dict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
 		}
 		return dict
 
@@ -211,7 +221,7 @@ func convertYAMLToObject(i any) object.VintObject {
 				Key:   key,
 				Value: convertYAMLToObject(v),
 			}
-			dict.Pairs[pair.Key.(object.Hashable).HashKey()] = pair
+			dict.SetPair(pair.Key.(object.Hashable).HashKey(), pair)
 		}
 		return dict
 
@@ -249,10 +259,9 @@ func convertYAMLToObject(i any) object.VintObject {
 func convertObjectToYAML(obj object.VintObject) any {
 	switch v := obj.(type) {
 	case *object.Dict:
-		m := make(map[string]any)
-		for _, pair := range v.Pairs {
-			key := pair.Key.(*object.String).Value
-			m[key] = convertObjectToYAML(pair.Value)
+		m := newOrderedMap()
+		for _, pair := range v.OrderedPairs() {
+			m.Set(dictKey(pair.Key), convertObjectToYAML(pair.Value))
 		}
 		return m
 
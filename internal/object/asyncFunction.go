@@ -10,6 +10,7 @@ import (
 // AsyncFunction represents an async function object
 type AsyncFunction struct {
 	Parameters []*ast.Identifier
+	Variadic   bool // the last parameter collects the remaining arguments
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -25,6 +26,9 @@ func (af *AsyncFunction) Inspect() string {
 	for _, p := range af.Parameters {
 		params = append(params, p.String())
 	}
+	if af.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
 
 	out.WriteString("async func")
 	out.WriteString("(")
@@ -53,7 +57,9 @@ func (af *AsyncFunction) Execute(args []VintObject, eval func(ast.Node, *Environ
 
 		// Bind parameters
 		for paramIdx, param := range af.Parameters {
-			if paramIdx < len(args) {
+			if af.Variadic && paramIdx == len(af.Parameters)-1 {
+				extEnv.Define(param.Value, VariadicArgs(args, paramIdx))
+			} else if paramIdx < len(args) {
 				extEnv.Define(param.Value, args[paramIdx])
 			}
 		}
@@ -0,0 +1,176 @@
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// BigInt is an integer of any size. Integer arithmetic that overflows
+// int64 gives a BigInt, as does a literal with an n suffix: 123n. Once an
+// integer is big it stays big, even if later results would fit in an
+// Integer.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Inspect() string      { return b.Value.String() }
+func (b *BigInt) Type() VintObjectType { return BIGINT_OBJ }
+
+// HashKey is the same as an Integer's for values that fit in one, so that
+// 5n and 5, which are equal, are the same dict key.
+func (b *BigInt) HashKey() HashKey {
+	if b.Value.IsInt64() {
+		return (&Integer{Value: b.Value.Int64()}).HashKey()
+	}
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+// IntegerFromBig returns v as an Integer if it fits in one, and as a
+// BigInt otherwise. It is used where int64 arithmetic overflows.
+func IntegerFromBig(v *big.Int) VintObject {
+	if v.IsInt64() {
+		return &Integer{Value: v.Int64()}
+	}
+	return &BigInt{Value: v}
+}
+
+// ParseBigInt parses an integer of any size, written in base 10 with
+// optional underscores between digits and an optional n suffix.
+func ParseBigInt(s string) (*BigInt, error) {
+	digits := strings.TrimSuffix(strings.TrimSpace(s), "n")
+	v, ok := new(big.Int).SetString(strings.ReplaceAll(digits, "_", ""), 10)
+	if !ok || strings.HasPrefix(digits, "_") || strings.HasSuffix(digits, "_") {
+		return nil, fmt.Errorf("'%s' is not an integer", s)
+	}
+	return &BigInt{Value: v}, nil
+}
+
+// ToBigInt converts an integer, or a float or decimal without a fraction,
+// to a BigInt.
+func ToBigInt(obj VintObject) (*BigInt, error) {
+	switch obj := obj.(type) {
+	case *BigInt:
+		return obj, nil
+	case *Integer:
+		return &BigInt{Value: big.NewInt(obj.Value)}, nil
+	case *String:
+		return ParseBigInt(obj.Value)
+	case *Float:
+		if math.IsInf(obj.Value, 0) || math.IsNaN(obj.Value) || obj.Value != math.Trunc(obj.Value) {
+			return nil, fmt.Errorf("%s has a fraction and cannot be a bigint", obj.Inspect())
+		}
+		v, _ := big.NewFloat(obj.Value).Int(nil)
+		return &BigInt{Value: v}, nil
+	case *Decimal:
+		if !obj.IsInteger() {
+			return nil, fmt.Errorf("%s has a fraction and cannot be a bigint", obj.Inspect())
+		}
+		return &BigInt{Value: obj.Truncate()}, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to BIGINT", obj.Type())
+}
+
+func (b *BigInt) Method(method string, args []VintObject) VintObject {
+	switch method {
+	case "abs":
+		if len(args) != 0 {
+			return newError("abs() expects 0 arguments, got %d", len(args))
+		}
+		return &BigInt{Value: new(big.Int).Abs(b.Value)}
+	case "sign":
+		if len(args) != 0 {
+			return newError("sign() expects 0 arguments, got %d", len(args))
+		}
+		return &Integer{Value: int64(b.Value.Sign())}
+	case "is_even":
+		if len(args) != 0 {
+			return newError("is_even() expects 0 arguments, got %d", len(args))
+		}
+		return &Boolean{Value: b.Value.Bit(0) == 0}
+	case "is_odd":
+		if len(args) != 0 {
+			return newError("is_odd() expects 0 arguments, got %d", len(args))
+		}
+		return &Boolean{Value: b.Value.Bit(0) == 1}
+	case "to_string":
+		if len(args) != 0 {
+			return newError("to_string() expects 0 arguments, got %d", len(args))
+		}
+		return &String{Value: b.Inspect()}
+	case "pow":
+		return b.pow(args)
+	case "sqrt":
+		if len(args) != 0 {
+			return newError("sqrt() expects 0 arguments, got %d", len(args))
+		}
+		if b.Value.Sign() < 0 {
+			return newError("Cannot calculate square root of negative number")
+		}
+		return &BigInt{Value: new(big.Int).Sqrt(b.Value)}
+	case "gcd":
+		if len(args) != 1 {
+			return newError("gcd() expects 1 argument, got %d", len(args))
+		}
+		other, err := ToBigInt(args[0])
+		if err != nil {
+			return newError("Argument must be an integer")
+		}
+		return &BigInt{Value: new(big.Int).GCD(nil, nil, new(big.Int).Abs(b.Value), new(big.Int).Abs(other.Value))}
+	case "toBinary":
+		if len(args) != 0 {
+			return newError("toBinary() expects 0 arguments, got %d", len(args))
+		}
+		return &String{Value: b.Value.Text(2)}
+	case "toHex":
+		if len(args) != 0 {
+			return newError("toHex() expects 0 arguments, got %d", len(args))
+		}
+		return &String{Value: b.Value.Text(16)}
+	case "toOctal":
+		if len(args) != 0 {
+			return newError("toOctal() expects 0 arguments, got %d", len(args))
+		}
+		return &String{Value: b.Value.Text(8)}
+	case "isPrime":
+		if len(args) != 0 {
+			return newError("isPrime() expects 0 arguments, got %d", len(args))
+		}
+		return &Boolean{Value: b.Value.ProbablyPrime(20)}
+	case "digits":
+		if len(args) != 0 {
+			return newError("digits() expects 0 arguments, got %d", len(args))
+		}
+		str := new(big.Int).Abs(b.Value).String()
+		digits := make([]VintObject, len(str))
+		for i, char := range str {
+			digits[i] = &Integer{Value: int64(char - '0')}
+		}
+		return &Array{Elements: digits}
+	case "bitLength":
+		if len(args) != 0 {
+			return newError("bitLength() expects 0 arguments, got %d", len(args))
+		}
+		return &Integer{Value: int64(b.Value.BitLen())}
+	default:
+		return newError("Sorry, the method '%s' is not supported for BigInt.", method)
+	}
+}
+
+func (b *BigInt) pow(args []VintObject) VintObject {
+	if len(args) != 1 {
+		return newError("pow() expects 1 argument, got %d", len(args))
+	}
+	exponent, ok := args[0].(*Integer)
+	if !ok {
+		return newError("Exponent must be an integer")
+	}
+	if exponent.Value < 0 {
+		return newError("Negative exponents not supported for integers")
+	}
+	return &BigInt{Value: new(big.Int).Exp(b.Value, big.NewInt(exponent.Value), nil)}
+}
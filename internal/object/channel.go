@@ -2,12 +2,13 @@ package object
 
 import (
 	"fmt"
+	"sync/atomic"
 )
 
 // Channel represents a communication channel between goroutines
 type Channel struct {
 	ch       chan VintObject
-	closed   bool
+	closed   atomic.Bool
 	buffered bool
 	size     int
 }
@@ -25,7 +26,7 @@ func (c *Channel) Inspect() string {
 
 // Send sends a value to the channel
 func (c *Channel) Send(value VintObject) error {
-	if c.closed {
+	if c.closed.Load() {
 		return fmt.Errorf("send on closed channel")
 	}
 
@@ -45,24 +46,53 @@ func (c *Channel) Receive() (VintObject, bool) {
 	return value, ok
 }
 
+// TrySend sends a value only if that can be done without blocking and
+// reports whether it was sent
+func (c *Channel) TrySend(value VintObject) (bool, error) {
+	if c.closed.Load() {
+		return false, fmt.Errorf("send on closed channel")
+	}
+
+	select {
+	case c.ch <- value:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// TryReceive receives a value only if one is ready. ok is false if nothing
+// was received, either because the channel is empty or because it is closed.
+func (c *Channel) TryReceive() (value VintObject, ok bool) {
+	select {
+	case value, ok = <-c.ch:
+		return value, ok
+	default:
+		return nil, false
+	}
+}
+
 // Close closes the channel
 func (c *Channel) Close() {
-	if !c.closed {
+	if c.closed.CompareAndSwap(false, true) {
 		close(c.ch)
-		c.closed = true
 	}
 }
 
 // IsClosed returns whether the channel is closed
 func (c *Channel) IsClosed() bool {
-	return c.closed
+	return c.closed.Load()
+}
+
+// Chan returns the underlying Go channel, for select statements
+func (c *Channel) Chan() chan VintObject {
+	return c.ch
 }
 
 // NewChannel creates a new unbuffered channel
 func NewChannel() *Channel {
 	return &Channel{
 		ch:       make(chan VintObject),
-		closed:   false,
 		buffered: false,
 		size:     0,
 	}
@@ -72,7 +102,6 @@ func NewChannel() *Channel {
 func NewBufferedChannel(size int) *Channel {
 	return &Channel{
 		ch:       make(chan VintObject, size),
-		closed:   false,
 		buffered: true,
 		size:     size,
 	}
@@ -0,0 +1,45 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/code"
+)
+
+// CompiledFunction is a function body compiled to bytecode for the VM.
+type CompiledFunction struct {
+	Instructions  code.Instructions
+	NumLocals     int
+	NumParameters int
+	NumRequired   int      // parameters without a default value
+	Parameters    []string // parameter names, used for keyword arguments
+	Name          string
+	Positions     code.SourceMap
+}
+
+func (cf *CompiledFunction) Type() VintObjectType { return COMPILED_FUNCTION_OBJ }
+func (cf *CompiledFunction) Inspect() string {
+	return fmt.Sprintf("CompiledFunction[%p]", cf)
+}
+
+// Closure is a compiled function together with the variables it captured.
+// To scripts it behaves like any other function.
+type Closure struct {
+	Fn   *CompiledFunction
+	Free []*Cell
+}
+
+func (c *Closure) Type() VintObjectType { return FUNCTION_OBJ }
+func (c *Closure) Inspect() string {
+	return fmt.Sprintf("func(%s) { <compiled> }", strings.Join(c.Fn.Parameters, ", "))
+}
+
+// Cell holds a variable shared between a function and the closures that
+// capture it.
+type Cell struct {
+	Value VintObject
+}
+
+func (c *Cell) Type() VintObjectType { return CELL_OBJ }
+func (c *Cell) Inspect() string      { return c.Value.Inspect() }
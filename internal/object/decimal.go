@@ -0,0 +1,464 @@
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decimal is an exact decimal number, for money and other amounts that
+// floats cannot hold exactly: decimal("19.99"). Its value is Coef divided
+// by 10 to the power of Scale, so Scale is the number of digits after the
+// point. Addition and multiplication keep every digit, so 1.10 + 2.20 is
+// 3.30; division rounds to the places of the decimal context.
+type Decimal struct {
+	Coef  *big.Int
+	Scale int32 // never negative
+}
+
+// Rounding is the way a decimal is rounded when digits are dropped.
+type Rounding string
+
+const (
+	RoundHalfEven Rounding = "half_even" // to the nearest, ties to an even digit
+	RoundHalfUp   Rounding = "half_up"   // to the nearest, ties away from zero
+	RoundHalfDown Rounding = "half_down" // to the nearest, ties towards zero
+	RoundUp       Rounding = "up"        // away from zero
+	RoundDown     Rounding = "down"      // towards zero
+	RoundCeiling  Rounding = "ceiling"   // towards positive infinity
+	RoundFloor    Rounding = "floor"     // towards negative infinity
+)
+
+var roundings = []Rounding{RoundHalfEven, RoundHalfUp, RoundHalfDown, RoundUp, RoundDown, RoundCeiling, RoundFloor}
+
+// ParseRounding returns the rounding mode called name.
+func ParseRounding(name string) (Rounding, error) {
+	names := make([]string, len(roundings))
+	for i, r := range roundings {
+		if string(r) == name {
+			return r, nil
+		}
+		names[i] = string(r)
+	}
+	return "", fmt.Errorf("unknown rounding mode '%s', expected one of %s", name, strings.Join(names, ", "))
+}
+
+// The decimal context is the number of places decimal division rounds to,
+// and the rounding mode it and round() use unless another is given.
+var decimalContext = struct {
+	sync.RWMutex
+	places   int32
+	rounding Rounding
+}{places: 28, rounding: RoundHalfEven}
+
+// DecimalContext returns the places and rounding of the decimal context.
+func DecimalContext() (int32, Rounding) {
+	decimalContext.RLock()
+	defer decimalContext.RUnlock()
+	return decimalContext.places, decimalContext.rounding
+}
+
+// SetDecimalContext changes the places and rounding of the decimal context.
+func SetDecimalContext(places int32, rounding Rounding) {
+	decimalContext.Lock()
+	defer decimalContext.Unlock()
+	decimalContext.places, decimalContext.rounding = places, rounding
+}
+
+var decimalPattern = regexp.MustCompile(`^([+-]?)([0-9]*)(?:\.([0-9]*))?(?:[eE]([+-]?[0-9]+))?$`)
+
+// ParseDecimal parses a decimal such as "19.99", "-0.5" or "1.5e3".
+func ParseDecimal(s string) (*Decimal, error) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil || match[2]+match[3] == "" {
+		return nil, fmt.Errorf("'%s' is not a decimal number", s)
+	}
+	coef, _ := new(big.Int).SetString(match[2]+match[3], 10)
+	if match[1] == "-" {
+		coef.Neg(coef)
+	}
+	scale := int64(len(match[3]))
+	if match[4] != "" {
+		exp, err := strconv.ParseInt(match[4], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("the exponent of '%s' is too large", s)
+		}
+		scale -= exp
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(-scale))
+		scale = 0
+	}
+	if scale > math.MaxInt32 {
+		return nil, fmt.Errorf("the exponent of '%s' is too large", s)
+	}
+	return &Decimal{Coef: coef, Scale: int32(scale)}, nil
+}
+
+// ToDecimal converts a number or a string to a Decimal. A float becomes the
+// shortest decimal that it prints as, so 0.1 is exactly 0.1.
+func ToDecimal(obj VintObject) (*Decimal, error) {
+	switch obj := obj.(type) {
+	case *Decimal:
+		return obj, nil
+	case *Integer:
+		return &Decimal{Coef: big.NewInt(obj.Value)}, nil
+	case *BigInt:
+		return &Decimal{Coef: obj.Value}, nil
+	case *Float:
+		if math.IsInf(obj.Value, 0) || math.IsNaN(obj.Value) {
+			return nil, fmt.Errorf("cannot convert %s to DECIMAL", obj.Inspect())
+		}
+		return ParseDecimal(strconv.FormatFloat(obj.Value, 'f', -1, 64))
+	case *String:
+		return ParseDecimal(obj.Value)
+	}
+	return nil, fmt.Errorf("cannot convert %s to DECIMAL", obj.Type())
+}
+
+func (d *Decimal) Type() VintObjectType { return DECIMAL_OBJ }
+
+func (d *Decimal) Inspect() string {
+	digits := new(big.Int).Abs(d.Coef).String()
+	if d.Scale > 0 {
+		if pad := int(d.Scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		point := len(digits) - int(d.Scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if d.Coef.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// HashKey ignores trailing zeros, so that 1.50 and 1.5 are the same key.
+func (d *Decimal) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(d.reduce(0).Inspect()))
+	return HashKey{Type: d.Type(), Value: h.Sum64()}
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// rescale returns the coefficient of d at a scale at least as large as
+// its own.
+func (d *Decimal) rescale(scale int32) *big.Int {
+	if scale == d.Scale {
+		return d.Coef
+	}
+	return new(big.Int).Mul(d.Coef, pow10(int64(scale-d.Scale)))
+}
+
+// align returns the coefficients of d and e at the same scale.
+func (d *Decimal) align(e *Decimal) (*big.Int, *big.Int, int32) {
+	scale := d.Scale
+	if e.Scale > scale {
+		scale = e.Scale
+	}
+	return d.rescale(scale), e.rescale(scale), scale
+}
+
+// reduce drops trailing zeros after the point, keeping at least minScale
+// digits.
+func (d *Decimal) reduce(minScale int32) *Decimal {
+	coef, scale := d.Coef, d.Scale
+	ten, r := big.NewInt(10), new(big.Int)
+	for scale > minScale {
+		q, _ := new(big.Int).QuoRem(coef, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		coef, scale = q, scale-1
+	}
+	return &Decimal{Coef: coef, Scale: scale}
+}
+
+func (d *Decimal) Add(e *Decimal) *Decimal {
+	x, y, scale := d.align(e)
+	return &Decimal{Coef: new(big.Int).Add(x, y), Scale: scale}
+}
+
+func (d *Decimal) Sub(e *Decimal) *Decimal {
+	x, y, scale := d.align(e)
+	return &Decimal{Coef: new(big.Int).Sub(x, y), Scale: scale}
+}
+
+func (d *Decimal) Mul(e *Decimal) *Decimal {
+	return &Decimal{Coef: new(big.Int).Mul(d.Coef, e.Coef), Scale: d.Scale + e.Scale}
+}
+
+// Quo divides d by e, rounding the result to places digits after the
+// point. Trailing zeros beyond those of d are dropped, so 10 / 4 is 2.5.
+func (d *Decimal) Quo(e *Decimal, places int32, mode Rounding) (*Decimal, error) {
+	if e.Coef.Sign() == 0 {
+		return nil, fmt.Errorf("cannot divide by zero")
+	}
+	n := new(big.Int).Mul(d.Coef, pow10(int64(e.Scale)+int64(places)))
+	m := new(big.Int).Mul(e.Coef, pow10(int64(d.Scale)))
+	ideal := d.Scale - e.Scale
+	if ideal < 0 {
+		ideal = 0
+	}
+	if ideal > places {
+		ideal = places
+	}
+	return (&Decimal{Coef: roundQuo(n, m, mode), Scale: places}).reduce(ideal), nil
+}
+
+// Rem is the remainder of d divided by e, with the sign of d.
+func (d *Decimal) Rem(e *Decimal) (*Decimal, error) {
+	if e.Coef.Sign() == 0 {
+		return nil, fmt.Errorf("cannot divide by zero")
+	}
+	x, y, scale := d.align(e)
+	return &Decimal{Coef: new(big.Int).Rem(x, y), Scale: scale}, nil
+}
+
+// Pow raises d to the power n. A negative power is divided out using the
+// decimal context.
+func (d *Decimal) Pow(n int64) (*Decimal, error) {
+	if n < 0 {
+		places, mode := DecimalContext()
+		denominator, err := d.Pow(-n)
+		if err != nil {
+			return nil, err
+		}
+		return (&Decimal{Coef: big.NewInt(1)}).Quo(denominator, places, mode)
+	}
+	if int64(d.Scale)*n > math.MaxInt32 {
+		return nil, fmt.Errorf("the result has too many digits")
+	}
+	return &Decimal{Coef: new(big.Int).Exp(d.Coef, big.NewInt(n), nil), Scale: d.Scale * int32(n)}, nil
+}
+
+func (d *Decimal) Neg() *Decimal {
+	return &Decimal{Coef: new(big.Int).Neg(d.Coef), Scale: d.Scale}
+}
+
+// Cmp compares d and e, returning -1, 0 or +1.
+func (d *Decimal) Cmp(e *Decimal) int {
+	x, y, _ := d.align(e)
+	return x.Cmp(y)
+}
+
+// Round returns d with exactly places digits after the point.
+func (d *Decimal) Round(places int32, mode Rounding) *Decimal {
+	if places >= d.Scale {
+		return &Decimal{Coef: d.rescale(places), Scale: places}
+	}
+	return &Decimal{Coef: roundQuo(d.Coef, pow10(int64(d.Scale-places)), mode), Scale: places}
+}
+
+// Truncate returns the integer part of d.
+func (d *Decimal) Truncate() *big.Int {
+	return d.Round(0, RoundDown).Coef
+}
+
+// IsInteger reports whether d has no fraction.
+func (d *Decimal) IsInteger() bool {
+	return d.reduce(0).Scale == 0
+}
+
+func (d *Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.Inspect(), 64)
+	return f
+}
+
+// roundQuo returns n / m rounded to an integer.
+func roundQuo(n, m *big.Int, mode Rounding) *big.Int {
+	q, r := new(big.Int).QuoRem(n, m, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := n.Sign() * m.Sign()
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	default:
+		// Compare the remainder with half of m
+		half := new(big.Int).Lsh(r.Abs(r), 1).Cmp(new(big.Int).Abs(m))
+		switch {
+		case half != 0:
+			away = half > 0
+		case mode == RoundHalfUp:
+			away = true
+		case mode == RoundHalfEven:
+			away = q.Bit(0) == 1
+		}
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Format lets fmt format a decimal without going through a float, so that
+// "${price:.2f}" rounds 2.675 to 2.68 the way the decimal context does.
+func (d *Decimal) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'f', 'F', 'v', 's':
+		s = d.Inspect()
+		if places, ok := f.Precision(); ok && verb != 's' {
+			_, mode := DecimalContext()
+			s = d.Round(int32(places), mode).Inspect()
+		}
+	case 'e', 'E', 'g', 'G':
+		format := "%" + string(verb)
+		if places, ok := f.Precision(); ok {
+			format = "%." + strconv.Itoa(places) + string(verb)
+		}
+		s = fmt.Sprintf(format, d.Float64())
+	default:
+		fmt.Fprintf(f, "%%!%c(DECIMAL=%s)", verb, d.Inspect())
+		return
+	}
+	if f.Flag('+') && !strings.HasPrefix(s, "-") {
+		s = "+" + s
+	}
+	if width, ok := f.Width(); ok && len(s) < width {
+		pad := width - len(s)
+		switch {
+		case f.Flag('-'):
+			s += strings.Repeat(" ", pad)
+		case f.Flag('0'):
+			sign := ""
+			if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+				sign, s = s[:1], s[1:]
+			}
+			s = sign + strings.Repeat("0", pad) + s
+		default:
+			s = strings.Repeat(" ", pad) + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+func (d *Decimal) Method(method string, args []VintObject) VintObject {
+	switch method {
+	case "round":
+		return d.round(args)
+	case "div":
+		return d.div(args)
+	case "floor", "ceil", "truncate":
+		if len(args) != 0 {
+			return newError("%s() expects 0 arguments, got %d", method, len(args))
+		}
+		mode := map[string]Rounding{"floor": RoundFloor, "ceil": RoundCeiling, "truncate": RoundDown}[method]
+		return IntegerFromBig(d.Round(0, mode).Coef)
+	case "abs":
+		if len(args) != 0 {
+			return newError("abs() expects 0 arguments, got %d", len(args))
+		}
+		return &Decimal{Coef: new(big.Int).Abs(d.Coef), Scale: d.Scale}
+	case "sign":
+		if len(args) != 0 {
+			return newError("sign() expects 0 arguments, got %d", len(args))
+		}
+		return &Integer{Value: int64(d.Coef.Sign())}
+	case "scale":
+		if len(args) != 0 {
+			return newError("scale() expects 0 arguments, got %d", len(args))
+		}
+		return &Integer{Value: int64(d.Scale)}
+	case "to_string":
+		if len(args) != 0 {
+			return newError("to_string() expects 0 arguments, got %d", len(args))
+		}
+		return &String{Value: d.Inspect()}
+	case "toFixed":
+		if len(args) != 1 {
+			return newError("toFixed() expects 1 argument, got %d", len(args))
+		}
+		places, ok := args[0].(*Integer)
+		if !ok || places.Value < 0 || places.Value > math.MaxInt32 {
+			return newError("Decimal places must be an integer that is not negative")
+		}
+		_, mode := DecimalContext()
+		return &String{Value: d.Round(int32(places.Value), mode).Inspect()}
+	case "toFloat":
+		if len(args) != 0 {
+			return newError("toFloat() expects 0 arguments, got %d", len(args))
+		}
+		return &Float{Value: d.Float64()}
+	default:
+		return newError("Method '%s' is not supported for Decimal objects", method)
+	}
+}
+
+// roundingArgs reads the optional places and rounding mode arguments of
+// round() and div(), starting at args[from].
+func roundingArgs(name string, args []VintObject, from int) (int32, Rounding, *Error) {
+	places, mode := DecimalContext()
+	if len(args) > from {
+		p, ok := args[from].(*Integer)
+		if !ok || p.Value < 0 || p.Value > math.MaxInt32 {
+			return 0, "", newError("%s() expects the number of places to be an integer that is not negative", name)
+		}
+		places = int32(p.Value)
+	}
+	if len(args) > from+1 {
+		s, ok := args[from+1].(*String)
+		if !ok {
+			return 0, "", newError("%s() expects the rounding mode to be a string", name)
+		}
+		var err error
+		if mode, err = ParseRounding(s.Value); err != nil {
+			return 0, "", newError("%s(): %s", name, err)
+		}
+	}
+	return places, mode, nil
+}
+
+// round rounds to the given places, 0 by default, with the given rounding
+// mode or the context's: price.round(2, "half_up").
+func (d *Decimal) round(args []VintObject) VintObject {
+	if len(args) > 2 {
+		return newError("round() expects at most 2 arguments (places, mode), got %d", len(args))
+	}
+	if len(args) == 0 {
+		args = []VintObject{&Integer{Value: 0}}
+	}
+	places, mode, err := roundingArgs("round", args, 0)
+	if err != nil {
+		return err
+	}
+	return d.Round(places, mode)
+}
+
+// div divides with its own places and rounding mode instead of the
+// context's, and keeps all the places: total.div(3, 2, "down").
+func (d *Decimal) div(args []VintObject) VintObject {
+	if len(args) < 1 || len(args) > 3 {
+		return newError("div() expects 1 to 3 arguments (divisor, places, mode), got %d", len(args))
+	}
+	divisor, convErr := ToDecimal(args[0])
+	if convErr != nil {
+		return newError("div() expects a number to divide by, got %s", args[0].Type())
+	}
+	places, mode, err := roundingArgs("div", args, 1)
+	if err != nil {
+		return err
+	}
+	result, quoErr := d.Quo(divisor, places, mode)
+	if quoErr != nil {
+		return newError("Division by zero: %s", quoErr)
+	}
+	return result.Round(places, mode)
+}
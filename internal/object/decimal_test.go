@@ -0,0 +1,132 @@
+package object
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		isError  bool
+	}{
+		{"19.99", "19.99", false},
+		{"-0.50", "-0.50", false},
+		{"+7", "7", false},
+		{".5", "0.5", false},
+		{"1.5e3", "1500", false},
+		{"1.5e-3", "0.0015", false},
+		{"1_000.25", "", true},
+		{"abc", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.input)
+		if tt.isError {
+			if err == nil {
+				t.Errorf("ParseDecimal(%q): expected error, got %s", tt.input, d.Inspect())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if d.Inspect() != tt.expected {
+			t.Errorf("ParseDecimal(%q) = %s, expected %s", tt.input, d.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	d := func(s string) *Decimal {
+		v, err := ParseDecimal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	if got := d("1.10").Add(d("2.20")).Inspect(); got != "3.30" {
+		t.Errorf("1.10 + 2.20 = %s", got)
+	}
+	if got := d("5").Sub(d("0.01")).Inspect(); got != "4.99" {
+		t.Errorf("5 - 0.01 = %s", got)
+	}
+	if got := d("1.5").Mul(d("1.5")).Inspect(); got != "2.25" {
+		t.Errorf("1.5 * 1.5 = %s", got)
+	}
+	if got, _ := d("2").Quo(d("3"), 4, RoundHalfEven); got.Inspect() != "0.6667" {
+		t.Errorf("2 / 3 = %s", got.Inspect())
+	}
+	if got, _ := d("10.00").Quo(d("4"), 28, RoundHalfEven); got.Inspect() != "2.50" {
+		t.Errorf("10.00 / 4 = %s", got.Inspect())
+	}
+	if _, err := d("1").Quo(d("0"), 2, RoundHalfEven); err == nil {
+		t.Error("expected an error dividing by zero")
+	}
+	if got, _ := d("-7.5").Rem(d("2")); got.Inspect() != "-1.5" {
+		t.Errorf("-7.5 %% 2 = %s", got.Inspect())
+	}
+	if d("1.50").Cmp(d("1.5")) != 0 || d("1.49").Cmp(d("1.5")) != -1 {
+		t.Error("Cmp does not ignore trailing zeros")
+	}
+	if d("1.50").HashKey() != d("1.5").HashKey() {
+		t.Error("equal decimals have different hash keys")
+	}
+}
+
+func TestDecimalRounding(t *testing.T) {
+	tests := []struct {
+		input    string
+		mode     Rounding
+		expected string
+	}{
+		{"2.5", RoundHalfEven, "2"},
+		{"3.5", RoundHalfEven, "4"},
+		{"2.5", RoundHalfUp, "3"},
+		{"-2.5", RoundHalfUp, "-3"},
+		{"2.5", RoundHalfDown, "2"},
+		{"2.1", RoundUp, "3"},
+		{"-2.1", RoundUp, "-3"},
+		{"2.9", RoundDown, "2"},
+		{"-2.1", RoundCeiling, "-2"},
+		{"-2.1", RoundFloor, "-3"},
+	}
+
+	for _, tt := range tests {
+		d, _ := ParseDecimal(tt.input)
+		if got := d.Round(0, tt.mode).Inspect(); got != tt.expected {
+			t.Errorf("%s rounded %s = %s, expected %s", tt.input, tt.mode, got, tt.expected)
+		}
+	}
+
+	d, _ := ParseDecimal("1.2")
+	if got := d.Round(3, RoundHalfEven).Inspect(); got != "1.200" {
+		t.Errorf("1.2 to 3 places = %s, expected 1.200", got)
+	}
+	if _, err := ParseRounding("sideways"); err == nil {
+		t.Error("expected an error for an unknown rounding mode")
+	}
+}
+
+func TestBigIntConversions(t *testing.T) {
+	if got := IntegerFromBig(big.NewInt(5)); got.Type() != INTEGER_OBJ {
+		t.Errorf("small values should be integers, got %s", got.Type())
+	}
+	huge, _ := new(big.Int).SetString("99999999999999999999", 10)
+	if got := IntegerFromBig(huge); got.Type() != BIGINT_OBJ {
+		t.Errorf("large values should be bigints, got %s", got.Type())
+	}
+	if (&BigInt{Value: big.NewInt(5)}).HashKey() != (&Integer{Value: 5}).HashKey() {
+		t.Error("5n and 5 should be the same dict key")
+	}
+	if _, err := ToBigInt(&Float{Value: 1.5}); err == nil {
+		t.Error("expected an error converting 1.5 to a bigint")
+	}
+	if b, err := ParseBigInt("1_000n"); err != nil || b.Inspect() != "1000" {
+		t.Errorf("ParseBigInt(1_000n) = %v, %v", b, err)
+	}
+}
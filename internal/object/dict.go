@@ -12,18 +12,107 @@ type DictPair struct {
 	Value VintObject
 }
 
+// Dict keeps its pairs in insertion order. Pairs gives constant-time
+// lookups; order records the keys in the order they were first added, so
+// iteration, Inspect and the encoders are deterministic. Add and remove
+// pairs with SetPair and Delete so that both stay in sync.
 type Dict struct {
 	Pairs  map[HashKey]DictPair
+	order  []HashKey
 	offset int
 }
 
+// NewDict returns an empty dictionary
+func NewDict() *Dict {
+	return &Dict{Pairs: make(map[HashKey]DictPair)}
+}
+
+// SetPair stores a pair under hashKey. A new key is added at the end;
+// replacing the value of an existing key keeps its position.
+func (d *Dict) SetPair(hashKey HashKey, pair DictPair) {
+	if d.Pairs == nil {
+		d.Pairs = make(map[HashKey]DictPair)
+	}
+	d.syncOrder()
+	if _, exists := d.Pairs[hashKey]; !exists {
+		d.order = append(d.order, hashKey)
+	}
+	d.Pairs[hashKey] = pair
+}
+
+// Delete removes the pair stored under hashKey and reports whether there
+// was one.
+func (d *Dict) Delete(hashKey HashKey) bool {
+	if _, exists := d.Pairs[hashKey]; !exists {
+		return false
+	}
+	d.syncOrder()
+	delete(d.Pairs, hashKey)
+	for i, k := range d.order {
+		if k == hashKey {
+			d.order = append(d.order[:i], d.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// OrderedPairs returns the pairs in insertion order
+func (d *Dict) OrderedPairs() []DictPair {
+	pairs := make([]DictPair, 0, len(d.Pairs))
+	for _, k := range d.orderedKeys() {
+		pairs = append(pairs, d.Pairs[k])
+	}
+	return pairs
+}
+
+// orderedKeys returns the keys in insertion order. Pairs written straight
+// into the map, as older code does, have no recorded position; they come
+// last, sorted so that the result is still deterministic.
+func (d *Dict) orderedKeys() []HashKey {
+	if len(d.order) == len(d.Pairs) {
+		return d.order
+	}
+
+	keys := make([]HashKey, 0, len(d.Pairs))
+	seen := make(map[HashKey]bool, len(d.Pairs))
+	for _, k := range d.order {
+		if _, exists := d.Pairs[k]; exists && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+
+	var untracked []HashKey
+	for k := range d.Pairs {
+		if !seen[k] {
+			untracked = append(untracked, k)
+		}
+	}
+	sort.Slice(untracked, func(i, j int) bool {
+		a, b := d.Pairs[untracked[i]].Key.Inspect(), d.Pairs[untracked[j]].Key.Inspect()
+		if a != b {
+			return a < b
+		}
+		return untracked[i].Value < untracked[j].Value
+	})
+	return append(keys, untracked...)
+}
+
+// syncOrder brings order up to date before it is modified
+func (d *Dict) syncOrder() {
+	if len(d.order) != len(d.Pairs) {
+		d.order = d.orderedKeys()
+	}
+}
+
 func (d *Dict) Type() VintObjectType { return DICT_OBJ }
 func (d *Dict) Inspect() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
 	}
 
@@ -35,24 +124,13 @@ func (d *Dict) Inspect() string {
 }
 
 func (d *Dict) Next() (VintObject, VintObject) {
-	idx := 0
-	dict := make(map[string]DictPair)
-	var keys []string
-	for _, v := range d.Pairs {
-		dict[v.Key.Inspect()] = v
-		keys = append(keys, v.Key.Inspect())
+	keys := d.orderedKeys()
+	if d.offset >= len(keys) {
+		return nil, nil
 	}
-
-	sort.Strings(keys)
-
-	for _, k := range keys {
-		if d.offset == idx {
-			d.offset += 1
-			return dict[k].Key, dict[k].Value
-		}
-		idx += 1
-	}
-	return nil, nil
+	pair := d.Pairs[keys[d.offset]]
+	d.offset++
+	return pair.Key, pair.Value
 }
 
 func (d *Dict) Reset() {
@@ -122,7 +200,7 @@ func (d *Dict) keys(args []VintObject) VintObject {
 	}
 
 	keys := make([]VintObject, 0, len(d.Pairs))
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		keys = append(keys, pair.Key)
 	}
 
@@ -135,7 +213,7 @@ func (d *Dict) values(args []VintObject) VintObject {
 	}
 
 	values := make([]VintObject, 0, len(d.Pairs))
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		values = append(values, pair.Value)
 	}
 
@@ -196,8 +274,7 @@ func (d *Dict) set(args []VintObject) VintObject {
 		return newError("Key must be hashable")
 	}
 
-	hashKey := key.HashKey()
-	d.Pairs[hashKey] = DictPair{Key: args[0], Value: args[1]}
+	d.SetPair(key.HashKey(), DictPair{Key: args[0], Value: args[1]})
 
 	return d
 }
@@ -212,13 +289,7 @@ func (d *Dict) remove(args []VintObject) VintObject {
 		return newError("Key must be hashable")
 	}
 
-	hashKey := key.HashKey()
-	if _, exists := d.Pairs[hashKey]; exists {
-		delete(d.Pairs, hashKey)
-		return &Boolean{Value: true}
-	}
-
-	return &Boolean{Value: false}
+	return &Boolean{Value: d.Delete(key.HashKey())}
 }
 
 func (d *Dict) clear(args []VintObject) VintObject {
@@ -227,6 +298,7 @@ func (d *Dict) clear(args []VintObject) VintObject {
 	}
 
 	d.Pairs = make(map[HashKey]DictPair)
+	d.order = nil
 	return d
 }
 
@@ -240,8 +312,8 @@ func (d *Dict) merge(args []VintObject) VintObject {
 		return newError("Argument must be a Dict")
 	}
 
-	for hashKey, pair := range other.Pairs {
-		d.Pairs[hashKey] = pair
+	for _, hashKey := range other.orderedKeys() {
+		d.SetPair(hashKey, other.Pairs[hashKey])
 	}
 
 	return d
@@ -252,12 +324,12 @@ func (d *Dict) copy(args []VintObject) VintObject {
 		return newError("copy() expects 0 arguments, got %d", len(args))
 	}
 
-	newPairs := make(map[HashKey]DictPair)
-	for hashKey, pair := range d.Pairs {
-		newPairs[hashKey] = pair
+	newDict := NewDict()
+	for _, hashKey := range d.orderedKeys() {
+		newDict.SetPair(hashKey, d.Pairs[hashKey])
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // filter creates a new dictionary with key-value pairs that pass the test
@@ -271,18 +343,18 @@ func (d *Dict) filter(args []VintObject) VintObject {
 		return newError("Argument must be a function")
 	}
 
-	newPairs := make(map[HashKey]DictPair)
-	for _, pair := range d.Pairs {
+	newDict := NewDict()
+	for _, pair := range d.OrderedPairs() {
 		// Call function with key, value
 		args := []VintObject{pair.Key, pair.Value}
 		result := callFunction(fn, args)
 
 		if isTruthy(result) {
-			newPairs[pair.Key.(Hashable).HashKey()] = pair
+			newDict.SetPair(pair.Key.(Hashable).HashKey(), pair)
 		}
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // mapDict creates a new dictionary with transformed values
@@ -296,17 +368,17 @@ func (d *Dict) mapDict(args []VintObject) VintObject {
 		return newError("Argument must be a function")
 	}
 
-	newPairs := make(map[HashKey]DictPair)
-	for _, pair := range d.Pairs {
+	newDict := NewDict()
+	for _, pair := range d.OrderedPairs() {
 		// Call function with key, value
 		args := []VintObject{pair.Key, pair.Value}
 		result := callFunction(fn, args)
 
 		newPair := DictPair{Key: pair.Key, Value: result}
-		newPairs[pair.Key.(Hashable).HashKey()] = newPair
+		newDict.SetPair(pair.Key.(Hashable).HashKey(), newPair)
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // reduce reduces the dictionary to a single value
@@ -320,27 +392,20 @@ func (d *Dict) reduce(args []VintObject) VintObject {
 		return newError("First argument must be a function")
 	}
 
+	pairs := d.OrderedPairs()
 	var accumulator VintObject
-	var skipKey *HashKey
 	if len(args) == 2 {
 		accumulator = args[1]
 	} else {
 		// Use first value as initial accumulator
-		if len(d.Pairs) == 0 {
+		if len(pairs) == 0 {
 			return newError("Cannot reduce empty dictionary without initial value")
 		}
-		for k, pair := range d.Pairs {
-			accumulator = pair.Value
-			hk := k
-			skipKey = &hk
-			break
-		}
+		accumulator = pairs[0].Value
+		pairs = pairs[1:]
 	}
 
-	for k, pair := range d.Pairs {
-		if skipKey != nil && k == *skipKey {
-			continue
-		}
+	for _, pair := range pairs {
 		args := []VintObject{accumulator, pair.Key, pair.Value}
 		accumulator = callFunction(fn, args)
 	}
@@ -359,7 +424,7 @@ func (d *Dict) forEach(args []VintObject) VintObject {
 		return newError("Argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		args := []VintObject{pair.Key, pair.Value}
 		callFunction(fn, args)
 	}
@@ -378,7 +443,7 @@ func (d *Dict) find(args []VintObject) VintObject {
 		return newError("Argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		args := []VintObject{pair.Key, pair.Value}
 		result := callFunction(fn, args)
 
@@ -401,7 +466,7 @@ func (d *Dict) some(args []VintObject) VintObject {
 		return newError("Argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		args := []VintObject{pair.Key, pair.Value}
 		result := callFunction(fn, args)
 
@@ -424,7 +489,7 @@ func (d *Dict) every(args []VintObject) VintObject {
 		return newError("Argument must be a function")
 	}
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		args := []VintObject{pair.Key, pair.Value}
 		result := callFunction(fn, args)
 
@@ -442,7 +507,7 @@ func (d *Dict) pick(args []VintObject) VintObject {
 		return newError("pick() expects at least 1 argument (keys), got %d", len(args))
 	}
 
-	newPairs := make(map[HashKey]DictPair)
+	newDict := NewDict()
 
 	for _, key := range args {
 		hashable, ok := key.(Hashable)
@@ -451,11 +516,11 @@ func (d *Dict) pick(args []VintObject) VintObject {
 		}
 
 		if pair, exists := d.Pairs[hashable.HashKey()]; exists {
-			newPairs[hashable.HashKey()] = pair
+			newDict.SetPair(hashable.HashKey(), pair)
 		}
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // omit creates a new dictionary excluding specified keys
@@ -464,7 +529,7 @@ func (d *Dict) omit(args []VintObject) VintObject {
 		return d.copy([]VintObject{})
 	}
 
-	newPairs := make(map[HashKey]DictPair)
+	newDict := NewDict()
 
 	// Create set of keys to omit
 	omitKeys := make(map[HashKey]bool)
@@ -475,13 +540,13 @@ func (d *Dict) omit(args []VintObject) VintObject {
 	}
 
 	// Copy all pairs except omitted ones
-	for hashKey, pair := range d.Pairs {
+	for _, hashKey := range d.orderedKeys() {
 		if !omitKeys[hashKey] {
-			newPairs[hashKey] = pair
+			newDict.SetPair(hashKey, d.Pairs[hashKey])
 		}
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // flatten flattens nested dictionary (one level deep)
@@ -490,23 +555,23 @@ func (d *Dict) flatten(args []VintObject) VintObject {
 		return newError("flatten() expects 0 arguments, got %d", len(args))
 	}
 
-	newPairs := make(map[HashKey]DictPair)
+	newDict := NewDict()
 
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		if nestedDict, ok := pair.Value.(*Dict); ok {
 			// Flatten nested dictionary
-			for _, nestedPair := range nestedDict.Pairs {
+			for _, nestedPair := range nestedDict.OrderedPairs() {
 				// Create new key by combining parent and child keys
 				combinedKey := &String{Value: pair.Key.Inspect() + "." + nestedPair.Key.Inspect()}
 				newPair := DictPair{Key: combinedKey, Value: nestedPair.Value}
-				newPairs[combinedKey.HashKey()] = newPair
+				newDict.SetPair(combinedKey.HashKey(), newPair)
 			}
 		} else {
-			newPairs[pair.Key.(Hashable).HashKey()] = pair
+			newDict.SetPair(pair.Key.(Hashable).HashKey(), pair)
 		}
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // deepMerge recursively merges dictionaries
@@ -520,30 +585,31 @@ func (d *Dict) deepMerge(args []VintObject) VintObject {
 		return newError("Argument must be a Dict")
 	}
 
-	newPairs := make(map[HashKey]DictPair)
+	newDict := NewDict()
 
 	// Copy original pairs
-	for hashKey, pair := range d.Pairs {
-		newPairs[hashKey] = pair
+	for _, hashKey := range d.orderedKeys() {
+		newDict.SetPair(hashKey, d.Pairs[hashKey])
 	}
 
-	// Merge other pairs
-	for hashKey, otherPair := range other.Pairs {
-		if existingPair, exists := newPairs[hashKey]; exists {
+	// Merge other pairs; keys already present keep their position
+	for _, hashKey := range other.orderedKeys() {
+		otherPair := other.Pairs[hashKey]
+		if existingPair, exists := newDict.Pairs[hashKey]; exists {
 			// If both values are dictionaries, recursively merge them
 			if existingDict, ok1 := existingPair.Value.(*Dict); ok1 {
 				if otherDict, ok2 := otherPair.Value.(*Dict); ok2 {
 					merged := existingDict.deepMerge([]VintObject{otherDict})
 					newPair := DictPair{Key: existingPair.Key, Value: merged}
-					newPairs[hashKey] = newPair
+					newDict.SetPair(hashKey, newPair)
 					continue
 				}
 			}
 		}
-		newPairs[hashKey] = otherPair
+		newDict.SetPair(hashKey, otherPair)
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // equals checks if two dictionaries are equal
@@ -591,7 +657,7 @@ func (d *Dict) entries(args []VintObject) VintObject {
 	}
 
 	entries := make([]VintObject, 0, len(d.Pairs))
-	for _, pair := range d.Pairs {
+	for _, pair := range d.OrderedPairs() {
 		entry := &Array{Elements: []VintObject{pair.Key, pair.Value}}
 		entries = append(entries, entry)
 	}
@@ -610,7 +676,7 @@ func (d *Dict) fromEntries(args []VintObject) VintObject {
 		return newError("Argument must be an array")
 	}
 
-	newPairs := make(map[HashKey]DictPair)
+	newDict := NewDict()
 
 	for _, element := range arr.Elements {
 		entry, ok := element.(*Array)
@@ -624,10 +690,10 @@ func (d *Dict) fromEntries(args []VintObject) VintObject {
 		}
 
 		pair := DictPair{Key: entry.Elements[0], Value: entry.Elements[1]}
-		newPairs[key.HashKey()] = pair
+		newDict.SetPair(key.HashKey(), pair)
 	}
 
-	return &Dict{Pairs: newPairs}
+	return newDict
 }
 
 // Helper functions for dict methods
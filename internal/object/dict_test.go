@@ -0,0 +1,104 @@
+package object
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestDict(keys ...string) *Dict {
+	d := NewDict()
+	for i, k := range keys {
+		key := &String{Value: k}
+		d.SetPair(key.HashKey(), DictPair{Key: key, Value: &Integer{Value: int64(i)}})
+	}
+	return d
+}
+
+func dictKeyOrder(d *Dict) string {
+	keys := []string{}
+	for _, pair := range d.OrderedPairs() {
+		keys = append(keys, pair.Key.Inspect())
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestDictInsertionOrder(t *testing.T) {
+	d := newTestDict("zeta", "alpha", "mid")
+	if got := dictKeyOrder(d); got != "zeta,alpha,mid" {
+		t.Fatalf("wrong order: got %q", got)
+	}
+
+	// Overwriting a key keeps its position
+	alpha := &String{Value: "alpha"}
+	d.SetPair(alpha.HashKey(), DictPair{Key: alpha, Value: &Integer{Value: 9}})
+	if got := dictKeyOrder(d); got != "zeta,alpha,mid" {
+		t.Errorf("overwrite moved key: got %q", got)
+	}
+
+	// A deleted key that is added again goes to the end
+	zeta := &String{Value: "zeta"}
+	if !d.Delete(zeta.HashKey()) {
+		t.Fatal("Delete returned false for an existing key")
+	}
+	if d.Delete(zeta.HashKey()) {
+		t.Error("Delete returned true for a missing key")
+	}
+	d.SetPair(zeta.HashKey(), DictPair{Key: zeta, Value: &Integer{Value: 1}})
+	if got := dictKeyOrder(d); got != "alpha,mid,zeta" {
+		t.Errorf("re-added key not at end: got %q", got)
+	}
+
+	if got := d.Inspect(); got != "{alpha: 9, mid: 2, zeta: 1}" {
+		t.Errorf("Inspect wrong: got %q", got)
+	}
+}
+
+func TestDictNextFollowsOrder(t *testing.T) {
+	d := newTestDict("c", "a", "b")
+	keys := []string{}
+	for {
+		key, _ := d.Next()
+		if key == nil {
+			break
+		}
+		keys = append(keys, key.Inspect())
+	}
+	if got := strings.Join(keys, ","); got != "c,a,b" {
+		t.Errorf("Next wrong order: got %q", got)
+	}
+}
+
+func TestDictMethodsKeepOrder(t *testing.T) {
+	d := newTestDict("b", "c", "a")
+	tests := []struct {
+		method   string
+		args     []VintObject
+		expected string
+	}{
+		{"keys", nil, "[b, c, a]"},
+		{"values", nil, "[0, 1, 2]"},
+		{"copy", nil, "{b: 0, c: 1, a: 2}"},
+		{"omit", []VintObject{&String{Value: "c"}}, "{b: 0, a: 2}"},
+		{"merge", []VintObject{newTestDict("d", "b")}, "{b: 1, c: 1, a: 2, d: 0}"},
+	}
+
+	for _, tt := range tests {
+		result := d.Method(tt.method, tt.args)
+		if result.Inspect() != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.method, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestDictWithoutOrderIsSorted(t *testing.T) {
+	// Dicts built by writing Pairs directly have no recorded order and
+	// fall back to a stable sorted order.
+	d := &Dict{Pairs: map[HashKey]DictPair{}}
+	for _, k := range []string{"b", "c", "a"} {
+		key := &String{Value: k}
+		d.Pairs[key.HashKey()] = DictPair{Key: key, Value: &Null{}}
+	}
+	if got := dictKeyOrder(d); got != "a,b,c" {
+		t.Errorf("fallback order wrong: got %q", got)
+	}
+}
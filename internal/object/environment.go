@@ -1,6 +1,7 @@
 package object
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/vintlang/vintlang/internal/ast"
@@ -8,16 +9,23 @@ import (
 
 // Environment represents a variable/function scope in VintLang.
 // Now supports function overloading: multiple functions with the same name but different signatures.
+//
+// Environments are shared by goroutines started with `go`, so every scope
+// guards its maps with its own lock. A lock is never held while walking to
+// the outer scope, which keeps lookups cheap and rules out lock-order
+// deadlocks between scopes.
 type Environment struct {
+	mu        sync.RWMutex           // protects store, funcs, constants and types
 	store     map[string]VintObject  // For variables and non-function objects
 	funcs     map[string][]*Function // For overloaded functions
 	constants map[string]bool
 	types     map[string]ast.Type // declared types for each name (Phase 2+)
 	outer     *Environment
 
-	isFuncScope   bool            // true for environments created by function calls
-	deferredCalls []*DeferredCall // deferred calls scoped to this function
-	deferMu       sync.Mutex      // protects deferredCalls
+	isFuncScope   bool                  // true for environments created by function calls
+	deferredCalls []*DeferredCall       // deferred calls scoped to this function
+	deferMu       sync.Mutex            // protects deferredCalls
+	yield         func(VintObject) bool // for the body of a generator function
 }
 
 // NewEnvironment creates a new environment with support for function overloading.
@@ -38,10 +46,13 @@ func NewEnclosedEnvironment(outer *Environment) *Environment {
 
 // Get returns a variable or function by name. For functions, returns the first overload (for backward compatibility).
 func (e *Environment) Get(name string) (VintObject, bool) {
+	e.mu.RLock()
 	if funcs, ok := e.funcs[name]; ok && len(funcs) > 0 {
+		e.mu.RUnlock()
 		return funcs[0], true // Return the first overload for compatibility
 	}
 	obj, ok := e.store[name]
+	e.mu.RUnlock()
 	if !ok && e.outer != nil {
 		return e.outer.Get(name)
 	}
@@ -50,7 +61,10 @@ func (e *Environment) Get(name string) (VintObject, bool) {
 
 // GetAllFunctions returns all overloads for a function name, or nil if none exist.
 func (e *Environment) GetAllFunctions(name string) []*Function {
-	if funcs, ok := e.funcs[name]; ok && len(funcs) > 0 {
+	e.mu.RLock()
+	funcs := e.funcs[name]
+	e.mu.RUnlock()
+	if len(funcs) > 0 {
 		return funcs
 	}
 	if e.outer != nil {
@@ -61,6 +75,8 @@ func (e *Environment) GetAllFunctions(name string) []*Function {
 
 // Define adds a variable or function to the environment. Functions are stored as overloads.
 func (e *Environment) Define(name string, val VintObject) VintObject {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	if fn, ok := val.(*Function); ok {
 		// Overload: append to the slice for this name
 		e.funcs[name] = append(e.funcs[name], fn)
@@ -75,6 +91,8 @@ func (e *Environment) Define(name string, val VintObject) VintObject {
 
 // DefineConst adds a constant variable to the environment.
 func (e *Environment) DefineConst(name string, val VintObject) VintObject {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	if _, ok := e.store[name]; ok {
 		return NewError("Identifier '" + name + "' has already been declared")
 	}
@@ -85,6 +103,8 @@ func (e *Environment) DefineConst(name string, val VintObject) VintObject {
 
 // DefineTyped adds a variable with an associated declared type.
 func (e *Environment) DefineTyped(name string, val VintObject, t ast.Type) VintObject {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	if fn, ok := val.(*Function); ok {
 		e.funcs[name] = append(e.funcs[name], fn)
 		if t != nil {
@@ -105,13 +125,18 @@ func (e *Environment) DefineTyped(name string, val VintObject, t ast.Type) VintO
 // SetDeclaredType records the declared type for a name in the current scope.
 func (e *Environment) SetDeclaredType(name string, t ast.Type) {
 	if t != nil {
+		e.mu.Lock()
 		e.types[name] = t
+		e.mu.Unlock()
 	}
 }
 
 // GetDeclaredType returns the declared type for a name, walking the closure chain.
 func (e *Environment) GetDeclaredType(name string) (ast.Type, bool) {
-	if t, ok := e.types[name]; ok {
+	e.mu.RLock()
+	t, ok := e.types[name]
+	e.mu.RUnlock()
+	if ok {
 		return t, true
 	}
 	if e.outer != nil {
@@ -122,21 +147,70 @@ func (e *Environment) GetDeclaredType(name string) (ast.Type, bool) {
 
 // Assign updates the value of a variable in the environment.
 func (e *Environment) Assign(name string, val VintObject) (VintObject, bool) {
+	e.mu.Lock()
 	if e.constants[name] {
+		e.mu.Unlock()
 		return NewError("Cannot assign to constant '" + name + "'"), true
 	}
 	if _, ok := e.store[name]; ok {
 		e.store[name] = val
+		e.mu.Unlock()
 		return val, true
 	}
+	e.mu.Unlock()
 	if e.outer != nil {
 		return e.outer.Assign(name, val)
 	}
 	return nil, false
 }
 
+// Owner returns the environment that declares the variable called name,
+// walking the closure chain, or nil if there is none.
+func (e *Environment) Owner(name string) *Environment {
+	for cur := e; cur != nil; cur = cur.outer {
+		cur.mu.RLock()
+		_, ok := cur.store[name]
+		cur.mu.RUnlock()
+		if ok {
+			return cur
+		}
+	}
+	return nil
+}
+
+// Names returns the names declared in this scope, not counting its outer
+// scopes, in sorted order.
+func (e *Environment) Names() []string {
+	e.mu.RLock()
+	names := make([]string, 0, len(e.store)+len(e.funcs))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	for name := range e.funcs {
+		if _, ok := e.store[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	e.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Outer returns the scope this one is enclosed in, or nil for the
+// outermost scope.
+func (e *Environment) Outer() *Environment {
+	return e.outer
+}
+
+// IsFuncScope reports whether this is the scope of a function call.
+func (e *Environment) IsFuncScope() bool {
+	return e.isFuncScope
+}
+
 // SetScoped sets a variable in the current scope only.
 func (e *Environment) SetScoped(name string, val VintObject) VintObject {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	if e.constants[name] {
 		return NewError("Cannot assign to constant '" + name + "'")
 	}
@@ -146,6 +220,8 @@ func (e *Environment) SetScoped(name string, val VintObject) VintObject {
 
 // Del deletes a variable from the environment.
 func (e *Environment) Del(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	_, ok := e.store[name]
 	if ok {
 		delete(e.store, name)
@@ -184,6 +260,24 @@ func (e *Environment) PopDefers() []*DeferredCall {
 	return defers
 }
 
+// SetYield marks this function scope as the body of a generator, whose
+// yield statements pass their values to yield. It is set before the body
+// runs and not changed after.
+func (e *Environment) SetYield(yield func(VintObject) bool) {
+	e.yield = yield
+}
+
+// Yield returns the yield function of the generator whose body this
+// environment is in, or nil outside generators.
+func (e *Environment) Yield() func(VintObject) bool {
+	for cur := e; cur != nil; cur = cur.outer {
+		if cur.isFuncScope {
+			return cur.yield
+		}
+	}
+	return nil
+}
+
 // nearestFuncScope walks up the environment chain to find the nearest
 // function-scoped environment. Falls back to the current env if none found.
 func (e *Environment) nearestFuncScope() *Environment {
@@ -0,0 +1,73 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Run with -race: goroutines started by `go` share environments.
+func TestEnvironmentConcurrentAccess(t *testing.T) {
+	global := NewEnvironment()
+	global.Define("shared", &Integer{Value: 0})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			local := NewEnclosedEnvironment(global)
+			for j := 0; j < 100; j++ {
+				name := fmt.Sprintf("v%d_%d", id, j)
+				global.Define(name, &Integer{Value: int64(j)})
+				local.SetScoped("x", &Integer{Value: int64(j)})
+				if _, ok := local.Get(name); !ok {
+					t.Errorf("%s not visible from an enclosed environment", name)
+				}
+				local.Assign("shared", &Integer{Value: int64(j)})
+				global.Del(name)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := global.Get("shared"); !ok {
+		t.Fatalf("shared variable lost")
+	}
+}
+
+func TestSyncPrimitives(t *testing.T) {
+	mu := &Mutex{}
+	if result := mu.Method("unlock", nil); result.Type() != ERROR_OBJ {
+		t.Errorf("unlocking an unlocked mutex should be an error, got %s", result.Inspect())
+	}
+	mu.Method("lock", nil)
+	if result := mu.Method("tryLock", nil); result.Inspect() != "false" {
+		t.Errorf("tryLock on a locked mutex should fail, got %s", result.Inspect())
+	}
+	mu.Method("unlock", nil)
+
+	wg := &WaitGroup{}
+	if result := wg.Method("done", nil); result.Type() != ERROR_OBJ {
+		t.Errorf("done() below zero should be an error, got %s", result.Inspect())
+	}
+
+	counter := NewAtomicInteger(0)
+	var done sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			for j := 0; j < 250; j++ {
+				counter.Method("increment", nil)
+			}
+		}()
+	}
+	done.Wait()
+	if got := counter.Method("get", nil).Inspect(); got != "1000" {
+		t.Errorf("atomic counter = %s, want 1000", got)
+	}
+	if ok := counter.Method("compareAndSwap", []VintObject{&Integer{Value: 1000}, &Integer{Value: 1}}); ok.Inspect() != "true" {
+		t.Errorf("compareAndSwap should succeed")
+	}
+}
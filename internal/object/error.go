@@ -7,11 +7,34 @@ import (
 // Error represents a custom error type with an associated message.
 type Error struct {
 	Message string
+	Thrown  VintObject // the value passed to 'throw', nil for runtime errors
+
+	// Source location where the error was raised. Line is 0 until the
+	// evaluator stamps the error with the innermost node that produced it.
+	File   string
+	Line   int
+	Column int
+
+	// Stack holds the Vint functions the error unwound through,
+	// innermost call first.
+	Stack []StackFrame
+
+	at             StackFrame // position reached while unwinding
+	awaitingCaller bool       // a frame was pushed and its call site is not known yet
+}
+
+// StackFrame is one entry of a runtime call stack: the function that was
+// executing and the position it had reached.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+	Column   int
 }
 
 func NewError(msg string) *Error {
 	return &Error{
-		msg,
+		Message: msg,
 	}
 }
 
@@ -24,3 +47,55 @@ func (e *Error) Inspect() string {
 func (e *Error) Type() VintObjectType {
 	return ERROR_OBJ
 }
+
+// HasLocation reports whether the error has been stamped with a source position.
+func (e *Error) HasLocation() bool {
+	return e.Line > 0
+}
+
+// Locate records the position of the node the error surfaced at. The first
+// call sets the origin of the error; after a frame was pushed, the next call
+// records the call site in the caller.
+func (e *Error) Locate(file string, line, column int) {
+	if line <= 0 {
+		return
+	}
+	if !e.HasLocation() {
+		e.File, e.Line, e.Column = file, line, column
+		e.at = StackFrame{File: file, Line: line, Column: column}
+		return
+	}
+	if e.awaitingCaller {
+		e.at = StackFrame{File: file, Line: line, Column: column}
+		e.awaitingCaller = false
+	}
+}
+
+// PushFrame records that the error unwound out of the named function.
+func (e *Error) PushFrame(function string) {
+	if !e.HasLocation() {
+		return
+	}
+	frame := e.at
+	frame.Function = function
+	e.Stack = append(e.Stack, frame)
+	e.awaitingCaller = true
+}
+
+// Traceback returns the call stack outermost call first, ending with the
+// frame the error was raised in. The outermost entry is the top-level code.
+func (e *Error) Traceback() []StackFrame {
+	if !e.HasLocation() {
+		return nil
+	}
+	frames := make([]StackFrame, 0, len(e.Stack)+1)
+	if len(e.Stack) == 0 || !e.awaitingCaller {
+		top := e.at
+		top.Function = "<main>"
+		frames = append(frames, top)
+	}
+	for i := len(e.Stack) - 1; i >= 0; i-- {
+		frames = append(frames, e.Stack[i])
+	}
+	return frames
+}
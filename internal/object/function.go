@@ -13,6 +13,10 @@ type Function struct {
 	ParamTypes  []ast.Type            // parallel to Parameters, nil for untyped
 	ReturnType  ast.Type              // nil for void/untyped
 	Defaults    map[string]ast.Expression
+	Variadic    bool // the last parameter collects the remaining arguments
+	Generator   bool // the body yields, so a call returns a generator
+	TypeParams  []*ast.TypeParameter // for a generic function
+	TypeArgs    map[string]ast.Type  // type arguments given with first<int>, nil if none
 	Body        *ast.BlockStatement
 	Env         *Environment
 	IsAsync     bool // Support for async handlers
@@ -27,6 +31,9 @@ func (f *Function) Inspect() string {
 	for _, p := range f.Parameters {
 		params = append(params, p.String())
 	}
+	if f.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
 
 	out.WriteString("func")
 	out.WriteString("(")
@@ -37,3 +44,13 @@ func (f *Function) Inspect() string {
 
 	return out.String()
 }
+
+// VariadicArgs returns the arguments collected by a variadic parameter at
+// index i, as an array that is empty if there are none.
+func VariadicArgs(args []VintObject, i int) *Array {
+	rest := []VintObject{}
+	if i < len(args) {
+		rest = append(rest, args[i:]...)
+	}
+	return &Array{Elements: rest}
+}
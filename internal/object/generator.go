@@ -0,0 +1,117 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Generator is a lazy sequence of values, such as the one returned by
+// calling a function that yields. Values are only produced as they are
+// asked for, so a generator can stand for a large file or an endless
+// series without holding it all in memory.
+type Generator struct {
+	Name string
+
+	mu   sync.Mutex // one value at a time, whichever goroutine asks
+	next func() (VintObject, bool)
+	stop func()
+	done bool
+}
+
+func (g *Generator) Type() VintObjectType { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string {
+	if g.Name == "" {
+		return "generator"
+	}
+	return fmt.Sprintf("generator(%s)", g.Name)
+}
+
+// Next returns the next value, and false once there are none left. If
+// producing a value fails, the error is the last value returned.
+func (g *Generator) Next() (VintObject, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return nil, false
+	}
+	value, ok := g.next()
+	if !ok || value.Type() == ERROR_OBJ {
+		g.done = true
+	}
+	return value, ok
+}
+
+// Close stops a generator that is not finished, after which it has no
+// more values.
+func (g *Generator) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return
+	}
+	g.done = true
+	if g.stop != nil {
+		g.stop()
+	}
+}
+
+// NewGenerator returns a generator whose values come from next, which
+// reports false when there are no more. stop, if not nil, is called when
+// the generator is closed before next has reported false.
+func NewGenerator(name string, next func() (VintObject, bool), stop func()) *Generator {
+	return &Generator{Name: name, next: next, stop: stop}
+}
+
+// NewBodyGenerator returns a generator that runs body on a goroutine of
+// its own. Each time a value is asked for, body runs until it passes one
+// to yield, and then waits inside yield until the next one is asked for.
+// yield reports false if the generator was closed instead, and body
+// should then return as soon as it can. An *Error returned by body is the
+// generator's last value.
+func NewBodyGenerator(name string, body func(yield func(VintObject) bool) VintObject) *Generator {
+	values := make(chan VintObject)
+	resume := make(chan bool)
+	started := false
+	var failed VintObject
+
+	yield := func(value VintObject) bool {
+		values <- value
+		return <-resume
+	}
+	run := func() {
+		defer close(values)
+		if !<-resume {
+			return
+		}
+		if result := body(yield); result != nil && result.Type() == ERROR_OBJ {
+			failed = result
+		}
+	}
+
+	next := func() (VintObject, bool) {
+		if !started {
+			started = true
+			go run()
+		}
+		resume <- true
+		if value, ok := <-values; ok {
+			return value, true
+		}
+		if failed != nil {
+			return failed, true
+		}
+		return nil, false
+	}
+	stop := func() {
+		if !started {
+			return
+		}
+		// Every yield made while the body winds down, as in a finally
+		// block, is told to stop too.
+		resume <- false
+		for range values {
+			resume <- false
+		}
+	}
+	return NewGenerator(name, next, stop)
+}
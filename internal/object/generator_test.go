@@ -0,0 +1,75 @@
+package object
+
+import "testing"
+
+func TestBodyGenerator(t *testing.T) {
+	started := false
+	g := NewBodyGenerator("count", func(yield func(VintObject) bool) VintObject {
+		started = true
+		for i := int64(0); i < 3; i++ {
+			if !yield(&Integer{Value: i}) {
+				return nil
+			}
+		}
+		return nil
+	})
+	if started {
+		t.Fatalf("body started before a value was asked for")
+	}
+
+	for want := int64(0); want < 3; want++ {
+		value, ok := g.Next()
+		if !ok || value.(*Integer).Value != want {
+			t.Fatalf("Next() = %v, %v, want %d", value, ok, want)
+		}
+	}
+	if value, ok := g.Next(); ok {
+		t.Fatalf("Next() after the last value = %v, want none", value)
+	}
+	if g.Inspect() != "generator(count)" {
+		t.Errorf("Inspect() = %q", g.Inspect())
+	}
+}
+
+func TestBodyGeneratorClose(t *testing.T) {
+	finished := make(chan []bool, 1)
+	g := NewBodyGenerator("", func(yield func(VintObject) bool) VintObject {
+		first := yield(&Integer{Value: 1})
+		// A yield while winding down, as in a finally block, is stopped too
+		second := yield(&Integer{Value: 2})
+		finished <- []bool{first, second}
+		return nil
+	})
+
+	if value, ok := g.Next(); !ok || value.(*Integer).Value != 1 {
+		t.Fatalf("Next() = %v, %v, want 1", value, ok)
+	}
+	g.Close()
+	if got := <-finished; got[0] || got[1] {
+		t.Errorf("yield reported %v after Close, want [false false]", got)
+	}
+	if value, ok := g.Next(); ok {
+		t.Errorf("Next() after Close = %v, want none", value)
+	}
+
+	// Closing a generator that never started does not start it
+	NewBodyGenerator("", func(yield func(VintObject) bool) VintObject {
+		t.Errorf("body of a closed generator ran")
+		return nil
+	}).Close()
+}
+
+func TestBodyGeneratorError(t *testing.T) {
+	g := NewBodyGenerator("", func(yield func(VintObject) bool) VintObject {
+		yield(&Integer{Value: 1})
+		return &Error{Message: "boom"}
+	})
+	g.Next()
+	value, ok := g.Next()
+	if err, isErr := value.(*Error); !ok || !isErr || err.Message != "boom" {
+		t.Fatalf("Next() = %v, %v, want the error", value, ok)
+	}
+	if value, ok := g.Next(); ok {
+		t.Errorf("Next() after an error = %v, want none", value)
+	}
+}
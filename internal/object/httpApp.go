@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -272,13 +273,18 @@ func (req *HTTPRequest) Method(name string, args []VintObject) VintObject {
 		return &String{Value: req.RemoteAddr}
 	case "headers":
 		// Return all headers as a Dict
-		pairs := make(map[HashKey]DictPair)
-		for k, v := range req.Headers {
+		names := make([]string, 0, len(req.Headers))
+		for k := range req.Headers {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		headers := NewDict()
+		for _, k := range names {
 			key := &String{Value: k}
-			val := &String{Value: v}
-			pairs[key.HashKey()] = DictPair{Key: key, Value: val}
+			val := &String{Value: req.Headers[k]}
+			headers.SetPair(key.HashKey(), DictPair{Key: key, Value: val})
 		}
-		return &Dict{Pairs: pairs}
+		return headers
 	default:
 		return &Error{Message: fmt.Sprintf("Unknown request method: %s", name)}
 	}
@@ -3,6 +3,7 @@ package object
 import (
 	"fmt"
 	"math"
+	"math/big"
 )
 
 type Integer struct {
@@ -65,6 +66,9 @@ func (i *Integer) abs(args []VintObject) VintObject {
 	if len(args) != 0 {
 		return newError("abs() expects 0 arguments, got %d", len(args))
 	}
+	if i.Value == math.MinInt64 {
+		return &BigInt{Value: new(big.Int).Neg(big.NewInt(i.Value))}
+	}
 	v := i.Value
 	if v < 0 {
 		v = -v
@@ -119,19 +123,8 @@ func (i *Integer) pow(args []VintObject) VintObject {
 		return newError("Negative exponents not supported for integers")
 	}
 
-	result := int64(1)
-	base := i.Value
-	exp := exponent.Value
-
-	for exp > 0 {
-		if exp%2 == 1 {
-			result *= base
-		}
-		base *= base
-		exp /= 2
-	}
-
-	return &Integer{Value: result}
+	// A result too large for an Integer is a BigInt
+	return IntegerFromBig(new(big.Int).Exp(big.NewInt(i.Value), big.NewInt(exponent.Value), nil))
 }
 
 func (i *Integer) sqrt(args []VintObject) VintObject {
@@ -209,16 +202,8 @@ func (i *Integer) factorial(args []VintObject) VintObject {
 		return newError("Factorial is not defined for negative numbers")
 	}
 
-	if i.Value > 20 {
-		return newError("Factorial of numbers greater than 20 may cause overflow")
-	}
-
-	result := int64(1)
-	for n := int64(2); n <= i.Value; n++ {
-		result *= n
-	}
-
-	return &Integer{Value: result}
+	// Factorials from 21! on are too large for an Integer and are BigInts
+	return IntegerFromBig(new(big.Int).MulRange(1, i.Value))
 }
 
 // toBinary converts the integer to binary representation
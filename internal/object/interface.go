@@ -0,0 +1,22 @@
+package object
+
+import (
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/ast"
+)
+
+// Interface is the value an interface declaration binds its name to. A
+// struct instance satisfies it if its struct has every method it lists.
+type Interface struct {
+	Def *ast.InterfaceType
+}
+
+func (i *Interface) Type() VintObjectType { return INTERFACE_OBJ }
+func (i *Interface) Inspect() string {
+	methods := make([]string, len(i.Def.Methods))
+	for j, m := range i.Def.Methods {
+		methods[j] = m.String()
+	}
+	return "interface " + i.Def.Name + " { " + strings.Join(methods, "; ") + " }"
+}
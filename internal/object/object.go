@@ -12,6 +12,8 @@ type VintObjectType string
 const (
 	INTEGER_OBJ         = "INTEGER"
 	FLOAT_OBJ           = "FLOAT"
+	BIGINT_OBJ          = "BIGINT"
+	DECIMAL_OBJ         = "DECIMAL"
 	BOOLEAN_OBJ         = "BOOLEAN"
 	NULL_OBJ            = "NULL"
 	RETURN_VALUE_OBJ    = "RETURN_VALUE"
@@ -38,6 +40,12 @@ const (
 	ENUM_OBJ            = "ENUM"
 	STRUCT_OBJ          = "STRUCT"
 	STRUCT_INSTANCE_OBJ = "STRUCT_INSTANCE"
+	INTERFACE_OBJ       = "INTERFACE"
+	GENERATOR_OBJ       = "GENERATOR"
+
+	// Bytecode Objects
+	COMPILED_FUNCTION_OBJ = "COMPILED_FUNCTION"
+	CELL_OBJ              = "CELL"
 
 	// Async/Concurrency Objects
 	PROMISE_OBJ        = "PROMISE"
@@ -107,6 +115,10 @@ func (et *ErrorType) Inspect() string {
 	return fmt.Sprintf("error type: %s(%s)", et.Name, strings.Join(et.Parameters, ", "))
 }
 
+// RuntimeErrorType is the error type given to runtime errors (failed
+// operations, `error` statements, module errors) when they are caught.
+var RuntimeErrorType = &ErrorType{Name: "RuntimeError", Parameters: []string{"message"}}
+
 // CustomError represents an instance of a custom error type
 type CustomError struct {
 	ErrorType *ErrorType
@@ -122,3 +134,17 @@ func (ce *CustomError) Inspect() string {
 	return fmt.Sprintf("\x1b[1;31m%s:\x1b[0m %s(%s)",
 		ce.ErrorType.Name, ce.ErrorType.Name, strings.Join(args, ", "))
 }
+
+// GetField returns the argument bound to the named error parameter.
+// The pseudo-field "name" returns the error type name if no parameter shadows it.
+func (ce *CustomError) GetField(name string) (VintObject, bool) {
+	for i, param := range ce.ErrorType.Parameters {
+		if param == name && i < len(ce.Arguments) {
+			return ce.Arguments[i], true
+		}
+	}
+	if name == "name" {
+		return &String{Value: ce.ErrorType.Name}, true
+	}
+	return nil, false
+}
@@ -1,9 +1,18 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
 
+	"github.com/vintlang/vintlang/internal/ast"
+)
+
+// Pointer is made by &. A pointer to a variable, a struct field or an
+// array element refers to that slot, so reading through it sees the
+// slot's current value and *p = v writes to the slot. A pointer to any
+// other value holds a copy of the value.
 type Pointer struct {
-	Ref VintObject
+	Ref  VintObject // the value, for pointers that have no slot
+	Slot Slot       // the variable, field or element pointed at, or nil
 }
 
 func (p *Pointer) Type() VintObjectType {
@@ -11,5 +20,126 @@ func (p *Pointer) Type() VintObjectType {
 }
 
 func (p *Pointer) Inspect() string {
-	return fmt.Sprintf("Pointer(addr=%p, value=%s)", p.Ref, p.Ref.Inspect())
+	value, err := p.Load()
+	if err != nil {
+		return fmt.Sprintf("Pointer(addr=%p, %s)", p.Slot, err)
+	}
+	if p.Slot != nil {
+		return fmt.Sprintf("Pointer(addr=%p, value=%s)", p.Slot, value.Inspect())
+	}
+	return fmt.Sprintf("Pointer(addr=%p, value=%s)", p.Ref, value.Inspect())
+}
+
+// Load returns the value the pointer points at.
+func (p *Pointer) Load() (VintObject, error) {
+	if p.Slot != nil {
+		return p.Slot.Load()
+	}
+	if p.Ref == nil {
+		return nil, fmt.Errorf("pointer is nil")
+	}
+	return p.Ref, nil
+}
+
+// Store sets the value the pointer points at.
+func (p *Pointer) Store(val VintObject) error {
+	if p.Slot != nil {
+		return p.Slot.Store(val)
+	}
+	p.Ref = val
+	return nil
+}
+
+// Slot is a place that holds a value, which a pointer can refer to.
+type Slot interface {
+	Load() (VintObject, error)
+	Store(val VintObject) error
+	// DeclaredType returns the type declared for the slot, or nil.
+	DeclaredType() ast.Type
+	// String names the slot in messages.
+	String() string
+}
+
+// VariableSlot is the variable called Name in the environment Env, which
+// is the one that declares it.
+type VariableSlot struct {
+	Env  *Environment
+	Name string
+}
+
+func (s *VariableSlot) Load() (VintObject, error) {
+	val, ok := s.Env.Get(s.Name)
+	if !ok {
+		return nil, fmt.Errorf("variable '%s' no longer exists", s.Name)
+	}
+	return val, nil
+}
+
+func (s *VariableSlot) Store(val VintObject) error {
+	result, _ := s.Env.Assign(s.Name, val)
+	if err, ok := result.(*Error); ok {
+		return fmt.Errorf("%s", err.Message)
+	}
+	return nil
+}
+
+func (s *VariableSlot) DeclaredType() ast.Type {
+	t, _ := s.Env.GetDeclaredType(s.Name)
+	return t
+}
+
+func (s *VariableSlot) String() string { return fmt.Sprintf("variable '%s'", s.Name) }
+
+// FieldSlot is the field called Name of a struct instance.
+type FieldSlot struct {
+	Instance *StructInstance
+	Name     string
+}
+
+func (s *FieldSlot) Load() (VintObject, error) {
+	val, ok := s.Instance.GetField(s.Name)
+	if !ok {
+		return nil, fmt.Errorf("struct '%s' has no field '%s'", s.Instance.Struct.Name, s.Name)
+	}
+	return val, nil
+}
+
+func (s *FieldSlot) Store(val VintObject) error { return s.Instance.SetField(s.Name, val) }
+
+func (s *FieldSlot) DeclaredType() ast.Type { return s.Instance.Struct.GetFieldType(s.Name) }
+
+func (s *FieldSlot) String() string {
+	return fmt.Sprintf("field '%s' in struct '%s'", s.Name, s.Instance.Struct.Name)
+}
+
+// ElementSlot is the element at Index of an array. Index counts from the
+// start of the array even if elements are removed after the pointer is
+// made, and reading or writing through the pointer fails once the array
+// is too short to have it.
+type ElementSlot struct {
+	Array *Array
+	Index int
 }
+
+func (s *ElementSlot) Load() (VintObject, error) {
+	if s.Index >= len(s.Array.Elements) {
+		return nil, s.outOfRange()
+	}
+	return s.Array.Elements[s.Index], nil
+}
+
+func (s *ElementSlot) Store(val VintObject) error {
+	if s.Index >= len(s.Array.Elements) {
+		return s.outOfRange()
+	}
+	s.Array.Elements[s.Index] = val
+	return nil
+}
+
+func (s *ElementSlot) outOfRange() error {
+	return fmt.Errorf("pointer to index %d of an array that now has length %d", s.Index, len(s.Array.Elements))
+}
+
+func (s *ElementSlot) DeclaredType() ast.Type { return nil }
+
+func (s *ElementSlot) String() string { return fmt.Sprintf("array element %d", s.Index) }
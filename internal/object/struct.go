@@ -13,6 +13,7 @@ type StructField struct {
 	Name    string
 	Type    ast.Type       // optional type annotation
 	Default ast.Expression // optional default value expression
+	Env     *Environment   // where an embedded field's default is evaluated, nil for own fields
 }
 
 // StructMethod defines a method in a struct definition
@@ -22,7 +23,10 @@ type StructMethod struct {
 	ParamTypes []ast.Type      // parallel to Parameters, nil for untyped
 	ReturnType ast.Type        // nil for void/untyped
 	Defaults   map[string]ast.Expression
+	Variadic   bool // the last parameter collects the remaining arguments
+	Generator  bool // the body yields, so a call returns a generator
 	Body       *ast.BlockStatement
+	Env        *Environment // where a promoted method runs, nil for own methods
 }
 
 // Struct represents a struct type definition (the blueprint)
@@ -31,6 +35,9 @@ type Struct struct {
 	Fields  []StructField
 	Methods map[string]*StructMethod
 	Env     *Environment // the environment where the struct was defined
+
+	TypeParams []*ast.TypeParameter // for a generic struct
+	TypeArgs   map[string]ast.Type  // type arguments given with Box<int>, nil if none
 }
 
 func (s *Struct) Type() VintObjectType { return STRUCT_OBJ }
@@ -91,6 +98,10 @@ func (s *Struct) GetMethod(name string) (*StructMethod, bool) {
 type StructInstance struct {
 	Struct *Struct      // reference to the struct definition
 	Fields *Environment // instance fields with their values
+
+	// What the struct's type parameters stand for in this instance, as far
+	// as they are known
+	TypeArgs map[string]ast.Type
 }
 
 func (si *StructInstance) Type() VintObjectType { return STRUCT_INSTANCE_OBJ }
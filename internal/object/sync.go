@@ -0,0 +1,291 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Synchronization primitives for Vint code that uses `go` and channels.
+// They wrap the types of Go's sync package. Misuse that would crash the
+// interpreter in Go, such as unlocking a mutex that is not locked, is
+// reported as an error instead.
+
+const (
+	MUTEX_OBJ          = "MUTEX"
+	RWMUTEX_OBJ        = "RWMUTEX"
+	WAITGROUP_OBJ      = "WAITGROUP"
+	ONCE_OBJ           = "ONCE"
+	ATOMIC_INTEGER_OBJ = "ATOMIC_INTEGER"
+)
+
+type Mutex struct {
+	mu     sync.Mutex
+	locked atomic.Bool
+}
+
+func (m *Mutex) Type() VintObjectType { return MUTEX_OBJ }
+func (m *Mutex) Inspect() string {
+	if m.locked.Load() {
+		return "mutex(locked)"
+	}
+	return "mutex(unlocked)"
+}
+
+func (m *Mutex) Method(method string, args []VintObject) VintObject {
+	switch method {
+	case "lock":
+		if len(args) != 0 {
+			return newError("lock() takes no arguments, got %d", len(args))
+		}
+		m.mu.Lock()
+		m.locked.Store(true)
+		return &Null{}
+	case "unlock":
+		if len(args) != 0 {
+			return newError("unlock() takes no arguments, got %d", len(args))
+		}
+		if !m.locked.CompareAndSwap(true, false) {
+			return newError("unlock of unlocked mutex")
+		}
+		m.mu.Unlock()
+		return &Null{}
+	case "tryLock":
+		if len(args) != 0 {
+			return newError("tryLock() takes no arguments, got %d", len(args))
+		}
+		if !m.mu.TryLock() {
+			return &Boolean{Value: false}
+		}
+		m.locked.Store(true)
+		return &Boolean{Value: true}
+	case "withLock":
+		fn, ok := singleFunctionArg(args)
+		if !ok {
+			return newError("withLock() expects a function")
+		}
+		m.mu.Lock()
+		m.locked.Store(true)
+		defer func() {
+			m.locked.Store(false)
+			m.mu.Unlock()
+		}()
+		return CallFunction(fn, []VintObject{})
+	default:
+		return newError("Sorry, the method '%s' is not supported for Mutex.", method)
+	}
+}
+
+type RWMutex struct {
+	mu      sync.RWMutex
+	locked  atomic.Bool
+	readers atomic.Int64
+}
+
+func (m *RWMutex) Type() VintObjectType { return RWMUTEX_OBJ }
+func (m *RWMutex) Inspect() string {
+	switch {
+	case m.locked.Load():
+		return "rwmutex(locked)"
+	case m.readers.Load() > 0:
+		return fmt.Sprintf("rwmutex(readers: %d)", m.readers.Load())
+	}
+	return "rwmutex(unlocked)"
+}
+
+func (m *RWMutex) Method(method string, args []VintObject) VintObject {
+	if len(args) != 0 {
+		return newError("%s() takes no arguments, got %d", method, len(args))
+	}
+	switch method {
+	case "lock":
+		m.mu.Lock()
+		m.locked.Store(true)
+	case "unlock":
+		if !m.locked.CompareAndSwap(true, false) {
+			return newError("unlock of unlocked rwmutex")
+		}
+		m.mu.Unlock()
+	case "rLock":
+		m.mu.RLock()
+		m.readers.Add(1)
+	case "rUnlock":
+		for {
+			n := m.readers.Load()
+			if n <= 0 {
+				return newError("rUnlock of rwmutex that is not read-locked")
+			}
+			if m.readers.CompareAndSwap(n, n-1) {
+				break
+			}
+		}
+		m.mu.RUnlock()
+	default:
+		return newError("Sorry, the method '%s' is not supported for RWMutex.", method)
+	}
+	return &Null{}
+}
+
+type WaitGroup struct {
+	wg      sync.WaitGroup
+	counter atomic.Int64
+}
+
+func (w *WaitGroup) Type() VintObjectType { return WAITGROUP_OBJ }
+func (w *WaitGroup) Inspect() string {
+	return fmt.Sprintf("waitgroup(%d)", w.counter.Load())
+}
+
+func (w *WaitGroup) Method(method string, args []VintObject) VintObject {
+	switch method {
+	case "add":
+		delta := int64(1)
+		if len(args) > 1 {
+			return newError("add() takes at most 1 argument, got %d", len(args))
+		}
+		if len(args) == 1 {
+			n, ok := args[0].(*Integer)
+			if !ok {
+				return newError("add() expects an integer, got %s", args[0].Type())
+			}
+			delta = n.Value
+		}
+		return w.add(delta)
+	case "done":
+		if len(args) != 0 {
+			return newError("done() takes no arguments, got %d", len(args))
+		}
+		return w.add(-1)
+	case "wait":
+		if len(args) != 0 {
+			return newError("wait() takes no arguments, got %d", len(args))
+		}
+		w.wg.Wait()
+		return &Null{}
+	default:
+		return newError("Sorry, the method '%s' is not supported for WaitGroup.", method)
+	}
+}
+
+func (w *WaitGroup) add(delta int64) VintObject {
+	for {
+		n := w.counter.Load()
+		if n+delta < 0 {
+			return newError("negative WaitGroup counter")
+		}
+		if w.counter.CompareAndSwap(n, n+delta) {
+			break
+		}
+	}
+	w.wg.Add(int(delta))
+	return &Null{}
+}
+
+type Once struct {
+	once   sync.Once
+	result VintObject
+}
+
+func (o *Once) Type() VintObjectType { return ONCE_OBJ }
+func (o *Once) Inspect() string      { return "once" }
+
+func (o *Once) Method(method string, args []VintObject) VintObject {
+	switch method {
+	case "do":
+		fn, ok := singleFunctionArg(args)
+		if !ok {
+			return newError("do() expects a function")
+		}
+		o.once.Do(func() {
+			o.result = CallFunction(fn, []VintObject{})
+		})
+		if o.result == nil {
+			return &Null{}
+		}
+		return o.result
+	default:
+		return newError("Sorry, the method '%s' is not supported for Once.", method)
+	}
+}
+
+type AtomicInteger struct {
+	value atomic.Int64
+}
+
+func NewAtomicInteger(value int64) *AtomicInteger {
+	a := &AtomicInteger{}
+	a.value.Store(value)
+	return a
+}
+
+func (a *AtomicInteger) Type() VintObjectType { return ATOMIC_INTEGER_OBJ }
+func (a *AtomicInteger) Inspect() string {
+	return fmt.Sprintf("atomic(%d)", a.value.Load())
+}
+
+func (a *AtomicInteger) Method(method string, args []VintObject) VintObject {
+	ints := make([]int64, len(args))
+	for i, arg := range args {
+		n, ok := arg.(*Integer)
+		if !ok {
+			return newError("%s() expects integers, got %s", method, arg.Type())
+		}
+		ints[i] = n.Value
+	}
+
+	expect := func(n int) *Error {
+		if len(args) != n {
+			return newError("%s() takes %d arguments, got %d", method, n, len(args))
+		}
+		return nil
+	}
+
+	switch method {
+	case "get":
+		if err := expect(0); err != nil {
+			return err
+		}
+		return &Integer{Value: a.value.Load()}
+	case "set":
+		if err := expect(1); err != nil {
+			return err
+		}
+		a.value.Store(ints[0])
+		return &Null{}
+	case "add":
+		if err := expect(1); err != nil {
+			return err
+		}
+		return &Integer{Value: a.value.Add(ints[0])}
+	case "increment":
+		if err := expect(0); err != nil {
+			return err
+		}
+		return &Integer{Value: a.value.Add(1)}
+	case "decrement":
+		if err := expect(0); err != nil {
+			return err
+		}
+		return &Integer{Value: a.value.Add(-1)}
+	case "swap":
+		if err := expect(1); err != nil {
+			return err
+		}
+		return &Integer{Value: a.value.Swap(ints[0])}
+	case "compareAndSwap":
+		if err := expect(2); err != nil {
+			return err
+		}
+		return &Boolean{Value: a.value.CompareAndSwap(ints[0], ints[1])}
+	default:
+		return newError("Sorry, the method '%s' is not supported for AtomicInteger.", method)
+	}
+}
+
+func singleFunctionArg(args []VintObject) (*Function, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	fn, ok := args[0].(*Function)
+	return fn, ok
+}
@@ -440,14 +440,14 @@ func (t *Time) isoWeek(args []VintObject, defs map[string]VintObject) VintObject
 
 	year, week := curTime.ISOWeek()
 
-	pairs := make(map[HashKey]DictPair)
+	dict := NewDict()
 	yearKey := &String{Value: "year"}
 	weekKey := &String{Value: "week"}
 
-	pairs[yearKey.HashKey()] = DictPair{Key: yearKey, Value: &Integer{Value: int64(year)}}
-	pairs[weekKey.HashKey()] = DictPair{Key: weekKey, Value: &Integer{Value: int64(week)}}
+	dict.SetPair(yearKey.HashKey(), DictPair{Key: yearKey, Value: &Integer{Value: int64(year)}})
+	dict.SetPair(weekKey.HashKey(), DictPair{Key: weekKey, Value: &Integer{Value: int64(week)}})
 
-	return &Dict{Pairs: pairs}
+	return dict
 }
 
 func (t *Time) timezone(args []VintObject, defs map[string]VintObject) VintObject {
@@ -42,9 +42,8 @@ func (p *Parser) parseArrayPattern() ast.Expression {
 				Value: p.curToken.Literal,
 			}
 
-			p.nextToken()
 			// Spread must be last element
-			if p.curTokenIs(token.COMMA) {
+			if p.peekTokenIs(token.COMMA) {
 				p.addError("Spread element must be last in array pattern")
 				return nil
 			}
@@ -95,16 +94,14 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	return list
 }
 
-func (p *Parser) parseSpreadPattern() ast.Expression {
-	spread := &ast.SpreadPattern{Token: p.curToken}
-
-	if p.peekTokenIs(token.IDENT) {
-		p.nextToken()
-		spread.Name = &ast.Identifier{
-			Token: p.curToken,
-			Value: p.curToken.Literal,
-		}
+// parseSpreadExpression parses ...value in calls, array literals and
+// dict literals.
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	spread := &ast.SpreadExpression{Token: p.curToken}
+	p.nextToken()
+	spread.Value = p.parseExpression(PREFIX)
+	if spread.Value == nil {
+		return nil
 	}
-
 	return spread
 }
@@ -7,6 +7,11 @@ import (
 )
 
 func (p *Parser) parseAssignEqualExpression(exp ast.Expression) ast.Expression {
+	if inOptionalChain(exp) {
+		msg := fmt.Sprintf("Line %d: Cannot assign to an optional chain", p.curToken.Line)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
 	switch node := exp.(type) {
 	case *ast.Identifier:
 		e := &ast.AssignEqual{
@@ -17,6 +22,17 @@ func (p *Parser) parseAssignEqualExpression(exp ast.Expression) ast.Expression {
 		p.nextToken()
 		e.Value = p.parseExpression(precedence)
 		return e
+	case *ast.PrefixExpression:
+		// *p += value updates what the pointer p points at
+		if node.Operator != "*" {
+			msg := fmt.Sprintf("Line %d: Expected an identifier or array, but found: %s", p.curToken.Line, node.TokenLiteral())
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		ae := &ast.AssignmentExpression{Token: p.curToken, Left: exp}
+		p.nextToken()
+		ae.Value = p.parseExpression(LOWEST)
+		return ae
 	case *ast.IndexExpression:
 		ae := &ast.AssignmentExpression{Token: p.curToken, Left: exp}
 
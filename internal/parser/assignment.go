@@ -7,6 +7,11 @@ import (
 )
 
 func (p *Parser) parseAssignmentExpression(exp ast.Expression) ast.Expression {
+	if inOptionalChain(exp) {
+		msg := fmt.Sprintf("Line %d: Cannot assign to an optional chain", p.curToken.Line)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
 	switch node := exp.(type) {
 	case *ast.Identifier:
 		e := &ast.Assign{
@@ -19,6 +24,13 @@ func (p *Parser) parseAssignmentExpression(exp ast.Expression) ast.Expression {
 		return e
 
 	case *ast.IndexExpression:
+	case *ast.PrefixExpression:
+		// *p = value writes through the pointer p
+		if node.Operator != "*" {
+			msg := fmt.Sprintf("Line %d: Expected an identifier or an array, but found: %s", p.curToken.Line, node.TokenLiteral())
+			p.errors = append(p.errors, msg)
+			return nil
+		}
 	case *ast.PropertyExpression:
 		e := &ast.PropertyAssignment{
 			Token: p.curToken,
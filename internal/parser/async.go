@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+
 	"github.com/vintlang/vintlang/internal/ast"
 	"github.com/vintlang/vintlang/internal/token"
 )
@@ -25,7 +27,12 @@ func (p *Parser) parseAsyncFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Body = p.parseBlockStatement()
+	body, generator := p.parseFunctionBody()
+	if generator {
+		msg := fmt.Sprintf("Line %d: an async function cannot yield", lit.Token.Line)
+		p.errors = append(p.errors, msg)
+	}
+	lit.Body = body
 
 	return lit
 }
@@ -42,6 +49,19 @@ func (p *Parser) parseAsyncFunctionParameters(lit *ast.AsyncFunctionLiteral) boo
 			continue
 		}
 
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
+			lit.Parameters = append(lit.Parameters, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+			lit.Variadic = true
+			if !p.peekTokenIs(token.RPAREN) {
+				p.addError("variadic parameter '" + p.curToken.Literal + "' must be the last parameter")
+				return false
+			}
+			continue
+		}
+
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		lit.Parameters = append(lit.Parameters, ident)
 
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// parseDestructureStatement parses let or const followed by a pattern:
+// let [first, second, ...rest] = items
+// const {name, age: years, ...others} = user
+func (p *Parser) parseDestructureStatement() ast.Statement {
+	stmt := &ast.DestructureStatement{Token: p.curToken}
+	p.nextToken()
+
+	stmt.Pattern = p.parseBindingPattern()
+	if stmt.Pattern == nil {
+		p.skipToNextStatement()
+		return nil
+	}
+	if !p.expectPeek(token.ASSIGN) {
+		p.skipToNextStatement()
+		return nil
+	}
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseBindingPattern parses the pattern of a destructuring let, const or
+// for: an array pattern or a dict pattern, whose elements are names or
+// nested patterns.
+func (p *Parser) parseBindingPattern() ast.Expression {
+	switch p.curToken.Type {
+	case token.LBRACKET:
+		return p.parseArrayBindingPattern()
+	case token.LBRACE:
+		return p.parseDictPattern()
+	}
+	p.addError(fmt.Sprintf("expected '[' or '{' to start a pattern, got %s", p.curToken.Literal))
+	return nil
+}
+
+// parsePatternElement parses one element of a binding pattern, a name or
+// a nested pattern. A name may have a default, name = value, used when the
+// value has no element or key for it.
+func (p *Parser) parsePatternElement() ast.Expression {
+	if !p.curTokenIs(token.IDENT) {
+		return p.parseBindingPattern()
+	}
+	name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.peekTokenIs(token.ASSIGN) {
+		return name
+	}
+	p.nextToken()
+	assign := &ast.Assign{Token: p.curToken, Name: name}
+	p.nextToken()
+	assign.Value = p.parseExpression(LOWEST)
+	if assign.Value == nil {
+		return nil
+	}
+	return assign
+}
+
+// parsePatternRest parses ...name, the last element of a pattern.
+func (p *Parser) parsePatternRest(end token.TokenType) *ast.Identifier {
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	rest := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.COMMA) {
+		p.addError("the ... element must be last in a pattern")
+		return nil
+	}
+	if !p.expectPeek(end) {
+		return nil
+	}
+	return rest
+}
+
+func (p *Parser) parseArrayBindingPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if pattern.Rest = p.parsePatternRest(token.RBRACKET); pattern.Rest == nil {
+				return nil
+			}
+			return pattern
+		}
+
+		element := p.parsePatternElement()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+	return pattern
+}
+
+func (p *Parser) parseDictPattern() ast.Expression {
+	pattern := &ast.DictPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if pattern.Rest = p.parsePatternRest(token.RBRACE); pattern.Rest == nil {
+				return nil
+			}
+			return pattern
+		}
+
+		// The key is a name or a string: {name}, {name: n}, {"full name": n}
+		if !p.curTokenIs(token.IDENT) && !p.curTokenIs(token.STRING) {
+			p.addError(fmt.Sprintf("expected a key in dict pattern, got %s", p.curToken.Literal))
+			return nil
+		}
+		key := p.curToken.Literal
+
+		var value ast.Expression
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parsePatternElement()
+		} else if p.curTokenIs(token.IDENT) {
+			value = p.parsePatternElement()
+		} else {
+			p.addError(fmt.Sprintf("expected ':' after \"%s\" in dict pattern", key))
+			return nil
+		}
+		if value == nil {
+			return nil
+		}
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+	return pattern
+}
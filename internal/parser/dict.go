@@ -13,6 +13,16 @@ func (p *Parser) parseDictLiteral() ast.Expression {
 		p.nextToken()
 		key := p.parseExpression(LOWEST)
 
+		// {...other} merges another dict into this one
+		if spread, ok := key.(*ast.SpreadExpression); ok && !p.peekTokenIs(token.COLON) {
+			dict.Pairs[spread] = spread.Value
+			dict.Keys = append(dict.Keys, spread)
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
 		if !p.expectPeek(token.COLON) {
 			return nil
 		}
@@ -21,6 +31,7 @@ func (p *Parser) parseDictLiteral() ast.Expression {
 		value := p.parseExpression(LOWEST)
 
 		dict.Pairs[key] = value
+		dict.Keys = append(dict.Keys, key)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
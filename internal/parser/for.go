@@ -8,6 +8,9 @@ import (
 func (p *Parser) parseForExpression() ast.Expression {
 	expression := &ast.For{Token: p.curToken}
 	p.nextToken()
+	if p.curTokenIs(token.LBRACKET) || p.curTokenIs(token.LBRACE) {
+		return p.parseForInExpression(expression)
+	}
 	if !p.curTokenIs(token.IDENT) {
 		return nil
 	}
@@ -63,22 +66,35 @@ func (p *Parser) parseForExpression() ast.Expression {
 func (p *Parser) parseForInExpression(initialExpression *ast.For) ast.Expression {
 	expression := &ast.ForIn{Token: initialExpression.Token}
 	if !p.curTokenIs(token.IDENT) {
-		return nil
-	}
-	val := p.curToken.Literal
-	var key string
-	p.nextToken()
-	if p.curTokenIs(token.COMMA) {
-		p.nextToken()
-		if !p.curTokenIs(token.IDENT) {
+		// for [k, v] in pairs
+		if expression.Pattern = p.parseBindingPattern(); expression.Pattern == nil {
 			return nil
 		}
-		key = val
-		val = p.curToken.Literal
 		p.nextToken()
+	} else {
+		val := p.curToken.Literal
+		var key string
+		p.nextToken()
+		if p.curTokenIs(token.COMMA) {
+			p.nextToken()
+			key = val
+			switch {
+			case p.curTokenIs(token.IDENT):
+				val = p.curToken.Literal
+			case p.curTokenIs(token.LBRACKET) || p.curTokenIs(token.LBRACE):
+				// for i, [k, v] in pairs
+				val = ""
+				if expression.Pattern = p.parseBindingPattern(); expression.Pattern == nil {
+					return nil
+				}
+			default:
+				return nil
+			}
+			p.nextToken()
+		}
+		expression.Key = key
+		expression.Value = val
 	}
-	expression.Key = key
-	expression.Value = val
 	if !p.curTokenIs(token.IN) {
 		return nil
 	}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+
 	"github.com/vintlang/vintlang/internal/ast"
 	"github.com/vintlang/vintlang/internal/token"
 )
@@ -14,6 +16,27 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		name = p.curToken.Literal
 	}
 
+	// Type parameters of a generic function: func first<T>(xs: []T): T
+	var typeParams []*ast.TypeParameter
+	if p.peekTokenIs(token.LT) {
+		p.nextToken()
+		if typeParams = p.parseTypeParameters(); typeParams == nil {
+			return nil
+		}
+		if name != "" {
+			p.generics[name] = true
+		}
+	}
+
+	var lit ast.Expression
+	p.withTypeParams(typeParams, func() {
+		lit = p.parseFunctionRest(tok, name, typeParams)
+	})
+	return lit
+}
+
+// parseFunctionRest parses a function literal from its parameter list on.
+func (p *Parser) parseFunctionRest(tok token.Token, name string, typeParams []*ast.TypeParameter) ast.Expression {
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
@@ -37,9 +60,12 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	body := p.parseBlockStatement()
+	body, generator := p.parseFunctionBody()
+	if generator && returnType != nil {
+		p.generatorReturnTypeError(name)
+	}
 
-	if !hasTypes {
+	if !hasTypes && len(typeParams) == 0 {
 		lit := &ast.FunctionLiteral{Token: tok, Name: name}
 		lit.Defaults = make(map[string]ast.Expression)
 		for _, tp := range params {
@@ -49,13 +75,17 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 			}
 		}
 		lit.Body = body
+		lit.Variadic = isVariadic(params)
+		lit.Generator = generator
 		return lit
 	}
 
 	flit := &ast.TypedFunctionLiteral{
 		Token:      tok,
+		TypeParams: typeParams,
 		Parameters: params,
 		ReturnType: returnType,
+		Generator:  generator,
 		Body:       body,
 	}
 	if name != "" {
@@ -64,6 +94,29 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return flit
 }
 
+// parseFunctionBody parses the body of a function and reports whether it
+// yields, which makes the function a generator.
+func (p *Parser) parseFunctionBody() (*ast.BlockStatement, bool) {
+	outer := p.yields
+	yields := false
+	p.yields = &yields
+	body := p.parseBlockStatement()
+	p.yields = outer
+	return body, yields
+}
+
+// generatorReturnTypeError reports a return type declared for a generator
+// function, which returns a generator whatever its body returns.
+func (p *Parser) generatorReturnTypeError(name string) {
+	if name == "" {
+		name = "function"
+	} else {
+		name = "'" + name + "'"
+	}
+	msg := fmt.Sprintf("Line %d: generator %s cannot declare a return type", p.curToken.Line, name)
+	p.errors = append(p.errors, msg)
+}
+
 // parseTypedFunctionParameters parses function parameters, supporting typed syntax.
 // Returns the list of TypedParameters and whether any type annotations were found.
 func (p *Parser) parseTypedFunctionParameters() ([]*ast.TypedParameter, bool) {
@@ -91,13 +144,20 @@ func (p *Parser) parseTypedFunctionParameters() ([]*ast.TypedParameter, bool) {
 			break
 		}
 
+		// A variadic parameter: ...name or ...name: type
+		variadic := false
+		if p.curTokenIs(token.ELLIPSIS) {
+			variadic = true
+			p.nextToken()
+		}
+
 		if p.curToken.Type != token.IDENT {
 			p.addError("expected parameter name, got " + p.curToken.Literal)
 			return nil, false
 		}
 
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		tp := &ast.TypedParameter{Token: p.curToken, Identifier: ident}
+		tp := &ast.TypedParameter{Token: p.curToken, Identifier: ident, Variadic: variadic}
 
 		// Check for type annotation: name: type
 		if p.peekTokenIs(token.COLON) {
@@ -108,18 +168,27 @@ func (p *Parser) parseTypedFunctionParameters() ([]*ast.TypedParameter, bool) {
 		}
 
 		// Check for default value: name: type = value
+		if variadic && (p.peekTokenIs(token.ASSIGN) || p.curTokenIs(token.ASSIGN)) {
+			p.addError("variadic parameter '" + ident.Value + "' cannot have a default value")
+			return nil, false
+		}
 		if p.peekTokenIs(token.ASSIGN) {
 			p.nextToken() // '='
 			p.nextToken() // value
 			tp.Default = p.parseExpression(LOWEST)
 			hasDefaults = true
-		} else if hasDefaults {
+		} else if hasDefaults && !variadic {
 			p.addError("non-default parameter cannot appear after a default parameter")
 			return nil, false
 		}
 
 		params = append(params, tp)
 
+		if variadic && !p.curTokenIs(token.RPAREN) && !p.peekTokenIs(token.RPAREN) {
+			p.addError("variadic parameter '" + ident.Value + "' must be the last parameter")
+			return nil, false
+		}
+
 		// After parsing a parameter, check what comes next.
 		// parseType may have left us at ',' (typed param) or at the next token.
 		if p.curTokenIs(token.RPAREN) {
@@ -139,6 +208,12 @@ func (p *Parser) parseTypedFunctionParameters() ([]*ast.TypedParameter, bool) {
 	return params, hasTypes
 }
 
+// isVariadic reports whether the last of params collects the remaining
+// arguments.
+func isVariadic(params []*ast.TypedParameter) bool {
+	return len(params) > 0 && params[len(params)-1].Variadic
+}
+
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
@@ -2,8 +2,30 @@ package parser
 
 import (
 	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
 )
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	// A generic function or struct followed by '<' and something that can
+	// start a type is given type arguments, as in first<int>(xs), rather
+	// than compared with '<'.
+	if p.generics[ident.Value] && p.peekTokenIs(token.LT) && startsType(p.peekSecondToken()) {
+		p.nextToken()
+		inst := &ast.InstantiateExpression{Token: p.curToken, Function: ident}
+		if inst.TypeArgs = p.parseTypeArguments(); inst.TypeArgs == nil {
+			return nil
+		}
+		return inst
+	}
+	return ident
+}
+
+// startsType reports whether tok can be the first token of a type.
+func startsType(tok token.Token) bool {
+	switch tok.Type {
+	case token.IDENT, token.ERROR, token.LBRACKET, token.LBRACE, token.ASTERISK, token.FUNCTION, token.CHAN, token.LPAREN:
+		return true
+	}
+	return false
 }
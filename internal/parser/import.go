@@ -14,7 +14,7 @@ func (p *Parser) parseImport() ast.Expression {
 		return nil
 	}
 	
-	identifier := &ast.Identifier{Value: p.curToken.Literal}
+	identifier := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	exp.Identifiers[p.curToken.Literal] = identifier
 	
 	// Handle comma-separated imports like "import time, math, string"
@@ -23,7 +23,7 @@ func (p *Parser) parseImport() ast.Expression {
 		if !p.expectPeek(token.IDENT) {
 			return nil
 		}
-		identifier := &ast.Identifier{Value: p.curToken.Literal}
+		identifier := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		exp.Identifiers[p.curToken.Literal] = identifier
 	}
 
@@ -1,8 +1,11 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/vintlang/vintlang/internal/ast"
 )
@@ -11,6 +14,12 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// Too large for an int64, so it is a bigint
+		if big, ok := new(big.Int).SetString(p.curToken.Literal, 0); ok {
+			return &ast.BigIntLiteral{Token: p.curToken, Value: big}
+		}
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Line %d: We cannot parse %q as a number", p.curToken.Line, p.curToken.Literal)
 		p.errors = append(p.errors, msg)
@@ -20,3 +29,14 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	return lit
 }
+
+// parseBigIntLiteral parses an integer with an n suffix: 123n.
+func (p *Parser) parseBigIntLiteral() ast.Expression {
+	value, ok := new(big.Int).SetString(strings.TrimSuffix(p.curToken.Literal, "n"), 0)
+	if !ok {
+		msg := fmt.Sprintf("Line %d: We cannot parse %q as a number", p.curToken.Line, p.curToken.Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	return &ast.BigIntLiteral{Token: p.curToken, Value: value}
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// parseInterfaceStatement parses an interface declaration:
+//
+//	interface Shape {
+//	    Named                 // embeds the methods of another interface
+//	    area(): float
+//	    scale(k: float): Shape
+//	}
+//
+// Methods can also be written with 'func', and separated by ';' or ','.
+// The name is registered like a type alias, so later annotations and 'is'
+// checks that use it get the interface itself.
+func (p *Parser) parseInterfaceStatement() ast.Statement {
+	stmt := &ast.InterfaceStatement{Token: p.curToken}
+	p.nextToken()
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	stmt.Type = &ast.InterfaceType{Token: p.curToken, Name: p.curToken.Literal}
+
+	// Register the interface first so that its methods can refer to it
+	p.aliases[stmt.Name.Value] = stmt.Type
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) {
+		switch {
+		case p.curTokenIs(token.EOF):
+			p.addError(fmt.Sprintf("interface '%s' is not closed, expected '}'", stmt.Name.Value))
+			return nil
+		case p.curTokenIs(token.SEMICOLON) || p.curTokenIs(token.COMMA):
+		case p.curTokenIs(token.FUNCTION) && p.peekTokenIs(token.IDENT):
+			p.nextToken()
+			fallthrough
+		case p.curTokenIs(token.IDENT) && p.peekTokenIs(token.LPAREN):
+			method := p.parseInterfaceMethod()
+			if method == nil {
+				return nil
+			}
+			if stmt.Type.Method(method.Name.Value) != nil {
+				p.errors = append(p.errors, p.formatErr(method.Name.Token.Line, method.Name.Token.Column,
+					fmt.Sprintf("interface '%s' has more than one method '%s'", stmt.Name.Value, method.Name.Value)))
+				return nil
+			}
+			stmt.Methods = append(stmt.Methods, method)
+			stmt.Type.Methods = append(stmt.Type.Methods, method)
+		case p.curTokenIs(token.IDENT):
+			embed := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			embedded, ok := p.aliases[embed.Value].(*ast.InterfaceType)
+			if !ok {
+				p.addError(fmt.Sprintf("'%s' is not an interface, so it cannot be embedded in interface '%s'", embed.Value, stmt.Name.Value))
+				return nil
+			}
+			stmt.Embeds = append(stmt.Embeds, embed)
+			for _, m := range embedded.Methods {
+				if stmt.Type.Method(m.Name.Value) == nil {
+					stmt.Type.Methods = append(stmt.Type.Methods, m)
+				}
+			}
+		default:
+			p.addError(fmt.Sprintf("expected a method or an embedded interface in interface '%s', got '%s'", stmt.Name.Value, p.curToken.Literal))
+			return nil
+		}
+		p.nextToken()
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseInterfaceMethod parses a method signature such as area(): float,
+// starting at its name.
+func (p *Parser) parseInterfaceMethod() *ast.InterfaceMethod {
+	method := &ast.InterfaceMethod{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+	p.nextToken() // '('
+	params, _ := p.parseTypedFunctionParameters()
+	if params == nil {
+		return nil
+	}
+	method.Parameters = params
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		p.nextToken()
+		method.ReturnType = p.parseType()
+		if method.ReturnType == nil {
+			return nil
+		}
+	}
+	return method
+}
@@ -95,6 +95,17 @@ func (p *Parser) parseMatchPattern() ast.Expression {
 		// Parse dict pattern
 		return p.parseDictLiteral()
 	case token.IDENT:
+		if p.peekTokenIs(token.COLON) {
+			// Parse type pattern: name: Type binds name if the value has that type
+			name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			p.nextToken()
+			pattern := &ast.TypePattern{Token: p.curToken, Name: name}
+			p.nextToken()
+			if pattern.Type = p.parseType(); pattern.Type == nil {
+				return nil
+			}
+			return pattern
+		}
 		// Parse identifier (variable binding or wildcard)
 		return &ast.Identifier{
 			Token: p.curToken,
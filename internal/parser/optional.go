@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// parseOptionalChain parses the link after ?., which is a property or
+// method (obj?.name), an index or slice (obj?.[i]) or a call (f?.(x)).
+func (p *Parser) parseOptionalChain(left ast.Expression) ast.Expression {
+	switch {
+	case p.peekTokenIs(token.LBRACKET):
+		p.nextToken()
+		switch exp := p.parseIndexExpression(left).(type) {
+		case *ast.IndexExpression:
+			exp.Optional = true
+			return exp
+		case *ast.SliceExpression:
+			exp.Optional = true
+			return exp
+		}
+	case p.peekTokenIs(token.LPAREN):
+		p.nextToken()
+		exp := p.parseCallExpression(left).(*ast.CallExpression)
+		exp.Optional = true
+		return exp
+	default:
+		switch exp := p.parseMethod(left).(type) {
+		case *ast.MethodExpression:
+			exp.Optional = true
+			return exp
+		case *ast.PropertyExpression:
+			exp.Optional = true
+			return exp
+		}
+	}
+	return nil
+}
+
+// inOptionalChain reports whether exp is part of a chain with a ?. link,
+// such as a?.b.c, which cannot be assigned to.
+func inOptionalChain(exp ast.Expression) bool {
+	for {
+		switch e := exp.(type) {
+		case *ast.PropertyExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Object
+		case *ast.MethodExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Object
+		case *ast.IndexExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Left
+		case *ast.SliceExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Left
+		case *ast.CallExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Function
+		default:
+			return false
+		}
+	}
+}
@@ -53,10 +53,23 @@ var precedences = map[token.TokenType]int{
 	token.POW:             POWER,
 	token.MODULUS:         MODULUS,
 	token.MODULUS_ASSIGN:  MODULUS,
+	// Bitwise operators bind like they do in Go: | and ^ like +, and &,
+	// << and >> like *
+	token.PIPE:               SUM,
+	token.CARET:              SUM,
+	token.AMPERSAND:          PRODUCT,
+	token.SHIFT_LEFT:         PRODUCT,
+	token.SHIFT_RIGHT:        PRODUCT,
+	token.PIPE_ASSIGN:        ASSIGN,
+	token.CARET_ASSIGN:       ASSIGN,
+	token.AMPERSAND_ASSIGN:   ASSIGN,
+	token.SHIFT_LEFT_ASSIGN:  ASSIGN,
+	token.SHIFT_RIGHT_ASSIGN: ASSIGN,
 	// token.BANG:     PREFIX,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
-	token.DOT:      DOT, // Highest priority
+	token.LPAREN:       CALL,
+	token.LBRACKET:     INDEX,
+	token.DOT:          DOT, // Highest priority
+	token.QUESTION_DOT: DOT,
 }
 
 type (
@@ -78,7 +91,13 @@ type Parser struct {
 	infixParseFns   map[token.TokenType]infixParseFn
 	postfixParseFns map[token.TokenType]postfixParseFn
 
-	aliases map[string]ast.Type // type aliases: type UserID = int
+	aliases    map[string]ast.Type           // named types: type UserID = int, and interfaces
+	typeParams map[string]*ast.TypeParameter // type parameters of the generic declarations being parsed
+	generics   map[string]bool               // names of generic functions and structs, which can take <type arguments>
+	pending    []token.Token                 // tokens to read before the lexer's next one
+	angles     []token.Token                 // the '<' and '>' around type parameters and type arguments
+	blocks     []*ast.BlockStatement         // the blocks of statements in braces
+	yields     *bool                         // set once the function being parsed yields, nil outside functions
 }
 
 // sourceLine returns the source line at the given line number.
@@ -121,21 +140,24 @@ func (p *Parser) registerPostfix(tokenType token.TokenType, fn postfixParseFn) {
 }
 
 func New(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l, errors: []string{}, aliases: make(map[string]ast.Type)}
+	p := &Parser{l: l, errors: []string{}, aliases: make(map[string]ast.Type), generics: make(map[string]bool)}
 
 	p.nextToken()
 	p.nextToken()
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.TEMPLATE, p.parseTemplateLiteral)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.BIGINT, p.parseBigIntLiteral)
 	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.PLUS, p.parsePrefixExpression)
 	p.registerPrefix(token.AMPERSAND, p.parsePrefixExpression)
 	p.registerPrefix(token.ASTERISK, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
@@ -156,7 +178,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.DEFER, p.parseDeferStatement)
 	p.registerPrefix(token.AT, p.parseAt)
 	p.registerPrefix(token.DOUBLECOLON, p.parseBuiltinExpression)
-	p.registerPrefix(token.ELLIPSIS, p.parseSpreadPattern)
+	p.registerPrefix(token.ELLIPSIS, p.parseSpreadExpression)
 	p.registerPrefix(token.INFO, p.parseInfoStatement)
 	p.registerPrefix(token.DEBUG, p.parseDebugStatement)
 	p.registerPrefix(token.NOTE, p.parseNoteStatement)
@@ -184,9 +206,11 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.ASYNC, p.parseAsyncFunctionLiteral)
 	p.registerPrefix(token.AWAIT, p.parseAwaitExpression)
 	p.registerPrefix(token.CHAN, p.parseChannelExpression)
+	p.registerPrefix(token.SELECT, p.parseSelectStatement)
 
 	// Error handling prefix parsers
 	p.registerPrefix(token.THROW, p.parseThrowStatement)
+	p.registerPrefix(token.TRY, p.parseTryStatement)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.AND, p.parseInfixExpression)
@@ -203,6 +227,16 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.POW, p.parseInfixExpression)
 	p.registerInfix(token.MODULUS, p.parseInfixExpression)
 	p.registerInfix(token.MODULUS_ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.PIPE_ASSIGN, p.parseAssignEqualExpression)
+	p.registerInfix(token.CARET, p.parseInfixExpression)
+	p.registerInfix(token.CARET_ASSIGN, p.parseAssignEqualExpression)
+	p.registerInfix(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfix(token.AMPERSAND_ASSIGN, p.parseAssignEqualExpression)
+	p.registerInfix(token.SHIFT_LEFT, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_LEFT_ASSIGN, p.parseAssignEqualExpression)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_RIGHT_ASSIGN, p.parseAssignEqualExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
@@ -214,6 +248,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.ASSIGN, p.parseAssignmentExpression)
 	p.registerInfix(token.IN, p.parseInfixExpression)
 	p.registerInfix(token.DOT, p.parseMethod)
+	p.registerInfix(token.QUESTION_DOT, p.parseOptionalChain)
 	p.registerInfix(token.RANGE, p.parseRangeExpression)
 	p.registerInfix(token.AS, p.parseTypeCast)
 	p.registerInfix(token.IS, p.parseTypeCheck)
@@ -275,9 +310,23 @@ func (p *Parser) ParseProgram() *ast.Program {
 func (p *Parser) nextToken() {
 	p.prevToken = p.curToken
 	p.curToken = p.peekToken
+	if len(p.pending) > 0 {
+		p.peekToken = p.pending[0]
+		p.pending = p.pending[1:]
+		return
+	}
 	p.peekToken = p.l.NextToken()
 }
 
+// peekSecondToken returns the token after the peek token without
+// consuming either.
+func (p *Parser) peekSecondToken() token.Token {
+	if len(p.pending) == 0 {
+		p.pending = append(p.pending, p.l.NextToken())
+	}
+	return p.pending[0]
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
@@ -311,6 +360,21 @@ func (p *Parser) curPrecedence() int {
 	return LOWEST
 }
 
+// TypeBrackets returns the '<' and '>' tokens that enclose type parameters
+// and type arguments, as in func<T> and Box<int>, so that they can be told
+// apart from comparisons. A '>>' that closes two lists is returned once, as
+// its first '>'.
+func (p *Parser) TypeBrackets() []token.Token {
+	return p.angles
+}
+
+// Blocks returns the blocks of statements written in braces, such as
+// function bodies and the branches of if, so that they can be told apart
+// from dict literals.
+func (p *Parser) Blocks() []*ast.BlockStatement {
+	return p.blocks
+}
+
 // error messages
 func (p *Parser) Errors() []string {
 	// Collect lexer errors first
@@ -349,7 +413,7 @@ func (p *Parser) Synchronize() {
 
 		switch p.curToken.Type {
 		case token.LET, token.CONST, token.FUNCTION, token.IF, token.WHILE,
-			token.FOR, token.RETURN, token.MATCH, token.SWITCH:
+			token.FOR, token.RETURN, token.YIELD, token.MATCH, token.SWITCH:
 			return
 		}
 
@@ -366,7 +430,7 @@ func (p *Parser) skipToNextStatement() {
 		p.nextToken()
 		switch p.peekToken.Type {
 		case token.LET, token.CONST, token.FUNCTION, token.IF, token.WHILE,
-			token.FOR, token.RETURN, token.SWITCH, token.PACKAGE, token.IMPORT, token.EOF:
+			token.FOR, token.RETURN, token.YIELD, token.SWITCH, token.PACKAGE, token.IMPORT, token.EOF:
 			return
 		}
 	}
@@ -397,7 +461,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	}
 	leftExp := prefix()
 
-	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() && !p.peekStartsDereference() {
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
 			p.noInfixParseFnError(p.peekToken.Type)
@@ -411,6 +475,13 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 }
 
+// peekStartsDereference reports whether the peek token is a '*' at the
+// start of a new line, which begins a statement such as *p = 1 rather
+// than multiplying the expression before it.
+func (p *Parser) peekStartsDereference() bool {
+	return p.peekTokenIs(token.ASTERISK) && p.peekToken.Line > p.curToken.Line
+}
+
 // prefix expressions
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
@@ -431,9 +502,9 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 func (p *Parser) isStatementStart() bool {
 	switch p.curToken.Type {
 	case token.LET, token.CONST, token.ENUM, token.STRUCT,
-		token.RETURN, token.BREAK, token.CONTINUE,
+		token.RETURN, token.YIELD, token.BREAK, token.CONTINUE,
 		token.INCLUDE, token.GO, token.FUNCTION,
-		token.IF, token.WHILE, token.FOR, token.SWITCH, token.MATCH:
+		token.IF, token.WHILE, token.FOR, token.SWITCH, token.MATCH, token.SELECT:
 		return true
 	case token.IDENT:
 		return p.curToken.Literal == "type"
@@ -448,9 +519,9 @@ func (p *Parser) isStatementStart() bool {
 func (p *Parser) isKeywordStatementStart() bool {
 	switch p.curToken.Type {
 	case token.LET, token.CONST, token.ENUM, token.STRUCT,
-		token.RETURN, token.BREAK, token.CONTINUE,
+		token.RETURN, token.YIELD, token.BREAK, token.CONTINUE,
 		token.INCLUDE, token.GO, token.FUNCTION,
-		token.IF, token.WHILE, token.FOR, token.SWITCH, token.MATCH:
+		token.IF, token.WHILE, token.FOR, token.SWITCH, token.MATCH, token.SELECT:
 		return true
 	case token.IDENT:
 		return true
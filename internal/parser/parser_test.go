@@ -184,6 +184,45 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestBigIntLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"123n;", "123"},
+		{"5n", "5"},
+		{"1_000n", "1000"},
+		{"0xffffffffffffffffff", "4722366482869645213695"},
+		{"99999999999999999999;", "99999999999999999999"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.BigIntLiteral)
+		if !ok {
+			t.Fatalf("%q: exp not *ast.BigIntLiteral. got=%T", tt.input, stmt.Expression)
+		}
+		if literal.Value.String() != tt.expected {
+			t.Errorf("%q: literal.Value not %s. got=%s", tt.input, tt.expected, literal.Value)
+		}
+	}
+
+	// An n that starts a name is not a suffix
+	l := lexer.New("5name")
+	p := New(l)
+	program := p.ParseProgram()
+	if stmt, ok := program.Statements[0].(*ast.ExpressionStatement); ok {
+		if _, ok := stmt.Expression.(*ast.BigIntLiteral); ok {
+			t.Errorf("5name parsed as a bigint literal")
+		}
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -196,6 +235,7 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		{"-foobar;", "-", "foobar"},
 		{"!true;", "!", true},
 		{"!false;", "!", false},
+		{"~5;", "~", 5},
 	}
 
 	for _, tt := range prefixTests {
@@ -257,6 +297,11 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"true == true", true, "==", true},
 		{"true != false", true, "!=", false},
 		{"false == false", false, "==", false},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
 	}
 
 	for _, tt := range infixTests {
@@ -667,3 +712,544 @@ func TestReservedKeywordAsIdentifier(t *testing.T) {
 		}
 	}
 }
+
+func TestTryStatementParsing(t *testing.T) {
+	input := `try { risky() } catch (e: NotFound) { a } catch (e) { b } finally { c }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	try, ok := stmt.Expression.(*ast.TryStatement)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.TryStatement. got=%T", stmt.Expression)
+	}
+
+	if len(try.CatchClauses) != 2 {
+		t.Fatalf("expected 2 catch clauses, got=%d", len(try.CatchClauses))
+	}
+	if try.CatchClauses[0].Param.Value != "e" || try.CatchClauses[0].ErrorType.Value != "NotFound" {
+		t.Errorf("first catch clause wrong. got=%s", try.CatchClauses[0].String())
+	}
+	if try.CatchClauses[1].ErrorType != nil {
+		t.Errorf("second catch clause should be untyped. got=%s", try.CatchClauses[1].String())
+	}
+	if try.Finally == nil || len(try.Finally.Statements) != 1 {
+		t.Errorf("finally block not parsed")
+	}
+}
+
+func TestTryWithoutCatchOrFinally(t *testing.T) {
+	l := lexer.New(`try { x }`)
+	p := New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected an error for try without catch or finally")
+	}
+}
+
+func TestTestStatementParsing(t *testing.T) {
+	input := `test "adds numbers" { assert.equal(1 + 1, 2) }
+let test = 5
+test + 1`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.TestStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.TestStatement. got=%T", program.Statements[0])
+	}
+	if stmt.Name != "adds numbers" {
+		t.Errorf("test name wrong. want=%q, got=%q", "adds numbers", stmt.Name)
+	}
+	if len(stmt.Body.Statements) != 1 {
+		t.Errorf("test body wrong. got=%s", stmt.Body.String())
+	}
+
+	// 'test' is only a keyword in front of a name
+	if _, ok := program.Statements[2].(*ast.ExpressionStatement); !ok {
+		t.Errorf("program.Statements[2] is not ast.ExpressionStatement. got=%T", program.Statements[2])
+	}
+}
+
+func TestSelectStatementParsing(t *testing.T) {
+	input := `select {
+    case v, ok = receive(a) { v }
+    case send(b, 1 + 2) { 1 }
+    case timeout(1s) { 2 }
+    case timeout(250ms) { 3 }
+    default { 4 }
+}
+term.select(["a", "b"])`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+	}
+
+	exprStmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+	stmt, ok := exprStmt.Expression.(*ast.SelectStatement)
+	if !ok {
+		t.Fatalf("expression is not ast.SelectStatement. got=%T", exprStmt.Expression)
+	}
+
+	tests := []struct {
+		op   string
+		want string
+	}{
+		{"receive", "case v, ok = receive(a)"},
+		{"send", "case send(b, (1 + 2))"},
+		{"timeout", "case timeout(1s)"},
+		{"timeout", "case timeout(250ms)"},
+		{"default", "default"},
+	}
+	if len(stmt.Cases) != len(tests) {
+		t.Fatalf("wrong number of cases. want=%d, got=%d", len(tests), len(stmt.Cases))
+	}
+	for i, tt := range tests {
+		sc := stmt.Cases[i]
+		if sc.Op != tt.op {
+			t.Errorf("case %d: op wrong. want=%q, got=%q", i, tt.op, sc.Op)
+		}
+		if got := sc.String(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("case %d: want prefix %q, got %q", i, tt.want, got)
+		}
+	}
+
+	// 'select' is still usable as a method name
+	if _, ok := program.Statements[1].(*ast.ExpressionStatement); !ok {
+		t.Errorf("program.Statements[1] is not ast.ExpressionStatement. got=%T", program.Statements[1])
+	}
+}
+
+func TestSelectStatementErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`select { }`, "at least one case"},
+		{`select { default { 1 } default { 2 } }`, "only have one DEFAULT"},
+		{`select { case wait(ch) { 1 } }`, "must be receive(ch), send(ch, value) or timeout(duration)"},
+		{`select { case v = send(ch, 1) { 1 } }`, "Only a receive case can assign a value"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errs := strings.Join(p.Errors(), "\n")
+		if !strings.Contains(errs, tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %q", tt.input, tt.want, errs)
+		}
+	}
+}
+
+func TestDestructuringParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let [a, b] = pair`, "let [a, b] = pair;"},
+		{`let [first, second = 2, ...rest] = items`, "let [first, second=2, ...rest] = items;"},
+		{`const {name, age: years, ...others} = user`, "const {name, age: years, ...others} = user;"},
+		{`let {nick = "none", "full name": full, pos: [x, y]} = d`, "let {nick = none, full name: full, pos: [x, y]} = d;"},
+		{`for [k, v] in pairs { k }`, "for [k, v] in pairs"},
+		{`for i, {name} in people { name }`, "for i, {name} in people"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if len(program.Statements) != 1 {
+			t.Fatalf("%s: expected 1 statement, got %d", tt.input, len(program.Statements))
+		}
+		if got := program.Statements[0].String(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: want prefix %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let [a, ...rest, b] = items`, "must be last in a pattern"},
+		{`let {1: a} = d`, "expected a key in dict pattern"},
+		{`let {"a"} = d`, "expected ':' after \"a\""},
+		{`let [a, 2] = items`, "expected '[' or '{' to start a pattern"},
+		{`let [a, b]`, "Expected next token to be ="},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errs := strings.Join(p.Errors(), "\n")
+		if !strings.Contains(errs, tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %q", tt.input, tt.want, errs)
+		}
+	}
+}
+
+func TestVariadicAndSpreadParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`let f = func(level, ...parts) { parts }`, "let f = func(level, ...parts)"},
+		{`let sum = func(...xs: int): int { 0 }`, "let sum = func(...xs: int): int"},
+		{`let g = async func(a, ...rest) { rest }`, "let g = async func(a, ...rest)"},
+		{`f(1, ...args, 2)`, "f(1, ...args, 2)"},
+		{`[...a, 0, ...b]`, "[...a, 0, ...b]"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if len(program.Statements) != 1 {
+			t.Fatalf("%s: expected 1 statement, got %d", tt.input, len(program.Statements))
+		}
+		if got := program.Statements[0].String(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: want prefix %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	p := New(lexer.New(`{...defaults, "x": 1, ...overrides}`))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	dict, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.DictLiteral)
+	if !ok {
+		t.Fatalf("expected a dict literal, got %T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	var keys []string
+	for _, key := range dict.Keys {
+		if spread, ok := key.(*ast.SpreadExpression); ok {
+			keys = append(keys, "..."+dict.Pairs[key].String())
+			if dict.Pairs[key] != spread.Value {
+				t.Errorf("the value of %s should be the spread value", spread)
+			}
+			continue
+		}
+		keys = append(keys, key.String())
+	}
+	if got := strings.Join(keys, " "); got != "...defaults x ...overrides" {
+		t.Errorf("wrong dict keys: %s", got)
+	}
+}
+
+func TestVariadicErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`func(...xs, y) { xs }`, "variadic parameter 'xs' must be the last parameter"},
+		{`func(...xs = [1]) { xs }`, "variadic parameter 'xs' cannot have a default value"},
+		{`func(...xs: int = 1) { xs }`, "variadic parameter 'xs' cannot have a default value"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errs := strings.Join(p.Errors(), "\n")
+		if !strings.Contains(errs, tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %q", tt.input, tt.want, errs)
+		}
+	}
+}
+
+func TestBitwiseParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a | b & c", "(a | (b & c))"},
+		{"a ^ b << 2", "(a ^ (b << 2))"},
+		{"1 + 2 & 3", "(1 + (2 & 3))"},
+		{"a | b == c", "((a | b) == c)"},
+		{"~a & b", "((~a) & b)"},
+		{"x |= a | b", "x|=(a | b)"},
+		{"0x1F + 0b11 + 0o7 + 1_000", "(((0x1F + 0b11) + 0o7) + 1_000)"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if got := program.String(); got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"0b102", "0x", "0o8", "1__0", "0x_"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if errs := strings.Join(p.Errors(), "\n"); !strings.Contains(errs, "as a number") {
+			t.Errorf("%s: expected a number error, got %q", input, errs)
+		}
+	}
+}
+
+func TestPointerParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"&x", "(&x)"},
+		{"*p = 1", "(*p)=1"},
+		{"*p += 2", "(*p)+=2"},
+		{"&a[0]", "(&(a[0]))"},
+		// A '*' that starts a line starts a new statement
+		{"f(x)\n*p = 1", "f(x)(*p)=1"},
+		{"a *\nb", "(a * b)"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if got := program.String(); got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	p := New(lexer.New("-x = 1"))
+	p.ParseProgram()
+	if errs := strings.Join(p.Errors(), "\n"); !strings.Contains(errs, "Expected an identifier or an array, but found: -") {
+		t.Errorf("expected an assignment error, got %q", errs)
+	}
+}
+
+func TestOptionalChainParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a?.[0]", "(a?.[0])"},
+		{"a?.[1:]", "(a?.[1:])"},
+		{"f?.(1, 2)", "f?.(1, 2)"},
+		{"a?.b()", "a?.b"},
+		{"a?.[0][1]", "((a?.[0])[1])"},
+		{"x ?? a?.[0]", "(x ?? (a?.[0]))"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if got := program.String(); got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	p := New(lexer.New("a?.b"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	prop, ok := stmt.Expression.(*ast.PropertyExpression)
+	if !ok || !prop.Optional {
+		t.Fatalf("expected an optional property expression, got %T", stmt.Expression)
+	}
+
+	for _, input := range []string{"a?.b = 1", "a?.b.c = 1", "a?.[0] = 1", "a?.[0] += 1"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if errs := strings.Join(p.Errors(), "\n"); !strings.Contains(errs, "Cannot assign to an optional chain") {
+			t.Errorf("%s: expected an optional chain assignment error, got %q", input, errs)
+		}
+	}
+}
+
+func TestYieldParsing(t *testing.T) {
+	p := New(lexer.New("let g = func(n) { if (n > 0) { yield n }; let inner = func() { return 1 } }"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	let := program.Statements[0].(*ast.LetStatement)
+	fn, ok := let.Value.(*ast.FunctionLiteral)
+	if !ok || !fn.Generator {
+		t.Fatalf("expected a generator function literal, got %T", let.Value)
+	}
+	if got := program.String(); !strings.Contains(got, "yield n;") {
+		t.Errorf("expected the yield statement in %q", got)
+	}
+	// A function that only contains a generator is not one itself
+	p = New(lexer.New("let outer = func() { let g = func() { yield 1 }; return g }"))
+	program = p.ParseProgram()
+	checkParserErrors(t, p)
+	if fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral); fn.Generator {
+		t.Errorf("a function containing a generator was marked as a generator")
+	}
+
+	p = New(lexer.New("struct Range { n: int; func iter() { yield this.n } }"))
+	program = p.ParseProgram()
+	checkParserErrors(t, p)
+	if st := program.Statements[0].(*ast.StructStatement); !st.Methods[0].Generator {
+		t.Errorf("expected iter() to be a generator method")
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"yield 1", "Line 1: 'yield' can only be used inside a function"},
+		{"let f = async func() { yield 1 }", "Line 1: an async function cannot yield"},
+		{"let f = func(): int { yield 1 }", "Line 1: generator function cannot declare a return type"},
+		{"struct S { func iter(): int { yield 1 } }", "Line 1: generator 'iter' cannot declare a return type"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if errs := strings.Join(p.Errors(), "\n"); !strings.Contains(errs, tt.want) {
+			t.Errorf("%s: expected %q, got %q", tt.input, tt.want, errs)
+		}
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"Hello ${user["name"]}, you have ${len(items)} items"`, `"Hello ${(user[name])}, you have ${len(items)} items"`},
+		{"`total: ${price * 2:.2f}`", `"total: ${(price * 2):.2f}"`},
+		{`"${ f(["}", 1]) } and ${"in${x}"}"`, `"${f([}, 1])} and ${"in${x}"}"`},
+		{"`no interpolation`", "no interpolation"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if got := program.String(); got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	p := New(lexer.NewWithFilename("let s = \"a\n${b + c}\"", "test.vint"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	str := program.Statements[0].(*ast.LetStatement).Value.(*ast.InterpolatedString)
+	in := str.Parts[1].(*ast.Interpolation)
+	infix := in.Value.(*ast.InfixExpression)
+	if in.Token.Line != 2 || in.Token.Column != 1 || infix.Token.Line != 2 || infix.Token.Column != 5 {
+		t.Errorf("wrong positions: ${ at %d:%d, + at %d:%d", in.Token.Line, in.Token.Column, infix.Token.Line, infix.Token.Column)
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"let s = \"a ${1 +} b\"", "main.vint:1:17: Unexpected token 'EOF'"},
+		{"let s = \"x\"\nlet t = `a ${b c}`", "main.vint:2:16: Unexpected 'c' in interpolation, expected '}'"},
+		{"let s = \"${}\"", "main.vint:1:10: Empty interpolation"},
+		{"let s = \"a ${b\"", "Unterminated string literal"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errs := strings.Join(p.Errors(), "\n")
+		if !strings.Contains(errs, tt.want) {
+			t.Errorf("%q: expected an error containing %q, got %q", tt.input, tt.want, errs)
+		}
+	}
+}
+
+func TestInterfaceParsing(t *testing.T) {
+	input := `interface Named { name(): string }
+interface Shape {
+	Named
+	func area(): float;
+	scale(k: float, ...rest): Shape,
+}
+let s: Shape = c
+let m = match s {
+	c: Circle => c
+	n: Named => n
+}`
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[1].(*ast.InterfaceStatement)
+	if !ok {
+		t.Fatalf("statement is not *ast.InterfaceStatement. got=%T", program.Statements[1])
+	}
+	if got := stmt.String(); got != "interface Shape { Named; area(): float; scale(k: float, ...rest): Shape }" {
+		t.Errorf("wrong interface, got %q", got)
+	}
+	var methods []string
+	for _, m := range stmt.Type.Methods {
+		methods = append(methods, m.Name.Value)
+	}
+	if strings.Join(methods, " ") != "name area scale" {
+		t.Errorf("wrong methods, got %v", methods)
+	}
+	// The method's return type and the annotation are the interface itself
+	if stmt.Type.Method("scale").ReturnType != stmt.Type {
+		t.Errorf("return type of scale() is not the interface")
+	}
+	let := program.Statements[2].(*ast.TypedLetStatement)
+	if let.TypeAnnotation.Type != stmt.Type {
+		t.Errorf("annotation is not the interface, got %T", let.TypeAnnotation.Type)
+	}
+
+	match := program.Statements[3].(*ast.LetStatement).Value.(*ast.MatchExpression)
+	for i, want := range []string{"c: Circle", "n: Named"} {
+		pattern, ok := match.Cases[i].Pattern.(*ast.TypePattern)
+		if !ok {
+			t.Fatalf("pattern %d is not *ast.TypePattern. got=%T", i, match.Cases[i].Pattern)
+		}
+		if pattern.String() != want {
+			t.Errorf("pattern %d: want %q, got %q", i, want, pattern.String())
+		}
+	}
+}
+
+func TestInterfaceErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"interface Shape { Drawable }", "main.vint:1:19: 'Drawable' is not an interface, so it cannot be embedded in interface 'Shape'"},
+		{"interface Shape { area(); area() }", "main.vint:1:27: interface 'Shape' has more than one method 'area'"},
+		{"interface Shape { 5 }", "main.vint:1:19: expected a method or an embedded interface in interface 'Shape', got '5'"},
+		{"interface Shape { area(): float", "interface 'Shape' is not closed, expected '}'"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errs := strings.Join(p.Errors(), "\n")
+		if !strings.Contains(errs, tt.want) {
+			t.Errorf("%q: expected an error containing %q, got %q", tt.input, tt.want, errs)
+		}
+	}
+}
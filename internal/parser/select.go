@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// timeoutUnits are the units a timeout(...) duration may be written with.
+var timeoutUnits = map[string]bool{
+	"ns": true, "us": true, "ms": true, "s": true, "m": true, "h": true,
+}
+
+// parseSelectStatement parses a select over channel operations
+// Syntax: select { case v = receive(ch) { ... } case send(ch, x) { ... } case timeout(1s) { ... } default { ... } }
+func (p *Parser) parseSelectStatement() ast.Expression {
+	// 'select' not followed by a block is an ordinary name, as in term.select(...)
+	if !p.peekTokenIs(token.LBRACE) {
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	stmt := &ast.SelectStatement{Token: p.curToken}
+	p.nextToken() // '{'
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) {
+		if p.curTokenIs(token.EOF) {
+			p.addError("The SELECT statement was not properly closed")
+			return nil
+		}
+
+		sc := &ast.SelectCase{Token: p.curToken}
+		switch {
+		case p.curTokenIs(token.DEFAULT):
+			sc.Op = "default"
+		case p.curTokenIs(token.CASE):
+			p.nextToken()
+			if !p.parseSelectOperation(sc) {
+				return nil
+			}
+		default:
+			p.addError(fmt.Sprintf("Expected CASE or DEFAULT in select, but received: %s", p.curToken.Type))
+			return nil
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		sc.Block = p.parseBlockStatement()
+		p.nextToken()
+		stmt.Cases = append(stmt.Cases, sc)
+	}
+
+	defaults := 0
+	for _, sc := range stmt.Cases {
+		if sc.Op == "default" {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		p.addError(fmt.Sprintf("A SELECT statement can only have one DEFAULT case! You have %d", defaults))
+		return nil
+	}
+	if len(stmt.Cases) == 0 {
+		p.addError("A SELECT statement needs at least one case")
+		return nil
+	}
+
+	return stmt
+}
+
+// parseSelectOperation parses what follows 'case': an optional binding and
+// one of receive(ch), send(ch, value) or timeout(duration).
+func (p *Parser) parseSelectOperation(sc *ast.SelectCase) bool {
+	if p.curTokenIs(token.IDENT) && (p.peekTokenIs(token.ASSIGN) || p.peekTokenIs(token.COMMA)) {
+		sc.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
+			sc.Ok = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		}
+		if !p.expectPeek(token.ASSIGN) {
+			return false
+		}
+		p.nextToken()
+	}
+
+	sc.Op = p.curToken.Literal
+	if !p.curTokenIs(token.IDENT) || (sc.Op != "receive" && sc.Op != "send" && sc.Op != "timeout") {
+		p.addError(fmt.Sprintf("A select case must be receive(ch), send(ch, value) or timeout(duration), got '%s'", p.curToken.Literal))
+		return false
+	}
+	if sc.Value != nil && sc.Op != "receive" {
+		p.addError(fmt.Sprintf("Only a receive case can assign a value, not %s", sc.Op))
+		return false
+	}
+	if !p.expectPeek(token.LPAREN) {
+		return false
+	}
+	p.nextToken()
+
+	switch sc.Op {
+	case "receive":
+		sc.Channel = p.parseExpression(LOWEST)
+	case "send":
+		sc.Channel = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.COMMA) {
+			return false
+		}
+		p.nextToken()
+		sc.Send = p.parseExpression(LOWEST)
+	case "timeout":
+		if lit, ok := p.splitDurationLiteral(sc); ok {
+			sc.Timeout = lit
+		} else {
+			sc.Timeout = p.parseExpression(LOWEST)
+		}
+		if sc.Unit == "" && p.peekTokenIs(token.IDENT) && timeoutUnits[p.peekToken.Literal] {
+			p.nextToken()
+			sc.Unit = p.curToken.Literal
+		}
+	}
+
+	return p.expectPeek(token.RPAREN)
+}
+
+// splitDurationLiteral handles a single-digit duration such as 1s, which the
+// lexer reads as one identifier rather than a number followed by a unit.
+func (p *Parser) splitDurationLiteral(sc *ast.SelectCase) (ast.Expression, bool) {
+	lit := p.curToken.Literal
+	if !p.curTokenIs(token.IDENT) || lit == "" || lit[0] < '0' || lit[0] > '9' {
+		return nil, false
+	}
+	end := 0
+	for end < len(lit) && lit[end] >= '0' && lit[end] <= '9' {
+		end++
+	}
+	if !timeoutUnits[lit[end:]] {
+		return nil, false
+	}
+	value, err := strconv.ParseInt(lit[:end], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	tok := p.curToken
+	tok.Type = token.INT
+	tok.Literal = lit[:end]
+	sc.Unit = lit[end:]
+	return &ast.IntegerLiteral{Token: tok, Value: value}, true
+}
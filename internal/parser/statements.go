@@ -19,6 +19,8 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseStructStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.YIELD:
+		return p.parseYieldStatement()
 	case token.BREAK:
 		return p.parseBreak()
 	case token.CONTINUE:
@@ -34,6 +36,16 @@ func (p *Parser) parseStatement() ast.Statement {
 			p.peekTokenIs(token.IDENT) {
 			return p.parseTypeAliasStatement()
 		}
+		// Contextual keyword: 'interface' followed by a name declares an interface
+		if p.curTokenIs(token.IDENT) && p.curToken.Literal == "interface" &&
+			p.peekTokenIs(token.IDENT) {
+			return p.parseInterfaceStatement()
+		}
+		// Contextual keyword: 'test' followed by a name starts a test block
+		if p.curTokenIs(token.IDENT) && p.curToken.Literal == "test" &&
+			p.peekTokenIs(token.STRING) {
+			return p.parseTestStatement()
+		}
 		return p.parseExpressionStatement()
 	}
 }
@@ -41,6 +53,10 @@ func (p *Parser) parseStatement() ast.Statement {
 func (p *Parser) parseLetStatement() ast.Statement {
 	tok := p.curToken
 
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		return p.parseDestructureStatement()
+	}
+
 	if !p.expectPeek(token.IDENT) {
 		p.skipToNextStatement()
 		return nil
@@ -102,6 +118,9 @@ func (p *Parser) parseLetStatement() ast.Statement {
 		p.nextToken()
 		stmt := &ast.LetStatement{Token: tok, Name: name}
 		stmt.Value = p.parseExpression(LOWEST)
+		if fn, ok := stmt.Value.(*ast.TypedFunctionLiteral); ok && len(fn.TypeParams) > 0 {
+			p.generics[name.Value] = true
+		}
 		if p.peekTokenIs(token.SEMICOLON) {
 			p.nextToken()
 		}
@@ -117,6 +136,10 @@ func (p *Parser) parseLetStatement() ast.Statement {
 func (p *Parser) parseConstStatement() ast.Statement {
 	tok := p.curToken
 
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		return p.parseDestructureStatement()
+	}
+
 	if !p.expectPeek(token.IDENT) {
 		p.skipToNextStatement()
 		return nil
@@ -241,9 +264,31 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseYieldStatement() *ast.YieldStatement {
+	stmt := &ast.YieldStatement{Token: p.curToken}
+	if p.yields == nil {
+		msg := fmt.Sprintf("Line %d: 'yield' can only be used inside a function", p.curToken.Line)
+		p.errors = append(p.errors, msg)
+	} else {
+		*p.yields = true
+	}
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
+	if p.curTokenIs(token.LBRACE) {
+		p.blocks = append(p.blocks, block)
+	}
 
 	p.nextToken()
 
@@ -363,34 +408,59 @@ func (p *Parser) parseStructStatement() *ast.StructStatement {
 
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
+	// Type parameters of a generic struct: struct Box<T> { value: T }
+	if p.peekTokenIs(token.LT) {
+		p.nextToken()
+		if stmt.TypeParams = p.parseTypeParameters(); stmt.TypeParams == nil {
+			return nil
+		}
+		p.generics[stmt.Name.Value] = true
+	}
+
 	// Expect opening brace
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	ok := true
+	p.withTypeParams(stmt.TypeParams, func() {
+		ok = p.parseStructMembers(stmt)
+	})
+	if !ok {
+		return nil
+	}
+
+	// Optional semicolon after closing brace
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseStructMembers parses the fields and methods of a struct, from its
+// opening brace to its closing one.
+func (p *Parser) parseStructMembers(stmt *ast.StructStatement) bool {
 	p.nextToken() // Move past {
 
 	// Parse struct members (fields and methods)
-	wasMethod := false
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
 		// Check if this is a method (starts with 'func')
 		if p.curTokenIs(token.FUNCTION) {
 			method := p.parseStructMethod()
 			if method == nil {
-				return nil
+				return false
 			}
 			stmt.Methods = append(stmt.Methods, *method)
-			wasMethod = true
 
-			// Skip comma if present after method
-			if p.peekTokenIs(token.COMMA) {
+			// Skip comma or semicolon if present after method
+			if p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.SEMICOLON) {
 				p.nextToken()
 			}
 		} else if p.curTokenIs(token.IDENT) {
 			// It's a field
 			field := ast.StructField{}
 			field.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-			wasMethod = false
 
 			// Check for ':' — could be type annotation or default value
 			if p.peekTokenIs(token.COLON) {
@@ -401,8 +471,8 @@ func (p *Parser) parseStructStatement() *ast.StructStatement {
 				// Otherwise → default value expression (back-compat)
 				if p.isTypeStart() {
 					field.Type = p.parseType()
-					p.nextToken() // advance past type
-					if p.curTokenIs(token.ASSIGN) {
+					if p.peekTokenIs(token.ASSIGN) {
+						p.nextToken() // advance past type
 						p.nextToken()
 						field.Default = p.parseExpression(LOWEST)
 					}
@@ -413,31 +483,25 @@ func (p *Parser) parseStructStatement() *ast.StructStatement {
 
 			stmt.Fields = append(stmt.Fields, field)
 
-			// Skip comma if present
-			if p.peekTokenIs(token.COMMA) {
+			// A field can end with '}', as a dict type or default does,
+			// so move past it before looking for the struct close
+			p.nextToken()
+			if p.curTokenIs(token.COMMA) || p.curTokenIs(token.SEMICOLON) {
 				p.nextToken()
 			}
+			continue
 		} else {
 			p.errors = append(p.errors,
 				fmt.Sprintf("Line %d: Expected field name or 'func' in struct, got %s",
 					p.curToken.Line, p.curToken.Type))
-			return nil
-		}
-
-		// After a field, curToken may be '}' (struct close). Don't advance.
-		// After a method, curToken is '}' (method close). Advance to next token.
-		if p.curTokenIs(token.RBRACE) && !wasMethod {
-			break
+			return false
 		}
-		p.nextToken()
-	}
 
-	// Optional semicolon after closing brace
-	if p.peekTokenIs(token.SEMICOLON) {
+		// After a method, curToken is its closing '}' (or the comma after it)
 		p.nextToken()
 	}
 
-	return stmt
+	return true
 }
 
 // parseStructMethod parses a method inside a struct declaration
@@ -471,6 +535,7 @@ func (p *Parser) parseStructMethod() *ast.StructMethod {
 			method.Defaults[tp.Identifier.Value] = tp.Default
 		}
 	}
+	method.Variadic = isVariadic(typedParams)
 
 	// Check for return type: func method(): returnType { ... }
 	var returnType ast.Type
@@ -488,7 +553,10 @@ func (p *Parser) parseStructMethod() *ast.StructMethod {
 		return nil
 	}
 
-	method.Body = p.parseBlockStatement()
+	method.Body, method.Generator = p.parseFunctionBody()
+	if method.Generator && method.ReturnType != nil {
+		p.generatorReturnTypeError(method.Name.Value)
+	}
 
 	return method
 }
@@ -1,9 +1,57 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/token"
 )
 
 func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
+
+// parseTemplateLiteral parses an interpolated string. Each ${...} in it is
+// parsed on its own, with a lexer that starts where the expression sits in
+// the source so that errors inside it point at the right line and column.
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	tok := p.curToken
+	str := &ast.InterpolatedString{Token: tok}
+	var text strings.Builder
+	interpolated := false
+
+	for _, part := range lexer.SplitTemplate(tok) {
+		at := token.Token{Type: token.TEMPLATE, Literal: "${", Line: part.Line, Column: part.Column, File: tok.File}
+		if !part.Expr {
+			at.Type, at.Literal = token.STRING, part.Text
+			str.Parts = append(str.Parts, &ast.StringLiteral{Token: at, Value: part.Text})
+			text.WriteString(part.Text)
+			continue
+		}
+		interpolated = true
+		if strings.TrimSpace(part.Text) == "" {
+			p.errors = append(p.errors, p.formatErr(part.Line, part.Column, "Empty interpolation: put an expression between '${' and '}'"))
+			continue
+		}
+
+		sub := New(lexer.NewEmbedded(p.l, part.Text, part.Line, part.Column+2))
+		sub.aliases, sub.typeParams, sub.generics = p.aliases, p.typeParams, p.generics
+		value := sub.parseExpression(LOWEST)
+		if !sub.peekTokenIs(token.EOF) {
+			sub.errors = append(sub.errors, sub.formatErr(sub.peekToken.Line, sub.peekToken.Column,
+				fmt.Sprintf("Unexpected '%s' in interpolation, expected '}'", sub.peekToken.Literal)))
+		}
+		p.errors = append(p.errors, sub.Errors()...)
+		str.Parts = append(str.Parts, &ast.Interpolation{Token: at, Value: value, Format: part.Format})
+	}
+
+	if !interpolated {
+		// A backtick string with nothing to interpolate is a plain string
+		plain := tok
+		plain.Type, plain.Literal = token.STRING, text.String()
+		return &ast.StringLiteral{Token: plain, Value: plain.Literal}
+	}
+	return str
+}
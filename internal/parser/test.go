@@ -0,0 +1,27 @@
+package parser
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// parseTestStatement parses a named test block
+// Syntax: test "adds two numbers" { ... }
+func (p *Parser) parseTestStatement() ast.Statement {
+	stmt := &ast.TestStatement{Token: p.curToken}
+
+	p.nextToken() // the name
+	stmt.Name = p.curToken.Literal
+
+	if !p.expectPeek(token.LBRACE) {
+		p.skipToNextStatement()
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
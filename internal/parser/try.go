@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/token"
+)
+
+// parseTryStatement parses try/catch/finally blocks
+// Syntax: try { ... } catch (e: ErrorType) { ... } catch (e) { ... } finally { ... }
+func (p *Parser) parseTryStatement() ast.Expression {
+	stmt := &ast.TryStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Block = p.parseBlockStatement()
+	if stmt.Block == nil {
+		return nil
+	}
+
+	for p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+		clause := p.parseCatchClause()
+		if clause == nil {
+			return nil
+		}
+		stmt.CatchClauses = append(stmt.CatchClauses, clause)
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		stmt.Finally = p.parseBlockStatement()
+		if stmt.Finally == nil {
+			return nil
+		}
+	}
+
+	if len(stmt.CatchClauses) == 0 && stmt.Finally == nil {
+		p.addError("'try' must be followed by at least one 'catch' or a 'finally' block")
+		return nil
+	}
+
+	return stmt
+}
+
+// parseCatchClause parses a single catch arm. The binding is optional and
+// may be written with or without parentheses: catch (e: NotFound), catch e, catch
+func (p *Parser) parseCatchClause() *ast.CatchClause {
+	clause := &ast.CatchClause{Token: p.curToken}
+
+	parens := false
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+		parens = true
+	}
+
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken()
+		clause.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			if !p.peekTokenIs(token.IDENT) && !p.peekTokenIs(token.ERROR) {
+				p.peekError(token.IDENT)
+				return nil
+			}
+			p.nextToken()
+			clause.ErrorType = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		}
+	} else if parens {
+		p.peekError(token.IDENT)
+		return nil
+	}
+
+	if parens && !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	clause.Block = p.parseBlockStatement()
+	if clause.Block == nil {
+		return nil
+	}
+
+	return clause
+}
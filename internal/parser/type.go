@@ -21,8 +21,10 @@ var typeKeywords = map[string]bool{
 	"uint32":  true,
 	"uint64":  true,
 	"byte":    true,
+	"float":   true,
 	"float32": true,
 	"float64": true,
+	"number":  true,
 	"any":     true,
 	"error":   true,
 	"nil":     true,
@@ -39,9 +41,9 @@ func (p *Parser) isTypeStart() bool {
 		if isTypeKeyword(p.curToken.Literal) {
 			return true
 		}
-		// Check if it's a registered alias
-		_, ok := p.aliases[p.curToken.Literal]
-		return ok
+		// Check if it's a registered alias, a type parameter or a generic struct
+		_, ok := p.namedType(p.curToken.Literal)
+		return ok || p.generics[p.curToken.Literal] && p.peekTokenIs(token.LT)
 	case token.ERROR:
 		return true
 	case token.LBRACKET, token.LBRACE, token.ASTERISK, token.FUNCTION, token.CHAN, token.LPAREN:
@@ -57,8 +59,11 @@ func (p *Parser) parseType() ast.Type {
 	switch p.curToken.Type {
 	case token.IDENT:
 		// Check if this identifier is a registered type alias
-		if aliasTarget, ok := p.aliases[p.curToken.Literal]; ok {
-			return aliasTarget
+		if named, ok := p.namedType(p.curToken.Literal); ok {
+			return named
+		}
+		if p.generics[p.curToken.Literal] && p.peekTokenIs(token.LT) {
+			return p.parseGenericType()
 		}
 		return p.parseBasicType()
 	case token.ERROR:
@@ -81,6 +86,123 @@ func (p *Parser) parseType() ast.Type {
 	}
 }
 
+// namedType resolves a name declared as a type: a type parameter in scope,
+// a type alias or an interface.
+func (p *Parser) namedType(name string) (ast.Type, bool) {
+	if tp, ok := p.typeParams[name]; ok {
+		return tp, true
+	}
+	t, ok := p.aliases[name]
+	return t, ok
+}
+
+// parseGenericType parses a generic struct with type arguments, Box<int>,
+// and leaves curToken at the closing '>'.
+func (p *Parser) parseGenericType() ast.Type {
+	t := &ast.GenericType{Token: p.curToken, Name: p.curToken.Literal}
+	p.nextToken()
+	if t.Args = p.parseTypeArguments(); t.Args == nil {
+		return nil
+	}
+	return t
+}
+
+// parseTypeArguments parses <int, string> from the '<' to the closing '>'.
+// A '>>' that closes two lists at once, as in Box<Box<int>>, is split in
+// two.
+func (p *Parser) parseTypeArguments() []ast.Type {
+	var args []ast.Type
+	p.angles = append(p.angles, p.curToken)
+	for {
+		p.nextToken()
+		arg := p.parseType()
+		if arg == nil {
+			return nil
+		}
+		args = append(args, arg)
+		p.nextToken()
+		switch {
+		case p.curTokenIs(token.COMMA):
+			continue
+		case p.curTokenIs(token.SHIFT_RIGHT):
+			p.splitShiftRight()
+			p.angles = append(p.angles, p.curToken)
+			return args
+		case p.curTokenIs(token.GT):
+			p.angles = append(p.angles, p.curToken)
+			return args
+		}
+		p.addError("expected ',' or '>' in type arguments, got " + p.curToken.Literal)
+		return nil
+	}
+}
+
+// splitShiftRight replaces a '>>' at curToken with two '>' tokens.
+func (p *Parser) splitShiftRight() {
+	second := p.curToken
+	second.Type, second.Literal = token.GT, ">"
+	second.Column++
+	p.curToken.Type, p.curToken.Literal = token.GT, ">"
+	p.pending = append([]token.Token{p.peekToken}, p.pending...)
+	p.peekToken = second
+}
+
+// parseTypeParameters parses the type parameters of a generic function or
+// struct, <T, U: number>, from the '<' to the closing '>'.
+func (p *Parser) parseTypeParameters() []*ast.TypeParameter {
+	var params []*ast.TypeParameter
+	seen := map[string]bool{}
+	p.angles = append(p.angles, p.curToken)
+	for {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		tp := &ast.TypeParameter{Token: p.curToken, Name: p.curToken.Literal}
+		if seen[tp.Name] {
+			p.addError("duplicate type parameter '" + tp.Name + "'")
+			return nil
+		}
+		seen[tp.Name] = true
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			if tp.Constraint = p.parseType(); tp.Constraint == nil {
+				return nil
+			}
+		}
+		params = append(params, tp)
+		p.nextToken()
+		switch {
+		case p.curTokenIs(token.COMMA):
+			continue
+		case p.curTokenIs(token.GT):
+			p.angles = append(p.angles, p.curToken)
+			return params
+		}
+		p.addError("expected ',' or '>' in type parameters, got " + p.curToken.Literal)
+		return nil
+	}
+}
+
+// withTypeParams brings params into scope for parse, so that annotations
+// can use them, and takes them out again afterwards.
+func (p *Parser) withTypeParams(params []*ast.TypeParameter, parse func()) {
+	if len(params) == 0 {
+		parse()
+		return
+	}
+	outer := p.typeParams
+	p.typeParams = make(map[string]*ast.TypeParameter, len(outer)+len(params))
+	for name, tp := range outer {
+		p.typeParams[name] = tp
+	}
+	for _, tp := range params {
+		p.typeParams[tp.Name] = tp
+	}
+	parse()
+	p.typeParams = outer
+}
+
 func (p *Parser) parseBasicType() ast.Type {
 	if !p.curTokenIs(token.IDENT) && !p.curTokenIs(token.ERROR) {
 		p.addError("expected type name, got " + p.curToken.Literal)
@@ -119,6 +241,18 @@ func (p *Parser) parseArrayOrFixedArrayType() ast.Type {
 		return &ast.FixedArrayType{Token: tok, Size: size, ElementType: elemType}
 	}
 
+	// Slice type written [T], as in func first<T>(xs: [T]): T
+	if !p.curTokenIs(token.RBRACKET) && p.isTypeStart() {
+		elemType := p.parseType()
+		if elemType == nil {
+			return nil
+		}
+		if !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+		return &ast.ArrayType{Token: tok, ElementType: elemType}
+	}
+
 	// Slice type: []T curToken should be ']'
 	if !p.curTokenIs(token.RBRACKET) {
 		p.addError("expected ']' in slice type, got " + p.curToken.Literal)
@@ -323,8 +457,11 @@ func (p *Parser) parseTypeCheck(left ast.Expression) ast.Expression {
 func (p *Parser) parseTypeNoAdvance() ast.Type {
 	switch p.curToken.Type {
 	case token.IDENT:
-		if aliasTarget, ok := p.aliases[p.curToken.Literal]; ok {
-			return aliasTarget
+		if named, ok := p.namedType(p.curToken.Literal); ok {
+			return named
+		}
+		if p.generics[p.curToken.Literal] && p.peekTokenIs(token.LT) {
+			return p.parseGenericType()
 		}
 		name := p.curToken.Literal
 		tok := p.curToken
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/vintlang/vintlang/internal/ast"
@@ -589,6 +590,38 @@ func TestTypedStructFieldWithDefault(t *testing.T) {
 	}
 }
 
+func TestStructFieldDictTypeLast(t *testing.T) {
+	tests := []struct {
+		input      string
+		hasDefault bool
+	}{
+		{`struct Outer { items: []int, meta: {string: any} }`, false},
+		{`struct Outer { items: []int, meta: {string: any} = {"a": 1} }`, true},
+		{`struct Outer { items: []int, meta: {string: any}; }; let x = 1`, false},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.StructStatement)
+		if !ok {
+			t.Fatalf("%s: statement not *ast.StructStatement. got=%T", tt.input, program.Statements[0])
+		}
+		if len(stmt.Fields) != 2 {
+			t.Fatalf("%s: expected 2 fields. got=%d", tt.input, len(stmt.Fields))
+		}
+		if _, ok := stmt.Fields[1].Type.(*ast.DictType); !ok {
+			t.Errorf("%s: field[1] type not *ast.DictType. got=%T", tt.input, stmt.Fields[1].Type)
+		}
+		if (stmt.Fields[1].Default != nil) != tt.hasDefault {
+			t.Errorf("%s: field[1] default wrong. got=%v", tt.input, stmt.Fields[1].Default)
+		}
+	}
+}
+
 func TestUntypedStructFieldBackwardCompat(t *testing.T) {
 	input := `struct Point { x: 0, y: 0 }`
 	l := lexer.New(input)
@@ -766,3 +799,113 @@ func TestTypedFunctionLiteralWithName(t *testing.T) {
 		t.Errorf("named function should have name 'add'. got=%q", funcLit.Name)
 	}
 }
+
+func TestGenericFunctionLiteral(t *testing.T) {
+	input := `let pick = func<K, V: number>(d: {K: V}, k: K): V { return d[k] }`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	letStmt := program.Statements[0].(*ast.LetStatement)
+	funcLit, ok := letStmt.Value.(*ast.TypedFunctionLiteral)
+	if !ok {
+		t.Fatalf("value is not *ast.TypedFunctionLiteral. got=%T", letStmt.Value)
+	}
+	if len(funcLit.TypeParams) != 2 {
+		t.Fatalf("expected 2 type parameters, got=%d", len(funcLit.TypeParams))
+	}
+	if funcLit.TypeParams[1].Constraint == nil || funcLit.TypeParams[1].Constraint.String() != "number" {
+		t.Errorf("wrong constraint for V. got=%v", funcLit.TypeParams[1].Constraint)
+	}
+	if _, ok := funcLit.Parameters[0].Type.(*ast.DictType).KeyType.(*ast.TypeParameter); !ok {
+		t.Errorf("K in a parameter type should be a type parameter")
+	}
+	if _, ok := funcLit.ReturnType.(*ast.TypeParameter); !ok {
+		t.Errorf("return type should be a type parameter. got=%T", funcLit.ReturnType)
+	}
+}
+
+func TestGenericStructStatement(t *testing.T) {
+	input := `struct Box<T> {
+	value: T
+	func get(): T { return this.value }
+}
+let b: Box<Box<int>> = Box<Box<int>>(value = Box(value = 1))`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.StructStatement)
+	if !ok {
+		t.Fatalf("statement is not *ast.StructStatement. got=%T", program.Statements[0])
+	}
+	if len(stmt.TypeParams) != 1 || stmt.TypeParams[0].Name != "T" {
+		t.Fatalf("wrong type parameters. got=%v", stmt.TypeParams)
+	}
+	if _, ok := stmt.Fields[0].Type.(*ast.TypeParameter); !ok {
+		t.Errorf("field type should be a type parameter. got=%T", stmt.Fields[0].Type)
+	}
+
+	// >> closes both type argument lists
+	letStmt, ok := program.Statements[1].(*ast.TypedLetStatement)
+	if !ok {
+		t.Fatalf("statement is not *ast.TypedLetStatement. got=%T", program.Statements[1])
+	}
+	if got := letStmt.TypeAnnotation.Type.String(); got != "Box<Box<int>>" {
+		t.Errorf("wrong type annotation. got=%q", got)
+	}
+	call := letStmt.Value.(*ast.CallExpression)
+	if inst, ok := call.Function.(*ast.InstantiateExpression); !ok || inst.String() != "Box<Box<int>>" {
+		t.Errorf("callee should be Box<Box<int>>. got=%T %v", call.Function, call.Function)
+	}
+}
+
+func TestGenericLessThanDisambiguation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// a and b are not generic, so < is a comparison
+		{"let a = 1\nlet b = 2\na < b", "(a < b)"},
+		{"let id = func<T>(x: T): T { return x }\nid<int>(1)", "id<int>(1)"},
+		{"struct Box<T> { value: T }\nBox < 1", "(Box < 1)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		last := program.Statements[len(program.Statements)-1]
+		if got := last.String(); got != tt.expected {
+			t.Errorf("wrong parse for %q. got=%q, want=%q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTypeParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = func<T, T>(x: T) { return x }", "duplicate type parameter 'T'"},
+		{"let f = func<T x>(x: T) { return x }", "expected ',' or '>' in type parameters, got x"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		found := false
+		for _, err := range p.Errors() {
+			if strings.Contains(err, tt.expected) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error containing %q for %q. got=%v", tt.expected, tt.input, p.Errors())
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vintlang/vintlang/internal/evaluator"
+)
+
+// Function is what a profile measured for one function. Self is the time
+// spent in the function itself and Total includes the functions it
+// called; a recursive call is only counted once in Total. Alloc estimates
+// the bytes the function itself allocated.
+type Function struct {
+	Name  string
+	File  string // where a Vint function is defined, empty for builtins and modules
+	Line  int
+	Calls int
+	Self  time.Duration
+	Total time.Duration
+	Alloc uint64
+
+	active int // calls of the function that have not returned
+}
+
+// Location is the file and line of the function, if it has them.
+func (f *Function) Location() string {
+	if f.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+}
+
+// Line is what a profile measured for one line of source: how often a
+// statement on it ran and the time and allocations spent there. Time in
+// the Vint functions it called goes to their lines, but builtins and
+// module functions have none, so their time stays with the caller.
+type Line struct {
+	File  string
+	Line  int
+	Hits  int
+	Self  time.Duration
+	Alloc uint64
+}
+
+// call is a call that has not returned yet.
+type call struct {
+	fn         *Function
+	stack      string // the calls down to this one, as in a collapsed stack
+	start      time.Time
+	alloc      uint64
+	inner      time.Duration // spent in the functions it called
+	innerAlloc uint64
+	caller     *Line // the line the call was made from
+}
+
+// thread is the call stack of one goroutine running Vint code, and the
+// line it has got to.
+type thread struct {
+	calls     []*call // for the main thread, calls[0] is the program itself, <main>
+	line      *Line   // the line running
+	lineStart time.Time
+	lineAlloc uint64
+}
+
+// Profile records the calls and statements of a running program. It is
+// an evaluator.Profiler.
+type Profile struct {
+	mu        sync.Mutex
+	sample    func() (time.Time, uint64) // the time and the bytes allocated so far
+	functions map[string]*Function
+	lines     map[string]*Line
+	stacks    map[string]time.Duration // self time by collapsed stack
+	threads   map[int]*thread          // nil once the profile has stopped
+	start     time.Time
+	total     time.Duration
+}
+
+// New starts a profile. Stop it when the program has finished.
+func New() *Profile {
+	return newProfile(readSample)
+}
+
+func newProfile(sample func() (time.Time, uint64)) *Profile {
+	p := &Profile{
+		sample:    sample,
+		functions: map[string]*Function{},
+		lines:     map[string]*Line{},
+		stacks:    map[string]time.Duration{},
+		threads:   map[int]*thread{},
+	}
+	now, alloc := p.sample()
+	p.start = now
+	p.push(p.thread(evaluator.MainThread), "<main>", "", 0, now, alloc)
+	return p
+}
+
+var allocSample = []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+
+// readSample reads the clock and the bytes the process has allocated.
+// The runtime counts small allocations in batches, so the bytes are an
+// estimate.
+func readSample() (time.Time, uint64) {
+	metrics.Read(allocSample)
+	var alloc uint64
+	if allocSample[0].Value.Kind() == metrics.KindUint64 {
+		alloc = allocSample[0].Value.Uint64()
+	}
+	return time.Now(), alloc
+}
+
+// thread returns the call stack of a thread, starting an empty one for a
+// thread not seen before.
+func (p *Profile) thread(id int) *thread {
+	t, ok := p.threads[id]
+	if !ok {
+		t = &thread{}
+		p.threads[id] = t
+	}
+	return t
+}
+
+// Enter records that a call of function has begun on a thread.
+func (p *Profile) Enter(thread int, function, file string, line int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now, alloc := p.sample()
+	if p.threads != nil {
+		p.push(p.thread(thread), function, file, line, now, alloc)
+	}
+}
+
+func (p *Profile) push(t *thread, function, file string, line int, now time.Time, alloc uint64) {
+	key := fmt.Sprintf("%s\x00%s:%d", function, file, line)
+	fn, ok := p.functions[key]
+	if !ok {
+		fn = &Function{Name: function, File: file, Line: line}
+		p.functions[key] = fn
+	}
+	fn.Calls++
+	fn.active++
+
+	frame := function
+	if loc := fn.Location(); loc != "" {
+		frame += " (" + loc + ")"
+	}
+	if len(t.calls) > 0 {
+		frame = t.calls[len(t.calls)-1].stack + ";" + frame
+	}
+	t.calls = append(t.calls, &call{fn: fn, stack: frame, start: now, alloc: alloc, caller: t.line})
+}
+
+// Leave records that the innermost call of a thread has returned.
+func (p *Profile) Leave(thread int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now, alloc := p.sample()
+	t, ok := p.threads[thread]
+	if !ok {
+		return
+	}
+	// <main> only returns when the profile stops
+	if thread != evaluator.MainThread || len(t.calls) > 1 {
+		p.pop(t, now, alloc)
+	}
+	if thread != evaluator.MainThread && len(t.calls) == 0 {
+		delete(p.threads, thread)
+	}
+}
+
+func (p *Profile) pop(t *thread, now time.Time, alloc uint64) {
+	if len(t.calls) == 0 {
+		return
+	}
+	t.countLine(now, alloc)
+	c := t.calls[len(t.calls)-1]
+	t.calls = t.calls[:len(t.calls)-1]
+	t.line = c.caller
+
+	elapsed, allocated := now.Sub(c.start), alloc-c.alloc
+	self := elapsed - c.inner
+	c.fn.Self += self
+	if allocated > c.innerAlloc {
+		c.fn.Alloc += allocated - c.innerAlloc
+	}
+	c.fn.active--
+	if c.fn.active == 0 {
+		c.fn.Total += elapsed
+	}
+	p.stacks[c.stack] += self
+	if len(t.calls) > 0 {
+		parent := t.calls[len(t.calls)-1]
+		parent.inner += elapsed
+		parent.innerAlloc += allocated
+	}
+}
+
+// Statement records that a statement on the given line has begun on a
+// thread.
+func (p *Profile) Statement(thread int, file string, line int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now, alloc := p.sample()
+	if p.threads == nil {
+		return
+	}
+	t := p.thread(thread)
+	t.countLine(now, alloc)
+	key := fmt.Sprintf("%s:%d", file, line)
+	l, ok := p.lines[key]
+	if !ok {
+		l = &Line{File: file, Line: line}
+		p.lines[key] = l
+	}
+	l.Hits++
+	t.line = l
+}
+
+// countLine gives the time and allocations since the last count to the
+// line the thread is running.
+func (t *thread) countLine(now time.Time, alloc uint64) {
+	if t.line != nil {
+		t.line.Self += now.Sub(t.lineStart)
+		t.line.Alloc += alloc - t.lineAlloc
+	}
+	t.lineStart, t.lineAlloc = now, alloc
+}
+
+// Stop ends the profile, along with any calls that have not returned.
+// Later calls and statements are not recorded.
+func (p *Profile) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now, alloc := p.sample()
+	if p.threads == nil {
+		return
+	}
+	p.total = now.Sub(p.start)
+	for _, t := range p.threads {
+		for len(t.calls) > 0 {
+			p.pop(t, now, alloc)
+		}
+		t.countLine(now, alloc)
+	}
+	p.threads = nil
+}
+
+// Functions returns the functions that were called, the ones with the
+// most self time first.
+func (p *Profile) Functions() []*Function {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	functions := make([]*Function, 0, len(p.functions))
+	for _, fn := range p.functions {
+		functions = append(functions, fn)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		a, b := functions[i], functions[j]
+		if a.Self != b.Self {
+			return a.Self > b.Self
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Location() < b.Location()
+	})
+	return functions
+}
+
+// Lines returns the lines that ran, the ones with the most self time
+// first.
+func (p *Profile) Lines() []*Line {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lines := make([]*Line, 0, len(p.lines))
+	for _, l := range p.lines {
+		lines = append(lines, l)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		if a.Self != b.Self {
+			return a.Self > b.Self
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return lines
+}
+
+// WriteTable writes the top functions and lines as tables, the ones with
+// the most self time first. If top is 0 or less, everything is written.
+func (p *Profile) WriteTable(w io.Writer, top int) error {
+	functions, lines := p.Functions(), p.Lines()
+	if top > 0 && len(functions) > top {
+		functions = functions[:top]
+	}
+	if top > 0 && len(lines) > top {
+		lines = lines[:top]
+	}
+	total := p.total
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Functions by self time (%s in total)\n", duration(total))
+	fmt.Fprintf(&b, "%10s %6s %10s %8s %9s  %s\n", "Self", "Self%", "Total", "Calls", "Alloc", "Function")
+	for _, fn := range functions {
+		name := fn.Name
+		if loc := fn.Location(); loc != "" {
+			name += "  " + loc
+		}
+		fmt.Fprintf(&b, "%10s %6s %10s %8d %9s  %s\n",
+			duration(fn.Self), percent(fn.Self, total), duration(fn.Total), fn.Calls, size(fn.Alloc), name)
+	}
+
+	fmt.Fprintf(&b, "\nLines by self time\n")
+	fmt.Fprintf(&b, "%10s %6s %8s %9s  %s\n", "Self", "Self%", "Hits", "Alloc", "Line")
+	sources := map[string][]string{}
+	for _, l := range lines {
+		where := fmt.Sprintf("%s:%d", filepath.Base(l.File), l.Line)
+		if text := sourceLine(sources, l.File, l.Line); text != "" {
+			where += "  " + text
+		}
+		fmt.Fprintf(&b, "%10s %6s %8d %9s  %s\n", duration(l.Self), percent(l.Self, total), l.Hits, size(l.Alloc), where)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFolded writes the profile in the collapsed-stack format read by
+// flamegraph.pl, speedscope and other flame graph tools: one line per
+// call stack, its frames separated by semicolons, followed by the
+// microseconds spent in the innermost frame.
+func (p *Profile) WriteFolded(w io.Writer) error {
+	p.mu.Lock()
+	stacks := make([]string, 0, len(p.stacks))
+	for stack := range p.stacks {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	var b strings.Builder
+	for _, stack := range stacks {
+		if us := p.stacks[stack].Microseconds(); us > 0 {
+			fmt.Fprintf(&b, "%s %d\n", stack, us)
+		}
+	}
+	p.mu.Unlock()
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sourceLine returns line n of file, trimmed and shortened for a table.
+func sourceLine(sources map[string][]string, file string, n int) string {
+	lines, ok := sources[file]
+	if !ok {
+		if data, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		sources[file] = lines
+	}
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	text := strings.TrimSpace(lines[n-1])
+	if r := []rune(text); len(r) > 50 {
+		text = string(r[:50]) + "…"
+	}
+	return text
+}
+
+func duration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+	case d >= time.Microsecond:
+		return fmt.Sprintf("%.1fµs", float64(d)/float64(time.Microsecond))
+	}
+	return fmt.Sprintf("%dns", d.Nanoseconds())
+}
+
+func percent(d, total time.Duration) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(d)/float64(total))
+}
+
+func size(bytes uint64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", bytes)
+}
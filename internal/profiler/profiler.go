@@ -0,0 +1,95 @@
+// Package profiler measures where a Vint program spends its time: the
+// calls, self and total time and allocations of each function, and the
+// time spent on each line, written as a table and as collapsed stacks for
+// flame graph tools.
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/repl"
+	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/toolkit"
+)
+
+// Main runs `vint --profile` and returns the exit code:
+//
+//	vint --profile [--top n] [--out file] file.vint [args]
+//
+// The program runs as with `vint file.vint`. When it has finished the
+// tables of the slowest functions and lines are written to out, and the
+// collapsed stacks to the --out file, vint_profile.folded by default.
+func Main(args []string, out io.Writer) int {
+	top, output := 20, "vint_profile.folded"
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		switch {
+		case args[0] == "--top" && len(args) > 1:
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: --top needs a number, got '%s'", args[1])))
+				return 2
+			}
+			top = n
+			args = args[1:]
+		case args[0] == "--out" && len(args) > 1:
+			output = args[1]
+			args = args[1:]
+		default:
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown flag %s", args[0])))
+			return 2
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 || !strings.HasSuffix(args[0], ".vint") {
+		fmt.Fprintln(out, styles.ErrorStyle.Render("Usage: vint --profile [--top n] [--out file] file.vint [args]"))
+		return 2
+	}
+
+	file := args[0]
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	toolkit.CLI_ARGS = append(toolkit.CLI_ARGS, args[1:]...)
+	if dir, err := filepath.Abs(filepath.Dir(file)); err == nil {
+		evaluator.AddSearchPath(dir)
+	}
+
+	profile := New()
+	evaluator.SetProfiler(profile)
+	repl.ReadWithFilename(string(contents), file)
+	evaluator.SetProfiler(nil)
+	profile.Stop()
+
+	fmt.Fprintln(out)
+	if err := profile.WriteTable(out, top); err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	if err := writeFolded(profile, output); err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, styles.HelpStyle.Render(fmt.Sprintf("Wrote the collapsed stacks to %s; view them with flamegraph.pl or speedscope", output)))
+	return 0
+}
+
+func writeFolded(profile *Profile, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := profile.WriteFolded(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
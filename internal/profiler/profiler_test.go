@@ -0,0 +1,199 @@
+package profiler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vintlang/vintlang/internal/evaluator"
+)
+
+// fakeClock advances by a millisecond and 100 bytes every time it is read.
+func fakeClock() func() (time.Time, uint64) {
+	now, alloc := time.Unix(0, 0), uint64(0)
+	return func() (time.Time, uint64) {
+		now, alloc = now.Add(time.Millisecond), alloc+100
+		return now, alloc
+	}
+}
+
+func TestProfileAccounting(t *testing.T) {
+	const thread = evaluator.MainThread
+	p := newProfile(fakeClock())         // 1ms
+	p.Statement(thread, "a.vint", 5)     // 2ms
+	p.Enter(thread, "fact", "a.vint", 1) // 3ms
+	p.Statement(thread, "a.vint", 2)     // 4ms
+	p.Enter(thread, "fact", "a.vint", 1) // 5ms
+	p.Statement(thread, "a.vint", 2)     // 6ms
+	p.Enter(thread, "print", "", 0)      // 7ms
+	p.Leave(thread)                      // 8ms
+	p.Leave(thread)                      // 9ms
+	p.Leave(thread)                      // 10ms
+	p.Stop()                             // 11ms
+
+	byName := map[string]*Function{}
+	for _, fn := range p.Functions() {
+		byName[fn.Name] = fn
+	}
+	fact, print, main := byName["fact"], byName["print"], byName["<main>"]
+	if fact.Calls != 2 || fact.Total != 7*time.Millisecond || fact.Self != 6*time.Millisecond || fact.Alloc != 600 {
+		t.Errorf("fact: %+v", fact)
+	}
+	if print.Calls != 1 || print.Self != time.Millisecond || print.Location() != "" {
+		t.Errorf("print: %+v", print)
+	}
+	if main.Total != 10*time.Millisecond || main.Self != 3*time.Millisecond {
+		t.Errorf("<main>: %+v", main)
+	}
+	if fns := p.Functions(); fns[0] != fact || fns[2] != print {
+		t.Errorf("functions are not sorted by self time: %v %v %v", fns[0].Name, fns[1].Name, fns[2].Name)
+	}
+
+	lines := p.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("lines: %+v", lines)
+	}
+	// Line 2 has 4ms to 10ms, print included as it has no lines of its
+	// own, and line 5 has 2ms to 4ms and 10ms to 11ms
+	if l := lines[0]; l.Line != 2 || l.Hits != 2 || l.Self != 6*time.Millisecond {
+		t.Errorf("line 2: %+v", l)
+	}
+	if l := lines[1]; l.Line != 5 || l.Hits != 1 || l.Self != 3*time.Millisecond {
+		t.Errorf("line 5: %+v", l)
+	}
+
+	var folded bytes.Buffer
+	p.WriteFolded(&folded)
+	want := "<main> 3000\n" +
+		"<main>;fact (a.vint:1) 3000\n" +
+		"<main>;fact (a.vint:1);fact (a.vint:1) 3000\n" +
+		"<main>;fact (a.vint:1);fact (a.vint:1);print 1000\n"
+	if folded.String() != want {
+		t.Errorf("folded:\n%s\nwant:\n%s", folded.String(), want)
+	}
+}
+
+func TestProfileThreads(t *testing.T) {
+	const main = evaluator.MainThread
+	p := newProfile(fakeClock())          // 1ms
+	p.Enter(main, "inner", "a.vint", 1)   // 2ms
+	p.Enter(main+1, "<goroutine>", "", 0) // 3ms
+	p.Enter(main+1, "a", "a.vint", 5)     // 4ms
+	p.Enter(main+2, "<goroutine>", "", 0) // 5ms
+	p.Enter(main+2, "b", "a.vint", 9)     // 6ms
+	p.Enter(main+1, "spin", "a.vint", 13) // 7ms
+	p.Enter(main+2, "spin", "a.vint", 13) // 8ms
+	p.Leave(main + 1)                     // 9ms
+	p.Leave(main)                         // 10ms
+	p.Leave(main + 2)                     // 11ms
+	p.Leave(main + 2)                     // 12ms
+	p.Leave(main + 2)                     // 13ms
+	p.Leave(main + 1)                     // 14ms
+	p.Leave(main + 1)                     // 15ms
+	p.Stop()                              // 16ms
+
+	var folded bytes.Buffer
+	p.WriteFolded(&folded)
+	want := "<goroutine> 4000\n" +
+		"<goroutine>;a (a.vint:5) 8000\n" +
+		"<goroutine>;a (a.vint:5);spin (a.vint:13) 2000\n" +
+		"<goroutine>;b (a.vint:9) 3000\n" +
+		"<goroutine>;b (a.vint:9);spin (a.vint:13) 3000\n" +
+		"<main> 7000\n" +
+		"<main>;inner (a.vint:1) 8000\n"
+	if folded.String() != want {
+		t.Errorf("folded:\n%s\nwant:\n%s", folded.String(), want)
+	}
+	for _, fn := range p.Functions() {
+		if fn.Name == "spin" && (fn.Calls != 2 || fn.Self != 5*time.Millisecond) {
+			t.Errorf("spin: %+v", fn)
+		}
+	}
+}
+
+func TestProfileGoroutines(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "prog.vint")
+	source := `let spin = func(n) {
+    let i = 0
+    while (i < n) { i++ }
+}
+let a = func(ch) { spin(3000); send(ch, 1) }
+let b = func(ch) { spin(3000); send(ch, 1) }
+let ch = chan
+go a(ch)
+go b(ch)
+receive(ch)
+receive(ch)
+`
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	folded := filepath.Join(dir, "out.folded")
+	var out bytes.Buffer
+	if code := Main([]string{"--out", folded, file}, &out); code != 0 {
+		t.Fatalf("exit code %d\n%s", code, out.String())
+	}
+	data, err := os.ReadFile(folded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stacks := string(data)
+	for _, want := range []string{"<goroutine>;a (prog.vint:5);spin (prog.vint:1) ", "<goroutine>;b (prog.vint:6);spin (prog.vint:1) "} {
+		if !strings.Contains(stacks, want) {
+			t.Errorf("missing %q in\n%s", want, stacks)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(stacks), "\n") {
+		if strings.HasPrefix(line, "<main>;") && strings.Contains(line, "spin") ||
+			strings.Contains(line, "a (") && strings.Contains(line, "b (") {
+			t.Errorf("goroutines share a stack: %s", line)
+		}
+	}
+}
+
+func TestProfileCommand(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "prog.vint")
+	source := `let square = func(n) {
+    return n * n
+}
+let total = 0
+for i in range(0, 50) {
+    total = total + square(i)
+}
+`
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	folded := filepath.Join(dir, "out.folded")
+	var out bytes.Buffer
+	if code := Main([]string{"--out", folded, file}, &out); code != 0 {
+		t.Fatalf("exit code %d\n%s", code, out.String())
+	}
+	table := out.String()
+	for _, want := range []string{"Functions by self time", "square  prog.vint:1", "range", "Lines by self time", "prog.vint:6  total = total + square(i)"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("missing %q in\n%s", want, table)
+		}
+	}
+	for _, line := range strings.Split(table, "\n") {
+		if strings.HasSuffix(line, "square  prog.vint:1") && !strings.Contains(line, " 50 ") {
+			t.Errorf("square was not called 50 times: %s", line)
+		}
+	}
+	data, err := os.ReadFile(folded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<main>;square (prog.vint:1) ") {
+		t.Errorf("folded stacks:\n%s", data)
+	}
+
+	if code := Main([]string{"--top", "x", file}, &out); code != 2 {
+		t.Errorf("--top x: exit code %d", code)
+	}
+}
@@ -45,11 +45,7 @@ func ReadWithFilename(contents string, filename string) {
 		return // Don't evaluate if there are parser errors
 	}
 	evaluated := evaluator.Eval(program, env)
-	if evaluated != nil {
-		if evaluated.Type() != object.NULL_OBJ {
-			fmt.Println(styles.ReplStyle.Render(evaluated.Inspect()))
-		}
-	}
+	printResult(evaluated, filename, contents)
 }
 
 func Start() {
@@ -98,11 +94,7 @@ func (d *dummy) executor(in string) {
 	}
 	env := d.env
 	evaluated := evaluator.Eval(program, env)
-	if evaluated != nil {
-		if evaluated.Type() != object.NULL_OBJ {
-			fmt.Println(styles.ReplStyle.Render(evaluated.Inspect()))
-		}
-	}
+	printResult(evaluated, "<repl>", in)
 }
 
 func completer(in prompt.Document) []prompt.Suggest {
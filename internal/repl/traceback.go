@@ -0,0 +1,72 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/styles"
+	vinterrors "github.com/vintlang/vintlang/internal/vintErrors"
+)
+
+var lineNumberPrefix = regexp.MustCompile(`^Line \d+: `)
+
+// printResult prints the value a program evaluated to. Runtime errors that
+// carry a source position are printed as a traceback.
+func printResult(evaluated object.VintObject, filename, contents string) {
+	if evaluated == nil || evaluated.Type() == object.NULL_OBJ {
+		return
+	}
+	if errObj, ok := evaluated.(*object.Error); ok && errObj.HasLocation() {
+		fmt.Println(styles.ErrorStyle.Render(runtimeError(errObj, filename, contents).Error()))
+		return
+	}
+	fmt.Println(styles.ReplStyle.Render(evaluated.Inspect()))
+}
+
+// runtimeError converts an evaluation error into a VintError that shows the
+// offending source line and the calls the error unwound through.
+func runtimeError(errObj *object.Error, filename, contents string) *vinterrors.VintError {
+	sources := map[string]string{filename: contents}
+	sourceLine := func(file string, line int) string {
+		source, ok := sources[file]
+		if !ok {
+			if data, err := os.ReadFile(file); err == nil {
+				source = string(data)
+			}
+			sources[file] = source
+		}
+		return vinterrors.SourceLine(source, line)
+	}
+
+	code := vinterrors.E403_RUNTIME_ERROR
+	if errObj.Thrown != nil {
+		code = vinterrors.E404_UNCAUGHT_THROW
+	}
+
+	var frames []vinterrors.Frame
+	for _, frame := range errObj.Traceback() {
+		frames = append(frames, vinterrors.Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			Column:   frame.Column,
+			Context:  sourceLine(frame.File, frame.Line),
+		})
+	}
+
+	// The header already shows where the error was raised, so a traceback
+	// is only worth printing once the error crossed a function call.
+	if len(frames) < 2 {
+		frames = nil
+	}
+
+	// Older errors carry their line in the message; the header shows it now
+	message := lineNumberPrefix.ReplaceAllString(errObj.Message, "")
+
+	return vinterrors.NewError(code, vinterrors.ERROR, message, errObj.Line, errObj.Column).
+		WithFile(errObj.File).
+		WithContext(sourceLine(errObj.File, errObj.Line)).
+		WithStack(frames)
+}
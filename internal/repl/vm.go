@@ -0,0 +1,48 @@
+package repl
+
+import (
+	"fmt"
+
+	"github.com/vintlang/vintlang/internal/compiler"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/vm"
+)
+
+// ReadWithVM compiles a program to bytecode and runs it on the VM instead of
+// the tree-walking evaluator.
+func ReadWithVM(contents string, filename string) bool {
+	l := lexer.NewWithFilename(contents, filename)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		fmt.Println(styles.ErrorStyle.Italic(false).Render("These errors occured:"))
+
+		for _, msg := range p.Errors() {
+			fmt.Println("\t" + styles.ErrorStyle.Render(msg))
+		}
+		return false
+	}
+
+	comp := compiler.New()
+	if err := comp.Compile(program); err != nil {
+		fmt.Println(styles.ErrorStyle.Render(fmt.Sprintf("Compilation failed: %s", err)))
+		return false
+	}
+
+	machine := vm.New(comp.Bytecode())
+	if err := machine.Run(); err != nil {
+		if vmErr, ok := err.(*vm.Error); ok {
+			printResult(vmErr.Object, filename, contents)
+		} else {
+			fmt.Println(styles.ErrorStyle.Render(err.Error()))
+		}
+		return false
+	}
+
+	printResult(machine.Result(), filename, contents)
+	return true
+}
@@ -0,0 +1,340 @@
+// Package testrunner implements `vint test`. It finds *_test.vint files,
+// runs every test in them and reports the results.
+//
+// A test is either a function bound to a name starting with test_:
+//
+//	let test_addition = func() { assert.equal(1 + 1, 2) }
+//
+// or a named test block:
+//
+//	test "addition" { assert.equal(1 + 1, 2) }
+//
+// Each test runs in a fresh environment: the top-level code of its file runs
+// first, then the test itself. A test fails if it raises an error, which is
+// what the functions of the assert module do when an assertion does not hold.
+package testrunner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vintlang/vintlang/internal/ast"
+	"github.com/vintlang/vintlang/internal/coverage"
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/object"
+	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/styles"
+)
+
+const testFileSuffix = "_test.vint"
+
+var lineNumberPrefix = regexp.MustCompile(`^Line \d+: `)
+
+// Result is the outcome of a single test.
+type Result struct {
+	File     string
+	Name     string
+	Duration time.Duration
+	Err      *object.Error // nil if the test passed
+}
+
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+// testCase is a test found in a file, in source order.
+type testCase struct {
+	name string
+	fn   string              // name of the test function, for test_ functions
+	body *ast.BlockStatement // body of a test block
+}
+
+// Discover returns the test files under the given paths. A path may be a
+// test file or a directory, which is searched recursively. With no paths the
+// current directory is searched.
+func Discover(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if p != path && (strings.HasPrefix(name, ".") || name == "node_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(p, testFileSuffix) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// RunFile runs the tests in a file whose names match filter. A nil filter
+// runs every test.
+func RunFile(file string, filter *regexp.Regexp) ([]Result, error) {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	l := lexer.NewWithFilename(string(source), file)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, fmt.Errorf("%s: %s", file, strings.Join(p.Errors(), "; "))
+	}
+
+	if dir, err := filepath.Abs(filepath.Dir(file)); err == nil {
+		evaluator.AddSearchPath(dir)
+	}
+
+	var results []Result
+	for _, tc := range findTests(program) {
+		if filter != nil && !filter.MatchString(tc.name) {
+			continue
+		}
+		start := time.Now()
+		errObj := runTest(program, tc)
+		results = append(results, Result{
+			File:     file,
+			Name:     tc.name,
+			Duration: time.Since(start),
+			Err:      errObj,
+		})
+	}
+	return results, nil
+}
+
+func findTests(program *ast.Program) []testCase {
+	var tests []testCase
+	for _, stmt := range program.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.TestStatement:
+			tests = append(tests, testCase{name: stmt.Name, body: stmt.Body})
+		case *ast.LetStatement:
+			if isTestFunction(stmt.Name, stmt.Value) {
+				tests = append(tests, testCase{name: stmt.Name.Value, fn: stmt.Name.Value})
+			}
+		case *ast.ConstStatement:
+			if isTestFunction(stmt.Name, stmt.Value) {
+				tests = append(tests, testCase{name: stmt.Name.Value, fn: stmt.Name.Value})
+			}
+		}
+	}
+	return tests
+}
+
+func isTestFunction(name *ast.Identifier, value ast.Expression) bool {
+	if !strings.HasPrefix(name.Value, "test_") {
+		return false
+	}
+	_, ok := value.(*ast.FunctionLiteral)
+	return ok
+}
+
+// runTest runs the top-level code of the file and then the test, all in a
+// new environment. It returns the error the test failed with, if any.
+func runTest(program *ast.Program, tc testCase) (failure *object.Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			failure = &object.Error{Message: fmt.Sprintf("test panicked: %v", r)}
+		}
+	}()
+
+	env := object.NewEnvironment()
+	for _, stmt := range program.Statements {
+		if _, ok := stmt.(*ast.TestStatement); ok {
+			continue
+		}
+		if errObj := asError(evaluator.Eval(stmt, env)); errObj != nil {
+			return errObj
+		}
+	}
+
+	if tc.body != nil {
+		return asError(evaluator.Eval(tc.body, object.NewEnclosedEnvironment(env)))
+	}
+
+	value, _ := env.Get(tc.fn)
+	fn, ok := value.(*object.Function)
+	if !ok {
+		return &object.Error{Message: fmt.Sprintf("%s is not a function", tc.fn)}
+	}
+	return asError(object.CallFunction(fn, []object.VintObject{}))
+}
+
+func asError(obj object.VintObject) *object.Error {
+	if rv, ok := obj.(*object.ReturnValue); ok {
+		obj = rv.Value
+	}
+	errObj, _ := obj.(*object.Error)
+	return errObj
+}
+
+// Main runs `vint test [dir|file]... [--run pattern] [--cover out.cov]`
+// and returns the exit code: 0 if every test passed, 1 otherwise.
+func Main(args []string, out io.Writer) int {
+	var paths []string
+	var filter *regexp.Regexp
+	var report string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--run" || arg == "-run":
+			if i+1 >= len(args) {
+				fmt.Fprintln(out, styles.ErrorStyle.Render("Error: --run needs a pattern"))
+				return 1
+			}
+			i++
+			arg = "--run=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--run="):
+			pattern := strings.TrimPrefix(arg, "--run=")
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: invalid --run pattern: %v", err)))
+				return 1
+			}
+			filter = re
+		case arg == "--cover" || arg == "-cover":
+			if i+1 >= len(args) {
+				fmt.Fprintln(out, styles.ErrorStyle.Render("Error: --cover needs a file for the report"))
+				return 1
+			}
+			i++
+			report = args[i]
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	files, err := Discover(paths)
+	if err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(out, styles.WarningStyle.Render("No *_test.vint files found"))
+		return 0
+	}
+
+	var profile *coverage.Profile
+	if report != "" {
+		profile = coverage.New()
+		evaluator.SetCoverage(profile)
+		defer evaluator.SetCoverage(nil)
+	}
+
+	start := time.Now()
+	passed, failed := 0, 0
+	for _, file := range files {
+		results, err := RunFile(file, filter)
+		if err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("FAIL  %v", err)))
+			failed++
+			continue
+		}
+		if len(results) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(out, styles.InfoStyle.Render("=== "+file))
+		for _, r := range results {
+			if r.Passed() {
+				passed++
+				fmt.Fprintf(out, "  %s %s (%s)\n", styles.SuccessStyle.Render("PASS"), r.Name, formatDuration(r.Duration))
+				continue
+			}
+			failed++
+			fmt.Fprintf(out, "  %s %s (%s)\n", styles.ErrorStyle.Render("FAIL"), r.Name, formatDuration(r.Duration))
+			fmt.Fprint(out, formatFailure(r.Err))
+		}
+	}
+
+	if profile != nil {
+		// Only the code under test is reported, not the tests
+		var covered []*coverage.File
+		for _, f := range profile.Files() {
+			if !strings.HasSuffix(f.Name, testFileSuffix) {
+				covered = append(covered, f)
+			}
+		}
+		fmt.Fprintln(out)
+		if err := coverage.Write(out, covered, report); err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		}
+	}
+
+	fmt.Fprintln(out)
+	summary := fmt.Sprintf("%d passed, %d failed in %s", passed, failed, formatDuration(time.Since(start)))
+	if failed > 0 {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(summary))
+		return 1
+	}
+	fmt.Fprintln(out, styles.SuccessStyle.Render(summary))
+	return 0
+}
+
+// formatFailure shows where a test failed and the calls that led there.
+func formatFailure(errObj *object.Error) string {
+	var out strings.Builder
+	message := errObj.Message
+	if errObj.HasLocation() {
+		message = lineNumberPrefix.ReplaceAllString(message, "")
+		message = fmt.Sprintf("%s:%d:%d: %s", errObj.File, errObj.Line, errObj.Column, message)
+	}
+	for _, line := range strings.Split(strings.TrimRight(message, "\n"), "\n") {
+		out.WriteString("        " + line + "\n")
+	}
+
+	frames := errObj.Traceback()
+	if len(frames) > 1 {
+		for i := len(frames) - 1; i >= 0; i-- {
+			f := frames[i]
+			out.WriteString(fmt.Sprintf("          at %s (%s:%d:%d)\n", f.Function, f.File, f.Line, f.Column))
+		}
+	}
+	return out.String()
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
@@ -0,0 +1,160 @@
+package testrunner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const sampleTests = `import assert
+
+let counter = 0
+let bump = func() { counter = counter + 1; counter }
+
+let test_fresh_environment = func() {
+    assert.equal(bump(), 1)
+}
+
+let test_fresh_environment_again = func() {
+    assert.equal(bump(), 1)
+}
+
+let helper = func() { assert.equal(1, 2) }
+
+test "fails in a helper" {
+    helper()
+}
+
+test "throws" {
+    assert.throws(func() { throw "boom" }, "boom")
+}
+`
+
+func writeTestFile(t *testing.T, dir, name, source string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunFile(t *testing.T) {
+	file := writeTestFile(t, t.TempDir(), "sample_test.vint", sampleTests)
+
+	results, err := RunFile(file, nil)
+	if err != nil {
+		t.Fatalf("RunFile failed: %v", err)
+	}
+
+	want := map[string]bool{
+		"test_fresh_environment":       true,
+		"test_fresh_environment_again": true,
+		"fails in a helper":            false,
+		"throws":                       true,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for _, r := range results {
+		passed, ok := want[r.Name]
+		if !ok {
+			t.Errorf("unexpected test %q", r.Name)
+			continue
+		}
+		if r.Passed() != passed {
+			t.Errorf("%s: expected passed=%t, got error %v", r.Name, passed, r.Err)
+		}
+	}
+
+	failure := formatFailure(results[2].Err)
+	if !strings.Contains(failure, "assert.equal failed") || !strings.Contains(failure, "at helper") {
+		t.Errorf("failure report is missing the message or call stack:\n%s", failure)
+	}
+}
+
+func TestRunFileFilter(t *testing.T) {
+	file := writeTestFile(t, t.TempDir(), "sample_test.vint", sampleTests)
+
+	results, err := RunFile(file, regexp.MustCompile("^throws$"))
+	if err != nil {
+		t.Fatalf("RunFile failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "throws" {
+		t.Fatalf("expected only the 'throws' test to run, got %+v", results)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a_test.vint", "")
+	writeTestFile(t, dir, "main.vint", "")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "sub"), "b_test.vint", "")
+
+	files, err := Discover([]string{dir})
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 test files, got %v", files)
+	}
+}
+
+func TestMainExitCode(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "ok_test.vint", `test "ok" { 1 }`)
+
+	var out bytes.Buffer
+	if code := Main([]string{dir}, &out); code != 0 {
+		t.Errorf("expected exit code 0, got %d\n%s", code, out.String())
+	}
+
+	writeTestFile(t, dir, "bad_test.vint", `test "bad" { 1 + true }`)
+	out.Reset()
+	if code := Main([]string{dir}, &out); code != 1 {
+		t.Errorf("expected exit code 1, got %d\n%s", code, out.String())
+	}
+}
+
+func TestMainCover(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "sign.vint", `package sign {
+    let of = func(n) {
+        if (n < 0) {
+            return -1
+        }
+        return 1
+    }
+}
+`)
+	writeTestFile(t, dir, "sign_test.vint", `import assert
+import sign
+test "positive" { assert.equal(sign.of(2), 1) }
+`)
+
+	report := filepath.Join(dir, "out.cov")
+	var out bytes.Buffer
+	if code := Main([]string{"--cover", report, dir}, &out); code != 0 {
+		t.Fatalf("expected exit code 0, got %d\n%s", code, out.String())
+	}
+	_, summary, _ := strings.Cut(out.String(), "Branches\n")
+	if !strings.Contains(summary, "sign.vint") || strings.Contains(summary, "sign_test.vint") {
+		t.Errorf("expected only sign.vint in the summary:\n%s", out.String())
+	}
+	lcov, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(lcov), "DA:4,0\nDA:6,1\nLF:5\nLH:4\n") {
+		t.Errorf("unexpected lcov:\n%s", lcov)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out.html")); err != nil {
+		t.Error(err)
+	}
+}
@@ -7,17 +7,21 @@ type Token struct {
 	Literal string
 	Line    int
 	Column  int
+	File    string // source file the token was read from
 }
 
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT" // only produced by lexer.NewWithComments
 
 	// Identifiers + literals
-	IDENT  = "IDENT"
-	INT    = "INT"
-	STRING = "STRING"
-	FLOAT  = "FLOAT"
+	IDENT    = "IDENT"
+	INT      = "INT"
+	BIGINT   = "BIGINT" // an integer with an n suffix: 123n
+	STRING   = "STRING"
+	TEMPLATE = "TEMPLATE" // "...${x}..." or `...`; the literal is the raw text between the delimiters
+	FLOAT    = "FLOAT"
 
 	// Operators
 	ASSIGN          = "="
@@ -38,6 +42,7 @@ const (
 	AND             = "&&"
 	OR              = "||"
 	NULL_COALESCE   = "??"
+	QUESTION_DOT    = "?."
 	PLUS_ASSIGN     = "+="
 	PLUS_PLUS       = "++"
 	MINUS_ASSIGN    = "-="
@@ -47,6 +52,17 @@ const (
 	MODULUS_ASSIGN  = "%="
 	SHEBANG         = "#!"
 
+	// Bitwise operators. | is PIPE and & is AMPERSAND.
+	CARET              = "^"
+	TILDE              = "~"
+	SHIFT_LEFT         = "<<"
+	SHIFT_RIGHT        = ">>"
+	AMPERSAND_ASSIGN   = "&="
+	PIPE_ASSIGN        = "|="
+	CARET_ASSIGN       = "^="
+	SHIFT_LEFT_ASSIGN  = "<<="
+	SHIFT_RIGHT_ASSIGN = ">>="
+
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
@@ -73,6 +89,7 @@ const (
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	YIELD    = "YIELD"
 	WHILE    = "WHILE"
 	NULL     = "NULL"
 	BREAK    = "BREAK"
@@ -103,13 +120,17 @@ const (
 	STRUCT   = "STRUCT"
 
 	// Async/Concurrency Keywords
-	ASYNC = "ASYNC"
-	AWAIT = "AWAIT"
-	GO    = "GO"
-	CHAN  = "CHAN"
+	ASYNC  = "ASYNC"
+	AWAIT  = "AWAIT"
+	GO     = "GO"
+	CHAN   = "CHAN"
+	SELECT = "SELECT"
 
 	// Error Handling Keywords
-	THROW = "THROW"
+	THROW   = "THROW"
+	TRY     = "TRY"
+	CATCH   = "CATCH"
+	FINALLY = "FINALLY"
 
 	// Capitalized Declaratives
 	INFO_CAP     = "INFO_CAP"
@@ -127,7 +148,7 @@ const (
 	// Type system tokens
 	AS   = "AS"   // type casting: x as int
 	IS   = "IS"   // type checking: x is int
-	PIPE = "|"    // union types: int | string
+	PIPE = "|"    // bitwise or, and union types: int | string
 )
 
 var keywords = map[string]TokenType{
@@ -140,6 +161,7 @@ var keywords = map[string]TokenType{
 	"else":     ELSE,
 	"while":    WHILE,
 	"return":   RETURN,
+	"yield":    YIELD,
 	"break":    BREAK,
 	"continue": CONTINUE,
 	"null":     NULL,
@@ -172,7 +194,11 @@ var keywords = map[string]TokenType{
 	"await":    AWAIT,
 	"go":       GO,
 	"chan":     CHAN,
+	"select":   SELECT,
 	"throw":    THROW,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
 	"Info":     INFO_CAP,
 	"Debug":    DEBUG_CAP,
 	"Note":     NOTE_CAP,
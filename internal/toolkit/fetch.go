@@ -0,0 +1,231 @@
+package toolkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fetch puts the files of the package at source into dest. A git source
+// is checked out at commit if it is set, or else at ref, or else at its
+// default branch; a directory is copied as it is.
+func fetch(source, ref, commit, dest string) (LockedPackage, error) {
+	pkg := LockedPackage{Source: source, Ref: ref}
+	isGit, err := isGitSource(source)
+	if err != nil {
+		return pkg, err
+	}
+	if isGit {
+		rev := commit
+		if rev == "" {
+			rev = ref
+		}
+		if pkg.Commit, err = fetchGit(source, rev, dest); err != nil {
+			return pkg, err
+		}
+	} else {
+		if ref != "" {
+			return pkg, fmt.Errorf("%s is a directory, not a git repository, so it has no version '%s'", source, ref)
+		}
+		if err := copyTree(source, dest); err != nil {
+			return pkg, err
+		}
+	}
+	pkg.Hash, err = hashTree(dest)
+	return pkg, err
+}
+
+// isRemote reports whether source is a URL rather than a local path. A
+// source that starts with '-' is neither, as git would take it for an
+// option.
+func isRemote(source string) bool {
+	if strings.HasPrefix(source, "-") {
+		return false
+	}
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
+
+// isGitSource reports whether source is a git repository, rather than a
+// plain directory. Local repositories can be bare or have a working tree.
+func isGitSource(source string) (bool, error) {
+	if isRemote(source) {
+		return true, nil
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return false, fmt.Errorf("package source %s does not exist", source)
+	}
+	if !info.IsDir() {
+		return false, fmt.Errorf("package source %s is not a directory", source)
+	}
+	if _, err := os.Stat(filepath.Join(source, ".git")); err == nil {
+		return true, nil
+	}
+	_, headErr := os.Stat(filepath.Join(source, "HEAD"))
+	objects, objectsErr := os.Stat(filepath.Join(source, "objects"))
+	return headErr == nil && objectsErr == nil && objects.IsDir(), nil
+}
+
+// fetchGit checks out rev of the repository at source into dest, without
+// its .git directory, and returns the commit it resolved to. rev can be a
+// tag, a branch or a commit; an empty rev is the default branch.
+func fetchGit(source, rev, dest string) (string, error) {
+	clone, err := os.MkdirTemp("", "vint-get-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(clone)
+
+	if _, err := git("clone", "--quiet", "--no-checkout", "--", source, clone); err != nil {
+		return "", err
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	// Only the default branch is a local branch in a fresh clone
+	commit, err := git("-C", clone, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		commit, err = git("-C", clone, "rev-parse", "--verify", "--quiet", "origin/"+rev+"^{commit}")
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s has no tag, branch or commit '%s'", source, rev)
+	}
+	if _, err := git("-C", clone, "-c", "advice.detachedHead=false", "checkout", "--quiet", commit); err != nil {
+		return "", err
+	}
+	return commit, copyTree(clone, dest)
+}
+
+func git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	// Fail instead of waiting for a password that will never come
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git: %s", msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// copyTree copies the regular files under src to dst, leaving out .git
+// directories.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir() && d.Name() == ".git":
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// hashTree returns a hash of the paths and contents of the files under
+// dir, so that any change to a package's files changes its hash.
+func hashTree(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		fmt.Fprintf(h, "%s %x\n", filepath.ToSlash(rel), sum)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// stage is a directory in the project that packages are fetched into,
+// so that the modules directory is only changed once every package has
+// been fetched.
+type stage struct {
+	project string
+	dir     string
+}
+
+func newStage(project string) (*stage, error) {
+	dir, err := os.MkdirTemp(project, ".vint-get-")
+	if err != nil {
+		return nil, err
+	}
+	return &stage{project: project, dir: dir}, nil
+}
+
+func (s *stage) path(name string) string { return filepath.Join(s.dir, name) }
+
+// commit moves the named packages into the modules directory, replacing
+// any earlier versions of them.
+func (s *stage) commit(names []string) error {
+	modules := filepath.Join(s.project, modulesDirName)
+	if err := os.MkdirAll(modules, 0o755); err != nil {
+		return err
+	}
+	for _, name := range names {
+		target := filepath.Join(modules, name)
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+		if err := os.Rename(s.path(name), target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stage) discard() { os.RemoveAll(s.dir) }
@@ -0,0 +1,549 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/config"
+	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/utils"
+)
+
+// A project's dependencies are listed in vintconfig.json, by the name they
+// are imported as, with where to get them from:
+//
+//	"dependencies": {
+//	    "strutil": "https://github.com/someone/strutil.git@v1.2.0",
+//	    "colors": "../shared/colors"
+//	}
+//
+// `vint get` fetches them, and the packages they depend on, into ./modules,
+// and records exactly what it fetched in vint.lock. `vint install` fetches
+// the same code again from vint.lock.
+
+const (
+	configFileName = "vintconfig.json"
+	lockFileName   = "vint.lock"
+	modulesDirName = "modules"
+)
+
+// Lock is the content of vint.lock: every package the project depends on,
+// directly or through other packages, pinned to the code that was fetched.
+type Lock struct {
+	Packages map[string]LockedPackage `json:"packages"`
+}
+
+// LockedPackage is one package in vint.lock.
+type LockedPackage struct {
+	Source string `json:"source"`           // a git URL, or the absolute path of a git repository or directory
+	Ref    string `json:"ref,omitempty"`    // the tag, branch or commit asked for
+	Commit string `json:"commit,omitempty"` // the commit it resolved to, for git sources
+	Hash   string `json:"hash"`             // of the package's files, see hashTree
+}
+
+// Get is `vint get`. It adds each package to the project's dependencies,
+// or changes the version of one already there, and fetches them all.
+// `vint get vintpm` installs the vintpm tool instead.
+func Get(args []string, out io.Writer) int {
+	if len(args) == 1 && args[0] == "vintpm" {
+		if err := InstallVintpm(); err != nil {
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return 1
+		}
+		return 0
+	}
+
+	var specs []string
+	alias := ""
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--as" && i+1 < len(args):
+			alias = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: unknown flag %s", arg)))
+			return 2
+		default:
+			specs = append(specs, arg)
+		}
+	}
+	if len(specs) == 0 || alias != "" && len(specs) > 1 {
+		fmt.Fprintln(out, styles.ErrorStyle.Render("Usage: vint get <git-url|path>[@version] [--as name]"))
+		return 2
+	}
+
+	if err := getPackages(".", specs, alias, out); err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	return 0
+}
+
+// Install is `vint install`. It fetches the packages in vint.lock, and
+// checks that their files are the ones that were locked. Without a
+// vint.lock, or if vintconfig.json has changed since it was written, the
+// dependencies are resolved again as `vint get` does. Packages that
+// nothing depends on any more are removed from both.
+func Install(args []string, out io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintln(out, styles.ErrorStyle.Render("Usage: vint install"))
+		return 2
+	}
+	if err := installPackages(".", out); err != nil {
+		fmt.Fprintln(out, styles.ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+	return 0
+}
+
+func getPackages(dir string, specs []string, alias string, out io.Writer) error {
+	cfg, err := readProjectConfig(dir)
+	exists := err == nil
+	if errors.Is(err, os.ErrNotExist) {
+		cfg = newProjectConfig(dir)
+	} else if err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		name := alias
+		if name == "" {
+			source, _ := splitSpec(spec)
+			name = packageName(source)
+		}
+		if !validPackageName.MatchString(name) {
+			return fmt.Errorf("'%s' is not a valid module name to import the package as; choose one with --as", name)
+		}
+		if cfg.Dependencies == nil {
+			cfg.Dependencies = map[string]string{}
+		}
+		cfg.Dependencies[name] = spec
+	}
+
+	old, err := loadLock(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	lock, err := resolve(dir, cfg.Dependencies, out)
+	if err != nil {
+		return err
+	}
+	if exists {
+		err = writeDependencies(dir, cfg.Dependencies)
+	} else {
+		err = writeJSON(filepath.Join(dir, configFileName), cfg)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, lockFileName), lock); err != nil {
+		return err
+	}
+	return prune(dir, old, lock, out)
+}
+
+func installPackages(dir string, out io.Writer) error {
+	cfg, err := readProjectConfig(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("there is no %s in this directory", configFileName)
+		}
+		return err
+	}
+
+	lock, err := loadLock(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if lock == nil || !lock.matches(dir, cfg.Dependencies) {
+		fmt.Fprintln(out, styles.HelpStyle.Render(lockFileName+" is missing or out of date, resolving dependencies"))
+		resolved, err := resolve(dir, cfg.Dependencies, out)
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(dir, lockFileName), resolved); err != nil {
+			return err
+		}
+		return prune(dir, lock, resolved, out)
+	}
+
+	stage, err := newStage(dir)
+	if err != nil {
+		return err
+	}
+	defer stage.discard()
+	for _, name := range utils.SortedKeys(lock.Packages) {
+		locked := lock.Packages[name]
+		fetched, err := fetch(locked.Source, locked.Ref, locked.Commit, stage.path(name))
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if fetched.Hash != locked.Hash {
+			return fmt.Errorf("the files of '%s' have changed since %s was written: it has %s, but they hash to %s; run vint get to update it",
+				name, lockFileName, locked.Hash, fetched.Hash)
+		}
+		report(out, name, fetched)
+	}
+
+	// A package removed from vintconfig.json is left in the lock until
+	// now, as are the packages only it depended on
+	used, err := lock.used(cfg.Dependencies, stage)
+	if err != nil {
+		return err
+	}
+	if err := stage.commit(utils.SortedKeys(used.Packages)); err != nil {
+		return err
+	}
+	if len(used.Packages) == len(lock.Packages) {
+		return nil
+	}
+	if err := writeJSON(filepath.Join(dir, lockFileName), used); err != nil {
+		return err
+	}
+	return prune(dir, lock, used, out)
+}
+
+// loadLock reads the project's vint.lock. The error wraps os.ErrNotExist
+// if there is none.
+func loadLock(dir string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, lockFileName))
+	if err != nil {
+		return nil, err
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%s: %v", lockFileName, err)
+	}
+	return &lock, nil
+}
+
+// matches reports whether every one of these dependencies is locked as it
+// is asked for. The lock can hold more packages than they need, which
+// used leaves out.
+func (l *Lock) matches(dir string, dependencies map[string]string) bool {
+	for name, spec := range dependencies {
+		locked, ok := l.Packages[name]
+		if !ok {
+			return false
+		}
+		source, ref, err := parseSpec(spec, dir)
+		if err != nil || source != locked.Source || ref != locked.Ref {
+			return false
+		}
+	}
+	return true
+}
+
+// used returns the part of the lock that the dependencies need, directly
+// or through the packages they depend on, whose files are in stage.
+func (l *Lock) used(dependencies map[string]string, stage *stage) (*Lock, error) {
+	used := &Lock{Packages: map[string]LockedPackage{}}
+	queue := utils.SortedKeys(dependencies)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		locked, ok := l.Packages[name]
+		if _, seen := used.Packages[name]; seen || !ok {
+			continue
+		}
+		used.Packages[name] = locked
+
+		cfg, err := readProjectConfig(stage.path(name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if cfg != nil {
+			queue = append(queue, utils.SortedKeys(cfg.Dependencies)...)
+		}
+	}
+	return used, nil
+}
+
+// prune removes the packages of old that are not in lock from the modules
+// directory. old is nil if there was no lock before.
+func prune(dir string, old, lock *Lock, out io.Writer) error {
+	if old == nil {
+		return nil
+	}
+	for _, name := range utils.SortedKeys(old.Packages) {
+		if _, ok := lock.Packages[name]; ok {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, modulesDirName, name)); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, styles.HelpStyle.Render(fmt.Sprintf("Removed %s, which nothing depends on any more", name)))
+	}
+	return nil
+}
+
+// request is a package that the project, or a package it depends on,
+// asks for.
+type request struct {
+	name   string
+	spec   string
+	base   string // the directory relative paths in spec are relative to, "" if they are not allowed
+	source string
+	ref    string
+	from   string // who asks for it: vintconfig.json, or the name of a package
+}
+
+// resolve fetches the dependencies, and the dependencies of those, into
+// the project's modules directory. The same package may be asked for more
+// than once, but only ever with the same source and version; anything
+// else is a conflict, and nothing is changed in the modules directory.
+func resolve(dir string, dependencies map[string]string, out io.Writer) (*Lock, error) {
+	stage, err := newStage(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer stage.discard()
+
+	lock := &Lock{Packages: map[string]LockedPackage{}}
+	wanted := map[string]request{}
+	var queue []request
+	for _, name := range utils.SortedKeys(dependencies) {
+		queue = append(queue, request{name: name, spec: dependencies[name], base: dir, from: configFileName})
+	}
+
+	var conflicts []string
+	for len(queue) > 0 {
+		r := queue[0]
+		queue = queue[1:]
+
+		source, ref, err := parseSpec(r.spec, r.base)
+		if err != nil {
+			return nil, fmt.Errorf("%s: dependency '%s': %v", r.from, r.name, err)
+		}
+		r.source, r.ref = source, ref
+		if first, ok := wanted[r.name]; ok {
+			if first.source != r.source || first.ref != r.ref {
+				conflicts = append(conflicts, fmt.Sprintf("version conflict for '%s': %s wants %s, but %s wants %s",
+					r.name, first.from, describe(first.source, first.ref), r.from, describe(r.source, r.ref)))
+			}
+			continue
+		}
+		wanted[r.name] = r
+
+		fetched, err := fetch(r.source, r.ref, "", stage.path(r.name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", r.name, err)
+		}
+		lock.Packages[r.name] = fetched
+		report(out, r.name, fetched)
+
+		// Relative paths in a package's own dependencies are relative to
+		// where it came from, which only means something on this machine.
+		base := ""
+		if !isRemote(r.source) {
+			base = r.source
+		}
+		cfg, err := readProjectConfig(stage.path(r.name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %v", r.name, err)
+		}
+		if cfg != nil {
+			for _, name := range utils.SortedKeys(cfg.Dependencies) {
+				queue = append(queue, request{name: name, spec: cfg.Dependencies[name], base: base, from: r.name})
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, errors.New(strings.Join(conflicts, "\n"))
+	}
+	return lock, stage.commit(utils.SortedKeys(lock.Packages))
+}
+
+func report(out io.Writer, name string, p LockedPackage) {
+	version := p.Ref
+	if p.Commit != "" {
+		if version == "" {
+			version = p.Commit[:7]
+		} else {
+			version += " (" + p.Commit[:7] + ")"
+		}
+	}
+	if version != "" {
+		version = " " + version
+	}
+	fmt.Fprintln(out, styles.HelpStyle.Render(fmt.Sprintf("Fetched %s%s from %s", name, version, p.Source)))
+}
+
+// describe writes a source and version the way they are written in a
+// dependency.
+func describe(source, ref string) string {
+	if ref == "" {
+		return source
+	}
+	return source + "@" + ref
+}
+
+// splitSpec splits a dependency into its source and the version after
+// the last @, if there is one. The @ of git@github.com:... is part of the
+// source.
+func splitSpec(spec string) (source, ref string) {
+	at := strings.LastIndex(spec, "@")
+	if at <= 0 || at < strings.LastIndexAny(spec, `/\:`) {
+		return spec, ""
+	}
+	return spec[:at], spec[at+1:]
+}
+
+// parseSpec returns the source and version of a dependency. Local paths
+// are made absolute, relative to base.
+func parseSpec(spec, base string) (source, ref string, err error) {
+	source, ref = splitSpec(spec)
+	if source == "" {
+		return "", "", fmt.Errorf("no source in '%s'", spec)
+	}
+	// git would take either as one of its options
+	if strings.HasPrefix(source, "-") || strings.HasPrefix(ref, "-") {
+		return "", "", fmt.Errorf("'%s' is not a package source: it starts with '-'", spec)
+	}
+	if isRemote(source) {
+		return source, ref, nil
+	}
+	if !filepath.IsAbs(source) {
+		if base == "" {
+			return "", "", fmt.Errorf("'%s' is a relative path in a package fetched from a remote repository", source)
+		}
+		source = filepath.Join(base, source)
+	}
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return "", "", err
+	}
+	return abs, ref, nil
+}
+
+var validPackageName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// packageName is the name a package from source is imported as when none
+// is given: the last element of its path, without .git.
+func packageName(source string) string {
+	source = strings.TrimRight(filepath.ToSlash(source), "/")
+	if i := strings.LastIndexAny(source, "/:"); i >= 0 {
+		source = source[i+1:]
+	}
+	return strings.TrimSuffix(source, ".git")
+}
+
+func readProjectConfig(dir string) (*VintConfig, error) {
+	data, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if err != nil {
+		return nil, err
+	}
+	var cfg VintConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", filepath.Join(dir, configFileName), err)
+	}
+	return &cfg, nil
+}
+
+// FindConfig reads the vintconfig.json of the project dir belongs to,
+// looking in dir and then in each directory above it. The config is nil
+// if there is none; otherwise its path is returned with it.
+func FindConfig(dir string) (*VintConfig, string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		path := filepath.Join(dir, configFileName)
+		if _, err := os.Stat(path); err == nil {
+			cfg, err := readProjectConfig(dir)
+			return cfg, path, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, "", nil
+		}
+		dir = parent
+	}
+}
+
+// newProjectConfig is the vintconfig.json written when `vint get` is run
+// in a directory without one.
+func newProjectConfig(dir string) *VintConfig {
+	name := "vint-project"
+	if abs, err := filepath.Abs(dir); err == nil {
+		name = filepath.Base(abs)
+	}
+	return &VintConfig{Name: name, Version: "1.0.0", VintVersion: config.VINT_VERSION}
+}
+
+// writeDependencies replaces the dependencies in the project's
+// vintconfig.json. The file is not decoded into a VintConfig and written
+// back, which would drop the keys vint does not know about, such as
+// "author" or "scripts"; every other key is kept as written, in its order.
+func writeDependencies(dir string, dependencies map[string]string) error {
+	path := filepath.Join(dir, configFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	deps, err := json.Marshal(dependencies)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("%s: not a JSON object", path)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	replaced := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		key := tok.(string)
+		if key == "dependencies" {
+			value, replaced = deps, true
+		}
+		writeMember(&buf, key, value)
+	}
+	if !replaced {
+		writeMember(&buf, "dependencies", deps)
+	}
+	buf.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	return os.WriteFile(path, out.Bytes(), 0o644)
+}
+
+// writeMember appends "key": value to a JSON object being written.
+func writeMember(buf *bytes.Buffer, key string, value json.RawMessage) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	name, _ := json.Marshal(key)
+	buf.Write(name)
+	buf.WriteByte(':')
+	buf.Write(value)
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,371 @@
+package toolkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readLock(t *testing.T, dir string) Lock {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, lockFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		t.Fatal(err)
+	}
+	return lock
+}
+
+func readModule(t *testing.T, project, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(project, modulesDirName, name, name+".vint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// gitRepo makes a bare repository with a commit tagged for each version,
+// each writing its version into <name>.vint.
+func gitRepo(t *testing.T, name string, versions ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	work := t.TempDir()
+	bare := filepath.Join(t.TempDir(), name+".git")
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git(append([]string{"-C", work, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "--quiet")
+	for _, version := range versions {
+		writeFiles(t, work, map[string]string{name + ".vint": "// " + version + "\n"})
+		run("add", "-A")
+		run("commit", "--quiet", "-m", version)
+		run("tag", version)
+	}
+	if _, err := git("clone", "--quiet", "--bare", work, bare); err != nil {
+		t.Fatal(err)
+	}
+	return bare
+}
+
+func TestSplitSpec(t *testing.T) {
+	tests := []struct{ spec, source, ref string }{
+		{"../colors", "../colors", ""},
+		{"../colors.git@v1.0.0", "../colors.git", "v1.0.0"},
+		{"https://example.com/x/colors.git@main", "https://example.com/x/colors.git", "main"},
+		{"git@example.com:x/colors.git", "git@example.com:x/colors.git", ""},
+		{"git@example.com:x/colors.git@v2", "git@example.com:x/colors.git", "v2"},
+		{"/home/me@work/colors", "/home/me@work/colors", ""},
+	}
+	for _, tt := range tests {
+		source, ref := splitSpec(tt.spec)
+		if source != tt.source || ref != tt.ref {
+			t.Errorf("splitSpec(%q) = %q, %q, want %q, %q", tt.spec, source, ref, tt.source, tt.ref)
+		}
+	}
+}
+
+func TestParseSpecRejectsOptions(t *testing.T) {
+	for _, spec := range []string{"--upload-pack=touch /tmp/pwned ://x", "-c://x", "https://example.com/x/colors.git@--detach"} {
+		if _, _, err := parseSpec(spec, t.TempDir()); err == nil || !strings.Contains(err.Error(), "starts with '-'") {
+			t.Errorf("parseSpec(%q): %v", spec, err)
+		}
+	}
+	if isRemote("--upload-pack=x ://x") {
+		t.Error("a source that starts with '-' is remote")
+	}
+}
+
+func TestGetPathDependencies(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	writeFiles(t, root, map[string]string{
+		"lib/colors/colors.vint":      "// colors\n",
+		"lib/strutil/strutil.vint":    "import colors\n",
+		"lib/strutil/vintconfig.json": `{"name": "strutil", "dependencies": {"colors": "../colors"}}`,
+	})
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := getPackages(project, []string{"../lib/strutil"}, "", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := readModule(t, project, "colors"); got != "// colors\n" {
+		t.Errorf("modules/colors/colors.vint = %q", got)
+	}
+
+	cfg, err := readProjectConfig(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Dependencies["strutil"] != "../lib/strutil" || len(cfg.Dependencies) != 1 {
+		t.Errorf("dependencies = %v", cfg.Dependencies)
+	}
+	lock := readLock(t, project)
+	if len(lock.Packages) != 2 {
+		t.Fatalf("locked packages = %v", lock.Packages)
+	}
+	if want := filepath.Join(root, "lib", "colors"); lock.Packages["colors"].Source != want {
+		t.Errorf("colors source = %q, want %q", lock.Packages["colors"].Source, want)
+	}
+	if !strings.HasPrefix(lock.Packages["strutil"].Hash, "sha256:") {
+		t.Errorf("strutil hash = %q", lock.Packages["strutil"].Hash)
+	}
+
+	entries, _ := os.ReadDir(project)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".vint-get-") {
+			t.Errorf("staging directory %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestGetGitVersions(t *testing.T) {
+	repo := gitRepo(t, "colors", "v1.0.0", "v2.0.0")
+	project := t.TempDir()
+	var out bytes.Buffer
+
+	if err := getPackages(project, []string{repo + "@v1.0.0"}, "", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := readModule(t, project, "colors"); got != "// v1.0.0\n" {
+		t.Errorf("at v1.0.0, colors.vint = %q", got)
+	}
+	v1 := readLock(t, project).Packages["colors"]
+	if v1.Ref != "v1.0.0" || len(v1.Commit) != 40 {
+		t.Errorf("locked %+v", v1)
+	}
+
+	if err := getPackages(project, []string{repo}, "", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := readModule(t, project, "colors"); got != "// v2.0.0\n" {
+		t.Errorf("at HEAD, colors.vint = %q", got)
+	}
+	if head := readLock(t, project).Packages["colors"]; head.Commit == v1.Commit || head.Hash == v1.Hash {
+		t.Errorf("HEAD locked as %+v, the same as v1.0.0", head)
+	}
+
+	if err := getPackages(project, []string{repo + "@v3.0.0"}, "", &out); err == nil || !strings.Contains(err.Error(), "no tag, branch or commit 'v3.0.0'") {
+		t.Errorf("getting a missing version: %v", err)
+	}
+	if got := readModule(t, project, "colors"); got != "// v2.0.0\n" {
+		t.Errorf("a failed get changed colors.vint to %q", got)
+	}
+
+	if err := getPackages(project, []string{repo}, "1colors", &out); err == nil || !strings.Contains(err.Error(), "--as") {
+		t.Errorf("getting with an invalid name: %v", err)
+	}
+}
+
+func TestInstallFromLock(t *testing.T) {
+	repo := gitRepo(t, "colors", "v1.0.0")
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	writeFiles(t, root, map[string]string{"lib/strutil/strutil.vint": "// strutil\n"})
+	writeFiles(t, project, map[string]string{
+		configFileName: `{"name": "app", "dependencies": {"colors": "` + filepath.ToSlash(repo) + `", "strutil": "../lib/strutil"}}`,
+	})
+	var out bytes.Buffer
+
+	// Without a lock, install resolves the dependencies and writes one
+	if err := installPackages(project, &out); err != nil {
+		t.Fatal(err)
+	}
+	locked := readLock(t, project).Packages["colors"]
+
+	// A new commit to the repository is not installed: the lock pins the
+	// commit that was fetched
+	work := t.TempDir()
+	if _, err := git("clone", "--quiet", repo, work); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, work, map[string]string{"colors.vint": "// changed\n"})
+	for _, args := range [][]string{{"commit", "--quiet", "-am", "change"}, {"push", "--quiet"}} {
+		if _, err := git(append([]string{"-C", work, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(project, modulesDirName)); err != nil {
+		t.Fatal(err)
+	}
+	if err := installPackages(project, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := readModule(t, project, "colors"); got != "// v1.0.0\n" {
+		t.Errorf("installed colors.vint = %q", got)
+	}
+	if got := readLock(t, project).Packages["colors"]; got != locked {
+		t.Errorf("install changed the lock from %+v to %+v", locked, got)
+	}
+
+	// A path dependency cannot be pinned, but a change to it is noticed
+	writeFiles(t, root, map[string]string{"lib/strutil/strutil.vint": "// tampered\n"})
+	err := installPackages(project, &out)
+	if err == nil || !strings.Contains(err.Error(), "the files of 'strutil' have changed") {
+		t.Fatalf("installing a changed package: %v", err)
+	}
+	if got := readModule(t, project, "strutil"); got != "// strutil\n" {
+		t.Errorf("a failed install changed strutil.vint to %q", got)
+	}
+}
+
+func TestInstallRemovesUnusedPackages(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	writeFiles(t, root, map[string]string{
+		"lib/colors/colors.vint":      "// colors\n",
+		"lib/strutil/strutil.vint":    "import colors\n",
+		"lib/strutil/vintconfig.json": `{"name": "strutil", "dependencies": {"colors": "../colors"}}`,
+		"lib/other/other.vint":        "// other\n",
+	})
+	writeFiles(t, project, map[string]string{
+		configFileName: `{"name": "app", "dependencies": {"strutil": "../lib/strutil", "other": "../lib/other"}}`,
+	})
+	var out bytes.Buffer
+	if err := installPackages(project, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(readLock(t, project).Packages); got != 3 {
+		t.Fatalf("locked %d packages, want 3", got)
+	}
+
+	// strutil is removed from vintconfig.json, and colors, which only it
+	// needed, goes with it
+	writeFiles(t, project, map[string]string{
+		configFileName: `{"name": "app", "dependencies": {"other": "../lib/other"}}`,
+	})
+	out.Reset()
+	if err := installPackages(project, &out); err != nil {
+		t.Fatal(err)
+	}
+	lock := readLock(t, project)
+	if _, ok := lock.Packages["other"]; !ok || len(lock.Packages) != 1 {
+		t.Errorf("locked packages = %v", lock.Packages)
+	}
+	for _, name := range []string{"strutil", "colors"} {
+		if _, err := os.Stat(filepath.Join(project, modulesDirName, name)); !os.IsNotExist(err) {
+			t.Errorf("modules/%s was not removed", name)
+		}
+		if !strings.Contains(out.String(), "Removed "+name) {
+			t.Errorf("removing %s was not reported:\n%s", name, out.String())
+		}
+	}
+	if got := readModule(t, project, "other"); got != "// other\n" {
+		t.Errorf("modules/other/other.vint = %q", got)
+	}
+
+	// vint get removes them too
+	writeFiles(t, project, map[string]string{
+		configFileName: `{"name": "app", "dependencies": {}}`,
+	})
+	if err := getPackages(project, []string{"../lib/colors"}, "", &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(project, modulesDirName, "other")); !os.IsNotExist(err) {
+		t.Errorf("modules/other was not removed")
+	}
+	if lock := readLock(t, project); len(lock.Packages) != 1 {
+		t.Errorf("locked packages = %v", lock.Packages)
+	}
+}
+
+func TestGetKeepsConfigKeys(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	writeFiles(t, root, map[string]string{"lib/strutil/strutil.vint": "// strutil\n"})
+	writeFiles(t, project, map[string]string{
+		configFileName: `{"name": "app", "author": {"name": "Ada"}, "dependencies": {}, "scripts": {"test": "vint test"}}`,
+	})
+
+	var out bytes.Buffer
+	if err := getPackages(project, []string{"../lib/strutil"}, "", &out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(project, configFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "name": "app",
+  "author": {
+    "name": "Ada"
+  },
+  "dependencies": {
+    "strutil": "../lib/strutil"
+  },
+  "scripts": {
+    "test": "vint test"
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("vintconfig.json is\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestInstallWithoutConfig(t *testing.T) {
+	var out bytes.Buffer
+	if err := installPackages(t.TempDir(), &out); err == nil || !strings.Contains(err.Error(), "no vintconfig.json") {
+		t.Errorf("installing without a vintconfig.json: %v", err)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	repo := gitRepo(t, "colors", "v1.0.0", "v2.0.0")
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	writeFiles(t, root, map[string]string{
+		"lib/strutil/strutil.vint":    "import colors\n",
+		"lib/strutil/vintconfig.json": `{"name": "strutil", "dependencies": {"colors": "` + filepath.ToSlash(repo) + `@v1.0.0"}}`,
+	})
+	writeFiles(t, project, map[string]string{
+		configFileName: `{"name": "app", "dependencies": {"colors": "` + filepath.ToSlash(repo) + `@v2.0.0"}}`,
+	})
+
+	var out bytes.Buffer
+	err := getPackages(project, []string{"../lib/strutil"}, "", &out)
+	if err == nil {
+		t.Fatal("expected a version conflict")
+	}
+	want := "version conflict for 'colors': vintconfig.json wants " + repo + "@v2.0.0, but strutil wants " + repo + "@v1.0.0"
+	if err.Error() != want {
+		t.Errorf("got error\n%s\nwant\n%s", err, want)
+	}
+	if _, err := os.Stat(filepath.Join(project, modulesDirName)); !os.IsNotExist(err) {
+		t.Errorf("modules were installed despite the conflict")
+	}
+	if _, err := os.Stat(filepath.Join(project, lockFileName)); !os.IsNotExist(err) {
+		t.Errorf("%s was written despite the conflict", lockFileName)
+	}
+	if cfg, _ := readProjectConfig(project); len(cfg.Dependencies) != 1 {
+		t.Errorf("vintconfig.json was changed despite the conflict: %v", cfg.Dependencies)
+	}
+}
@@ -151,36 +151,31 @@ func installBinary(binaryName, platform string) error {
 	return nil
 }
 
-func InstallVintpm() {
+func InstallVintpm() error {
 	platform := detectPlatform()
 	if platform == "unsupported" {
-		fmt.Println("Unsupported platform. Exiting.")
-		return
+		return fmt.Errorf("unsupported platform")
 	}
 
 	binaryName := getBinaryName(platform)
 	if binaryName == "" {
-		fmt.Println("No binary name mapping found for platform. Exiting.")
-		return
+		return fmt.Errorf("no binary name mapping found for platform %s", platform)
 	}
 
 	fmt.Println("Fetching the latest release information...")
 	assetURL, err := fetchLatestReleaseURL(binaryName)
 	if err != nil {
-		fmt.Printf("❌ Error fetching release: %v\n", err)
-		return
+		return fmt.Errorf("fetching release: %v", err)
 	}
 
 	fmt.Println("Downloading the latest release...")
 	if err := downloadFile(assetURL, binaryName); err != nil {
-		fmt.Printf("❌ Error downloading binary: %v\n", err)
-		return
+		return fmt.Errorf("downloading binary: %v", err)
 	}
 
 	fmt.Println("Installing vintpm...")
 	if err := installBinary(binaryName, platform); err != nil {
-		fmt.Printf("❌ Error installing binary: %v\n", err)
-		return
+		return fmt.Errorf("installing binary: %v", err)
 	}
 
 	fmt.Println("Cleaning up...")
@@ -189,13 +184,7 @@ func InstallVintpm() {
 	}
 
 	fmt.Println("Installation complete!")
-}
-
-func Get(pkg string) {
-	switch pkg {
-	case "vintpm":
-		InstallVintpm()
-	}
+	return nil
 }
 
 type VintConfig struct {
@@ -203,6 +192,14 @@ type VintConfig struct {
 	Version     string `json:"version"`
 	VintVersion string `json:"vint,omitempty"`
 	Description string `json:"description"`
+
+	// Dependencies maps the name each package is imported as to where it
+	// comes from, see packages.go.
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+
+	// Lint sets how `vint lint` reports each rule, by name: "error",
+	// "warning" or "off".
+	Lint map[string]string `json:"lint,omitempty"`
 }
 
 const sampleReadme = `# VintLang Starter
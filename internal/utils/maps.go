@@ -0,0 +1,15 @@
+package utils
+
+import "sort"
+
+// SortedKeys returns the keys of a map in sorted order. Go maps have no
+// order, so anything built from one walks it this way to come out the same
+// on every run.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -28,6 +28,8 @@ const (
 	E400_INDEX_OUT_BOUNDS ErrorCode = "E400"
 	E401_INVALID_ARG      ErrorCode = "E401"
 	E402_NULL_REFERENCE   ErrorCode = "E402"
+	E403_RUNTIME_ERROR    ErrorCode = "E403"
+	E404_UNCAUGHT_THROW   ErrorCode = "E404"
 )
 
 // Severity levels
@@ -44,22 +46,46 @@ type VintError struct {
 	Code     ErrorCode
 	Severity Severity
 	Message  string
+	File     string
 	Line     int
 	Column   int
 	Source   string
 	Context  string
 	Suggestion string
+	Stack    []Frame
+}
+
+// Frame is one entry of a runtime traceback
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	Column   int
+	Context  string // the source line the frame had reached
 }
 
 // Error implements the error interface
 func (e *VintError) Error() string {
 	var builder strings.Builder
+
+	// Write the traceback, outermost call first
+	if len(e.Stack) > 0 {
+		builder.WriteString("Traceback (most recent call last):\n")
+		for _, frame := range e.Stack {
+			builder.WriteString(fmt.Sprintf("  %s, in %s\n", formatPosition(frame.File, frame.Line, frame.Column), frame.Function))
+			if context := strings.TrimSpace(frame.Context); context != "" {
+				builder.WriteString(fmt.Sprintf("    %s\n", context))
+			}
+		}
+	}
 	
 	// Write severity and code
 	builder.WriteString(fmt.Sprintf("[%s %s] ", e.Severity, e.Code))
 	
 	// Write position
-	if e.Line > 0 {
+	if e.Line > 0 && e.File != "" {
+		builder.WriteString(formatPosition(e.File, e.Line, e.Column) + ": ")
+	} else if e.Line > 0 {
 		if e.Column > 0 {
 			builder.WriteString(fmt.Sprintf("Line %d:%d: ", e.Line, e.Column))
 		} else {
@@ -86,6 +112,17 @@ func (e *VintError) Error() string {
 	return builder.String()
 }
 
+// formatPosition renders a position as file:line:column
+func formatPosition(file string, line, column int) string {
+	if file == "" {
+		file = "<input>"
+	}
+	if column > 0 {
+		return fmt.Sprintf("%s:%d:%d", file, line, column)
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
 // NewError creates a new VintError
 func NewError(code ErrorCode, severity Severity, message string, line, column int) *VintError {
 	return &VintError{
@@ -107,4 +144,28 @@ func (e *VintError) WithContext(context string) *VintError {
 func (e *VintError) WithSuggestion(suggestion string) *VintError {
 	e.Suggestion = suggestion
 	return e
-}
\ No newline at end of file
+}
+
+// WithFile sets the source file the error points into
+func (e *VintError) WithFile(file string) *VintError {
+	e.File = file
+	return e
+}
+
+// WithStack attaches a runtime traceback, outermost call first
+func (e *VintError) WithStack(frames []Frame) *VintError {
+	e.Stack = frames
+	return e
+}
+
+// SourceLine returns the given 1-based line of source, or "" if it is out of range
+func SourceLine(source string, line int) string {
+	if line <= 0 {
+		return ""
+	}
+	lines := strings.Split(source, "\n")
+	if line > len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[line-1], "\r")
+}
@@ -0,0 +1,198 @@
+package vm
+
+import (
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+func (vm *VM) executeCall(numArgs int) error {
+	callee := vm.stack[vm.sp-1-numArgs]
+	if cl, ok := callee.(*object.Closure); ok {
+		return vm.callClosure(cl, numArgs)
+	}
+
+	// Builtins and other callables run through the evaluator
+	args := make([]object.VintObject, numArgs)
+	copy(args, vm.stack[vm.sp-numArgs:vm.sp])
+	vm.sp = vm.sp - numArgs - 1
+
+	return vm.pushResult(evaluator.ApplyFunction(callee, args, vm.line()))
+}
+
+// callClosure starts executing cl with its arguments on top of the stack.
+// Like the evaluator, extra arguments are ignored; parameters without an
+// argument are left unset so the function's prologue can fill in defaults.
+func (vm *VM) callClosure(cl *object.Closure, numArgs int) error {
+	fn := cl.Fn
+	if numArgs > fn.NumParameters {
+		vm.sp -= numArgs - fn.NumParameters
+		numArgs = fn.NumParameters
+	}
+	if numArgs < fn.NumRequired {
+		return &Error{Object: &object.Error{Message: "Missing argument"}}
+	}
+
+	basePointer := vm.sp - numArgs
+	if err := vm.reserve(basePointer + fn.NumLocals); err != nil {
+		return err
+	}
+	for i := basePointer + numArgs; i < basePointer+fn.NumLocals; i++ {
+		vm.stack[i] = nil
+	}
+
+	if err := vm.pushFrame(NewFrame(cl, basePointer)); err != nil {
+		return err
+	}
+	vm.sp = basePointer + fn.NumLocals
+	return nil
+}
+
+// executeKeywordCall maps positional and keyword arguments onto the
+// parameters of the callee, following the same rules as the evaluator.
+// The keywords are on the stack as name/value pairs after the positional
+// arguments.
+func (vm *VM) executeKeywordCall(numPositional, numKeywords int) error {
+	base := vm.sp - numPositional - 2*numKeywords
+	cl, ok := vm.stack[base-1].(*object.Closure)
+	if !ok {
+		return vm.newError("keyword arguments are not supported when calling %s", vm.stack[base-1].Type())
+	}
+	fn := cl.Fn
+
+	keywords := make(map[string]object.VintObject, numKeywords)
+	for i := base + numPositional; i < vm.sp; i += 2 {
+		keywords[vm.stack[i].(*object.String).Value] = vm.stack[i+1]
+	}
+
+	args := make([]object.VintObject, fn.NumParameters)
+	for i, name := range fn.Parameters {
+		if i < numPositional {
+			args[i] = vm.stack[base+i]
+			continue
+		}
+		if value, ok := keywords[name]; ok {
+			args[i] = value
+			delete(keywords, name)
+			continue
+		}
+		if i < fn.NumRequired {
+			return &Error{Object: &object.Error{Message: "Missing argument"}}
+		}
+	}
+
+	for name := range keywords {
+		for _, param := range fn.Parameters {
+			if param == name {
+				return &Error{Object: &object.Error{Message: "Multiple arguments for a single parameter"}}
+			}
+		}
+		return &Error{Object: &object.Error{Message: "Unknown keyword argument: " + name}}
+	}
+
+	if err := vm.reserve(base + len(args)); err != nil {
+		return err
+	}
+	copy(vm.stack[base:], args)
+	vm.sp = base + len(args)
+	return vm.callClosure(cl, len(args))
+}
+
+func (vm *VM) executeMethodCall(name string, numArgs int) error {
+	receiver := vm.stack[vm.sp-1-numArgs]
+	args := make([]object.VintObject, numArgs)
+	copy(args, vm.stack[vm.sp-numArgs:vm.sp])
+	vm.sp = vm.sp - numArgs - 1
+
+	// Array callbacks defined in the script have to run on the VM
+	if array, ok := receiver.(*object.Array); ok && numArgs == 1 {
+		if cl, ok := args[0].(*object.Closure); ok {
+			switch name {
+			case "map":
+				return vm.mapArray(array, cl)
+			case "filter":
+				return vm.filterArray(array, cl)
+			}
+		}
+	}
+
+	return vm.pushResult(evaluator.MethodCall(receiver, name, args, vm.line()))
+}
+
+func (vm *VM) mapArray(array *object.Array, fn *object.Closure) error {
+	elements := make([]object.VintObject, 0, len(array.Elements))
+	for _, el := range array.Elements {
+		result, err := vm.invoke(fn, []object.VintObject{el})
+		if err != nil {
+			return err
+		}
+		elements = append(elements, result)
+	}
+	return vm.push(&object.Array{Elements: elements})
+}
+
+func (vm *VM) filterArray(array *object.Array, fn *object.Closure) error {
+	elements := []object.VintObject{}
+	for _, el := range array.Elements {
+		result, err := vm.invoke(fn, []object.VintObject{el})
+		if err != nil {
+			return err
+		}
+		if result.Inspect() == "true" {
+			elements = append(elements, el)
+		}
+	}
+	return vm.push(&object.Array{Elements: elements})
+}
+
+// invoke calls fn from Go code and runs it to completion.
+func (vm *VM) invoke(fn *object.Closure, args []object.VintObject) (object.VintObject, error) {
+	depth := vm.framesIndex
+	if err := vm.push(fn); err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		if err := vm.push(arg); err != nil {
+			return nil, err
+		}
+	}
+	if err := vm.callClosure(fn, len(args)); err != nil {
+		return nil, err
+	}
+	if err := vm.run(depth); err != nil {
+		return nil, err
+	}
+	return vm.pop(), nil
+}
+
+func (vm *VM) pushClosure(constIndex, numFree int) error {
+	fn, ok := vm.constants[constIndex].(*object.CompiledFunction)
+	if !ok {
+		return vm.newError("not a function: %+v", vm.constants[constIndex])
+	}
+
+	free := make([]*object.Cell, numFree)
+	for i := 0; i < numFree; i++ {
+		free[i] = vm.stack[vm.sp-numFree+i].(*object.Cell)
+	}
+	vm.sp = vm.sp - numFree
+
+	return vm.push(&object.Closure{Fn: fn, Free: free})
+}
+
+// reserve grows the stack so that it holds at least size values.
+func (vm *VM) reserve(size int) error {
+	if size <= len(vm.stack) {
+		return nil
+	}
+	if size > MaxStackSize {
+		return &Error{Object: &object.Error{Message: "stack overflow"}}
+	}
+	newSize := len(vm.stack) * 2
+	for newSize < size {
+		newSize *= 2
+	}
+	grown := make([]object.VintObject, newSize)
+	copy(grown, vm.stack)
+	vm.stack = grown
+	return nil
+}
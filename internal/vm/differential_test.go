@@ -0,0 +1,98 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/vintlang/vintlang/internal/compiler"
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+// The programs below run on both the tree-walking evaluator and the VM,
+// which must agree on the result.
+var differentialPrograms = []string{
+	`1 + 2 * 3 - 4 / 2`,
+	`7 / 2`,
+	`10 % 4 + 2 ** 3`,
+	`[0xF0 | 0b1010, 0o777 & 0xFF, 6 ^ 3, 1 << 10, -16 >> 2, ~5, 1 + 2 & 3]`,
+	`let m = 1; m <<= 4; m |= 3; m &= ~1; m ^= 0x10; m`,
+	"let name = \"vint\"; let xs = [1, 2]; `${name}: ${len(xs)} items, ${xs}, ${3.14159:.2f} ${\"[${name:-6}]\"}`",
+	`1.5 * 2 + 0.25`,
+	`-5 + -(-2)`,
+	`!true == false`,
+	`3 >= 3 && 2 <= 1 || 4 != 5`,
+	`"vint" + "lang"`,
+	`"lang" in "vintlang"`,
+	`null ?? "fallback"`,
+	`[1, "two", 3.0, true, null]`,
+	`{"name": "vint", "tags": ["a", "b"], "nested": {"x": 1}}`,
+	`let a = [1, 2, 3]; a[1] = 20; a[2] += 10; a`,
+	`let d = {"a": 1}; d["b"] = d["a"] + 1; d`,
+	`"hello world".split(" ")`,
+	`[3, 1, 2].sort()`,
+	`let x = 10; if (x > 5) { "big" } else { "small" }`,
+	`let x = 3; if (x > 5) { "big" } else if (x > 2) { "medium" } else { "small" }`,
+	`if (false) { 1 }`,
+	`let total = 0; let i = 0; while (i < 100) { total += i; i++ }; total`,
+	`let evens = []; for n in range(0, 10) { if (n % 2 == 0) { evens.push(n) } }; evens`,
+	`let keys = []; for k, v in {"b": 2, "a": 1} { keys.push(k + string(v)) }; keys`,
+	`let out = ""; for c in "abc" { out = c + out }; out`,
+	`let pairs = []; for i, v in ["x", "y"] { pairs.push([i, v]) }; pairs`,
+	`let n = 0; while (true) { n++; if (n >= 7) { break } }; n`,
+	`let square = func(x) { x * x }; square(12)`,
+	`let pow = func(base, exp = 2) { base ** exp }; [pow(3), pow(2, 10), pow(exp = 3, base = 2)]`,
+	`let fact = func(n) { if (n <= 1) { return 1 }; return n * fact(n - 1) }; fact(10)`,
+	`let compose = func(f, g) { func(x) { f(g(x)) } }
+	 let inc = func(x) { x + 1 }
+	 let double = func(x) { x * 2 }
+	 compose(inc, double)(5)`,
+	`let makeCounter = func() {
+		let count = 0
+		return func() { count += 1; count }
+	 }
+	 let a = makeCounter()
+	 let b = makeCounter()
+	 a(); a(); b()
+	 let counts = [a(), b()]
+	 counts`,
+	`let fns = []
+	 for i in [1, 2, 3] { fns.push(func() { i * 10 }) }
+	 fns.map(func(f) { f() })`,
+	`[1, 2, 3, 4, 5].filter(func(n) { n > 2 }).map(func(n) { n * n })`,
+	`let total = 0
+	 let add = func(n) { total = total + n }
+	 add(5); add(7)
+	 total`,
+	`let xs = [5, 3, 8]; len(xs) + xs.length()`,
+	`type(3.5) + type("s")`,
+	`const greeting = "hi"; greeting.upper()`,
+	`let main = func() { return 99 }`,
+	`let x = 9223372036854775807; [x + 1, x * 3, -x - 2, 3n ** 40, 5n / 2]`,
+	`[2 ** 10, 3 ** 40, 2 ** -1, type(2 ** 64)]`,
+	`let total = decimal("0"); for p in [decimal("0.10"), decimal("0.20")] { total = total + p }; [total, total * 3, decimal("1") / 3]`,
+}
+
+func TestDifferential(t *testing.T) {
+	for _, input := range differentialPrograms {
+		expected := evaluator.Eval(parse(input), object.NewEnvironment())
+		if errObj, ok := expected.(*object.Error); ok {
+			t.Fatalf("evaluator failed on %q: %s", input, errObj.Message)
+		}
+
+		comp := compiler.New()
+		if err := comp.Compile(parse(input)); err != nil {
+			t.Fatalf("compiler error on %q: %s", input, err)
+		}
+		machine := New(comp.Bytecode())
+		if err := machine.Run(); err != nil {
+			t.Fatalf("vm error on %q: %s", input, err)
+		}
+
+		// Dicts keep their insertion order, so equal values print the same
+		got := machine.Result()
+		if expected.Type() != got.Type() || expected.Inspect() != got.Inspect() {
+			t.Errorf("engines disagree on %q.\nevaluator: %s\nvm:        %s",
+				input, expected.Inspect(), got.Inspect())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package vm
+
+import (
+	"github.com/vintlang/vintlang/internal/code"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+type Frame struct {
+	cl          *object.Closure
+	ip          int
+	basePointer int
+}
+
+func NewFrame(cl *object.Closure, basePointer int) *Frame {
+	return &Frame{
+		cl:          cl,
+		ip:          -1,
+		basePointer: basePointer,
+	}
+}
+
+func (f *Frame) Instructions() code.Instructions {
+	return f.cl.Fn.Instructions
+}
+
+// position returns the source position of the instruction being executed.
+func (f *Frame) position() (code.Position, bool) {
+	return f.cl.Fn.Positions.Lookup(f.ip)
+}
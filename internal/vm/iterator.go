@@ -0,0 +1,40 @@
+package vm
+
+import "github.com/vintlang/vintlang/internal/object"
+
+const ITERATOR_OBJ = "ITERATOR"
+
+// iterator walks a snapshot of an iterable for a for..in loop, the same way
+// the evaluator does, so that nested loops over one value do not interfere.
+type iterator struct {
+	keys   []object.VintObject
+	values []object.VintObject
+	index  int
+}
+
+func newIterator(it object.Iterable) *iterator {
+	iter := &iterator{}
+	it.Reset()
+	for {
+		key, value := it.Next()
+		if key == nil {
+			break
+		}
+		iter.keys = append(iter.keys, key)
+		iter.values = append(iter.values, value)
+	}
+	it.Reset()
+	return iter
+}
+
+func (it *iterator) Type() object.VintObjectType { return ITERATOR_OBJ }
+func (it *iterator) Inspect() string             { return "iterator" }
+
+func (it *iterator) next() (object.VintObject, object.VintObject, bool) {
+	if it.index >= len(it.keys) {
+		return nil, nil, false
+	}
+	i := it.index
+	it.index++
+	return it.keys[i], it.values[i], true
+}
@@ -0,0 +1,218 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vintlang/vintlang/internal/code"
+	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/object"
+)
+
+var binaryOperators = map[code.Opcode]string{
+	code.OpAdd:          "+",
+	code.OpSub:          "-",
+	code.OpMul:          "*",
+	code.OpDiv:          "/",
+	code.OpMod:          "%",
+	code.OpPow:          "**",
+	code.OpEqual:        "==",
+	code.OpNotEqual:     "!=",
+	code.OpGreaterThan:  ">",
+	code.OpGreaterEqual: ">=",
+	code.OpAnd:          "&&",
+	code.OpOr:           "||",
+	code.OpIn:           "in",
+	code.OpCoalesce:     "??",
+	code.OpBitAnd:       "&",
+	code.OpBitOr:        "|",
+	code.OpBitXor:       "^",
+	code.OpShiftLeft:    "<<",
+	code.OpShiftRight:   ">>",
+}
+
+func (vm *VM) executeBinaryOperation(op code.Opcode) error {
+	right := vm.pop()
+	left := vm.pop()
+
+	// Integer arithmetic is by far the most common case, so it skips the
+	// evaluator entirely
+	if l, ok := left.(*object.Integer); ok {
+		if r, ok := right.(*object.Integer); ok {
+			if result, ok := integerOperation(op, l.Value, r.Value); ok {
+				return vm.push(result)
+			}
+		}
+	}
+
+	result := evaluator.InfixOperation(binaryOperators[op], left, right, vm.line())
+	return vm.pushResult(result)
+}
+
+// integerOperation handles the operators whose result is always obvious. It
+// reports false for anything the evaluator should decide, such as division
+// that does not come out even, division by zero, or a result too large for
+// an Integer, which becomes a BigInt.
+func integerOperation(op code.Opcode, left, right int64) (object.VintObject, bool) {
+	switch op {
+	case code.OpAdd:
+		sum := left + right
+		if (left^sum)&(right^sum) < 0 {
+			return nil, false
+		}
+		return &object.Integer{Value: sum}, true
+	case code.OpSub:
+		difference := left - right
+		if (left^right)&(left^difference) < 0 {
+			return nil, false
+		}
+		return &object.Integer{Value: difference}, true
+	case code.OpMul:
+		product := left * right
+		if left != 0 && (product/left != right || (left == -1 && right == math.MinInt64)) {
+			return nil, false
+		}
+		return &object.Integer{Value: product}, true
+	case code.OpDiv:
+		if right == 0 || left%right != 0 {
+			return nil, false
+		}
+		return &object.Integer{Value: left / right}, true
+	case code.OpMod:
+		if right == 0 {
+			return nil, false
+		}
+		return &object.Integer{Value: left % right}, true
+	case code.OpEqual:
+		return nativeBoolToBooleanObject(left == right), true
+	case code.OpNotEqual:
+		return nativeBoolToBooleanObject(left != right), true
+	case code.OpGreaterThan:
+		return nativeBoolToBooleanObject(left > right), true
+	case code.OpGreaterEqual:
+		return nativeBoolToBooleanObject(left >= right), true
+	case code.OpBitAnd:
+		return &object.Integer{Value: left & right}, true
+	case code.OpBitOr:
+		return &object.Integer{Value: left | right}, true
+	case code.OpBitXor:
+		return &object.Integer{Value: left ^ right}, true
+	}
+	return nil, false
+}
+
+func (vm *VM) executePrefixOperation(op code.Opcode) error {
+	operand := vm.pop()
+
+	operator := "-"
+	switch op {
+	case code.OpBang:
+		operator = "!"
+	case code.OpBitNot:
+		operator = "~"
+	}
+	return vm.pushResult(evaluator.PrefixOperation(operator, operand, vm.line()))
+}
+
+func (vm *VM) executeIndexExpression(left, index object.VintObject) error {
+	if array, ok := left.(*object.Array); ok {
+		if i, ok := index.(*object.Integer); ok && i.Value >= 0 && i.Value < int64(len(array.Elements)) {
+			return vm.push(array.Elements[i.Value])
+		}
+	}
+	return vm.pushResult(evaluator.IndexOperation(left, index, vm.line()))
+}
+
+func (vm *VM) buildDict(startIndex, endIndex int) (object.VintObject, error) {
+	dict := object.NewDict()
+
+	for i := startIndex; i < endIndex; i += 2 {
+		key := vm.stack[i]
+		value := vm.stack[i+1]
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return nil, vm.newError("Hashing failed: %s", key.Inspect())
+		}
+		dict.SetPair(hashKey.HashKey(), object.DictPair{Key: key, Value: value})
+	}
+
+	return dict, nil
+}
+
+func (vm *VM) executeRange(start, end object.VintObject) error {
+	s, ok := start.(*object.Integer)
+	if !ok {
+		return &Error{Object: &object.Error{Message: fmt.Sprintf("range start must be an integer, got %T", start)}}
+	}
+	e, ok := end.(*object.Integer)
+	if !ok {
+		return &Error{Object: &object.Error{Message: fmt.Sprintf("range end must be an integer, got %T", end)}}
+	}
+	return vm.push(&object.Range{Start: s.Value, End: e.Value, Current: s.Value})
+}
+
+// newError builds a runtime error prefixed with the current line, in the
+// same format the evaluator uses.
+func (vm *VM) newError(format string, a ...interface{}) error {
+	msg := fmt.Sprintf(format, a...)
+	if line := vm.line(); line > 0 {
+		msg = fmt.Sprintf("Line %d: %s", line, msg)
+	}
+	return &Error{Object: &object.Error{Message: msg}}
+}
+
+// line returns the source line of the instruction being executed, or 0 if
+// it is not known.
+func (vm *VM) line() int {
+	pos, ok := vm.currentFrame().position()
+	if !ok {
+		return 0
+	}
+	return pos.Line
+}
+
+// fail attaches the position and call stack of the failing instruction to
+// err and unwinds every frame.
+func (vm *VM) fail(err error) error {
+	vmErr, ok := err.(*Error)
+	if !ok {
+		vmErr = &Error{Object: &object.Error{Message: err.Error()}}
+	}
+
+	errObj := vmErr.Object
+	if !errObj.HasLocation() && len(errObj.Stack) == 0 {
+		for i := vm.framesIndex - 1; i >= 0; i-- {
+			frame := vm.frames[i]
+			if pos, ok := frame.position(); ok {
+				errObj.Locate(pos.File, pos.Line, pos.Column)
+			}
+			if frame.cl.Fn != vm.program {
+				errObj.PushFrame(frameName(frame))
+			}
+		}
+	}
+
+	vm.framesIndex = 0
+	return vmErr
+}
+
+func frameName(f *Frame) string {
+	if f.cl.Fn.Name == "" {
+		return "<anonymous>"
+	}
+	return f.cl.Fn.Name
+}
+
+func (vm *VM) String() string {
+	var out strings.Builder
+	for i := 0; i < vm.sp; i++ {
+		if vm.stack[i] == nil {
+			out.WriteString("<nil>\n")
+			continue
+		}
+		out.WriteString(vm.stack[i].Inspect() + "\n")
+	}
+	return out.String()
+}
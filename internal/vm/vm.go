@@ -1,36 +1,86 @@
 package vm
 
 import (
-	"encoding/binary"
 	"fmt"
+	"strings"
 
 	"github.com/vintlang/vintlang/internal/code"
 	"github.com/vintlang/vintlang/internal/compiler"
+	"github.com/vintlang/vintlang/internal/evaluator"
 	"github.com/vintlang/vintlang/internal/object"
 )
 
 const StackSize = 2048
+const MaxStackSize = 1 << 22
+const MaxFrames = 1 << 16
 
+// The VM shares its singletons with the evaluator so values coming from
+// builtins compare the same way in both engines.
 var (
-	True  = &object.Boolean{Value: true}
-	False = &object.Boolean{Value: false}
+	True  = evaluator.TRUE
+	False = evaluator.FALSE
+	Null  = evaluator.NULL
 )
 
+// Error is a runtime error raised while running bytecode. Object is the
+// same error value the evaluator would produce, with its position and
+// call stack filled in.
+type Error struct {
+	Object *object.Error
+}
+
+func (e *Error) Error() string {
+	return e.Object.Message
+}
+
 type VM struct {
-	constants    []object.VintObject
-	instructions []byte
+	constants []object.VintObject
+	program   *object.CompiledFunction // the top-level code
 
 	stack               []object.VintObject
 	sp                  int // Always points to the next value. Top of the stack is stack[sp-1]
 	lastPoppedStackElem object.VintObject
+
+	globals     []object.VintObject
+	globalNames []string
+
+	frames      []*Frame
+	framesIndex int
+
+	result    object.VintObject
+	returned  bool // the top level ended with a return statement
+	hasResult bool
 }
 
 func New(bytecode *compiler.Bytecode) *VM {
+	mainFn := &object.CompiledFunction{
+		Instructions: bytecode.Instructions,
+		NumLocals:    bytecode.NumLocals,
+		Name:         "<main>",
+		Positions:    bytecode.Positions,
+	}
+	mainFrame := NewFrame(&object.Closure{Fn: mainFn}, 0)
+
+	frames := make([]*Frame, 1, 64)
+	frames[0] = mainFrame
+
+	// Globals that are never defined by the script resolve to builtins
+	globals := make([]object.VintObject, len(bytecode.Globals))
+	for i, name := range bytecode.Globals {
+		if builtin, ok := evaluator.GetBuiltinFunction(name); ok {
+			globals[i] = builtin
+		}
+	}
+
 	return &VM{
-		instructions: bytecode.Instructions,
-		constants:    bytecode.Constants,
-		stack:        make([]object.VintObject, StackSize),
-		sp:           0,
+		constants:   bytecode.Constants,
+		program:     mainFn,
+		stack:       make([]object.VintObject, StackSize),
+		sp:          bytecode.NumLocals,
+		globals:     globals,
+		globalNames: bytecode.Globals,
+		frames:      frames,
+		framesIndex: 1,
 	}
 }
 
@@ -38,6 +88,17 @@ func (vm *VM) LastPoppedStackElem() object.VintObject {
 	return vm.lastPoppedStackElem
 }
 
+// Result is the value the program evaluated to, matching what
+// evaluator.Eval returns for a program: the return value of main() if the
+// script defines one, the value of a top-level return, or else the value
+// of the last expression statement.
+func (vm *VM) Result() object.VintObject {
+	if vm.hasResult {
+		return vm.result
+	}
+	return vm.lastPoppedStackElem
+}
+
 func (vm *VM) StackTop() object.VintObject {
 	if vm.sp == 0 {
 		return nil
@@ -45,112 +106,421 @@ func (vm *VM) StackTop() object.VintObject {
 	return vm.stack[vm.sp-1]
 }
 
+func (vm *VM) currentFrame() *Frame {
+	return vm.frames[vm.framesIndex-1]
+}
+
+func (vm *VM) pushFrame(f *Frame) error {
+	if vm.framesIndex >= MaxFrames {
+		return fmt.Errorf("maximum recursion depth exceeded")
+	}
+	if vm.framesIndex < len(vm.frames) {
+		vm.frames[vm.framesIndex] = f
+	} else {
+		vm.frames = append(vm.frames, f)
+	}
+	vm.framesIndex++
+	return nil
+}
+
+func (vm *VM) popFrame() *Frame {
+	vm.framesIndex--
+	return vm.frames[vm.framesIndex]
+}
+
+// Run executes the program, then calls main() if the script defines it,
+// just like the evaluator does.
 func (vm *VM) Run() error {
-	for ip := 0; ip < len(vm.instructions); ip++ {
-		op := vm.instructions[ip]
+	err := vm.run(0)
+	if err != nil {
+		return err
+	}
+	if vm.returned {
+		return nil
+	}
+
+	for i, name := range vm.globalNames {
+		if name != "main" {
+			continue
+		}
+		if main, ok := vm.globals[i].(*object.Closure); ok {
+			result, err := vm.invoke(main, nil)
+			if err != nil {
+				return err
+			}
+			vm.result, vm.hasResult = result, true
+		}
+	}
+	return nil
+}
+
+// run executes instructions until the frame count drops back to depth.
+func (vm *VM) run(depth int) error {
+	var ip int
+	var ins code.Instructions
+	var op code.Opcode
+
+	frame := vm.currentFrame()
+
+	for {
+		frame.ip++
+		ip = frame.ip
+		ins = frame.cl.Fn.Instructions
+		if ip >= len(ins) {
+			// Only the top level can run off the end of its instructions
+			vm.framesIndex--
+			return nil
+		}
+		op = code.Opcode(ins[ip])
 
 		switch op {
-		case byte(code.OpConstant):
-			constIndex := binary.BigEndian.Uint16(vm.instructions[ip+1:])
-			ip += 2 // Move instruction pointer past the 2-byte operand
+		case code.OpConstant:
+			constIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2 // Move instruction pointer past the 2-byte operand
 
 			err := vm.push(vm.constants[constIndex])
 			if err != nil {
-				return err
+				return vm.fail(err)
 			}
-		case byte(code.OpPop):
+
+		case code.OpPop:
 			vm.pop()
-		case byte(code.OpAdd), byte(code.OpSub), byte(code.OpMul), byte(code.OpDiv):
+
+		case code.OpDup:
+			err := vm.push(vm.stack[vm.sp-1])
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpAdd, code.OpSub, code.OpMul, code.OpDiv, code.OpMod, code.OpPow,
+			code.OpEqual, code.OpNotEqual, code.OpGreaterThan, code.OpGreaterEqual,
+			code.OpAnd, code.OpOr, code.OpIn, code.OpCoalesce,
+			code.OpBitAnd, code.OpBitOr, code.OpBitXor, code.OpShiftLeft, code.OpShiftRight:
 			err := vm.executeBinaryOperation(op)
 			if err != nil {
-				return err
+				return vm.fail(err)
 			}
-		case byte(code.OpEqual), byte(code.OpNotEqual), byte(code.OpGreaterThan):
-			err := vm.executeComparison(op)
+
+		case code.OpMinus, code.OpBang, code.OpBitNot:
+			err := vm.executePrefixOperation(op)
 			if err != nil {
-				return err
+				return vm.fail(err)
 			}
-		case byte(code.OpTrue):
+
+		case code.OpTrue:
 			err := vm.push(True)
 			if err != nil {
-				return err
+				return vm.fail(err)
 			}
-		case byte(code.OpFalse):
+
+		case code.OpFalse:
 			err := vm.push(False)
 			if err != nil {
-				return err
+				return vm.fail(err)
 			}
-		}
-	}
-	return nil
-}
 
-func (vm *VM) executeComparison(op byte) error {
-	right := vm.pop()
-	left := vm.pop()
+		case code.OpNull:
+			err := vm.push(Null)
+			if err != nil {
+				return vm.fail(err)
+			}
 
-	if left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ {
-		return vm.executeIntegerComparison(op, left, right)
-	}
+		case code.OpJump:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip = pos - 1
 
-	switch op {
-	case byte(code.OpEqual):
-		return vm.push(nativeBoolToBooleanObject(right == left))
-	case byte(code.OpNotEqual):
-		return vm.push(nativeBoolToBooleanObject(right != left))
-	default:
-		return fmt.Errorf("unknown operator: %T %d %T",
-			left, op, right)
-	}
-}
+		case code.OpJumpNotTruthy:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
 
-func (vm *VM) executeIntegerComparison(
-	op byte,
-	left, right object.VintObject,
-) error {
-	leftValue := left.(*object.Integer).Value
-	rightValue := right.(*object.Integer).Value
+			condition := vm.pop()
+			if !evaluator.IsTruthy(condition) {
+				frame.ip = pos - 1
+			}
 
-	switch op {
-	case byte(code.OpEqual):
-		return vm.push(nativeBoolToBooleanObject(leftValue == rightValue))
-	case byte(code.OpNotEqual):
-		return vm.push(nativeBoolToBooleanObject(leftValue != rightValue))
-	case byte(code.OpGreaterThan):
-		return vm.push(nativeBoolToBooleanObject(leftValue > rightValue))
-	default:
-		return fmt.Errorf("unknown integer operator: %d", op)
-	}
-}
+		case code.OpSetGlobal:
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
+			vm.globals[globalIndex] = vm.pop()
 
-func (vm *VM) executeBinaryOperation(op byte) error {
-	right := vm.pop()
-	left := vm.pop()
+		case code.OpAssignGlobal:
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
+			if vm.globals[globalIndex] == nil {
+				return vm.fail(vm.newError("Assignment to undeclared variable '%s'. Use 'let' to declare the variable first",
+					vm.globalNames[globalIndex]))
+			}
+			vm.globals[globalIndex] = vm.pop()
 
-	leftVal := left.(*object.Integer).Value
-	rightVal := right.(*object.Integer).Value
+		case code.OpGetGlobal:
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
+			value := vm.globals[globalIndex]
+			if value == nil {
+				return vm.fail(vm.newError("Identifier not recognized: %s", vm.globalNames[globalIndex]))
+			}
+			err := vm.push(value)
+			if err != nil {
+				return vm.fail(err)
+			}
 
-	var result int64
+		case code.OpSetLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			vm.stack[frame.basePointer+int(localIndex)] = vm.pop()
 
-	switch op {
-	case byte(code.OpAdd):
-		result = leftVal + rightVal
-	case byte(code.OpSub):
-		result = leftVal - rightVal
-	case byte(code.OpMul):
-		result = leftVal * rightVal
-	case byte(code.OpDiv):
-		result = leftVal / rightVal
-	default:
-		return fmt.Errorf("unknown integer operator: %d", op)
-	}
+		case code.OpGetLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			value := vm.stack[frame.basePointer+int(localIndex)]
+			if value == nil {
+				value = Null
+			}
+			err := vm.push(value)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpMakeCell:
+			vm.stack[vm.sp-1] = &object.Cell{Value: vm.stack[vm.sp-1]}
+
+		case code.OpGetLocalCell:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			cell := vm.stack[frame.basePointer+int(localIndex)].(*object.Cell)
+			err := vm.push(cell.Value)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpSetLocalCell:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			cell := vm.stack[frame.basePointer+int(localIndex)].(*object.Cell)
+			cell.Value = vm.pop()
+
+		case code.OpGetFree:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			err := vm.push(frame.cl.Free[freeIndex].Value)
+			if err != nil {
+				return vm.fail(err)
+			}
 
-	return vm.push(&object.Integer{Value: result})
+		case code.OpSetFree:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			frame.cl.Free[freeIndex].Value = vm.pop()
+
+		case code.OpGetFreeCell:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+			err := vm.push(frame.cl.Free[freeIndex])
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpArray:
+			numElements := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			elements := make([]object.VintObject, numElements)
+			copy(elements, vm.stack[vm.sp-numElements:vm.sp])
+			vm.sp = vm.sp - numElements
+
+			err := vm.push(&object.Array{Elements: elements})
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpDict:
+			numElements := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			dict, err := vm.buildDict(vm.sp-numElements, vm.sp)
+			if err != nil {
+				return vm.fail(err)
+			}
+			vm.sp = vm.sp - numElements
+
+			err = vm.push(dict)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpIndex:
+			index := vm.pop()
+			left := vm.pop()
+
+			err := vm.executeIndexExpression(left, index)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpSetIndex:
+			value := vm.pop()
+			index := vm.pop()
+			container := vm.pop()
+
+			result := evaluator.IndexAssignment(container, index, value, vm.line())
+			err := vm.pushResult(result)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpInterpolate:
+			formatIndex := code.ReadUint16(ins[ip+1:])
+			frame.ip += 2
+
+			format := vm.constants[formatIndex].(*object.String).Value
+			err := vm.pushResult(evaluator.InterpolationString(vm.pop(), format, vm.line()))
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpConcat:
+			numParts := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			var out strings.Builder
+			for _, part := range vm.stack[vm.sp-numParts : vm.sp] {
+				out.WriteString(part.(*object.String).Value)
+			}
+			vm.sp = vm.sp - numParts
+
+			err := vm.push(&object.String{Value: out.String()})
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpRange:
+			end := vm.pop()
+			start := vm.pop()
+
+			err := vm.executeRange(start, end)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpIterInit:
+			iterable, ok := vm.pop().(object.Iterable)
+			if !ok {
+				return vm.fail(vm.newError("for..in loop requires an iterable object, but got %s", vm.lastPoppedStackElem.Type()))
+			}
+			err := vm.push(newIterator(iterable))
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpIterNext:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			frame.ip += 2
+
+			key, value, ok := vm.stack[vm.sp-1].(*iterator).next()
+			if !ok {
+				frame.ip = pos - 1
+				continue
+			}
+			err := vm.push(key)
+			if err == nil {
+				err = vm.push(value)
+			}
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpSkipIfBound:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			pos := int(code.ReadUint16(ins[ip+2:]))
+			frame.ip += 3
+			if vm.stack[frame.basePointer+int(localIndex)] != nil {
+				frame.ip = pos - 1
+			}
+
+		case code.OpCall:
+			numArgs := code.ReadUint8(ins[ip+1:])
+			frame.ip += 1
+
+			err := vm.executeCall(int(numArgs))
+			if err != nil {
+				return vm.fail(err)
+			}
+			frame = vm.currentFrame()
+
+		case code.OpCallKeywords:
+			numPositional := int(code.ReadUint8(ins[ip+1:]))
+			numKeywords := int(code.ReadUint8(ins[ip+2:]))
+			frame.ip += 2
+
+			err := vm.executeKeywordCall(numPositional, numKeywords)
+			if err != nil {
+				return vm.fail(err)
+			}
+			frame = vm.currentFrame()
+
+		case code.OpMethod:
+			nameIndex := code.ReadUint16(ins[ip+1:])
+			numArgs := int(code.ReadUint8(ins[ip+3:]))
+			frame.ip += 3
+
+			name := vm.constants[nameIndex].(*object.String).Value
+			err := vm.executeMethodCall(name, numArgs)
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		case code.OpReturnValue, code.OpReturn:
+			returnValue := object.VintObject(Null)
+			if op == code.OpReturnValue {
+				returnValue = vm.pop()
+			}
+
+			returning := vm.popFrame()
+			if returning.cl.Fn == vm.program {
+				// A return statement at the top level ends the program
+				vm.result, vm.hasResult, vm.returned = returnValue, true, true
+				vm.sp = 0
+				return nil
+			}
+			vm.sp = returning.basePointer - 1
+
+			err := vm.push(returnValue)
+			if err != nil {
+				return vm.fail(err)
+			}
+			if vm.framesIndex == depth {
+				return nil
+			}
+			frame = vm.currentFrame()
+
+		case code.OpClosure:
+			constIndex := code.ReadUint16(ins[ip+1:])
+			numFree := code.ReadUint8(ins[ip+3:])
+			frame.ip += 3
+
+			err := vm.pushClosure(int(constIndex), int(numFree))
+			if err != nil {
+				return vm.fail(err)
+			}
+
+		default:
+			def, err := code.Lookup(byte(op))
+			if err != nil {
+				return vm.fail(err)
+			}
+			return vm.fail(fmt.Errorf("unhandled opcode %s", def.Name))
+		}
+	}
 }
 
 func (vm *VM) push(o object.VintObject) error {
-	if vm.sp >= StackSize {
-		return fmt.Errorf("stack overflow")
+	if vm.sp >= len(vm.stack) {
+		if len(vm.stack) >= MaxStackSize {
+			return fmt.Errorf("stack overflow")
+		}
+		grown := make([]object.VintObject, len(vm.stack)*2)
+		copy(grown, vm.stack)
+		vm.stack = grown
 	}
 
 	vm.stack[vm.sp] = o
@@ -169,6 +539,18 @@ func (vm *VM) pop() object.VintObject {
 	return o
 }
 
+// pushResult pushes the result of an operation, turning error values into
+// runtime errors.
+func (vm *VM) pushResult(result object.VintObject) error {
+	if errObj, ok := result.(*object.Error); ok {
+		return &Error{Object: errObj}
+	}
+	if result == nil {
+		result = Null
+	}
+	return vm.push(result)
+}
+
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	if input {
 		return True
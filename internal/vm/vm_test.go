@@ -39,6 +39,153 @@ func TestBooleanExpressions(t *testing.T) {
 	runVmTests(t, tests)
 }
 
+func TestConditionals(t *testing.T) {
+	tests := []vmTestCase{
+		{"if (true) { 10 }", 10},
+		{"if (true) { 10 } else { 20 }", 10},
+		{"if (false) { 10 } else { 20 } ", 20},
+		{"if (1 < 2) { 10 }", 10},
+		{"if (1 > 2) { 10 }", Null},
+		{"if (!(if (false) { 5 })) { 10 } else { 20 }", 10},
+		{"if (1 > 2) { 10 } else if (2 > 1) { 20 } else { 30 }", 20},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestLetAndConstStatements(t *testing.T) {
+	tests := []vmTestCase{
+		{"let one = 1; one", 1},
+		{"let one = 1; let two = 2; one + two", 3},
+		{"let one = 1; let two = one + one; one + two", 3},
+		{"const limit = 10; limit * 2", 20},
+		{"let x = 1; x = x + 41; x", 42},
+		{"let x = 5; x += 3; x", 8},
+		{"let i = 0; i++; i++; i", 2},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestStringExpressions(t *testing.T) {
+	tests := []vmTestCase{
+		{`"vint"`, "vint"},
+		{`"vi" + "nt"`, "vint"},
+		{`"vi" + "nt" + "lang"`, "vintlang"},
+		{`"hello".upper()`, "HELLO"},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestArrayAndDictLiterals(t *testing.T) {
+	tests := []vmTestCase{
+		{"[]", []int{}},
+		{"[1, 2, 3]", []int{1, 2, 3}},
+		{"[1 + 2, 3 * 4, 5 + 6]", []int{3, 12, 11}},
+		{"[1, 2, 3][1]", 2},
+		{"[[1, 1, 1]][0][0]", 1},
+		{"[1, 2, 3][-1]", 3},
+		{`{"one": 1, "two": 2}["two"]`, 2},
+		{`let d = {"a": 1}; d["b"] = 2; d["a"] + d["b"]`, 3},
+		{"let a = [1, 2]; a[0] = 5; a", []int{5, 2}},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestLoops(t *testing.T) {
+	tests := []vmTestCase{
+		{"let i = 0; while (i < 10) { i++ }; i", 10},
+		{"let sum = 0; for x in [1, 2, 3, 4] { sum += x }; sum", 10},
+		{"let sum = 0; for i, x in [5, 6] { sum += i }; sum", 1},
+		{"let sum = 0; for i in range(1, 5) { sum += i }; sum", 10},
+		{"let i = 0; while (true) { i++; if (i == 3) { break } }; i", 3},
+		{"let n = 0; for x in [1, 2, 3, 4] { if (x % 2 == 0) { continue }; n += x }; n", 4},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestFunctionsAndClosures(t *testing.T) {
+	tests := []vmTestCase{
+		{"let add = func(a, b) { a + b }; add(1, 2)", 3},
+		{"let early = func() { return 1; 2 }; early()", 1},
+		{"let noop = func() { }; noop()", Null},
+		{"let greet = func(name = \"vint\") { name }; greet()", "vint"},
+		{"let sub = func(a, b) { a - b }; sub(b = 1, a = 5)", 4},
+		{"let fib = func(n) { if (n < 2) { return n }; fib(n - 1) + fib(n - 2) }; fib(15)", 610},
+		{"let adder = func(x) { func(y) { x + y } }; let addTwo = adder(2); addTwo(3)", 5},
+		{`let counter = func() { let n = 0; func() { n++; n } }
+		  let next = counter(); next(); next(); next()`, 3},
+		{"let f = func() { let a = 1; let g = func() { a = a + 1 }; g(); a }; f()", 2},
+		{"[1, 2, 3].map(func(x) { x * 2 })", []int{2, 4, 6}},
+		{"[1, 2, 3, 4].filter(func(x) { x % 2 == 0 })", []int{2, 4}},
+		{"len([1, 2, 3])", 3},
+		{"let main = func() { 7 }; main()", 7},
+	}
+
+	runVmTests(t, tests)
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		line    int
+	}{
+		{"let x = 1\nx + true", "Line 2: Type mismatch: cannot use '+' operator between INTEGER and BOOLEAN. Consider type conversion", 2},
+		{"y + 1", "Line 1: Identifier not recognized: y", 1},
+		{"let f = func(a) { a }\nf()", "Missing argument", 2},
+		{"let price = \"free\"\nlet s = `cost:\n${price:d}`", "Line 3: cannot format STRING with 'd'", 3},
+	}
+
+	for _, tt := range tests {
+		comp := compiler.New()
+		if err := comp.Compile(parse(tt.input)); err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		err := New(comp.Bytecode()).Run()
+		vmErr, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("expected a runtime error for %q, got %v", tt.input, err)
+		}
+		if vmErr.Object.Message != tt.message {
+			t.Errorf("wrong message for %q. want=%q, got=%q", tt.input, tt.message, vmErr.Object.Message)
+		}
+		if vmErr.Object.Line != tt.line {
+			t.Errorf("wrong line for %q. want=%d, got=%d", tt.input, tt.line, vmErr.Object.Line)
+		}
+	}
+}
+
+func TestErrorCallStack(t *testing.T) {
+	input := `let inner = func() { 1 + true }
+let outer = func() { inner() }
+outer()`
+
+	comp := compiler.New()
+	if err := comp.Compile(parse(input)); err != nil {
+		t.Fatalf("compiler error: %s", err)
+	}
+
+	err := New(comp.Bytecode()).Run()
+	vmErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected a runtime error, got %v", err)
+	}
+
+	var got []string
+	for _, f := range vmErr.Object.Traceback() {
+		got = append(got, fmt.Sprintf("%s:%d", f.Function, f.Line))
+	}
+	want := []string{"<main>:3", "outer:2", "inner:1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("wrong traceback. want=%v, got=%v", want, got)
+	}
+}
+
 func runVmTests(t *testing.T, tests []vmTestCase) {
 	t.Helper()
 
@@ -56,9 +203,7 @@ func runVmTests(t *testing.T, tests []vmTestCase) {
 			t.Fatalf("vm error: %s", err)
 		}
 
-		stackElem := vm.LastPoppedStackElem()
-
-		testExpectedObject(t, tt.expected, stackElem)
+		testExpectedObject(t, tt.expected, vm.Result())
 	}
 }
 
@@ -80,9 +225,47 @@ func testExpectedObject(
 		if err != nil {
 			t.Errorf("testBooleanObject failed: %s", err)
 		}
+	case string:
+		err := testStringObject(expected, actual)
+		if err != nil {
+			t.Errorf("testStringObject failed: %s", err)
+		}
+	case []int:
+		array, ok := actual.(*object.Array)
+		if !ok {
+			t.Errorf("object not Array: %T (%+v)", actual, actual)
+			return
+		}
+		if len(array.Elements) != len(expected) {
+			t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+			return
+		}
+		for i, expectedElem := range expected {
+			err := testIntegerObject(int64(expectedElem), array.Elements[i])
+			if err != nil {
+				t.Errorf("testIntegerObject failed: %s", err)
+			}
+		}
+	case *object.Null:
+		if actual != Null {
+			t.Errorf("object is not Null: %T (%+v)", actual, actual)
+		}
 	}
 }
 
+func testStringObject(expected string, actual object.VintObject) error {
+	result, ok := actual.(*object.String)
+	if !ok {
+		return fmt.Errorf("object is not String. got=%T (%+v)", actual, actual)
+	}
+
+	if result.Value != expected {
+		return fmt.Errorf("object has wrong value. got=%q, want=%q", result.Value, expected)
+	}
+
+	return nil
+}
+
 func testIntegerObject(expected int64, actual object.VintObject) error {
 	result, ok := actual.(*object.Integer)
 	if !ok {
@@ -9,13 +9,21 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/vintlang/vintlang/internal/bundler"
+	"github.com/vintlang/vintlang/internal/checker"
 	"github.com/vintlang/vintlang/internal/config"
+	"github.com/vintlang/vintlang/internal/coverage"
+	"github.com/vintlang/vintlang/internal/debugger"
 	"github.com/vintlang/vintlang/internal/evaluator"
+	"github.com/vintlang/vintlang/internal/formatter"
 	"github.com/vintlang/vintlang/internal/lexer"
+	"github.com/vintlang/vintlang/internal/linter"
+	"github.com/vintlang/vintlang/internal/lsp"
 	"github.com/vintlang/vintlang/internal/object"
 	"github.com/vintlang/vintlang/internal/parser"
+	"github.com/vintlang/vintlang/internal/profiler"
 	"github.com/vintlang/vintlang/internal/repl"
 	"github.com/vintlang/vintlang/internal/styles"
+	"github.com/vintlang/vintlang/internal/testrunner"
 	"github.com/vintlang/vintlang/internal/token"
 	"github.com/vintlang/vintlang/internal/toolkit"
 )
@@ -47,11 +55,19 @@ var (
     %s: Run a vint file
     %s: Bundle a vint file into binary
     %s: Initialize a new vint project
-    %s: Install a vint package
+    %s: Add a package from git or a local path
+    %s: Install the packages in vint.lock
     %s: Run tests in current directory
     %s: Format vint code
+    %s: Check types without running
+    %s: Find likely mistakes such as unused variables
+    %s: Start the language server for editors
+    %s: Debug a vint file, or serve DAP with --dap
     %s: Open interactive documentation
     %s: Trace pipeline stages to a txt file
+    %s: Time the functions and lines of a vint file
+    %s: Report the lines and branches a run covered
+    %s: Run a vint file on the bytecode VM
     %s: Show vint version
     %s: Show this help message
 `,
@@ -59,11 +75,19 @@ var (
 		styles.HelpStyle.Bold(true).Render("vint filename.vint"),
 		styles.HelpStyle.Bold(true).Render("vint bundler filename.vint"),
 		styles.HelpStyle.Bold(true).Render("vint init"),
-		styles.HelpStyle.Bold(true).Render("vint get package"),
-		styles.HelpStyle.Bold(true).Render("vint test"),
-		styles.HelpStyle.Bold(true).Render("vint fmt filename.vint"),
+		styles.HelpStyle.Bold(true).Render("vint get <git-url|path>[@version]"),
+		styles.HelpStyle.Bold(true).Render("vint install"),
+		styles.HelpStyle.Bold(true).Render("vint test [dir|file] [--run pattern] [--cover out.cov]"),
+		styles.HelpStyle.Bold(true).Render("vint fmt [--check] [--diff] [paths]"),
+		styles.HelpStyle.Bold(true).Render("vint check [paths]"),
+		styles.HelpStyle.Bold(true).Render("vint lint [--format text|json] [paths]"),
+		styles.HelpStyle.Bold(true).Render("vint lsp"),
+		styles.HelpStyle.Bold(true).Render("vint debug filename.vint"),
 		styles.HelpStyle.Bold(true).Render("vint docs"),
 		styles.HelpStyle.Bold(true).Render("vint --trace filename.vint"),
+		styles.HelpStyle.Bold(true).Render("vint --profile filename.vint"),
+		styles.HelpStyle.Bold(true).Render("vint --cover out.cov filename.vint"),
+		styles.HelpStyle.Bold(true).Render("vint --vm filename.vint"),
 		styles.HelpStyle.Bold(true).Render("vint version"),
 		styles.HelpStyle.Bold(true).Render("vint help")))
 )
@@ -114,21 +138,25 @@ func main() {
 			}
 			fmt.Println(styles.HelpStyle.Render("Build successful!"))
 		case "get":
-			if len(args) < 3 {
-				fmt.Println(styles.ErrorStyle.Render("Error: Please specify a package to install"))
-				os.Exit(1)
-			}
-			toolkit.Get(args[2])
+			os.Exit(toolkit.Get(args[2:], os.Stdout))
+		case "install":
+			os.Exit(toolkit.Install(args[2:], os.Stdout))
+		case "test", "-test", "--test":
+			os.Exit(testrunner.Main(args[2:], os.Stdout))
+		case "lsp":
+			os.Exit(lsp.Main(args[2:], os.Stdin, os.Stdout))
+		case "debug":
+			os.Exit(debugger.Main(args[2:], os.Stdin, os.Stdout))
 		case "init":
 			toolkit.Init(args)
 		case "new":
 			toolkit.New(args)
 		case "fmt", "-fmt", "--fmt", "-f":
-			if len(args) < 3 {
-				fmt.Println(styles.ErrorStyle.Render("Error: Please specify a Vint file to format"))
-				os.Exit(1)
-			}
-			formatFile(args[2])
+			os.Exit(formatter.Main(args[2:], os.Stdout))
+		case "check", "-check", "--check":
+			os.Exit(checker.Main(args[2:], os.Stdout))
+		case "lint", "-lint", "--lint":
+			os.Exit(linter.Main(args[2:], os.Stdout))
 		case "trace", "-trace", "--trace":
 			if len(args) < 3 {
 				fmt.Println(styles.ErrorStyle.Render("Error: Please specify a Vint file to trace"))
@@ -139,6 +167,16 @@ func main() {
 				outputFile = args[3]
 			}
 			runWithTrace(args[2], outputFile)
+		case "profile", "-profile", "--profile":
+			os.Exit(profiler.Main(args[2:], os.Stderr))
+		case "cover", "-cover", "--cover":
+			os.Exit(coverage.Main(args[2:], os.Stderr))
+		case "vm", "-vm", "--vm":
+			if len(args) < 3 {
+				fmt.Println(styles.ErrorStyle.Render("Error: Please specify a Vint file to run"))
+				os.Exit(1)
+			}
+			runWithVM(args[2])
 		case ".":
 			run("main.vint")
 		default:
@@ -183,41 +221,26 @@ func run(file string) {
 	}
 }
 
-// formatFile formats a Vint source file
-func formatFile(file string) {
+// runWithVM compiles the specified Vint file to bytecode and runs it on the VM
+func runWithVM(file string) {
+	if len(os.Args) > 3 {
+		toolkit.CLI_ARGS = append(toolkit.CLI_ARGS, os.Args[3:]...)
+	}
+
 	if !strings.HasSuffix(file, ".vint") {
-		fmt.Println(styles.ErrorStyle.Render("Error: Can only format .vint files"))
+		fmt.Println(styles.ErrorStyle.Render("'"+file+"'", "is not a correct file type. Use '.vint'"))
 		os.Exit(1)
 	}
 
 	contents, err := os.ReadFile(file)
 	if err != nil {
-		fmt.Println(styles.ErrorStyle.Render("Error: Failed to read file:", file))
-		os.Exit(1)
-	}
-
-	// Parse the file using the lexer and parser
-	l := lexer.NewWithFilename(string(contents), file)
-	p := parser.New(l)
-	program := p.ParseProgram()
-
-	if len(p.Errors()) > 0 {
-		fmt.Println(styles.ErrorStyle.Render("Error: Failed to parse file. Cannot format."))
-		for _, msg := range p.Errors() {
-			fmt.Println(styles.ErrorStyle.Render(msg))
-		}
+		fmt.Println(styles.ErrorStyle.Render("Error: vint Failed to read the file: ", file))
 		os.Exit(1)
 	}
 
-	formatted := program.String()
-
-	err = os.WriteFile(file, []byte(formatted), 0o644)
-	if err != nil {
-		fmt.Println(styles.ErrorStyle.Render("Error: Failed to write formatted code to file."))
+	if !repl.ReadWithVM(string(contents), file) {
 		os.Exit(1)
 	}
-
-	fmt.Println(styles.HelpStyle.Render("Formatted", file))
 }
 
 // runWithTrace executes a Vint file and writes the output of every pipeline